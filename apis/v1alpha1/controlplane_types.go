@@ -80,12 +80,66 @@ type ControlPlaneSpec struct {
 type ControlPlaneDeploymentOptions struct {
 	DeploymentOptions `json:",inline"`
 
-	// DataPlanes refers to the named DataPlane objects which this ControlPlane
-	// is responsible for. Currently they must be in the same namespace as the
-	// Dataplane.
+	// DataPlane refers to the named DataPlane object which this ControlPlane
+	// is responsible for. Unless DataPlaneNamespace is set, it must be in the
+	// same namespace as the ControlPlane. Its Service is published in the
+	// status of the resources configured by the ControlPlane.
 	//
 	// +optional
 	DataPlane *string `json:"dataplane,omitempty"`
+
+	// DataPlanes refers to additional named DataPlane objects which this
+	// ControlPlane is responsible for, in the same namespace as DataPlane.
+	//
+	// Managing more than one DataPlane, here or through DataPlaneSelector,
+	// requires a ControlPlane version which accepts a list of Kong Admin API
+	// URLs: 2.9 or newer. All the DataPlanes trust the ControlPlane's client
+	// certificate, as they share the cluster CA.
+	//
+	// +optional
+	DataPlanes []string `json:"dataplanes,omitempty"`
+
+	// DataPlaneSelector selects additional DataPlane objects, in the same
+	// namespace as DataPlane, which this ControlPlane is responsible for.
+	//
+	// +optional
+	DataPlaneSelector *metav1.LabelSelector `json:"dataplaneSelector,omitempty"`
+
+	// DataPlaneNamespace is the namespace of the DataPlane referenced by this
+	// ControlPlane. When unset, the namespace of the ControlPlane is used.
+	// References to DataPlanes in other namespaces are only permitted when a
+	// ReferenceGrant in the DataPlane's namespace allows them.
+	//
+	// +optional
+	DataPlaneNamespace *string `json:"dataplaneNamespace,omitempty"`
+
+	// Rollout describes how changes to the ControlPlane's Deployment are
+	// rolled out. When it's not set, the existing Deployment is updated in
+	// place.
+	//
+	// +optional
+	Rollout *ControlPlaneRollout `json:"rollout,omitempty"`
+}
+
+// ControlPlaneRollout defines the rollout behavior of a ControlPlane.
+type ControlPlaneRollout struct {
+	// Strategy contains the deployment strategy for the rollout.
+	Strategy ControlPlaneRolloutStrategy `json:"strategy"`
+}
+
+// ControlPlaneRolloutStrategy holds the rollout strategy options of a
+// ControlPlane.
+type ControlPlaneRolloutStrategy struct {
+	// Canary defines the canary deployment strategy: changes are deployed to
+	// a canary Deployment which Pods push the Kong configuration to the
+	// DataPlanes along with the live ones. The share of the Pods running the
+	// changes is progressively increased following the steps, as long as the
+	// canary Pods are ready and pass the analysis of their configuration
+	// pushes. The changes are then applied to the live Deployment, or rolled
+	// back otherwise.
+	//
+	// +optional
+	Canary *CanaryStrategy `json:"canary,omitempty"`
 }
 
 // ControlPlaneStatus defines the observed state of ControlPlane
@@ -98,8 +152,178 @@ type ControlPlaneStatus struct {
 	// +kubebuilder:validation:MaxItems=8
 	// +kubebuilder:default={{type: "Scheduled", status: "Unknown", reason:"NotReconciled", message:"Waiting for controller", lastTransitionTime: "1970-01-01T00:00:00Z"}}
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ObservedGeneration is the generation of the ControlPlane the status was
+	// computed for.
+	//
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// DataPlanes describe the connection state of every DataPlane this
+	// ControlPlane is responsible for.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	DataPlanes []ControlPlaneDataPlaneStatus `json:"dataplanes,omitempty"`
+
+	// Version is the version of the ControlPlane's container image chosen by
+	// AutomaticUpgrades.
+	//
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// DataPlane is the name of the DataPlane the ControlPlane is attached to.
+	//
+	// +optional
+	DataPlane string `json:"dataplane,omitempty"`
+
+	// Deployment is the status of the Deployment of the ControlPlane.
+	//
+	// +optional
+	Deployment *DeploymentStatus `json:"deployment,omitempty"`
+
+	// CertificateExpiry is the time the mTLS certificate of the ControlPlane
+	// expires at.
+	//
+	// +optional
+	CertificateExpiry *metav1.Time `json:"certificateExpiry,omitempty"`
+
+	// Sync is the status of the synchronization of the Kong configuration
+	// with the DataPlanes, as reported by the metrics of the ingress
+	// controller.
+	//
+	// +optional
+	Sync *ControlPlaneSyncStatus `json:"sync,omitempty"`
+
+	// Rollout contains the status of the ControlPlane rollout, when a rollout
+	// strategy is configured.
+	//
+	// +optional
+	Rollout *ControlPlaneRolloutStatus `json:"rollout,omitempty"`
+}
+
+// ControlPlaneRolloutStatus describes the status of a ControlPlane rollout.
+type ControlPlaneRolloutStatus struct {
+	// Phase is the current phase of the rollout.
+	Phase ControlPlaneRolloutPhase `json:"phase,omitempty"`
+
+	// CanaryDeployment is the name of the Deployment the changes are being
+	// rolled out to.
+	CanaryDeployment string `json:"canaryDeployment,omitempty"`
+
+	// Step is the index of the current step of the canary rollout.
+	Step int32 `json:"step,omitempty"`
+
+	// StepStartTime is the time the current step of the canary rollout
+	// started.
+	StepStartTime *metav1.Time `json:"stepStartTime,omitempty"`
+
+	// AnalysisBaseline holds the configuration push counters of the canary
+	// Pods when the analysis of the current step started. The error rate of
+	// a step only accounts for the pushes made since then.
+	//
+	// +optional
+	AnalysisBaseline *CanaryAnalysisBaseline `json:"analysisBaseline,omitempty"`
+
+	// RolledBackRevision is the Pod template hash of the changes which were
+	// rolled back. They aren't rolled out again until the ControlPlane
+	// changes.
+	RolledBackRevision string `json:"rolledBackRevision,omitempty"`
+
+	// Message is a human readable message about the rollout.
+	Message string `json:"message,omitempty"`
+}
+
+// ControlPlaneRolloutPhase is the phase of a ControlPlane rollout.
+// +kubebuilder:validation:Enum=Complete;AwaitingPromotion;Progressing;RolledBack
+type ControlPlaneRolloutPhase string
+
+const (
+	// ControlPlaneRolloutPhaseComplete indicates that there's no rollout in
+	// progress: the live Deployment matches the ControlPlane spec.
+	ControlPlaneRolloutPhaseComplete ControlPlaneRolloutPhase = "Complete"
+
+	// ControlPlaneRolloutPhaseAwaitingPromotion indicates that the current
+	// canary step is ready and waits to be manually promoted.
+	ControlPlaneRolloutPhaseAwaitingPromotion ControlPlaneRolloutPhase = "AwaitingPromotion"
+
+	// ControlPlaneRolloutPhaseProgressing indicates that a canary rollout is
+	// in progress.
+	ControlPlaneRolloutPhaseProgressing ControlPlaneRolloutPhase = "Progressing"
+
+	// ControlPlaneRolloutPhaseRolledBack indicates that the changes failed to
+	// be rolled out and were rolled back.
+	ControlPlaneRolloutPhaseRolledBack ControlPlaneRolloutPhase = "RolledBack"
+)
+
+// ControlPlaneSyncStatus describes the synchronization of the Kong
+// configuration by the ingress controller of a ControlPlane.
+type ControlPlaneSyncStatus struct {
+	// Synced tells whether the last configuration pushes observed by the
+	// operator succeeded. It's kept as it is after a restart of the operator
+	// until the next pushes are observed.
+	//
+	// +optional
+	Synced bool `json:"synced,omitempty"`
+
+	// LastTransitionTime is the time at which Synced, or the error reading
+	// the metrics, last changed.
+	//
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// Error tells why the metrics of the ControlPlane couldn't be read.
+	//
+	// +optional
+	Error string `json:"error,omitempty"`
 }
 
+// ControlPlaneDataPlaneStatus describes the connection state of a DataPlane
+// managed by a ControlPlane.
+type ControlPlaneDataPlaneStatus struct {
+	// Name is the name of the DataPlane.
+	Name string `json:"name"`
+
+	// AdminURL is the URL of the DataPlane's Kong Admin API which is
+	// configured by the ControlPlane.
+	//
+	// +optional
+	AdminURL string `json:"adminURL,omitempty"`
+
+	// State is the connection state of the DataPlane.
+	State ControlPlaneDataPlaneState `json:"state"`
+
+	// Message is a human readable explanation of the state.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ControlPlaneDataPlaneState is the connection state of a DataPlane managed by
+// a ControlPlane.
+//
+// +kubebuilder:validation:Enum=Connected;Pending;NotFound;RefNotPermitted
+type ControlPlaneDataPlaneState string
+
+const (
+	// ControlPlaneDataPlaneStateConnected indicates that the DataPlane is ready
+	// and its Kong Admin API is configured by the ControlPlane.
+	ControlPlaneDataPlaneStateConnected ControlPlaneDataPlaneState = "Connected"
+
+	// ControlPlaneDataPlaneStatePending indicates that the DataPlane exists but
+	// is not ready yet.
+	ControlPlaneDataPlaneStatePending ControlPlaneDataPlaneState = "Pending"
+
+	// ControlPlaneDataPlaneStateNotFound indicates that the DataPlane does not
+	// exist.
+	ControlPlaneDataPlaneStateNotFound ControlPlaneDataPlaneState = "NotFound"
+
+	// ControlPlaneDataPlaneStateRefNotPermitted indicates that the DataPlane
+	// lives in another namespace and no ReferenceGrant permits the reference.
+	ControlPlaneDataPlaneStateRefNotPermitted ControlPlaneDataPlaneState = "RefNotPermitted"
+)
+
 // GetConditions returns the ControlPlane Status Conditions
 func (c *ControlPlane) GetConditions() []metav1.Condition {
 	return c.Status.Conditions
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -56,8 +57,244 @@ type DataPlaneSpec struct {
 // deploy the DataPlane.
 type DataPlaneDeploymentOptions struct {
 	DeploymentOptions `json:",inline"`
+
+	// Rollout describes how changes to the DataPlane's Deployment are rolled
+	// out. When it's not set, the existing Deployment is updated in place.
+	//
+	// +optional
+	Rollout *DataPlaneRollout `json:"rollout,omitempty"`
+
+	// Database configures the database the DataPlane stores its configuration
+	// in. When it's not set, the DataPlane runs without database (DB-less).
+	// The database migrations are run by the operator in Jobs, around the
+	// rollouts of the DataPlane Deployment.
+	//
+	// +optional
+	Database *DataPlaneDatabase `json:"database,omitempty"`
+
+	// Role is the role of the DataPlane in a Kong cluster. Traditional nodes,
+	// the default, store their configuration and serve the traffic. In Kong
+	// hybrid mode, the control_plane nodes store the configuration and push it
+	// to the data_plane nodes which serve the traffic. A control plane
+	// requires a Database, and its cluster listener is exposed by a dedicated
+	// Service. The hybrid mode connections are secured with the certificates
+	// issued by the operator for the DataPlanes.
+	//
+	// +optional
+	// +kubebuilder:validation:Enum=traditional;control_plane;data_plane
+	Role DataPlaneRole `json:"role,omitempty"`
+
+	// ClusterControlPlane is the name of the DataPlane, in the same namespace,
+	// running the hybrid mode control plane which the data planes connect to.
+	// It must be set when the role is data_plane.
+	//
+	// +optional
+	ClusterControlPlane string `json:"clusterControlPlane,omitempty"`
+
+	// License references the Kong Enterprise license of the DataPlane. It's
+	// set in the KONG_LICENSE_DATA environment variable of the proxy container,
+	// and the DataPlane Pods are rolled when the license changes. The
+	// LicenseValid condition warns about its upcoming expiration.
+	//
+	// +optional
+	License *DataPlaneLicense `json:"license,omitempty"`
+
+	// DeclarativeConfig references the declarative Kong configuration
+	// (kong.yml) of a DataPlane which isn't configured by a ControlPlane. It's
+	// loaded by the DataPlane Pods on startup, and pushed to their Kong Admin
+	// API when it changes. It can only be used by the DB-less traditional
+	// DataPlanes.
+	//
+	// +optional
+	DeclarativeConfig *DataPlaneDeclarativeConfig `json:"declarativeConfig,omitempty"`
+}
+
+// DataPlaneDeclarativeConfig defines the source of the declarative Kong
+// configuration of a DataPlane. Exactly one of the sources must be set.
+type DataPlaneDeclarativeConfig struct {
+	// ConfigMapKeyRef selects the key of a ConfigMap, in the namespace of the
+	// DataPlane, holding the configuration.
+	//
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+
+	// SecretKeyRef selects the key of a Secret, in the namespace of the
+	// DataPlane, holding the configuration.
+	//
+	// +optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+}
+
+// DataPlaneLicense defines the Kong Enterprise license of a DataPlane.
+type DataPlaneLicense struct {
+	// SecretRef references the Secret, in the namespace of the DataPlane,
+	// holding the license JSON in its license key.
+	SecretRef corev1.LocalObjectReference `json:"secretRef"`
 }
 
+// DataPlaneRole is the role of a DataPlane in a Kong cluster.
+type DataPlaneRole string
+
+const (
+	// DataPlaneRoleTraditional is the role of the DataPlanes which are not
+	// part of a Kong hybrid mode cluster.
+	DataPlaneRoleTraditional DataPlaneRole = "traditional"
+
+	// DataPlaneRoleControlPlane is the role of the DataPlanes storing the
+	// configuration of a Kong hybrid mode cluster.
+	DataPlaneRoleControlPlane DataPlaneRole = "control_plane"
+
+	// DataPlaneRoleDataPlane is the role of the DataPlanes serving the traffic
+	// of a Kong hybrid mode cluster, with the configuration pushed by its
+	// control plane.
+	DataPlaneRoleDataPlane DataPlaneRole = "data_plane"
+)
+
+// DataPlaneDatabase defines the database of a DataPlane.
+type DataPlaneDatabase struct {
+	// Postgres configures the connection to a PostgreSQL database.
+	Postgres PostgresDatabase `json:"postgres"`
+}
+
+// PostgresDatabase defines the connection to a PostgreSQL database.
+type PostgresDatabase struct {
+	// ConnectionSecretRef references the Secret, in the namespace of the
+	// DataPlane, holding the connection settings in its host, port, user,
+	// password and database keys. Only the host key is required.
+	ConnectionSecretRef corev1.LocalObjectReference `json:"connectionSecretRef"`
+}
+
+// DataPlaneRollout defines the rollout behavior of a DataPlane.
+type DataPlaneRollout struct {
+	// Strategy contains the deployment strategy for the rollout.
+	Strategy DataPlaneRolloutStrategy `json:"strategy"`
+}
+
+// DataPlaneRolloutStrategy holds the rollout strategy options. Only one of the
+// strategies can be set.
+type DataPlaneRolloutStrategy struct {
+	// BlueGreen defines the blue/green deployment strategy: changes are
+	// deployed to a second "preview" Deployment, exposed by a preview Service,
+	// and the DataPlane Service is switched over to it once it's ready and
+	// promoted.
+	//
+	// +optional
+	BlueGreen *BlueGreenStrategy `json:"blueGreen,omitempty"`
+
+	// Canary defines the canary deployment strategy: changes are deployed to
+	// a canary Deployment which Pods receive, along with the live ones, the
+	// traffic of the DataPlane Service. The share of the Pods running the
+	// changes is progressively increased following the steps, as long as the
+	// canary Pods are ready and pass the analysis. Otherwise the changes are
+	// rolled back.
+	//
+	// +optional
+	Canary *CanaryStrategy `json:"canary,omitempty"`
+}
+
+// BlueGreenStrategy defines the blue/green deployment strategy.
+type BlueGreenStrategy struct {
+	// Promotion defines how the preview Deployment is promoted once it's
+	// ready. With the Automatic promotion the DataPlane Service is switched
+	// over as soon as the preview Deployment is ready. With the Manual
+	// promotion the controller waits for the DataPlane to be annotated with
+	// gateway-operator.konghq.com/promote-preview: "true".
+	//
+	// +optional
+	// +kubebuilder:default=Automatic
+	// +kubebuilder:validation:Enum=Automatic;Manual
+	Promotion PromotionStrategy `json:"promotion,omitempty"`
+}
+
+// CanaryStrategy defines the canary deployment strategy of a DataPlane or a
+// ControlPlane.
+type CanaryStrategy struct {
+	// Steps are the weights the canary goes through. The changes are promoted
+	// once the last step is passed.
+	//
+	// +kubebuilder:validation:MinItems=1
+	Steps []CanaryStep `json:"steps"`
+
+	// Analysis configures the checks run against the canary Pods before
+	// progressing to the next step. When it's not set, only the readiness of
+	// the canary Pods is checked.
+	//
+	// +optional
+	Analysis *CanaryAnalysis `json:"analysis,omitempty"`
+
+	// ProgressDeadline is the maximum time a step can take for the canary
+	// Pods to become ready and pass the analysis. The changes are rolled back
+	// once it's exceeded.
+	//
+	// +optional
+	// +kubebuilder:default="10m"
+	ProgressDeadline *metav1.Duration `json:"progressDeadline,omitempty"`
+}
+
+// CanaryStep is a step of a canary rollout.
+type CanaryStep struct {
+	// Weight is the percentage of the Pods running the changes. It's
+	// approximated by the number of canary Pods relative to the live ones: the
+	// traffic is balanced across the Pods of a DataPlane by its Service, and
+	// the configuration is pushed by all the Pods of a ControlPlane.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	Weight int32 `json:"weight"`
+
+	// Pause is how long the canary stays at this step once its Pods are ready
+	// and pass the analysis. When it's not set, the canary waits for the
+	// DataPlane or ControlPlane to be annotated with
+	// gateway-operator.konghq.com/promote-preview: "true" to progress.
+	//
+	// +optional
+	Pause *metav1.Duration `json:"pause,omitempty"`
+}
+
+// CanaryAnalysis defines the checks run against the canary Pods, using the
+// metrics they expose: the ones of Kong on its status port for DataPlanes,
+// which requires the Prometheus plugin to be enabled, and the ones of the
+// ingress controller for ControlPlanes.
+type CanaryAnalysis struct {
+	// MaxErrorRate is the maximum percentage of the requests served by the
+	// canary Pods of a DataPlane with a 5xx status code, or of the
+	// configuration pushes of the canary Pods of a ControlPlane which failed.
+	// The changes are rolled back once it's exceeded, or when the canary Pods
+	// served no request (or made no configuration push) before the progress
+	// deadline of the step.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	MaxErrorRate int32 `json:"maxErrorRate"`
+}
+
+// CanaryAnalysisBaseline holds the counters read from the canary Pods when the
+// analysis of a canary step started.
+type CanaryAnalysisBaseline struct {
+	// Total is the number of requests served by the canary Pods of a
+	// DataPlane, or the number of configuration pushes of the canary Pods of
+	// a ControlPlane.
+	Total int64 `json:"total"`
+
+	// Errors is the number of requests served with a 5xx status code by the
+	// canary Pods of a DataPlane, or the number of failed configuration
+	// pushes of the canary Pods of a ControlPlane.
+	Errors int64 `json:"errors"`
+}
+
+// PromotionStrategy is the type of the strategy used to promote a preview
+// Deployment.
+type PromotionStrategy string
+
+const (
+	// AutomaticPromotion promotes the preview Deployment as soon as it's ready.
+	AutomaticPromotion PromotionStrategy = "Automatic"
+
+	// ManualPromotion promotes the preview Deployment once the DataPlane is
+	// annotated to do so.
+	ManualPromotion PromotionStrategy = "Manual"
+)
+
 // DataPlaneStatus defines the observed state of DataPlane
 type DataPlaneStatus struct {
 	// Conditions describe the status of the DataPlane.
@@ -67,10 +304,176 @@ type DataPlaneStatus struct {
 	// +kubebuilder:validation:MaxItems=8
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
+	// ObservedGeneration is the generation of the DataPlane the status was
+	// computed for.
+	//
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// Service indicates the Service that exposes the DataPlane's configured routes
 	Service string `json:"service,omitempty"`
+
+	// ClusterService indicates the Service that exposes the cluster listener
+	// of a DataPlane running a Kong hybrid mode control plane.
+	//
+	// +optional
+	ClusterService string `json:"clusterService,omitempty"`
+
+	// Version is the version of the DataPlane's container image chosen by
+	// AutomaticUpgrades.
+	//
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Rollout contains the status of the DataPlane rollout, when a rollout
+	// strategy is configured.
+	//
+	// +optional
+	Rollout *DataPlaneRolloutStatus `json:"rollout,omitempty"`
+
+	// License contains the status of the Kong Enterprise license of the
+	// DataPlane, when one is configured.
+	//
+	// +optional
+	License *DataPlaneLicenseStatus `json:"license,omitempty"`
+
+	// DeclarativeConfigChecksum is the checksum of the declarative Kong
+	// configuration loaded by the DataPlane Pods.
+	//
+	// +optional
+	DeclarativeConfigChecksum string `json:"declarativeConfigChecksum,omitempty"`
+
+	// Pods contains the health of the ready DataPlane Pods, as reported by
+	// Kong on the status endpoint of their status port.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Pods []DataPlanePodStatus `json:"pods,omitempty"`
+
+	// Deployment is the status of the Deployment of the DataPlane, or of its
+	// live Deployment when a rollout strategy is configured.
+	//
+	// +optional
+	Deployment *DeploymentStatus `json:"deployment,omitempty"`
+
+	// Addresses are the addresses of the Service of the DataPlane: the
+	// addresses of its load balancer, if any, followed by its ClusterIP.
+	//
+	// +optional
+	Addresses []string `json:"addresses,omitempty"`
+
+	// CertificateExpiry is the time the mTLS certificate of the DataPlane
+	// expires at.
+	//
+	// +optional
+	CertificateExpiry *metav1.Time `json:"certificateExpiry,omitempty"`
+}
+
+// DataPlanePodStatus describes the health of a DataPlane Pod.
+type DataPlanePodStatus struct {
+	// Name is the name of the Pod.
+	Name string `json:"name"`
+
+	// ConfigurationHash is the hash of the Kong configuration loaded by the
+	// Pod. It's empty when no configuration is loaded, and for the DataPlanes
+	// using a database.
+	//
+	// +optional
+	ConfigurationHash string `json:"configurationHash,omitempty"`
+
+	// DatabaseReachable tells whether the Pod reaches the database of the
+	// DataPlane. It's always true for DB-less DataPlanes.
+	//
+	// +optional
+	DatabaseReachable bool `json:"databaseReachable,omitempty"`
+
+	// Workers is the number of Kong worker processes running in the Pod.
+	//
+	// +optional
+	Workers int32 `json:"workers,omitempty"`
+
+	// Error tells why the health of the Pod couldn't be read.
+	//
+	// +optional
+	Error string `json:"error,omitempty"`
 }
 
+// DataPlaneLicenseStatus describes the status of the Kong Enterprise license
+// of a DataPlane.
+type DataPlaneLicenseStatus struct {
+	// ExpirationTime is the time the license expires at.
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
+
+	// Checksum is the checksum of the license the DataPlane Pods run with.
+	Checksum string `json:"checksum,omitempty"`
+}
+
+// DataPlaneRolloutStatus describes the status of a DataPlane rollout.
+type DataPlaneRolloutStatus struct {
+	// Phase is the current phase of the rollout.
+	Phase DataPlaneRolloutPhase `json:"phase,omitempty"`
+
+	// Deployment is the name of the live Deployment, receiving the traffic
+	// of the DataPlane Service.
+	Deployment string `json:"deployment,omitempty"`
+
+	// PreviewDeployment is the name of the Deployment the changes are being
+	// rolled out to.
+	PreviewDeployment string `json:"previewDeployment,omitempty"`
+
+	// PreviewService is the name of the Service exposing the preview
+	// Deployment.
+	PreviewService string `json:"previewService,omitempty"`
+
+	// Step is the index of the current step of a canary rollout.
+	Step int32 `json:"step,omitempty"`
+
+	// StepStartTime is the time the current step of a canary rollout started.
+	StepStartTime *metav1.Time `json:"stepStartTime,omitempty"`
+
+	// AnalysisBaseline holds the counters of the canary Pods when the
+	// analysis of the current step of a canary rollout started. The error
+	// rate of a step only accounts for what happened since then.
+	//
+	// +optional
+	AnalysisBaseline *CanaryAnalysisBaseline `json:"analysisBaseline,omitempty"`
+
+	// RolledBackRevision is the Pod template hash of the changes which were
+	// rolled back. They aren't rolled out again until the DataPlane changes.
+	RolledBackRevision string `json:"rolledBackRevision,omitempty"`
+
+	// Message is a human readable message about the rollout.
+	Message string `json:"message,omitempty"`
+}
+
+// DataPlaneRolloutPhase is the phase of a DataPlane rollout.
+// +kubebuilder:validation:Enum=Complete;ProvisioningPreview;AwaitingPromotion;Progressing;RolledBack
+type DataPlaneRolloutPhase string
+
+const (
+	// DataPlaneRolloutPhaseComplete indicates that there's no rollout in
+	// progress: the live Deployment matches the DataPlane spec.
+	DataPlaneRolloutPhaseComplete DataPlaneRolloutPhase = "Complete"
+
+	// DataPlaneRolloutPhaseProvisioningPreview indicates that the preview
+	// Deployment is being provisioned and isn't ready yet.
+	DataPlaneRolloutPhaseProvisioningPreview DataPlaneRolloutPhase = "ProvisioningPreview"
+
+	// DataPlaneRolloutPhaseAwaitingPromotion indicates that the preview
+	// Deployment (or the current canary step) is ready and waits to be
+	// manually promoted.
+	DataPlaneRolloutPhaseAwaitingPromotion DataPlaneRolloutPhase = "AwaitingPromotion"
+
+	// DataPlaneRolloutPhaseProgressing indicates that a canary rollout is in
+	// progress.
+	DataPlaneRolloutPhaseProgressing DataPlaneRolloutPhase = "Progressing"
+
+	// DataPlaneRolloutPhaseRolledBack indicates that the changes failed to be
+	// rolled out and were rolled back.
+	DataPlaneRolloutPhaseRolledBack DataPlaneRolloutPhase = "RolledBack"
+)
+
 // GetConditions retrieves the DataPlane Status Conditions
 func (d *DataPlane) GetConditions() []metav1.Condition {
 	return d.Status.Conditions
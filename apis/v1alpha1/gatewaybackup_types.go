@@ -0,0 +1,125 @@
+/*
+Copyright 2022 Kong Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func init() {
+	SchemeBuilder.Register(&GatewayBackup{}, &GatewayBackupList{})
+}
+
+//+genclient
+//+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// GatewayBackup is the Schema for the gatewaybackups API
+type GatewayBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GatewayBackupSpec   `json:"spec,omitempty"`
+	Status GatewayBackupStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GatewayBackupList contains a list of GatewayBackup
+type GatewayBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GatewayBackup `json:"items"`
+}
+
+// GatewayBackupSpec defines the desired state of GatewayBackup
+type GatewayBackupSpec struct {
+	// GatewayName is the name of the Gateway to back up, in the namespace of
+	// the GatewayBackup.
+	//
+	// The backup snapshots the Gateway along with its GatewayClass and
+	// GatewayConfiguration, the DataPlane and ControlPlane provisioned for it,
+	// their issued certificates and the configuration Kong is running.
+	GatewayName string `json:"gatewayName"`
+
+	// Storage is where the snapshot of the Gateway is stored. The backup is
+	// taken once: the GatewayBackup has to be recreated to take a new one.
+	Storage GatewayBackupStorage `json:"storage"`
+}
+
+// GatewayBackupStorage is where the snapshot of a Gateway is stored. Exactly
+// one of the storages must be set.
+type GatewayBackupStorage struct {
+	// Secret stores the snapshot in a Secret.
+	//
+	// +optional
+	Secret *GatewayBackupSecretStorage `json:"secret,omitempty"`
+
+	// PersistentVolumeClaim stores the snapshot in a file of a volume.
+	//
+	// +optional
+	PersistentVolumeClaim *GatewayBackupVolumeStorage `json:"persistentVolumeClaim,omitempty"`
+}
+
+// GatewayBackupSecretStorage stores the snapshot of a Gateway in a Secret, in
+// the namespace of the GatewayBackup or GatewayRestore.
+type GatewayBackupSecretStorage struct {
+	// Name is the name of the Secret.
+	Name string `json:"name"`
+}
+
+// GatewayBackupVolumeStorage stores the snapshot of a Gateway in a file of a
+// PersistentVolumeClaim, in the namespace of the GatewayBackup or
+// GatewayRestore. The file is written and read by Jobs mounting the volume.
+type GatewayBackupVolumeStorage struct {
+	// ClaimName is the name of the PersistentVolumeClaim.
+	ClaimName string `json:"claimName"`
+
+	// Path is the name of the file in the volume. It defaults to the name of
+	// the GatewayBackup followed by .json.
+	//
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9._-]+$`
+	Path string `json:"path,omitempty"`
+}
+
+// GatewayBackupStatus defines the observed state of GatewayBackup
+type GatewayBackupStatus struct {
+	// Conditions describe the current conditions of the GatewayBackup.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +kubebuilder:validation:MaxItems=8
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// CompletionTime is the time the backup completed at.
+	//
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// GetConditions returns the GatewayBackup Status Conditions
+func (b *GatewayBackup) GetConditions() []metav1.Condition {
+	return b.Status.Conditions
+}
+
+// SetConditions sets the GatewayBackup Status Conditions
+func (b *GatewayBackup) SetConditions(conditions []metav1.Condition) {
+	b.Status.Conditions = conditions
+}
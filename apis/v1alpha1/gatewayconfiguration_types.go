@@ -50,8 +50,77 @@ type GatewayConfigurationSpec struct {
 	//
 	// +optional
 	ControlPlaneDeploymentOptions *ControlPlaneDeploymentOptions `json:"controlPlaneDeploymentOptions,omitempty"`
+
+	// SharedDataPlane enables sharing a single DataPlane and ControlPlane pair
+	// between the Gateways of the GatewayClass using this configuration, instead
+	// of provisioning a dedicated pair for every Gateway.
+	//
+	// +optional
+	SharedDataPlane *SharedDataPlaneOptions `json:"sharedDataPlane,omitempty"`
+
+	// TargetRef attaches the GatewayConfiguration to a Gateway in its
+	// namespace. The configuration is merged over the one of the GatewayClass
+	// of the Gateway, the fields it sets taking precedence. When several
+	// GatewayConfigurations target the same Gateway, the oldest one is used.
+	// SharedDataPlane can't be set in attached GatewayConfigurations.
+	//
+	// +optional
+	TargetRef *GatewayConfigurationTargetReference `json:"targetRef,omitempty"`
+}
+
+// GatewayConfigurationTargetReference identifies the object a
+// GatewayConfiguration is attached to.
+type GatewayConfigurationTargetReference struct {
+	// Group is the group of the target.
+	//
+	// +optional
+	// +kubebuilder:default=gateway.networking.k8s.io
+	// +kubebuilder:validation:Enum=gateway.networking.k8s.io
+	Group string `json:"group,omitempty"`
+
+	// Kind is the kind of the target.
+	//
+	// +kubebuilder:validation:Enum=Gateway
+	Kind GatewayConfigurationTargetKind `json:"kind"`
+
+	// Name is the name of the target, in the namespace of the
+	// GatewayConfiguration.
+	//
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// SharedDataPlaneOptions defines how the DataPlane and ControlPlane pair is
+// shared between Gateways.
+type SharedDataPlaneOptions struct {
+	// Scope defines which Gateways share a DataPlane and ControlPlane pair.
+	// With the Namespace scope the Gateways of a GatewayClass in a namespace
+	// share a pair created in that namespace, while with the Cluster scope all
+	// the Gateways of a GatewayClass share a pair created in the namespace of
+	// the GatewayConfiguration. The Gateways of the other namespaces must be
+	// permitted to use the shared DataPlane by a ReferenceGrant in that
+	// namespace.
+	//
+	// +optional
+	// +kubebuilder:default=Namespace
+	// +kubebuilder:validation:Enum=Namespace;Cluster
+	Scope SharedDataPlaneScope `json:"scope,omitempty"`
 }
 
+// SharedDataPlaneScope is the scope in which Gateways share a DataPlane and
+// ControlPlane pair.
+type SharedDataPlaneScope string
+
+const (
+	// SharedDataPlaneScopeNamespace makes the Gateways of a GatewayClass share
+	// a DataPlane and ControlPlane pair per namespace.
+	SharedDataPlaneScopeNamespace SharedDataPlaneScope = "Namespace"
+
+	// SharedDataPlaneScopeCluster makes all the Gateways of a GatewayClass
+	// share a single DataPlane and ControlPlane pair.
+	SharedDataPlaneScopeCluster SharedDataPlaneScope = "Cluster"
+)
+
 // GatewayConfigurationStatus defines the observed state of GatewayConfiguration
 type GatewayConfigurationStatus struct {
 	// Conditions describe the current conditions of the GatewayConfigurationStatus.
@@ -61,6 +130,74 @@ type GatewayConfigurationStatus struct {
 	// +listMapKey=type
 	// +kubebuilder:validation:MaxItems=8
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// GatewayClasses are the names of the GatewayClasses using the
+	// GatewayConfiguration as their parameters.
+	//
+	// +optional
+	// +listType=set
+	GatewayClasses []string `json:"gatewayClasses,omitempty"`
+
+	// Gateways are the Gateways of the GatewayClasses using the
+	// GatewayConfiguration, and the Gateways using it through their
+	// targetRef or annotation.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=namespace
+	// +listMapKey=name
+	Gateways []GatewayConfigurationObjectReference `json:"gateways,omitempty"`
+
+	// DataPlanes are the DataPlanes of the Gateways configured with the
+	// GatewayConfiguration, and whether they have converged to its current
+	// generation.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=namespace
+	// +listMapKey=name
+	DataPlanes []GatewayConfigurationDownstreamStatus `json:"dataPlanes,omitempty"`
+
+	// ControlPlanes are the ControlPlanes of the Gateways configured with the
+	// GatewayConfiguration, and whether they have converged to its current
+	// generation.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=namespace
+	// +listMapKey=name
+	ControlPlanes []GatewayConfigurationDownstreamStatus `json:"controlPlanes,omitempty"`
+}
+
+// GatewayConfigurationObjectReference identifies an object using a
+// GatewayConfiguration.
+type GatewayConfigurationObjectReference struct {
+	// Namespace is the namespace of the object.
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the object.
+	Name string `json:"name"`
+}
+
+// GatewayConfigurationDownstreamStatus defines the observed state of a
+// DataPlane or a ControlPlane configured with a GatewayConfiguration.
+type GatewayConfigurationDownstreamStatus struct {
+	// Namespace is the namespace of the DataPlane or ControlPlane.
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the DataPlane or ControlPlane.
+	Name string `json:"name"`
+
+	// ObservedGeneration is the generation of the GatewayConfiguration the
+	// DataPlane or ControlPlane has last been configured with.
+	//
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Converged indicates whether the DataPlane or ControlPlane has been
+	// configured with the current generation of the GatewayConfiguration and
+	// is ready.
+	Converged bool `json:"converged"`
 }
 
 //+kubebuilder:object:root=true
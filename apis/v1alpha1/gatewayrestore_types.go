@@ -0,0 +1,111 @@
+/*
+Copyright 2022 Kong Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func init() {
+	SchemeBuilder.Register(&GatewayRestore{}, &GatewayRestoreList{})
+}
+
+//+genclient
+//+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// GatewayRestore is the Schema for the gatewayrestores API
+type GatewayRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GatewayRestoreSpec   `json:"spec,omitempty"`
+	Status GatewayRestoreStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GatewayRestoreList contains a list of GatewayRestore
+type GatewayRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GatewayRestore `json:"items"`
+}
+
+// GatewayRestoreSpec defines the desired state of GatewayRestore
+type GatewayRestoreSpec struct {
+	// Source is the snapshot of the Gateway to restore.
+	Source GatewayRestoreSource `json:"source"`
+
+	// TargetNamespace is the namespace the Gateway is restored into. It
+	// defaults to the namespace of the GatewayRestore. Restoring into another
+	// namespace requires a ReferenceGrant in the target namespace allowing
+	// the GatewayRestores of the namespace of the GatewayRestore to refer to
+	// Gateways.
+	//
+	// The objects of the snapshot are recreated with the ownership they had
+	// when they were backed up. The GatewayClass and GatewayConfiguration are
+	// only restored when the GatewayClass doesn't exist, as in a fresh cluster,
+	// and the restore of GatewayClasses is enabled on the operator.
+	//
+	// +optional
+	TargetNamespace string `json:"targetNamespace,omitempty"`
+}
+
+// GatewayRestoreSource is the snapshot of a Gateway to restore. Exactly one of
+// the sources must be set.
+type GatewayRestoreSource struct {
+	// BackupName is the name of a completed GatewayBackup, in the namespace of
+	// the GatewayRestore, whose snapshot is restored.
+	//
+	// +optional
+	BackupName *string `json:"backupName,omitempty"`
+
+	// Storage is where the snapshot is stored, to restore the snapshots taken
+	// in other clusters. The path of the file of a PersistentVolumeClaim must
+	// be set.
+	//
+	// +optional
+	Storage *GatewayBackupStorage `json:"storage,omitempty"`
+}
+
+// GatewayRestoreStatus defines the observed state of GatewayRestore
+type GatewayRestoreStatus struct {
+	// Conditions describe the current conditions of the GatewayRestore.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +kubebuilder:validation:MaxItems=8
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// CompletionTime is the time the restore completed at.
+	//
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// GetConditions returns the GatewayRestore Status Conditions
+func (r *GatewayRestore) GetConditions() []metav1.Condition {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the GatewayRestore Status Conditions
+func (r *GatewayRestore) SetConditions(conditions []metav1.Condition) {
+	r.Status.Conditions = conditions
+}
@@ -2,6 +2,7 @@ package v1alpha1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // DeploymentOptions is a shared type used on objects to indicate that their
@@ -25,10 +26,32 @@ type DeploymentOptions struct {
 	// +optional
 	Version *string `json:"version,omitempty"`
 
+	// SemanticVersion indicates the semantic version of the ContainerImage,
+	// when it can't be determined from the image tag, e.g. for images pinned
+	// by digest or using custom tags. It does not change the image, but is
+	// used to pick the configuration depending on the version, such as the
+	// ControlPlane's RBAC permissions.
+	//
+	// +optional
+	SemanticVersion *string `json:"semanticVersion,omitempty"`
+
+	// AutomaticUpgrades indicates that the version of the ContainerImage is
+	// chosen and upgraded by the operator, using the newest version allowed by
+	// the upgrade policy in the operator's version catalog.
+	//
+	// When in use, the ContainerImage (if set) must not include a tag and the
+	// chosen version is reported in the object's status.
+	//
+	// +optional
+	AutomaticUpgrades *AutomaticUpgrades `json:"automaticUpgrades,omitempty"`
+
 	// Env indicates the environment variables to set for the Deployment.
+	// When GatewayConfigurations are merged, the variables are merged by name.
 	//
 	// +optional
-	Env []corev1.EnvVar `json:"env,omitempty"`
+	// +patchMergeKey=name
+	// +patchStrategy=merge
+	Env []corev1.EnvVar `json:"env,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
 
 	// EnvFrom indicates the environment variables to be set for the Deployment
 	// with the values set from specific sources (such as Secrets).
@@ -37,6 +60,98 @@ type DeploymentOptions struct {
 	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
 }
 
+// AutomaticUpgrades configures how the operator upgrades the version of a
+// Deployment's container image.
+type AutomaticUpgrades struct {
+	// Policy indicates which versions the operator is allowed to upgrade to.
+	//
+	// +optional
+	// +kubebuilder:default=Patch
+	// +kubebuilder:validation:Enum=Patch;Minor;Channel
+	Policy UpgradePolicy `json:"policy,omitempty"`
+
+	// Channel is the name of the version catalog channel to follow. It is
+	// required by, and only used with, the Channel policy.
+	//
+	// +optional
+	Channel string `json:"channel,omitempty"`
+
+	// MaintenanceWindows restrict the upgrades to the given time windows.
+	// Upgrades can happen at any time when empty.
+	//
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+}
+
+// UpgradePolicy indicates which versions the operator is allowed to upgrade to.
+type UpgradePolicy string
+
+const (
+	// UpgradePolicyPatch allows upgrades to newer patch versions of the
+	// current minor version.
+	UpgradePolicyPatch UpgradePolicy = "Patch"
+
+	// UpgradePolicyMinor allows upgrades to newer minor and patch versions of
+	// the current major version.
+	UpgradePolicyMinor UpgradePolicy = "Minor"
+
+	// UpgradePolicyChannel allows upgrades to any newer version of the
+	// version catalog channel.
+	UpgradePolicyChannel UpgradePolicy = "Channel"
+)
+
+// MaintenanceWindow is a recurring time window during which upgrades are
+// allowed to happen.
+type MaintenanceWindow struct {
+	// Days are the days of the week on which the window starts. The window
+	// starts every day when empty.
+	//
+	// +optional
+	Days []Weekday `json:"days,omitempty"`
+
+	// Start is the UTC time of the day at which the window starts, in the
+	// HH:MM format.
+	//
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// Duration is the length of the window.
+	Duration metav1.Duration `json:"duration"`
+}
+
+// DeploymentStatus is a shared type used in the status of objects whose
+// configuration results in a Deployment managed by the Operator, to report
+// the state of the Deployment without having to look it up.
+type DeploymentStatus struct {
+	// Name is the name of the Deployment.
+	Name string `json:"name"`
+
+	// Replicas is the number of Pods of the Deployment.
+	//
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// ReadyReplicas is the number of ready Pods of the Deployment.
+	//
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// Image is the container image of the Deployment.
+	//
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Version is the version of the container image, when it's known.
+	//
+	// +optional
+	Version string `json:"version,omitempty"`
+}
+
+// Weekday is a day of the week.
+//
+// +kubebuilder:validation:Enum=Monday;Tuesday;Wednesday;Thursday;Friday;Saturday;Sunday
+type Weekday string
+
 // GatewayConfigurationTargetKind is an object kind that can be targeted for
 // GatewayConfiguration attachment.
 type GatewayConfigurationTargetKind string
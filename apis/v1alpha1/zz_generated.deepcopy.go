@@ -28,27 +28,826 @@ import (
 	"sigs.k8s.io/gateway-api/apis/v1alpha2"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutomaticUpgrades) DeepCopyInto(out *AutomaticUpgrades) {
+	*out = *in
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutomaticUpgrades.
+func (in *AutomaticUpgrades) DeepCopy() *AutomaticUpgrades {
+	if in == nil {
+		return nil
+	}
+	out := new(AutomaticUpgrades)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlueGreenStrategy) DeepCopyInto(out *BlueGreenStrategy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueGreenStrategy.
+func (in *BlueGreenStrategy) DeepCopy() *BlueGreenStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(BlueGreenStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryAnalysis) DeepCopyInto(out *CanaryAnalysis) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryAnalysis.
+func (in *CanaryAnalysis) DeepCopy() *CanaryAnalysis {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryAnalysis)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryAnalysisBaseline) DeepCopyInto(out *CanaryAnalysisBaseline) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryAnalysisBaseline.
+func (in *CanaryAnalysisBaseline) DeepCopy() *CanaryAnalysisBaseline {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryAnalysisBaseline)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryStep) DeepCopyInto(out *CanaryStep) {
+	*out = *in
+	if in.Pause != nil {
+		in, out := &in.Pause, &out.Pause
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryStep.
+func (in *CanaryStep) DeepCopy() *CanaryStep {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryStrategy) DeepCopyInto(out *CanaryStrategy) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]CanaryStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Analysis != nil {
+		in, out := &in.Analysis, &out.Analysis
+		*out = new(CanaryAnalysis)
+		**out = **in
+	}
+	if in.ProgressDeadline != nil {
+		in, out := &in.ProgressDeadline, &out.ProgressDeadline
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryStrategy.
+func (in *CanaryStrategy) DeepCopy() *CanaryStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControlPlane) DeepCopyInto(out *ControlPlane) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlane.
+func (in *ControlPlane) DeepCopy() *ControlPlane {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlane)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ControlPlane) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneDataPlaneStatus) DeepCopyInto(out *ControlPlaneDataPlaneStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneDataPlaneStatus.
+func (in *ControlPlaneDataPlaneStatus) DeepCopy() *ControlPlaneDataPlaneStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneDataPlaneStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneDeploymentOptions) DeepCopyInto(out *ControlPlaneDeploymentOptions) {
+	*out = *in
+	in.DeploymentOptions.DeepCopyInto(&out.DeploymentOptions)
+	if in.DataPlane != nil {
+		in, out := &in.DataPlane, &out.DataPlane
+		*out = new(string)
+		**out = **in
+	}
+	if in.DataPlanes != nil {
+		in, out := &in.DataPlanes, &out.DataPlanes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DataPlaneSelector != nil {
+		in, out := &in.DataPlaneSelector, &out.DataPlaneSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DataPlaneNamespace != nil {
+		in, out := &in.DataPlaneNamespace, &out.DataPlaneNamespace
+		*out = new(string)
+		**out = **in
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(ControlPlaneRollout)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneDeploymentOptions.
+func (in *ControlPlaneDeploymentOptions) DeepCopy() *ControlPlaneDeploymentOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneDeploymentOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneList) DeepCopyInto(out *ControlPlaneList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ControlPlane, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneList.
+func (in *ControlPlaneList) DeepCopy() *ControlPlaneList {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ControlPlaneList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneRollout) DeepCopyInto(out *ControlPlaneRollout) {
+	*out = *in
+	in.Strategy.DeepCopyInto(&out.Strategy)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneRollout.
+func (in *ControlPlaneRollout) DeepCopy() *ControlPlaneRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneRolloutStatus) DeepCopyInto(out *ControlPlaneRolloutStatus) {
+	*out = *in
+	if in.StepStartTime != nil {
+		in, out := &in.StepStartTime, &out.StepStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.AnalysisBaseline != nil {
+		in, out := &in.AnalysisBaseline, &out.AnalysisBaseline
+		*out = new(CanaryAnalysisBaseline)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneRolloutStatus.
+func (in *ControlPlaneRolloutStatus) DeepCopy() *ControlPlaneRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneRolloutStrategy) DeepCopyInto(out *ControlPlaneRolloutStrategy) {
+	*out = *in
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneRolloutStrategy.
+func (in *ControlPlaneRolloutStrategy) DeepCopy() *ControlPlaneRolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneRolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneSpec) DeepCopyInto(out *ControlPlaneSpec) {
+	*out = *in
+	in.ControlPlaneDeploymentOptions.DeepCopyInto(&out.ControlPlaneDeploymentOptions)
+	if in.GatewayClass != nil {
+		in, out := &in.GatewayClass, &out.GatewayClass
+		*out = new(v1alpha2.ObjectName)
+		**out = **in
+	}
+	if in.IngressClass != nil {
+		in, out := &in.IngressClass, &out.IngressClass
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneSpec.
+func (in *ControlPlaneSpec) DeepCopy() *ControlPlaneSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneStatus) DeepCopyInto(out *ControlPlaneStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DataPlanes != nil {
+		in, out := &in.DataPlanes, &out.DataPlanes
+		*out = make([]ControlPlaneDataPlaneStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Deployment != nil {
+		in, out := &in.Deployment, &out.Deployment
+		*out = new(DeploymentStatus)
+		**out = **in
+	}
+	if in.CertificateExpiry != nil {
+		in, out := &in.CertificateExpiry, &out.CertificateExpiry
+		*out = (*in).DeepCopy()
+	}
+	if in.Sync != nil {
+		in, out := &in.Sync, &out.Sync
+		*out = new(ControlPlaneSyncStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(ControlPlaneRolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneStatus.
+func (in *ControlPlaneStatus) DeepCopy() *ControlPlaneStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneSyncStatus) DeepCopyInto(out *ControlPlaneSyncStatus) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneSyncStatus.
+func (in *ControlPlaneSyncStatus) DeepCopy() *ControlPlaneSyncStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneSyncStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataPlane) DeepCopyInto(out *DataPlane) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataPlane.
+func (in *DataPlane) DeepCopy() *DataPlane {
+	if in == nil {
+		return nil
+	}
+	out := new(DataPlane)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DataPlane) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataPlaneDatabase) DeepCopyInto(out *DataPlaneDatabase) {
+	*out = *in
+	out.Postgres = in.Postgres
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataPlaneDatabase.
+func (in *DataPlaneDatabase) DeepCopy() *DataPlaneDatabase {
+	if in == nil {
+		return nil
+	}
+	out := new(DataPlaneDatabase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataPlaneDeclarativeConfig) DeepCopyInto(out *DataPlaneDeclarativeConfig) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataPlaneDeclarativeConfig.
+func (in *DataPlaneDeclarativeConfig) DeepCopy() *DataPlaneDeclarativeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DataPlaneDeclarativeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataPlaneDeploymentOptions) DeepCopyInto(out *DataPlaneDeploymentOptions) {
+	*out = *in
+	in.DeploymentOptions.DeepCopyInto(&out.DeploymentOptions)
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(DataPlaneRollout)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Database != nil {
+		in, out := &in.Database, &out.Database
+		*out = new(DataPlaneDatabase)
+		**out = **in
+	}
+	if in.License != nil {
+		in, out := &in.License, &out.License
+		*out = new(DataPlaneLicense)
+		**out = **in
+	}
+	if in.DeclarativeConfig != nil {
+		in, out := &in.DeclarativeConfig, &out.DeclarativeConfig
+		*out = new(DataPlaneDeclarativeConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataPlaneDeploymentOptions.
+func (in *DataPlaneDeploymentOptions) DeepCopy() *DataPlaneDeploymentOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(DataPlaneDeploymentOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataPlaneLicense) DeepCopyInto(out *DataPlaneLicense) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataPlaneLicense.
+func (in *DataPlaneLicense) DeepCopy() *DataPlaneLicense {
+	if in == nil {
+		return nil
+	}
+	out := new(DataPlaneLicense)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataPlaneLicenseStatus) DeepCopyInto(out *DataPlaneLicenseStatus) {
+	*out = *in
+	if in.ExpirationTime != nil {
+		in, out := &in.ExpirationTime, &out.ExpirationTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataPlaneLicenseStatus.
+func (in *DataPlaneLicenseStatus) DeepCopy() *DataPlaneLicenseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DataPlaneLicenseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataPlaneList) DeepCopyInto(out *DataPlaneList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DataPlane, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataPlaneList.
+func (in *DataPlaneList) DeepCopy() *DataPlaneList {
+	if in == nil {
+		return nil
+	}
+	out := new(DataPlaneList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DataPlaneList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataPlanePodStatus) DeepCopyInto(out *DataPlanePodStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataPlanePodStatus.
+func (in *DataPlanePodStatus) DeepCopy() *DataPlanePodStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DataPlanePodStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataPlaneRollout) DeepCopyInto(out *DataPlaneRollout) {
+	*out = *in
+	in.Strategy.DeepCopyInto(&out.Strategy)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataPlaneRollout.
+func (in *DataPlaneRollout) DeepCopy() *DataPlaneRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(DataPlaneRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataPlaneRolloutStatus) DeepCopyInto(out *DataPlaneRolloutStatus) {
+	*out = *in
+	if in.StepStartTime != nil {
+		in, out := &in.StepStartTime, &out.StepStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.AnalysisBaseline != nil {
+		in, out := &in.AnalysisBaseline, &out.AnalysisBaseline
+		*out = new(CanaryAnalysisBaseline)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataPlaneRolloutStatus.
+func (in *DataPlaneRolloutStatus) DeepCopy() *DataPlaneRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DataPlaneRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataPlaneRolloutStrategy) DeepCopyInto(out *DataPlaneRolloutStrategy) {
+	*out = *in
+	if in.BlueGreen != nil {
+		in, out := &in.BlueGreen, &out.BlueGreen
+		*out = new(BlueGreenStrategy)
+		**out = **in
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataPlaneRolloutStrategy.
+func (in *DataPlaneRolloutStrategy) DeepCopy() *DataPlaneRolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(DataPlaneRolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataPlaneSpec) DeepCopyInto(out *DataPlaneSpec) {
+	*out = *in
+	in.DataPlaneDeploymentOptions.DeepCopyInto(&out.DataPlaneDeploymentOptions)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataPlaneSpec.
+func (in *DataPlaneSpec) DeepCopy() *DataPlaneSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DataPlaneSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataPlaneStatus) DeepCopyInto(out *DataPlaneStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(DataPlaneRolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.License != nil {
+		in, out := &in.License, &out.License
+		*out = new(DataPlaneLicenseStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]DataPlanePodStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Deployment != nil {
+		in, out := &in.Deployment, &out.Deployment
+		*out = new(DeploymentStatus)
+		**out = **in
+	}
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CertificateExpiry != nil {
+		in, out := &in.CertificateExpiry, &out.CertificateExpiry
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataPlaneStatus.
+func (in *DataPlaneStatus) DeepCopy() *DataPlaneStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DataPlaneStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeploymentOptions) DeepCopyInto(out *DeploymentOptions) {
+	*out = *in
+	if in.ContainerImage != nil {
+		in, out := &in.ContainerImage, &out.ContainerImage
+		*out = new(string)
+		**out = **in
+	}
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		*out = new(string)
+		**out = **in
+	}
+	if in.SemanticVersion != nil {
+		in, out := &in.SemanticVersion, &out.SemanticVersion
+		*out = new(string)
+		**out = **in
+	}
+	if in.AutomaticUpgrades != nil {
+		in, out := &in.AutomaticUpgrades, &out.AutomaticUpgrades
+		*out = new(AutomaticUpgrades)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]corev1.EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentOptions.
+func (in *DeploymentOptions) DeepCopy() *DeploymentOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(DeploymentOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeploymentStatus) DeepCopyInto(out *DeploymentStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentStatus.
+func (in *DeploymentStatus) DeepCopy() *DeploymentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DeploymentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayBackup) DeepCopyInto(out *GatewayBackup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayBackup.
+func (in *GatewayBackup) DeepCopy() *GatewayBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GatewayBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayBackupList) DeepCopyInto(out *GatewayBackupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GatewayBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlane.
-func (in *ControlPlane) DeepCopy() *ControlPlane {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayBackupList.
+func (in *GatewayBackupList) DeepCopy() *GatewayBackupList {
 	if in == nil {
 		return nil
 	}
-	out := new(ControlPlane)
+	out := new(GatewayBackupList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ControlPlane) DeepCopyObject() runtime.Object {
+func (in *GatewayBackupList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -56,108 +855,104 @@ func (in *ControlPlane) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ControlPlaneDeploymentOptions) DeepCopyInto(out *ControlPlaneDeploymentOptions) {
+func (in *GatewayBackupSecretStorage) DeepCopyInto(out *GatewayBackupSecretStorage) {
 	*out = *in
-	in.DeploymentOptions.DeepCopyInto(&out.DeploymentOptions)
-	if in.DataPlane != nil {
-		in, out := &in.DataPlane, &out.DataPlane
-		*out = new(string)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayBackupSecretStorage.
+func (in *GatewayBackupSecretStorage) DeepCopy() *GatewayBackupSecretStorage {
+	if in == nil {
+		return nil
 	}
+	out := new(GatewayBackupSecretStorage)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneDeploymentOptions.
-func (in *ControlPlaneDeploymentOptions) DeepCopy() *ControlPlaneDeploymentOptions {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayBackupSpec) DeepCopyInto(out *GatewayBackupSpec) {
+	*out = *in
+	in.Storage.DeepCopyInto(&out.Storage)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayBackupSpec.
+func (in *GatewayBackupSpec) DeepCopy() *GatewayBackupSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ControlPlaneDeploymentOptions)
+	out := new(GatewayBackupSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ControlPlaneList) DeepCopyInto(out *ControlPlaneList) {
+func (in *GatewayBackupStatus) DeepCopyInto(out *GatewayBackupStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ControlPlane, len(*in))
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneList.
-func (in *ControlPlaneList) DeepCopy() *ControlPlaneList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayBackupStatus.
+func (in *GatewayBackupStatus) DeepCopy() *GatewayBackupStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ControlPlaneList)
+	out := new(GatewayBackupStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ControlPlaneList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ControlPlaneSpec) DeepCopyInto(out *ControlPlaneSpec) {
+func (in *GatewayBackupStorage) DeepCopyInto(out *GatewayBackupStorage) {
 	*out = *in
-	in.ControlPlaneDeploymentOptions.DeepCopyInto(&out.ControlPlaneDeploymentOptions)
-	if in.GatewayClass != nil {
-		in, out := &in.GatewayClass, &out.GatewayClass
-		*out = new(v1alpha2.ObjectName)
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(GatewayBackupSecretStorage)
 		**out = **in
 	}
-	if in.IngressClass != nil {
-		in, out := &in.IngressClass, &out.IngressClass
-		*out = new(string)
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(GatewayBackupVolumeStorage)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneSpec.
-func (in *ControlPlaneSpec) DeepCopy() *ControlPlaneSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayBackupStorage.
+func (in *GatewayBackupStorage) DeepCopy() *GatewayBackupStorage {
 	if in == nil {
 		return nil
 	}
-	out := new(ControlPlaneSpec)
+	out := new(GatewayBackupStorage)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ControlPlaneStatus) DeepCopyInto(out *ControlPlaneStatus) {
+func (in *GatewayBackupVolumeStorage) DeepCopyInto(out *GatewayBackupVolumeStorage) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneStatus.
-func (in *ControlPlaneStatus) DeepCopy() *ControlPlaneStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayBackupVolumeStorage.
+func (in *GatewayBackupVolumeStorage) DeepCopy() *GatewayBackupVolumeStorage {
 	if in == nil {
 		return nil
 	}
-	out := new(ControlPlaneStatus)
+	out := new(GatewayBackupVolumeStorage)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataPlane) DeepCopyInto(out *DataPlane) {
+func (in *GatewayConfiguration) DeepCopyInto(out *GatewayConfiguration) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -165,18 +960,18 @@ func (in *DataPlane) DeepCopyInto(out *DataPlane) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataPlane.
-func (in *DataPlane) DeepCopy() *DataPlane {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayConfiguration.
+func (in *GatewayConfiguration) DeepCopy() *GatewayConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(DataPlane)
+	out := new(GatewayConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DataPlane) DeepCopyObject() runtime.Object {
+func (in *GatewayConfiguration) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -184,47 +979,46 @@ func (in *DataPlane) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataPlaneDeploymentOptions) DeepCopyInto(out *DataPlaneDeploymentOptions) {
+func (in *GatewayConfigurationDownstreamStatus) DeepCopyInto(out *GatewayConfigurationDownstreamStatus) {
 	*out = *in
-	in.DeploymentOptions.DeepCopyInto(&out.DeploymentOptions)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataPlaneDeploymentOptions.
-func (in *DataPlaneDeploymentOptions) DeepCopy() *DataPlaneDeploymentOptions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayConfigurationDownstreamStatus.
+func (in *GatewayConfigurationDownstreamStatus) DeepCopy() *GatewayConfigurationDownstreamStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DataPlaneDeploymentOptions)
+	out := new(GatewayConfigurationDownstreamStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataPlaneList) DeepCopyInto(out *DataPlaneList) {
+func (in *GatewayConfigurationList) DeepCopyInto(out *GatewayConfigurationList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]DataPlane, len(*in))
+		*out = make([]GatewayConfiguration, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataPlaneList.
-func (in *DataPlaneList) DeepCopy() *DataPlaneList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayConfigurationList.
+func (in *GatewayConfigurationList) DeepCopy() *GatewayConfigurationList {
 	if in == nil {
 		return nil
 	}
-	out := new(DataPlaneList)
+	out := new(GatewayConfigurationList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DataPlaneList) DeepCopyObject() runtime.Object {
+func (in *GatewayConfigurationList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -232,23 +1026,57 @@ func (in *DataPlaneList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataPlaneSpec) DeepCopyInto(out *DataPlaneSpec) {
+func (in *GatewayConfigurationObjectReference) DeepCopyInto(out *GatewayConfigurationObjectReference) {
 	*out = *in
-	in.DataPlaneDeploymentOptions.DeepCopyInto(&out.DataPlaneDeploymentOptions)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataPlaneSpec.
-func (in *DataPlaneSpec) DeepCopy() *DataPlaneSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayConfigurationObjectReference.
+func (in *GatewayConfigurationObjectReference) DeepCopy() *GatewayConfigurationObjectReference {
 	if in == nil {
 		return nil
 	}
-	out := new(DataPlaneSpec)
+	out := new(GatewayConfigurationObjectReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataPlaneStatus) DeepCopyInto(out *DataPlaneStatus) {
+func (in *GatewayConfigurationSpec) DeepCopyInto(out *GatewayConfigurationSpec) {
+	*out = *in
+	if in.DataPlaneDeploymentOptions != nil {
+		in, out := &in.DataPlaneDeploymentOptions, &out.DataPlaneDeploymentOptions
+		*out = new(DataPlaneDeploymentOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ControlPlaneDeploymentOptions != nil {
+		in, out := &in.ControlPlaneDeploymentOptions, &out.ControlPlaneDeploymentOptions
+		*out = new(ControlPlaneDeploymentOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SharedDataPlane != nil {
+		in, out := &in.SharedDataPlane, &out.SharedDataPlane
+		*out = new(SharedDataPlaneOptions)
+		**out = **in
+	}
+	if in.TargetRef != nil {
+		in, out := &in.TargetRef, &out.TargetRef
+		*out = new(GatewayConfigurationTargetReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayConfigurationSpec.
+func (in *GatewayConfigurationSpec) DeepCopy() *GatewayConfigurationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayConfigurationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayConfigurationStatus) DeepCopyInto(out *GatewayConfigurationStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
@@ -257,59 +1085,55 @@ func (in *DataPlaneStatus) DeepCopyInto(out *DataPlaneStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.GatewayClasses != nil {
+		in, out := &in.GatewayClasses, &out.GatewayClasses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Gateways != nil {
+		in, out := &in.Gateways, &out.Gateways
+		*out = make([]GatewayConfigurationObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.DataPlanes != nil {
+		in, out := &in.DataPlanes, &out.DataPlanes
+		*out = make([]GatewayConfigurationDownstreamStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.ControlPlanes != nil {
+		in, out := &in.ControlPlanes, &out.ControlPlanes
+		*out = make([]GatewayConfigurationDownstreamStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataPlaneStatus.
-func (in *DataPlaneStatus) DeepCopy() *DataPlaneStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayConfigurationStatus.
+func (in *GatewayConfigurationStatus) DeepCopy() *GatewayConfigurationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DataPlaneStatus)
+	out := new(GatewayConfigurationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DeploymentOptions) DeepCopyInto(out *DeploymentOptions) {
+func (in *GatewayConfigurationTargetReference) DeepCopyInto(out *GatewayConfigurationTargetReference) {
 	*out = *in
-	if in.ContainerImage != nil {
-		in, out := &in.ContainerImage, &out.ContainerImage
-		*out = new(string)
-		**out = **in
-	}
-	if in.Version != nil {
-		in, out := &in.Version, &out.Version
-		*out = new(string)
-		**out = **in
-	}
-	if in.Env != nil {
-		in, out := &in.Env, &out.Env
-		*out = make([]corev1.EnvVar, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.EnvFrom != nil {
-		in, out := &in.EnvFrom, &out.EnvFrom
-		*out = make([]corev1.EnvFromSource, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentOptions.
-func (in *DeploymentOptions) DeepCopy() *DeploymentOptions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayConfigurationTargetReference.
+func (in *GatewayConfigurationTargetReference) DeepCopy() *GatewayConfigurationTargetReference {
 	if in == nil {
 		return nil
 	}
-	out := new(DeploymentOptions)
+	out := new(GatewayConfigurationTargetReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GatewayConfiguration) DeepCopyInto(out *GatewayConfiguration) {
+func (in *GatewayRestore) DeepCopyInto(out *GatewayRestore) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -317,18 +1141,18 @@ func (in *GatewayConfiguration) DeepCopyInto(out *GatewayConfiguration) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayConfiguration.
-func (in *GatewayConfiguration) DeepCopy() *GatewayConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayRestore.
+func (in *GatewayRestore) DeepCopy() *GatewayRestore {
 	if in == nil {
 		return nil
 	}
-	out := new(GatewayConfiguration)
+	out := new(GatewayRestore)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GatewayConfiguration) DeepCopyObject() runtime.Object {
+func (in *GatewayRestore) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -336,31 +1160,31 @@ func (in *GatewayConfiguration) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GatewayConfigurationList) DeepCopyInto(out *GatewayConfigurationList) {
+func (in *GatewayRestoreList) DeepCopyInto(out *GatewayRestoreList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]GatewayConfiguration, len(*in))
+		*out = make([]GatewayRestore, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayConfigurationList.
-func (in *GatewayConfigurationList) DeepCopy() *GatewayConfigurationList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayRestoreList.
+func (in *GatewayRestoreList) DeepCopy() *GatewayRestoreList {
 	if in == nil {
 		return nil
 	}
-	out := new(GatewayConfigurationList)
+	out := new(GatewayRestoreList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GatewayConfigurationList) DeepCopyObject() runtime.Object {
+func (in *GatewayRestoreList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -368,32 +1192,48 @@ func (in *GatewayConfigurationList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GatewayConfigurationSpec) DeepCopyInto(out *GatewayConfigurationSpec) {
+func (in *GatewayRestoreSource) DeepCopyInto(out *GatewayRestoreSource) {
 	*out = *in
-	if in.DataPlaneDeploymentOptions != nil {
-		in, out := &in.DataPlaneDeploymentOptions, &out.DataPlaneDeploymentOptions
-		*out = new(DataPlaneDeploymentOptions)
-		(*in).DeepCopyInto(*out)
+	if in.BackupName != nil {
+		in, out := &in.BackupName, &out.BackupName
+		*out = new(string)
+		**out = **in
 	}
-	if in.ControlPlaneDeploymentOptions != nil {
-		in, out := &in.ControlPlaneDeploymentOptions, &out.ControlPlaneDeploymentOptions
-		*out = new(ControlPlaneDeploymentOptions)
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(GatewayBackupStorage)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayConfigurationSpec.
-func (in *GatewayConfigurationSpec) DeepCopy() *GatewayConfigurationSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayRestoreSource.
+func (in *GatewayRestoreSource) DeepCopy() *GatewayRestoreSource {
 	if in == nil {
 		return nil
 	}
-	out := new(GatewayConfigurationSpec)
+	out := new(GatewayRestoreSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GatewayConfigurationStatus) DeepCopyInto(out *GatewayConfigurationStatus) {
+func (in *GatewayRestoreSpec) DeepCopyInto(out *GatewayRestoreSpec) {
+	*out = *in
+	in.Source.DeepCopyInto(&out.Source)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayRestoreSpec.
+func (in *GatewayRestoreSpec) DeepCopy() *GatewayRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayRestoreStatus) DeepCopyInto(out *GatewayRestoreStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
@@ -402,14 +1242,70 @@ func (in *GatewayConfigurationStatus) DeepCopyInto(out *GatewayConfigurationStat
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayConfigurationStatus.
-func (in *GatewayConfigurationStatus) DeepCopy() *GatewayConfigurationStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayRestoreStatus.
+func (in *GatewayRestoreStatus) DeepCopy() *GatewayRestoreStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(GatewayConfigurationStatus)
+	out := new(GatewayRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]Weekday, len(*in))
+		copy(*out, *in)
+	}
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresDatabase) DeepCopyInto(out *PostgresDatabase) {
+	*out = *in
+	out.ConnectionSecretRef = in.ConnectionSecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresDatabase.
+func (in *PostgresDatabase) DeepCopy() *PostgresDatabase {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresDatabase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SharedDataPlaneOptions) DeepCopyInto(out *SharedDataPlaneOptions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedDataPlaneOptions.
+func (in *SharedDataPlaneOptions) DeepCopy() *SharedDataPlaneOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(SharedDataPlaneOptions)
 	in.DeepCopyInto(out)
 	return out
 }
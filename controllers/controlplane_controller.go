@@ -2,7 +2,7 @@ package controllers
 
 import (
 	"context"
-	"errors"
+	"reflect"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -11,20 +11,24 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/source"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
-	operatorerrors "github.com/kong/gateway-operator/internal/errors"
+	"github.com/kong/gateway-operator/internal/consts"
+	controlplaneutils "github.com/kong/gateway-operator/internal/utils/controlplane"
 	gatewayutils "github.com/kong/gateway-operator/internal/utils/gateway"
+	"github.com/kong/gateway-operator/internal/utils/image"
 	k8sutils "github.com/kong/gateway-operator/internal/utils/kubernetes"
+	"github.com/kong/gateway-operator/internal/versions"
 )
 
 // -----------------------------------------------------------------------------
@@ -35,12 +39,31 @@ import (
 type ControlPlaneReconciler struct {
 	client.Client
 	Scheme                   *runtime.Scheme
+	eventRecorder            record.EventRecorder
 	ClusterCASecretName      string
 	ClusterCASecretNamespace string
+
+	// ReferenceGrantEnabled indicates whether the ReferenceGrant CRD is installed
+	// in the cluster and ReferenceGrants have to be watched.
+	ReferenceGrantEnabled bool
+
+	// VersionCatalog loads the version catalog used by AutomaticUpgrades.
+	// The embedded catalog is used when nil.
+	VersionCatalog *versions.CatalogLoader
+
+	// ImagePolicy is the operator-level policy of the container images: it
+	// rewrites the default images to a mirror registry and sets the
+	// imagePullSecrets of the generated resources.
+	ImagePolicy *image.Policy
+
+	// configurationPushCounts are the last configuration push counts observed
+	// for the ControlPlanes, reset when the operator restarts.
+	configurationPushCounts configurationPushCounts
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ControlPlaneReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.eventRecorder = mgr.GetEventRecorderFor("controlplane")
 
 	// for owned objects we need to check if updates to the objects resulted in the
 	// removal of an OwnerReference to the parent object, and if so we need to
@@ -54,7 +77,7 @@ func (r *ControlPlaneReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return r.clusterRoleBindingHasControlplaneOwner(e.ObjectOld)
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	blder := ctrl.NewControllerManagedBy(mgr).
 		// watch Controlplane objects
 		For(&operatorv1alpha1.ControlPlane{}).
 		// watch for changes in Secrets created by the controlplane controller
@@ -81,7 +104,21 @@ func (r *ControlPlaneReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Watches(
 			&source.Kind{Type: &operatorv1alpha1.DataPlane{}},
 			&handler.EnqueueRequestForOwner{OwnerType: &operatorv1alpha1.ControlPlane{}, IsController: true}).
-		Complete(r)
+		// watch for changes in DataPlanes referenced by controlplanes, either by name
+		// or by label selector, to keep the connection state of each of them up to date.
+		Watches(
+			&source.Kind{Type: &operatorv1alpha1.DataPlane{}},
+			handler.EnqueueRequestsFromMapFunc(r.getControlplanesForDataPlane))
+
+	if r.ReferenceGrantEnabled {
+		// watch for changes in ReferenceGrants that may permit or deny the
+		// references from ControlPlanes to DataPlanes in other namespaces.
+		blder.Watches(
+			&source.Kind{Type: &gatewayv1alpha2.ReferenceGrant{}},
+			handler.EnqueueRequestsFromMapFunc(r.getControlplanesForReferenceGrant))
+	}
+
+	return blder.Complete(r)
 }
 
 // Reconcile moves the current state of an object to the intended state.
@@ -92,6 +129,7 @@ func (r *ControlPlaneReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	controlplane := new(operatorv1alpha1.ControlPlane)
 	if err := r.Client.Get(ctx, req.NamespacedName, controlplane); err != nil {
 		if k8serrors.IsNotFound(err) {
+			r.configurationPushCounts.delete(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{}, err
@@ -99,6 +137,8 @@ func (r *ControlPlaneReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 	// controlplane is deleted, just run garbage collection for cluster wide resources.
 	if !controlplane.DeletionTimestamp.IsZero() {
+		r.configurationPushCounts.delete(req.NamespacedName)
+
 		// wait for termination grace period before cleaning up roles and bindings
 		if controlplane.DeletionTimestamp.After(metav1.Now().Time) {
 			debug(log, "control plane deletion still under grace period", controlplane)
@@ -138,6 +178,11 @@ func (r *ControlPlaneReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{}, nil
 	}
 
+	if restore, ok := controlplane.Annotations[consts.GatewayRestoreInProgressAnnotation]; ok {
+		debug(log, "ControlPlane is being restored by GatewayRestore "+restore+", nothing to do", controlplane)
+		return ctrl.Result{}, nil // requeue will be triggered by the removal of the annotation
+	}
+
 	// ensure the controlplane has a finalizer to delete owned cluster wide resources on delete.
 	finalizersChanged := k8sutils.EnsureFinalizersInMetadata(&controlplane.ObjectMeta,
 		string(ControlPlaneFinalizerCleanupClusterRole),
@@ -161,57 +206,42 @@ func (r *ControlPlaneReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{}, nil // no need to requeue, status update will requeue
 	}
 
-	debug(log, "retrieving connected dataplane", controlplane)
-	dataplane, err := gatewayutils.GetDataPlaneForControlPlane(ctx, r.Client, controlplane)
-	var dataplaneServiceName string
+	debug(log, "retrieving connected dataplanes", controlplane)
+	dataplaneNamespace := gatewayutils.GetDataPlaneNamespaceForControlPlane(controlplane)
+	dataplaneStatuses, dataplaneServiceNames, err := r.resolveDataPlanesConnectionStatus(ctx, controlplane)
 	if err != nil {
-		if !errors.Is(err, operatorerrors.ErrDataPlaneNotSet) {
-			return ctrl.Result{}, err
-		}
-		debug(log, "no existing dataplane for controlplane", controlplane, "error", err)
-	} else {
-		if err := controllerutil.SetOwnerReference(controlplane, dataplane, r.Scheme); err != nil {
-			return ctrl.Result{}, err
-		}
-		dataplaneServiceName, err = gatewayutils.GetDataplaneServiceName(ctx, r.Client, dataplane)
-		if err != nil {
-			debug(log, "no existing dataplane service for controlplane", controlplane, "error", err)
-			return ctrl.Result{}, err
-		}
+		return ctrl.Result{}, err
 	}
-
-	debug(log, "validating ControlPlane configuration", controlplane)
-	// TODO: add validating here: https://github.com/Kong/gateway-operator/issues/109
-
-	debug(log, "configuring ControlPlane resource", controlplane)
-	changed := setControlPlaneDefaults(&controlplane.Spec.ControlPlaneDeploymentOptions, controlplane.Namespace, dataplaneServiceName, nil)
-	if changed {
-		debug(log, "updating ControlPlane resource after defaults are set since resource has changed", controlplane)
-		err := r.Client.Update(ctx, controlplane)
-		if err != nil {
-			if k8serrors.IsConflict(err) {
-				debug(log, "conflict found when updating ControlPlane resource, retrying", controlplane)
-				return ctrl.Result{Requeue: true, RequeueAfter: requeueWithoutBackoff}, nil
-			}
+	dataplaneIsPermitted := true
+	for _, dataplaneStatus := range dataplaneStatuses {
+		if controlplane.Spec.DataPlane != nil && dataplaneStatus.Name == *controlplane.Spec.DataPlane &&
+			dataplaneStatus.State == operatorv1alpha1.ControlPlaneDataPlaneStateRefNotPermitted {
+			debug(log, "reference to dataplane is not permitted by any ReferenceGrant", controlplane)
+			dataplaneIsPermitted = false
 		}
-		return ctrl.Result{}, err // no need to requeue, the update will trigger.
 	}
 
-	debug(log, "validating that the ControlPlane's DataPlane configuration is up to date", controlplane)
-	if err = r.ensureDataPlaneConfiguration(ctx, controlplane, dataplaneServiceName); err != nil {
-		if k8serrors.IsConflict(err) {
-			debug(
-				log,
-				"conflict found when trying to ensure ControlPlane's DataPlane configuration was up to date, retrying",
-				controlplane,
-			)
-			return ctrl.Result{Requeue: true, RequeueAfter: requeueWithoutBackoff}, nil
-		}
+	debug(log, "resolving the ControlPlane version", controlplane)
+	updated, err := r.ensureControlPlaneVersionStatus(ctx, controlplane)
+	if err != nil {
 		return ctrl.Result{}, err
 	}
+	if updated {
+		return ctrl.Result{}, nil // no need to requeue, the status update will trigger.
+	}
+
+	debug(log, "setting the ControlPlane defaults", controlplane)
+	// the defaults are stored by the mutating admission webhook, they are only
+	// set in memory here for the ControlPlanes stored while it was not running,
+	// without updating the ControlPlane to avoid conflicting with its users.
+	// The environment related to the dataplanes is only set on the generated
+	// Deployment, taking all the dataplanes managed by the controlplane into
+	// account.
+	controlplaneutils.SetControlPlaneDefaults(&controlplane.Spec.ControlPlaneDeploymentOptions, dataplaneNamespace, "", nil)
 
 	debug(log, "validating ControlPlane's DataPlane status", controlplane)
-	dataplaneIsSet := r.ensureDataPlaneStatus(controlplane)
+	controlplane.Status.DataPlanes = dataplaneStatuses
+	dataplaneIsSet := r.ensureDataPlaneStatus(controlplane, dataplaneIsPermitted)
 	if dataplaneIsSet {
 		debug(log, "DataPlane was set, deployment for ControlPlane will be provisioned", controlplane)
 	} else {
@@ -254,11 +284,25 @@ func (r *ControlPlaneReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{}, nil // requeue will be triggered by the creation or update of the owned object
 	}
 
-	debug(log, "looking for existing Deployments for ControlPlane resource", controlplane)
-	createdOrUpdated, controlplaneDeployment, err := r.ensureDeploymentForControlPlane(ctx, controlplane, controlplaneServiceAccount.Name, certSecret.Name)
+	var (
+		controlplaneDeployment *appsv1.Deployment
+		rolloutStatus          *operatorv1alpha1.ControlPlaneRolloutStatus
+	)
+	if controlplaneUsesCanaryRollout(controlplane) {
+		debug(log, "rolling out deployments for ControlPlane resource using the canary strategy", controlplane)
+		createdOrUpdated, controlplaneDeployment, rolloutStatus, err = r.ensureCanaryDeploymentForControlPlane(ctx, controlplane, dataplaneIsSet,
+			dataplaneNamespace, dataplaneServiceNames, controlplaneServiceAccount.Name, certSecret.Name)
+	} else {
+		debug(log, "looking for existing Deployments for ControlPlane resource", controlplane)
+		createdOrUpdated, controlplaneDeployment, err = r.ensureDeploymentForControlPlane(ctx, controlplane, dataplaneIsSet,
+			dataplaneNamespace, dataplaneServiceNames, controlplaneServiceAccount.Name, certSecret.Name)
+	}
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+	if err := r.ensureControlPlaneRolloutStatus(ctx, controlplane, rolloutStatus); err != nil {
+		return ctrl.Result{}, err
+	}
 	if createdOrUpdated {
 		if !dataplaneIsSet {
 			debug(log, "DataPlane not set, deployment for ControlPlane has been scaled down to 0 replicas", controlplane)
@@ -273,6 +317,11 @@ func (r *ControlPlaneReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 	// TODO: updates need to update sub-resources https://github.com/Kong/gateway-operator/issues/27
 
+	debug(log, "reporting the state of the ControlPlane deployment", controlplane)
+	if err := r.ensureControlPlaneDeploymentStatus(ctx, controlplane, controlplaneDeployment, certSecret); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	debug(log, "checking readiness of ControlPlane deployments", controlplane)
 
 	if controlplaneDeployment.Status.Replicas == 0 || controlplaneDeployment.Status.AvailableReplicas < controlplaneDeployment.Status.Replicas {
@@ -280,6 +329,23 @@ func (r *ControlPlaneReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{}, nil // requeue will be triggered by the status update
 	}
 
+	var result ctrl.Result
+	if controlplane.Spec.AutomaticUpgrades != nil {
+		// neither new versions in the catalog nor maintenance windows trigger a requeue.
+		result.RequeueAfter = automaticUpgradesInterval
+	}
+	if result.RequeueAfter == 0 || controlplaneSyncCheckInterval < result.RequeueAfter {
+		// the metrics of the pods don't trigger a requeue.
+		result.RequeueAfter = controlplaneSyncCheckInterval
+	}
+	if rolloutStatus != nil &&
+		(rolloutStatus.Phase == operatorv1alpha1.ControlPlaneRolloutPhaseProgressing ||
+			rolloutStatus.Phase == operatorv1alpha1.ControlPlaneRolloutPhaseAwaitingPromotion) &&
+		canaryAnalysisInterval < result.RequeueAfter {
+		// the canary steps are timed and analyzed using metrics, none of which triggers a requeue.
+		result.RequeueAfter = canaryAnalysisInterval
+	}
+
 	r.ensureIsMarkedProvisioned(controlplane)
 	err = r.updateStatus(ctx, controlplane)
 
@@ -293,7 +359,7 @@ func (r *ControlPlaneReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	} else {
 		debug(log, "reconciliation complete for ControlPlane resource", controlplane)
 	}
-	return ctrl.Result{}, err
+	return result, err
 }
 
 // updateStatus Updates the resource status only when there are changes in the Conditions
@@ -304,7 +370,7 @@ func (r *ControlPlaneReconciler) updateStatus(ctx context.Context, updated *oper
 	if err != nil && !k8serrors.IsNotFound(err) {
 		return err
 	}
-	if k8sutils.NeedsUpdate(current, updated) {
+	if k8sutils.NeedsUpdate(current, updated) || !reflect.DeepEqual(current.Status.DataPlanes, updated.Status.DataPlanes) {
 		return r.Client.Status().Update(ctx, updated)
 	}
 	return nil
@@ -32,4 +32,14 @@ const (
 	// ControlPlaneConditionsReasonNoDataplane is a reason which indicates that no DataPlane
 	// has been provisioned.
 	ControlPlaneConditionReasonNoDataplane k8sutils.ConditionReason = "NoDataplane"
+
+	// ControlPlaneConditionReasonDataPlanePending is a reason which indicates
+	// that the DataPlane of a ControlPlane is set, but can't be configured yet
+	// as it isn't reachable through its Service.
+	ControlPlaneConditionReasonDataPlanePending k8sutils.ConditionReason = "DataPlanePending"
+
+	// ControlPlaneConditionReasonRefNotPermitted is a reason which indicates that the
+	// DataPlane referenced by a ControlPlane lives in another namespace and no
+	// ReferenceGrant permits that reference.
+	ControlPlaneConditionReasonRefNotPermitted k8sutils.ConditionReason = "RefNotPermitted"
 )
@@ -1,5 +1,10 @@
 package controllers
 
+import (
+	"net/http"
+	"time"
+)
+
 // -----------------------------------------------------------------------------
 // ControlPlane - Finalizers
 // -----------------------------------------------------------------------------
@@ -13,3 +18,16 @@ const (
 	// ControlPlaneFinalizerCleanupClusterRoleBinding is the finalizer to cleanup clusterrolebindings owned by controlplane on deleting.
 	ControlPlaneFinalizerCleanupClusterRoleBinding ControlPlaneFinalizer = "gateway-operator.konghq.com/cleanup-clusterrolebinding"
 )
+
+// -----------------------------------------------------------------------------
+// ControlPlane - Configuration Sync
+// -----------------------------------------------------------------------------
+
+// controlplaneSyncCheckInterval is the interval the metrics of the provisioned
+// ControlPlanes are read at, to report the synchronization of their Kong
+// configuration.
+const controlplaneSyncCheckInterval = 30 * time.Second
+
+// controlplaneMetricsHTTPClient is the HTTP client used to fetch the metrics
+// of the ControlPlane Pods.
+var controlplaneMetricsHTTPClient = &http.Client{Timeout: 5 * time.Second}
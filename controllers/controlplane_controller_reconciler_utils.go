@@ -2,8 +2,10 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 	appsv1 "k8s.io/api/apps/v1"
@@ -13,11 +15,16 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
 	"github.com/kong/gateway-operator/internal/consts"
+	operatorerrors "github.com/kong/gateway-operator/internal/errors"
+	controlplaneutils "github.com/kong/gateway-operator/internal/utils/controlplane"
+	gatewayutils "github.com/kong/gateway-operator/internal/utils/gateway"
 	k8sutils "github.com/kong/gateway-operator/internal/utils/kubernetes"
 	k8sresources "github.com/kong/gateway-operator/internal/utils/kubernetes/resources"
+	"github.com/kong/gateway-operator/internal/versions"
 )
 
 // numReplicasWhenNoDataplane represents the desired number of replicas
@@ -65,8 +72,15 @@ func (r *ControlPlaneReconciler) ensureIsMarkedProvisioned(
 // Information about the missing dataplane is stored in the controlplane status.
 func (r *ControlPlaneReconciler) ensureDataPlaneStatus(
 	controlplane *operatorv1alpha1.ControlPlane,
+	dataplaneIsPermitted bool,
 ) (dataplaneIsSet bool) {
-	dataplaneIsSet = controlplane.Spec.DataPlane != nil && *controlplane.Spec.DataPlane != ""
+	// only the dataplanes which can be reached through their Service can be configured,
+	// the state of the others is reported in the status of the controlplane.
+	for _, dataplaneStatus := range controlplane.Status.DataPlanes {
+		if dataplaneStatus.AdminURL != "" {
+			dataplaneIsSet = true
+		}
+	}
 	condition, present := k8sutils.GetCondition(ControlPlaneConditionTypeProvisioned, controlplane)
 
 	newCondition := k8sutils.NewCondition(
@@ -75,6 +89,24 @@ func (r *ControlPlaneReconciler) ensureDataPlaneStatus(
 		ControlPlaneConditionReasonNoDataplane,
 		"DataPlane is not set",
 	)
+	if controlplane.Spec.DataPlane != nil {
+		newCondition = k8sutils.NewCondition(
+			ControlPlaneConditionTypeProvisioned,
+			metav1.ConditionFalse,
+			ControlPlaneConditionReasonDataPlanePending,
+			fmt.Sprintf("DataPlane %s is set but is not reachable through its Service yet", *controlplane.Spec.DataPlane),
+		)
+	}
+	if !dataplaneIsPermitted && controlplane.Spec.DataPlane != nil {
+		dataplaneIsSet = false
+		newCondition = k8sutils.NewCondition(
+			ControlPlaneConditionTypeProvisioned,
+			metav1.ConditionFalse,
+			ControlPlaneConditionReasonRefNotPermitted,
+			fmt.Sprintf("reference to DataPlane %s/%s is not permitted by any ReferenceGrant",
+				gatewayutils.GetDataPlaneNamespaceForControlPlane(controlplane), *controlplane.Spec.DataPlane),
+		)
+	}
 	if dataplaneIsSet {
 		newCondition = k8sutils.NewCondition(
 			ControlPlaneConditionTypeProvisioned,
@@ -83,30 +115,182 @@ func (r *ControlPlaneReconciler) ensureDataPlaneStatus(
 			"DataPlane was set, ControlPlane resource is scheduled for provisioning",
 		)
 	}
-	if !present || condition.Status != newCondition.Status || condition.Reason != newCondition.Reason {
+	if !present || condition.Status != newCondition.Status || condition.Reason != newCondition.Reason || condition.Message != newCondition.Message ||
+		condition.ObservedGeneration != controlplane.Generation {
 		k8sutils.SetCondition(newCondition, controlplane)
 	}
 	return dataplaneIsSet
 }
 
-// -----------------------------------------------------------------------------
-// ControlPlaneReconciler - Spec Management
-// -----------------------------------------------------------------------------
+// ensureControlPlaneVersionStatus resolves the version chosen by the
+// ControlPlane's AutomaticUpgrades and records it in the ControlPlane status.
+// No new version is chosen while a rollout is in progress. It returns true when
+// the status was updated.
+func (r *ControlPlaneReconciler) ensureControlPlaneVersionStatus(
+	ctx context.Context,
+	controlplane *operatorv1alpha1.ControlPlane,
+) (bool, error) {
+	var version string
+	if controlplane.Spec.AutomaticUpgrades != nil {
+		if rollout := controlplane.Status.Rollout; controlplane.Status.Version != "" && rollout != nil && controlplaneRolloutIsInProgress(rollout.Phase) {
+			return false, nil
+		}
+		catalog, err := r.VersionCatalog.Load(ctx)
+		if err != nil {
+			return false, err
+		}
+		version, err = resolveAutomaticUpgradeVersion(&controlplane.Spec.DeploymentOptions,
+			catalog.KongIngressController, controlplane.Status.Version, consts.DefaultControlPlaneTag, time.Now())
+		if err != nil {
+			return false, err
+		}
+	}
 
-func (r *ControlPlaneReconciler) ensureDataPlaneConfiguration(
+	if controlplane.Status.Version == version {
+		return false, nil
+	}
+	controlplane.Status.Version = version
+	return true, r.Client.Status().Update(ctx, controlplane)
+}
+
+// ensureControlPlaneRolloutStatus records the state of the rollout of the
+// ControlPlane Deployment in its status.
+func (r *ControlPlaneReconciler) ensureControlPlaneRolloutStatus(
 	ctx context.Context,
 	controlplane *operatorv1alpha1.ControlPlane,
-	dataplaneServiceName string,
+	rolloutStatus *operatorv1alpha1.ControlPlaneRolloutStatus,
 ) error {
-	changed := setControlPlaneEnvOnDataPlaneChange(
-		&controlplane.Spec.ControlPlaneDeploymentOptions,
-		controlplane.Namespace,
-		dataplaneServiceName,
+	if reflect.DeepEqual(controlplane.Status.Rollout, rolloutStatus) {
+		return nil
+	}
+	controlplane.Status.Rollout = rolloutStatus
+	return r.Status().Update(ctx, controlplane)
+}
+
+// ensureControlPlaneDeploymentStatus records the state of the Deployment and
+// of the mTLS certificate of the ControlPlane in its status, so that they
+// don't have to be looked up, together with the synchronization of its Kong
+// configuration reported by its ready Pods.
+func (r *ControlPlaneReconciler) ensureControlPlaneDeploymentStatus(
+	ctx context.Context,
+	controlplane *operatorv1alpha1.ControlPlane,
+	deployment *appsv1.Deployment,
+	certSecret *corev1.Secret,
+) error {
+	certificateExpiry, err := certificateExpiry(certSecret, controlplane.Status.CertificateExpiry)
+	if err != nil {
+		return err
+	}
+
+	status := controlplane.Status.DeepCopy()
+	status.ObservedGeneration = controlplane.Generation
+	status.DataPlane = ""
+	if controlplane.Spec.DataPlane != nil {
+		status.DataPlane = *controlplane.Spec.DataPlane
+	}
+	status.Deployment = newDeploymentStatus(deployment, consts.ControlPlaneControllerContainerName, versions.KICVersionForControlPlane(controlplane))
+	status.CertificateExpiry = certificateExpiry
+
+	pods := &corev1.PodList{}
+	if err := r.Client.List(ctx, pods,
+		client.InNamespace(controlplane.Namespace),
+		client.MatchingLabels{"app": controlplane.Name},
+	); err != nil {
+		return err
+	}
+	var (
+		counts    controlplaneutils.ConfigurationPushCounts
+		readyPods int
+		fetchErr  error
 	)
-	if changed {
-		return r.Client.Update(ctx, controlplane)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !podIsReady(pod) || pod.Status.PodIP == "" {
+			continue
+		}
+		readyPods++
+		podCounts, err := fetchControlPlanePodConfigurationPushCounts(ctx, pod)
+		if err != nil {
+			fetchErr = fmt.Errorf("failed to read the metrics of Pod %s: %w", pod.Name, err)
+			break
+		}
+		counts.Add(podCounts)
 	}
-	return nil
+	switch {
+	case fetchErr != nil:
+		r.configurationPushCounts.delete(client.ObjectKeyFromObject(controlplane))
+		status.Sync = newControlPlaneSyncStatus(status.Sync, false, fetchErr.Error())
+	case readyPods > 0:
+		last := r.configurationPushCounts.swap(client.ObjectKeyFromObject(controlplane), counts)
+		status.Sync = newControlPlaneSyncStatus(status.Sync, controlplaneIsSynced(status.Sync, last, counts), "")
+	case controlplane.Spec.DataPlane == nil:
+		// the Deployment is scaled down when the DataPlane isn't set.
+		r.configurationPushCounts.delete(client.ObjectKeyFromObject(controlplane))
+		status.Sync = nil
+	}
+
+	if reflect.DeepEqual(&controlplane.Status, status) {
+		return nil
+	}
+	controlplane.Status = *status
+	return r.Client.Status().Update(ctx, controlplane)
+}
+
+// resolveDataPlanesConnectionStatus resolves all the dataplanes the controlplane
+// is responsible for and returns their connection state, to be recorded in the
+// controlplane status, together with the names of the Services of the dataplanes
+// which can be configured, starting with the one of the controlplane's DataPlane.
+func (r *ControlPlaneReconciler) resolveDataPlanesConnectionStatus(
+	ctx context.Context,
+	controlplane *operatorv1alpha1.ControlPlane,
+) ([]operatorv1alpha1.ControlPlaneDataPlaneStatus, []string, error) {
+	names, err := gatewayutils.ListDataPlaneNamesForControlPlane(ctx, r.Client, controlplane)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dataplaneNamespace := gatewayutils.GetDataPlaneNamespaceForControlPlane(controlplane)
+	dataplaneServiceNames := make([]string, 0, len(names))
+	dataplaneStatuses := make([]operatorv1alpha1.ControlPlaneDataPlaneStatus, 0, len(names))
+	for _, name := range names {
+		dataplaneStatus := operatorv1alpha1.ControlPlaneDataPlaneStatus{Name: name}
+
+		dataplane, err := gatewayutils.GetDataPlaneByNameForControlPlane(ctx, r.Client, controlplane, name)
+		if err != nil {
+			switch {
+			case errors.Is(err, operatorerrors.ErrReferenceNotPermitted):
+				dataplaneStatus.State = operatorv1alpha1.ControlPlaneDataPlaneStateRefNotPermitted
+				dataplaneStatus.Message = fmt.Sprintf("reference to DataPlane %s/%s is not permitted by any ReferenceGrant", dataplaneNamespace, name)
+			case k8serrors.IsNotFound(err):
+				dataplaneStatus.State = operatorv1alpha1.ControlPlaneDataPlaneStateNotFound
+				dataplaneStatus.Message = fmt.Sprintf("DataPlane %s/%s does not exist", dataplaneNamespace, name)
+			default:
+				return nil, nil, err
+			}
+			dataplaneStatuses = append(dataplaneStatuses, dataplaneStatus)
+			continue
+		}
+
+		serviceName, err := gatewayutils.GetDataplaneServiceName(ctx, r.Client, dataplane)
+		if err != nil {
+			dataplaneStatus.State = operatorv1alpha1.ControlPlaneDataPlaneStatePending
+			dataplaneStatus.Message = err.Error()
+			dataplaneStatuses = append(dataplaneStatuses, dataplaneStatus)
+			continue
+		}
+
+		dataplaneServiceNames = append(dataplaneServiceNames, serviceName)
+		dataplaneStatus.AdminURL = controlplaneutils.KongAdminURL(serviceName, dataplaneNamespace)
+		if k8sutils.IsReady(dataplane) {
+			dataplaneStatus.State = operatorv1alpha1.ControlPlaneDataPlaneStateConnected
+		} else {
+			dataplaneStatus.State = operatorv1alpha1.ControlPlaneDataPlaneStatePending
+			dataplaneStatus.Message = "DataPlane is not ready yet"
+		}
+		dataplaneStatuses = append(dataplaneStatuses, dataplaneStatus)
+	}
+
+	return dataplaneStatuses, dataplaneServiceNames, nil
 }
 
 // -----------------------------------------------------------------------------
@@ -115,14 +299,51 @@ func (r *ControlPlaneReconciler) ensureDataPlaneConfiguration(
 
 // ensureDeploymentForControlPlane ensures that a Deployment is created for the
 // ControlPlane resource. Deployment will remain in dormant state until
-// corresponding dataplane is set.
+// corresponding dataplane is set and the reference to it is permitted. The
+// Deployment is connected to the Services of the provided dataplanes.
 func (r *ControlPlaneReconciler) ensureDeploymentForControlPlane(
 	ctx context.Context,
 	controlplane *operatorv1alpha1.ControlPlane,
+	dataplaneIsSet bool,
+	dataplaneNamespace string,
+	dataplaneServiceNames []string,
 	serviceAccountName, certSecretName string,
 ) (bool, *appsv1.Deployment, error) {
-	dataplaneIsSet := controlplane.Spec.DataPlane != nil && *controlplane.Spec.DataPlane != ""
+	existingDeployment, generatedDeployment, err := r.getDeploymentsForControlPlane(ctx, controlplane,
+		dataplaneNamespace, dataplaneServiceNames, serviceAccountName, certSecretName)
+	if err != nil {
+		return false, nil, err
+	}
+
+	if existingDeployment != nil {
+		updated := updateControlPlaneDeployment(existingDeployment, generatedDeployment, dataplaneIsSet)
+		if updateControlPlanePodTemplate(&existingDeployment.Spec.Template, &generatedDeployment.Spec.Template) {
+			updated = true
+		}
+		if updated {
+			return true, existingDeployment, r.Client.Update(ctx, existingDeployment)
+		}
+
+		// the leftovers of a rollout are removed if the rollout strategy was unset
+		deleted, err := r.deleteCanaryDeploymentsForControlPlane(ctx, controlplane)
+		return deleted, existingDeployment, err
+	}
 
+	if !dataplaneIsSet {
+		generatedDeployment.Spec.Replicas = pointer.Int32(numReplicasWhenNoDataplane)
+	}
+	return true, generatedDeployment, r.Client.Create(ctx, generatedDeployment)
+}
+
+// getDeploymentsForControlPlane returns the existing Deployment of the
+// ControlPlane, if any, along with the Deployment generated from its spec.
+func (r *ControlPlaneReconciler) getDeploymentsForControlPlane(
+	ctx context.Context,
+	controlplane *operatorv1alpha1.ControlPlane,
+	dataplaneNamespace string,
+	dataplaneServiceNames []string,
+	serviceAccountName, certSecretName string,
+) (existing *appsv1.Deployment, generated *appsv1.Deployment, err error) {
 	deployments, err := k8sutils.ListDeploymentsForOwner(ctx,
 		r.Client,
 		consts.GatewayOperatorControlledLabel,
@@ -131,75 +352,408 @@ func (r *ControlPlaneReconciler) ensureDeploymentForControlPlane(
 		controlplane.UID,
 	)
 	if err != nil {
-		return false, nil, err
+		return nil, nil, err
 	}
 
 	count := len(deployments)
 	if count > 1 {
-		return false, nil, fmt.Errorf("found %d deployments for ControlPlane currently unsupported: expected 1 or less", count)
+		return nil, nil, fmt.Errorf("found %d deployments for ControlPlane currently unsupported: expected 1 or less", count)
 	}
 
-	generatedDeployment := generateNewDeploymentForControlPlane(controlplane, serviceAccountName, certSecretName)
+	generatedDeployment := generateNewDeploymentForControlPlane(controlplane, dataplaneNamespace, dataplaneServiceNames,
+		serviceAccountName, certSecretName, r.ImagePolicy)
 	k8sutils.SetOwnerForObject(generatedDeployment, controlplane)
 	addLabelForControlPlane(generatedDeployment)
 
 	if count == 1 {
-		var updated bool
-		existingDeployment := &deployments[0]
-		updated, existingDeployment.ObjectMeta = k8sutils.EnsureObjectMetaIsUpdated(existingDeployment.ObjectMeta, generatedDeployment.ObjectMeta)
-		container := k8sresources.GetPodContainerByName(&existingDeployment.Spec.Template.Spec, consts.ControlPlaneControllerContainerName)
-		if container == nil {
-			// someone has deleted the main container from the Deployment for ??? reasons. we can't fathom why they
-			// would do this, but don't allow it and replace the container set entirely
-			existingDeployment.Spec.Template.Spec.Containers = generatedDeployment.Spec.Template.Spec.Containers
-			updated = true
-			container = k8sresources.GetPodContainerByName(&existingDeployment.Spec.Template.Spec, consts.ControlPlaneControllerContainerName)
-		}
+		return &deployments[0], generatedDeployment, nil
+	}
+	return nil, generatedDeployment, nil
+}
 
-		replicas := existingDeployment.Spec.Replicas
-		switch {
+// updateControlPlaneDeployment updates the metadata of the existing Deployment
+// of a ControlPlane, and scales it depending on whether the dataplane is set.
+// Such changes are always applied in place. It returns whether the Deployment
+// was updated.
+func updateControlPlaneDeployment(existing, generated *appsv1.Deployment, dataplaneIsSet bool) bool {
+	var updated bool
+	updated, existing.ObjectMeta = k8sutils.EnsureObjectMetaIsUpdated(existing.ObjectMeta, generated.ObjectMeta)
+
+	replicas := existing.Spec.Replicas
+	switch {
+
+	// Dataplane was just unset, so we need to scale down the Deployment.
+	case !dataplaneIsSet && (replicas == nil || *replicas != numReplicasWhenNoDataplane):
+		existing.Spec.Replicas = pointer.Int32(numReplicasWhenNoDataplane)
+		updated = true
+
+	// Dataplane was just set, so we need to scale up the Deployment.
+	case dataplaneIsSet && (replicas != nil && *replicas == numReplicasWhenNoDataplane):
+		existing.Spec.Replicas = nil
+		updated = true
+	}
 
-		// Dataplane was just unset, so we need to scale down the Deployment.
-		case !dataplaneIsSet && (replicas == nil || *replicas != numReplicasWhenNoDataplane):
-			existingDeployment.Spec.Replicas = pointer.Int32(numReplicasWhenNoDataplane)
-			updated = true
+	return updated
+}
+
+// updateControlPlanePodTemplate updates the Pod template of the existing
+// Deployment of a ControlPlane to match the generated one. It returns whether
+// the Pod template was updated.
+func updateControlPlanePodTemplate(existing, generated *corev1.PodTemplateSpec) bool {
+	var updated bool
+	container := k8sresources.GetPodContainerByName(&existing.Spec, consts.ControlPlaneControllerContainerName)
+	if container == nil {
+		// someone has deleted the main container from the Deployment for ??? reasons. we can't fathom why they
+		// would do this, but don't allow it and replace the container set entirely
+		existing.Spec.Containers = generated.Spec.Containers
+		updated = true
+		container = k8sresources.GetPodContainerByName(&existing.Spec, consts.ControlPlaneControllerContainerName)
+	}
+	generatedContainer := k8sresources.GetPodContainerByName(&generated.Spec, consts.ControlPlaneControllerContainerName)
+
+	// We do not want to permit direct edits of the Deployment environment. Any user-supplied values should be set
+	// in the ControlPlane. If the actual Deployment environment does not match the generated environment, either
+	// something requires an update (e.g. the associated DataPlane Service changed and value generation changed the
+	// publish service configuration) or there was a manual edit we want to purge.
+	if !reflect.DeepEqual(container.Env, generatedContainer.Env) {
+		container.Env = generatedContainer.Env
+		updated = true
+	}
+
+	if !reflect.DeepEqual(container.EnvFrom, generatedContainer.EnvFrom) {
+		container.EnvFrom = generatedContainer.EnvFrom
+		updated = true
+	}
+
+	// the image changes when the version is upgraded by AutomaticUpgrades.
+	if container.Image != generatedContainer.Image {
+		container.Image = generatedContainer.Image
+		updated = true
+	}
+
+	if !reflect.DeepEqual(existing.Spec.ImagePullSecrets, generated.Spec.ImagePullSecrets) {
+		existing.Spec.ImagePullSecrets = generated.Spec.ImagePullSecrets
+		updated = true
+	}
+
+	return updated
+}
+
+// -----------------------------------------------------------------------------
+// ControlPlaneReconciler - Canary Rollout
+// -----------------------------------------------------------------------------
+
+// ensureCanaryDeploymentForControlPlane rolls out the ControlPlane spec using
+// the canary strategy. Changes of the Pod template aren't applied to the live
+// Deployment: a canary Deployment is created instead, which Pods push the Kong
+// configuration to the DataPlanes along with the live ones. The number of
+// canary Pods is increased following the steps of the strategy as long as the
+// canary Pods are ready and pass the analysis of their configuration pushes,
+// and the changes are applied to the live Deployment once the last step is
+// passed. Otherwise, the canary Deployment is deleted. The live Deployment is
+// returned along with the status of the rollout.
+func (r *ControlPlaneReconciler) ensureCanaryDeploymentForControlPlane(
+	ctx context.Context,
+	controlplane *operatorv1alpha1.ControlPlane,
+	dataplaneIsSet bool,
+	dataplaneNamespace string,
+	dataplaneServiceNames []string,
+	serviceAccountName, certSecretName string,
+) (createdOrUpdated bool, deploy *appsv1.Deployment, rolloutStatus *operatorv1alpha1.ControlPlaneRolloutStatus, err error) {
+	canary := controlplane.Spec.Rollout.Strategy.Canary
+	previousStatus := controlplane.Status.Rollout
+	completeStatus := &operatorv1alpha1.ControlPlaneRolloutStatus{Phase: operatorv1alpha1.ControlPlaneRolloutPhaseComplete}
+
+	liveDeployment, generatedDeployment, err := r.getDeploymentsForControlPlane(ctx, controlplane,
+		dataplaneNamespace, dataplaneServiceNames, serviceAccountName, certSecretName)
+	if err != nil {
+		return false, nil, nil, err
+	}
+	if liveDeployment == nil || !dataplaneIsSet {
+		// there's nothing to roll out to a new or dormant Deployment.
+		createdOrUpdated, deploy, err := r.ensureDeploymentForControlPlane(ctx, controlplane, dataplaneIsSet,
+			dataplaneNamespace, dataplaneServiceNames, serviceAccountName, certSecretName)
+		return createdOrUpdated, deploy, completeStatus, err
+	}
+
+	if updateControlPlaneDeployment(liveDeployment, generatedDeployment, dataplaneIsSet) {
+		return true, liveDeployment, previousStatus, r.Client.Update(ctx, liveDeployment)
+	}
+
+	template := liveDeployment.Spec.Template.DeepCopy()
+	if !updateControlPlanePodTemplate(template, &generatedDeployment.Spec.Template) {
+		deleted, err := r.deleteCanaryDeploymentsForControlPlane(ctx, controlplane)
+		return deleted, liveDeployment, completeStatus, err
+	}
+	hash, err := computePodTemplateHash(template)
+	if err != nil {
+		return false, nil, nil, err
+	}
+
+	if previousStatus != nil && previousStatus.RolledBackRevision == hash {
+		deleted, err := r.deleteCanaryDeploymentsForControlPlane(ctx, controlplane)
+		return deleted, liveDeployment, previousStatus, err
+	}
+
+	liveReplicas := int32(1)
+	if liveDeployment.Spec.Replicas != nil {
+		liveReplicas = *liveDeployment.Spec.Replicas
+	}
+
+	now := metav1.Now()
+	rolloutStatus = &operatorv1alpha1.ControlPlaneRolloutStatus{
+		Phase:         operatorv1alpha1.ControlPlaneRolloutPhaseProgressing,
+		StepStartTime: &now,
+	}
+
+	generatedCanary := generateCanaryDeploymentForControlPlane(controlplane, template, hash)
+	generatedCanary.Spec.Replicas = pointer.Int32(canaryReplicas(liveReplicas, canary.Steps[0].Weight))
+	created, canaryDeployment, err := r.ensureCanaryDeploymentIsCreated(ctx, controlplane, generatedCanary, hash)
+	if err != nil || canaryDeployment == nil {
+		return created, liveDeployment, rolloutStatus, err
+	}
+	rolloutStatus.CanaryDeployment = canaryDeployment.Name
+	if created {
+		return true, liveDeployment, rolloutStatus, nil
+	}
+
+	if previousStatus != nil && previousStatus.CanaryDeployment == canaryDeployment.Name && previousStatus.StepStartTime != nil {
+		rolloutStatus.Step = previousStatus.Step
+		rolloutStatus.StepStartTime = previousStatus.StepStartTime
+		rolloutStatus.AnalysisBaseline = previousStatus.AnalysisBaseline
+	}
+	if int(rolloutStatus.Step) >= len(canary.Steps) {
+		// the steps were changed during the rollout
+		rolloutStatus.Step = int32(len(canary.Steps) - 1)
+	}
+	step := canary.Steps[rolloutStatus.Step]
+
+	replicas := canaryReplicas(liveReplicas, step.Weight)
+	if canaryDeployment.Spec.Replicas == nil || *canaryDeployment.Spec.Replicas != replicas {
+		canaryDeployment.Spec.Replicas = &replicas
+		return true, liveDeployment, rolloutStatus, r.Client.Update(ctx, canaryDeployment)
+	}
 
-		// Dataplane was just set, so we need to scale up the Deployment
-		// and ensure the env variables that might have been changed in
-		// deployment are updated.
-		case dataplaneIsSet && (replicas != nil && *replicas == numReplicasWhenNoDataplane):
-			existingDeployment.Spec.Replicas = nil
-			if len(container.Env) > 0 {
-				container.Env = controlplane.Spec.Env
+	deadline := canaryProgressDeadline(canary)
+	deadlineExceeded := time.Since(rolloutStatus.StepStartTime.Time) > deadline
+
+	if !deploymentIsReady(canaryDeployment) {
+		if deadlineExceeded {
+			return r.rollbackCanaryForControlPlane(ctx, controlplane, liveDeployment, hash,
+				fmt.Sprintf("canary pods were not ready within %s", deadline))
+		}
+		rolloutStatus.Message = "waiting for the canary pods to be ready"
+		return false, liveDeployment, rolloutStatus, nil
+	}
+
+	if canary.Analysis != nil {
+		counts, err := r.getConfigurationPushCountsForDeployment(ctx, canaryDeployment)
+		if err != nil {
+			if deadlineExceeded {
+				return r.rollbackCanaryForControlPlane(ctx, controlplane, liveDeployment, hash,
+					fmt.Sprintf("canary pods could not be analyzed within %s: %v", deadline, err))
 			}
-			updated = true
+			rolloutStatus.Message = fmt.Sprintf("unable to analyze the canary pods: %v", err)
+			return false, liveDeployment, rolloutStatus, nil
+		}
+		// the counters of the Pods only grow, so the analysis of a step only
+		// accounts for the configuration pushes made since the step started.
+		current := operatorv1alpha1.CanaryAnalysisBaseline{Total: counts.Successful + counts.Failed, Errors: counts.Failed}
+		errorRate, analyzed, ok := canaryAnalysisErrorRate(rolloutStatus.AnalysisBaseline, current)
+		if !ok {
+			rolloutStatus.AnalysisBaseline = &current
+			rolloutStatus.Message = "collecting the metrics of the canary pods"
+			return false, liveDeployment, rolloutStatus, nil
 		}
+		if analyzed == 0 {
+			if deadlineExceeded {
+				return r.rollbackCanaryForControlPlane(ctx, controlplane, liveDeployment, hash,
+					fmt.Sprintf("canary pods made no configuration pushes within %s", deadline))
+			}
+			rolloutStatus.Message = "waiting for the canary pods to push configuration"
+			return false, liveDeployment, rolloutStatus, nil
+		}
+		if errorRate > float64(canary.Analysis.MaxErrorRate) {
+			return r.rollbackCanaryForControlPlane(ctx, controlplane, liveDeployment, hash,
+				fmt.Sprintf("canary pods failed configuration pushes rate %.2f%% exceeds %d%%", errorRate, canary.Analysis.MaxErrorRate))
+		}
+	}
 
-		// We do not want to permit direct edits of the Deployment environment. Any user-supplied values should be set
-		// in the ControlPlane. If the actual Deployment environment does not match the generated environment, either
-		// something requires an update (e.g. the associated DataPlane Service changed and value generation changed the
-		// publish service configuration) or there was a manual edit we want to purge.
-		if !reflect.DeepEqual(container.Env, controlplane.Spec.Env) {
-			container.Env = controlplane.Spec.Env
-			updated = true
+	if step.Pause == nil {
+		if controlplane.Annotations[consts.ControlPlanePromoteCanaryAnnotation] != "true" {
+			rolloutStatus.Phase = operatorv1alpha1.ControlPlaneRolloutPhaseAwaitingPromotion
+			return false, liveDeployment, rolloutStatus, nil
 		}
+	} else if time.Since(rolloutStatus.StepStartTime.Time) < step.Pause.Duration {
+		return false, liveDeployment, rolloutStatus, nil
+	}
 
-		if !reflect.DeepEqual(container.EnvFrom, controlplane.Spec.EnvFrom) {
-			container.EnvFrom = controlplane.Spec.EnvFrom
-			updated = true
+	if int(rolloutStatus.Step) < len(canary.Steps)-1 {
+		rolloutStatus.Step++
+		rolloutStatus.StepStartTime = &now
+		rolloutStatus.AnalysisBaseline = nil
+		return false, liveDeployment, rolloutStatus, r.ensurePromoteAnnotationIsRemoved(ctx, controlplane)
+	}
+
+	// the changes are promoted to the live Deployment, which Pods are rolled.
+	liveDeployment.Spec.Template = *template
+	if err := r.Client.Update(ctx, liveDeployment); err != nil {
+		return false, liveDeployment, rolloutStatus, err
+	}
+	if _, err := r.deleteCanaryDeploymentsForControlPlane(ctx, controlplane); err != nil {
+		return false, liveDeployment, rolloutStatus, err
+	}
+	return true, liveDeployment, completeStatus, r.ensurePromoteAnnotationIsRemoved(ctx, controlplane)
+}
+
+// ensureCanaryDeploymentIsCreated ensures that the canary Deployment of the
+// ControlPlane runs the changes of the provided Pod template hash. As the
+// selector of a Deployment can't be changed, an outdated canary Deployment is
+// deleted rather than updated.
+func (r *ControlPlaneReconciler) ensureCanaryDeploymentIsCreated(
+	ctx context.Context,
+	controlplane *operatorv1alpha1.ControlPlane,
+	generatedDeployment *appsv1.Deployment,
+	hash string,
+) (createdOrDeleted bool, deploy *appsv1.Deployment, err error) {
+	deployments, err := k8sutils.ListDeploymentsForOwner(
+		ctx,
+		r.Client,
+		consts.ControlPlaneRolloutStateLabel,
+		consts.ControlPlaneRolloutStateCanaryLabelValue,
+		controlplane.Namespace,
+		controlplane.UID,
+	)
+	if err != nil {
+		return false, nil, err
+	}
+
+	var canaryDeployment *appsv1.Deployment
+	for i := range deployments {
+		if deployments[i].Spec.Selector.MatchLabels[consts.ControlPlanePodTemplateHashLabel] == hash && canaryDeployment == nil {
+			canaryDeployment = &deployments[i]
+			continue
+		}
+		if err := r.Client.Delete(ctx, &deployments[i]); client.IgnoreNotFound(err) != nil {
+			return false, nil, err
 		}
+		createdOrDeleted = true
+	}
+	if canaryDeployment != nil || createdOrDeleted {
+		return createdOrDeleted, canaryDeployment, nil
+	}
 
-		if updated {
-			return true, existingDeployment, r.Client.Update(ctx, existingDeployment)
+	return true, generatedDeployment, r.Client.Create(ctx, generatedDeployment)
+}
+
+// rollbackCanaryForControlPlane deletes the canary Deployment of the
+// ControlPlane. The rolled back changes are recorded in the rollout status so
+// that they're not rolled out again until the ControlPlane changes.
+func (r *ControlPlaneReconciler) rollbackCanaryForControlPlane(
+	ctx context.Context,
+	controlplane *operatorv1alpha1.ControlPlane,
+	liveDeployment *appsv1.Deployment,
+	hash string,
+	reason string,
+) (bool, *appsv1.Deployment, *operatorv1alpha1.ControlPlaneRolloutStatus, error) {
+	r.eventRecorder.Event(controlplane, "Warning", "RolledBack", reason)
+	_, err := r.deleteCanaryDeploymentsForControlPlane(ctx, controlplane)
+	return true, liveDeployment, &operatorv1alpha1.ControlPlaneRolloutStatus{
+		Phase:              operatorv1alpha1.ControlPlaneRolloutPhaseRolledBack,
+		RolledBackRevision: hash,
+		Message:            reason,
+	}, err
+}
+
+// deleteCanaryDeploymentsForControlPlane deletes the canary Deployments of the
+// ControlPlane.
+func (r *ControlPlaneReconciler) deleteCanaryDeploymentsForControlPlane(
+	ctx context.Context,
+	controlplane *operatorv1alpha1.ControlPlane,
+) (deleted bool, err error) {
+	deployments, err := k8sutils.ListDeploymentsForOwner(
+		ctx,
+		r.Client,
+		consts.ControlPlaneRolloutStateLabel,
+		consts.ControlPlaneRolloutStateCanaryLabelValue,
+		controlplane.Namespace,
+		controlplane.UID,
+	)
+	if err != nil {
+		return false, err
+	}
+	for i := range deployments {
+		if err := r.Client.Delete(ctx, &deployments[i]); client.IgnoreNotFound(err) != nil {
+			return deleted, err
+		}
+		deleted = true
+	}
+	return deleted, nil
+}
+
+// ensurePromoteAnnotationIsRemoved removes the annotation used to progress a
+// canary rollout from the ControlPlane: a manual promotion only applies to the
+// canary step it was given for. Only the metadata of a fresh copy of the
+// ControlPlane is patched, as the in-memory one holds its defaults.
+func (r *ControlPlaneReconciler) ensurePromoteAnnotationIsRemoved(
+	ctx context.Context,
+	controlplane *operatorv1alpha1.ControlPlane,
+) error {
+	if _, ok := controlplane.Annotations[consts.ControlPlanePromoteCanaryAnnotation]; !ok {
+		return nil
+	}
+
+	current := &operatorv1alpha1.ControlPlane{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(controlplane), current); err != nil {
+		return err
+	}
+	if _, ok := current.Annotations[consts.ControlPlanePromoteCanaryAnnotation]; ok {
+		patched := current.DeepCopy()
+		delete(patched.Annotations, consts.ControlPlanePromoteCanaryAnnotation)
+		if err := r.Client.Patch(ctx, patched, client.MergeFrom(current)); err != nil {
+			return err
 		}
+	}
+	delete(controlplane.Annotations, consts.ControlPlanePromoteCanaryAnnotation)
+	return nil
+}
 
-		return false, existingDeployment, nil
+// getConfigurationPushCountsForDeployment returns the number of Kong
+// configuration pushes made by the ready Pods of a ControlPlane Deployment.
+func (r *ControlPlaneReconciler) getConfigurationPushCountsForDeployment(
+	ctx context.Context,
+	deployment *appsv1.Deployment,
+) (controlplaneutils.ConfigurationPushCounts, error) {
+	var counts controlplaneutils.ConfigurationPushCounts
+
+	pods := &corev1.PodList{}
+	if err := r.Client.List(
+		ctx,
+		pods,
+		client.InNamespace(deployment.Namespace),
+		client.MatchingLabels(deployment.Spec.Selector.MatchLabels),
+	); err != nil {
+		return counts, err
 	}
 
-	if !dataplaneIsSet {
-		generatedDeployment.Spec.Replicas = pointer.Int32(numReplicasWhenNoDataplane)
+	ready := 0
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !podIsReady(pod) || pod.Status.PodIP == "" {
+			continue
+		}
+		podCounts, err := fetchControlPlanePodConfigurationPushCounts(ctx, pod)
+		if err != nil {
+			return counts, fmt.Errorf("failed to read the metrics of Pod %s: %w", pod.Name, err)
+		}
+		counts.Add(podCounts)
+		ready++
 	}
-	return true, generatedDeployment, r.Client.Create(ctx, generatedDeployment)
+	if ready == 0 {
+		return counts, fmt.Errorf("no ready pods found for deployment %s", deployment.Name)
+	}
+
+	return counts, nil
 }
 
 func (r *ControlPlaneReconciler) ensureServiceAccountForControlPlane(
@@ -217,6 +771,7 @@ func (r *ControlPlaneReconciler) ensureServiceAccountForControlPlane(
 	}
 
 	generatedServiceAccount := k8sresources.GenerateNewServiceAccountForControlPlane(controlplane.Namespace, controlplane.Name)
+	generatedServiceAccount.ImagePullSecrets = r.ImagePolicy.LocalObjectReferences()
 	k8sutils.SetOwnerForObject(generatedServiceAccount, controlplane)
 	addLabelForControlPlane(generatedServiceAccount)
 
@@ -224,6 +779,10 @@ func (r *ControlPlaneReconciler) ensureServiceAccountForControlPlane(
 		var updated bool
 		existingServiceAccount := &serviceAccounts[0]
 		updated, existingServiceAccount.ObjectMeta = k8sutils.EnsureObjectMetaIsUpdated(existingServiceAccount.ObjectMeta, generatedServiceAccount.ObjectMeta)
+		if !reflect.DeepEqual(existingServiceAccount.ImagePullSecrets, generatedServiceAccount.ImagePullSecrets) {
+			existingServiceAccount.ImagePullSecrets = generatedServiceAccount.ImagePullSecrets
+			updated = true
+		}
 		if updated {
 			return true, existingServiceAccount, r.Client.Update(ctx, existingServiceAccount)
 		}
@@ -247,7 +806,11 @@ func (r *ControlPlaneReconciler) ensureClusterRoleForControlPlane(
 		return false, nil, fmt.Errorf("found %d deployments for ControlPlane currently unsupported: expected 1 or less", count)
 	}
 
-	generatedClusterRole, err := k8sresources.GenerateNewClusterRoleForControlPlane(controlplane.Name, controlplane.Spec.ContainerImage)
+	version, err := controlplaneRBACVersion(controlplane)
+	if err != nil {
+		return false, nil, err
+	}
+	generatedClusterRole, err := k8sresources.GenerateNewClusterRoleForControlPlane(controlplane.Name, version)
 	if err != nil {
 		return false, nil, err
 	}
@@ -258,6 +821,11 @@ func (r *ControlPlaneReconciler) ensureClusterRoleForControlPlane(
 		var updated bool
 		existingClusterRole := &clusterRoles[0]
 		updated, existingClusterRole.ObjectMeta = k8sutils.EnsureObjectMetaIsUpdated(existingClusterRole.ObjectMeta, generatedClusterRole.ObjectMeta)
+		// the rules depend on the version of the ControlPlane, which may have been upgraded.
+		if !reflect.DeepEqual(existingClusterRole.Rules, generatedClusterRole.Rules) {
+			existingClusterRole.Rules = generatedClusterRole.Rules
+			updated = true
+		}
 		if updated {
 			return true, existingClusterRole, r.Client.Update(ctx, existingClusterRole)
 		}
@@ -0,0 +1,489 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	"github.com/kong/gateway-operator/internal/consts"
+	controlplaneutils "github.com/kong/gateway-operator/internal/utils/controlplane"
+	k8sutils "github.com/kong/gateway-operator/internal/utils/kubernetes"
+)
+
+func TestEnsureControlPlaneDeploymentStatus(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, operatorv1alpha1.AddToScheme(scheme))
+
+	var (
+		pushCounts controlplaneutils.ConfigurationPushCounts
+		fetchErr   error
+	)
+	fetchCounts := fetchControlPlanePodConfigurationPushCounts
+	fetchControlPlanePodConfigurationPushCounts = func(context.Context, *corev1.Pod) (controlplaneutils.ConfigurationPushCounts, error) {
+		return pushCounts, fetchErr
+	}
+	defer func() { fetchControlPlanePodConfigurationPushCounts = fetchCounts }()
+
+	controlplane := &operatorv1alpha1.ControlPlane{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kic", UID: "controlplane-uid", Generation: 3},
+		Spec: operatorv1alpha1.ControlPlaneSpec{
+			ControlPlaneDeploymentOptions: operatorv1alpha1.ControlPlaneDeploymentOptions{
+				DeploymentOptions: operatorv1alpha1.DeploymentOptions{Version: pointer.String("2.6.0")},
+				DataPlane:         pointer.String("kong"),
+			},
+		},
+	}
+	deployment := generateNewDeploymentForControlPlane(controlplane, "", nil, "sa", "cert", nil)
+	deployment.Name = "controlplane-kic-abcde"
+	deployment.Status = appsv1.DeploymentStatus{Replicas: 1, ReadyReplicas: 1}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kic-1", Labels: map[string]string{"app": controlplane.Name}},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			PodIP:      "10.0.0.2",
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	certSecret := newTestCASecret(t)
+
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(controlplane, pod).Build()
+	r := &ControlPlaneReconciler{Client: c}
+
+	t.Log("reporting the state of the deployment, certificate and configuration sync")
+	pushCounts = controlplaneutils.ConfigurationPushCounts{Successful: 3}
+	require.NoError(t, r.ensureControlPlaneDeploymentStatus(ctx, controlplane, deployment, certSecret))
+	require.Equal(t, int64(3), controlplane.Status.ObservedGeneration)
+	require.Equal(t, "kong", controlplane.Status.DataPlane)
+	require.Equal(t, &operatorv1alpha1.DeploymentStatus{
+		Name:          "controlplane-kic-abcde",
+		Replicas:      1,
+		ReadyReplicas: 1,
+		Image:         consts.DefaultControlPlaneBaseImage + ":2.6.0",
+		Version:       "2.6.0",
+	}, controlplane.Status.Deployment)
+	require.NotNil(t, controlplane.Status.CertificateExpiry)
+	require.True(t, controlplane.Status.Sync.Synced)
+	require.NotNil(t, controlplane.Status.Sync.LastTransitionTime)
+
+	t.Log("reporting the failure of the last configuration push")
+	pushCounts = controlplaneutils.ConfigurationPushCounts{Successful: 3, Failed: 1}
+	require.NoError(t, r.ensureControlPlaneDeploymentStatus(ctx, controlplane, deployment, certSecret))
+	require.False(t, controlplane.Status.Sync.Synced)
+	require.Empty(t, controlplane.Status.Sync.Error)
+	syncStatus := controlplane.Status.Sync.DeepCopy()
+
+	t.Log("keeping the status as it is when only the push counts changed")
+	resourceVersion := controlplane.ResourceVersion
+	pushCounts = controlplaneutils.ConfigurationPushCounts{Successful: 3, Failed: 4}
+	require.NoError(t, r.ensureControlPlaneDeploymentStatus(ctx, controlplane, deployment, certSecret))
+	require.Equal(t, syncStatus, controlplane.Status.Sync)
+	require.Equal(t, resourceVersion, controlplane.ResourceVersion, "the status must not be updated")
+
+	t.Log("reporting the errors reading the metrics")
+	fetchErr = fmt.Errorf("connection refused")
+	require.NoError(t, r.ensureControlPlaneDeploymentStatus(ctx, controlplane, deployment, certSecret))
+	require.False(t, controlplane.Status.Sync.Synced)
+	require.Equal(t, "failed to read the metrics of Pod kic-1: connection refused", controlplane.Status.Sync.Error)
+
+	t.Log("clearing the configuration sync once the DataPlane is unset")
+	controlplane.Spec.DataPlane = nil
+	require.NoError(t, c.Delete(ctx, pod))
+	require.NoError(t, r.ensureControlPlaneDeploymentStatus(ctx, controlplane, deployment, certSecret))
+	require.Empty(t, controlplane.Status.DataPlane)
+	require.Nil(t, controlplane.Status.Sync)
+
+	t.Log("forgetting the push counts observed for a deleted ControlPlane")
+	r.configurationPushCounts.swap(client.ObjectKeyFromObject(controlplane), pushCounts)
+	require.NoError(t, c.Delete(ctx, controlplane))
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(controlplane)})
+	require.NoError(t, err)
+	require.Nil(t, r.configurationPushCounts.swap(client.ObjectKeyFromObject(controlplane), pushCounts))
+}
+
+func TestEnsureDataPlaneStatus(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		dataplane            *string
+		dataplanes           []operatorv1alpha1.ControlPlaneDataPlaneStatus
+		dataplaneIsPermitted bool
+		expectedIsSet        bool
+		expectedReason       k8sutils.ConditionReason
+	}{
+		{
+			name:                 "no dataplane is set",
+			dataplaneIsPermitted: true,
+			expectedReason:       ControlPlaneConditionReasonNoDataplane,
+		},
+		{
+			name:                 "the dataplane is set but not reachable yet",
+			dataplane:            pointer.String("kong"),
+			dataplanes:           []operatorv1alpha1.ControlPlaneDataPlaneStatus{{Name: "kong"}},
+			dataplaneIsPermitted: true,
+			expectedReason:       ControlPlaneConditionReasonDataPlanePending,
+		},
+		{
+			name:                 "the dataplane is reachable",
+			dataplane:            pointer.String("kong"),
+			dataplanes:           []operatorv1alpha1.ControlPlaneDataPlaneStatus{{Name: "kong", AdminURL: "https://10.0.0.1:8444"}},
+			dataplaneIsPermitted: true,
+			expectedIsSet:        true,
+			expectedReason:       ControlPlaneConditionReasonPodsNotReady,
+		},
+		{
+			name:           "the reference to the dataplane is not permitted",
+			dataplane:      pointer.String("kong"),
+			dataplanes:     []operatorv1alpha1.ControlPlaneDataPlaneStatus{{Name: "kong", AdminURL: "https://10.0.0.1:8444"}},
+			expectedReason: ControlPlaneConditionReasonRefNotPermitted,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			controlplane := &operatorv1alpha1.ControlPlane{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kic"},
+				Spec: operatorv1alpha1.ControlPlaneSpec{
+					ControlPlaneDeploymentOptions: operatorv1alpha1.ControlPlaneDeploymentOptions{
+						DataPlane: tc.dataplane,
+					},
+				},
+				Status: operatorv1alpha1.ControlPlaneStatus{DataPlanes: tc.dataplanes},
+			}
+			r := &ControlPlaneReconciler{}
+
+			require.Equal(t, tc.expectedIsSet, r.ensureDataPlaneStatus(controlplane, tc.dataplaneIsPermitted))
+			condition, present := k8sutils.GetCondition(ControlPlaneConditionTypeProvisioned, controlplane)
+			require.True(t, present)
+			require.Equal(t, metav1.ConditionFalse, condition.Status)
+			require.Equal(t, string(tc.expectedReason), condition.Reason)
+		})
+	}
+}
+
+func TestEnsureDeploymentForControlPlane(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, operatorv1alpha1.AddToScheme(scheme))
+
+	controlplane := &operatorv1alpha1.ControlPlane{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kic", UID: "controlplane-uid"},
+		Spec: operatorv1alpha1.ControlPlaneSpec{
+			ControlPlaneDeploymentOptions: operatorv1alpha1.ControlPlaneDeploymentOptions{
+				DeploymentOptions: operatorv1alpha1.DeploymentOptions{
+					Env: []corev1.EnvVar{{Name: "CONTROLLER_LOG_LEVEL", Value: "debug"}},
+				},
+				DataPlane: pointer.String("kong"),
+			},
+		},
+	}
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(controlplane).Build()
+	r := &ControlPlaneReconciler{Client: c}
+
+	t.Log("creating the deployment connected to the dataplane service")
+	created, deployment, err := r.ensureDeploymentForControlPlane(ctx, controlplane, true, "default", []string{"dataplane-kong-abcde"}, "sa", "cert")
+	require.NoError(t, err)
+	require.True(t, created)
+	env := deployment.Spec.Template.Spec.Containers[0].Env
+	require.Equal(t, "default/dataplane-kong-abcde", k8sutils.EnvValueByName(env, "CONTROLLER_PUBLISH_SERVICE"))
+	require.Equal(t, "debug", k8sutils.EnvValueByName(env, "CONTROLLER_LOG_LEVEL"))
+
+	t.Log("keeping the dataplane environment on the existing deployment")
+	updated, deployment, err := r.ensureDeploymentForControlPlane(ctx, controlplane, true, "default", []string{"dataplane-kong-abcde"}, "sa", "cert")
+	require.NoError(t, err)
+	require.False(t, updated)
+	require.Equal(t, env, deployment.Spec.Template.Spec.Containers[0].Env)
+
+	t.Log("updating the deployment once the dataplane service changes")
+	updated, deployment, err = r.ensureDeploymentForControlPlane(ctx, controlplane, true, "default", []string{"dataplane-kong-fghij"}, "sa", "cert")
+	require.NoError(t, err)
+	require.True(t, updated)
+	require.Equal(t, "default/dataplane-kong-fghij",
+		k8sutils.EnvValueByName(deployment.Spec.Template.Spec.Containers[0].Env, "CONTROLLER_PUBLISH_SERVICE"))
+	require.Equal(t, []corev1.EnvVar{{Name: "CONTROLLER_LOG_LEVEL", Value: "debug"}}, controlplane.Spec.Env,
+		"the dataplane environment must not be set on the ControlPlane")
+}
+
+func TestEnsureCanaryDeploymentForControlPlane(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, operatorv1alpha1.AddToScheme(scheme))
+
+	pushCounts := controlplaneutils.ConfigurationPushCounts{Successful: 10, Failed: 1}
+	fetchMetrics := fetchControlPlanePodConfigurationPushCounts
+	fetchControlPlanePodConfigurationPushCounts = func(context.Context, *corev1.Pod) (controlplaneutils.ConfigurationPushCounts, error) {
+		return pushCounts, nil
+	}
+	defer func() { fetchControlPlanePodConfigurationPushCounts = fetchMetrics }()
+
+	controlplane := &operatorv1alpha1.ControlPlane{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kic", UID: "controlplane-uid"},
+		Spec: operatorv1alpha1.ControlPlaneSpec{
+			ControlPlaneDeploymentOptions: operatorv1alpha1.ControlPlaneDeploymentOptions{
+				DataPlane: pointer.String("kong"),
+				Rollout: &operatorv1alpha1.ControlPlaneRollout{
+					Strategy: operatorv1alpha1.ControlPlaneRolloutStrategy{
+						Canary: &operatorv1alpha1.CanaryStrategy{
+							Steps: []operatorv1alpha1.CanaryStep{
+								{Weight: 50},
+								{Weight: 100, Pause: &metav1.Duration{}},
+							},
+							Analysis: &operatorv1alpha1.CanaryAnalysis{MaxErrorRate: 10},
+						},
+					},
+				},
+			},
+		},
+	}
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(controlplane).Build()
+	r := &ControlPlaneReconciler{Client: c, eventRecorder: record.NewFakeRecorder(10)}
+
+	reconcile := func() (bool, *appsv1.Deployment, *operatorv1alpha1.ControlPlaneRolloutStatus) {
+		createdOrUpdated, live, rollout, err := r.ensureCanaryDeploymentForControlPlane(ctx, controlplane, true,
+			"default", []string{"dataplane-kong-abcde"}, "sa", "cert")
+		require.NoError(t, err)
+		controlplane.Status.Rollout = rollout
+		return createdOrUpdated, live, rollout
+	}
+	getCanary := func() *appsv1.Deployment {
+		canaries, err := k8sutils.ListDeploymentsForOwner(ctx, c,
+			consts.ControlPlaneRolloutStateLabel, consts.ControlPlaneRolloutStateCanaryLabelValue, controlplane.Namespace, controlplane.UID)
+		require.NoError(t, err)
+		if len(canaries) == 0 {
+			return nil
+		}
+		require.Len(t, canaries, 1)
+		return &canaries[0]
+	}
+	makeReady := func(deployment *appsv1.Deployment) {
+		deployment.Status.Replicas = *deployment.Spec.Replicas
+		deployment.Status.AvailableReplicas = *deployment.Spec.Replicas
+		require.NoError(t, c.Status().Update(ctx, deployment))
+		require.NoError(t, c.Create(ctx, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: deployment.Namespace,
+				Name:      deployment.Name + "-pod",
+				Labels:    deployment.Spec.Template.Labels,
+			},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				PodIP:      "10.0.0.1",
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+		}))
+	}
+
+	t.Log("creating the first live deployment without canary")
+	createdOrUpdated, live, rollout := reconcile()
+	require.True(t, createdOrUpdated)
+	require.Equal(t, operatorv1alpha1.ControlPlaneRolloutPhaseComplete, rollout.Phase)
+	createdOrUpdated, _, rollout = reconcile()
+	require.False(t, createdOrUpdated)
+	require.Equal(t, operatorv1alpha1.ControlPlaneRolloutPhaseComplete, rollout.Phase)
+	require.Nil(t, getCanary())
+
+	t.Log("rolling out a change to a canary deployment")
+	controlplane.Spec.Env = []corev1.EnvVar{{Name: "CONTROLLER_LOG_LEVEL", Value: "debug"}}
+	createdOrUpdated, _, rollout = reconcile()
+	require.True(t, createdOrUpdated)
+	require.Equal(t, operatorv1alpha1.ControlPlaneRolloutPhaseProgressing, rollout.Phase)
+	canary := getCanary()
+	require.NotNil(t, canary)
+	require.Equal(t, int32(1), *canary.Spec.Replicas)
+	require.Equal(t, controlplane.Name, canary.Spec.Template.Labels["app"],
+		"the canary pods must be accepted by the network policies of the dataplane")
+	require.Equal(t, "debug", k8sutils.EnvValueByName(canary.Spec.Template.Spec.Containers[0].Env, "CONTROLLER_LOG_LEVEL"))
+
+	createdOrUpdated, current, rollout := reconcile()
+	require.False(t, createdOrUpdated)
+	require.Equal(t, operatorv1alpha1.ControlPlaneRolloutPhaseProgressing, rollout.Phase)
+	require.Equal(t, canary.Name, rollout.CanaryDeployment)
+	require.Empty(t, k8sutils.EnvValueByName(current.Spec.Template.Spec.Containers[0].Env, "CONTROLLER_LOG_LEVEL"),
+		"the change must not be applied to the live deployment during the rollout")
+
+	t.Log("collecting the metrics of the canary once it's ready, ignoring the failures before")
+	pushCounts = controlplaneutils.ConfigurationPushCounts{Successful: 10, Failed: 10}
+	makeReady(canary)
+	createdOrUpdated, _, rollout = reconcile()
+	require.False(t, createdOrUpdated)
+	require.Equal(t, &operatorv1alpha1.CanaryAnalysisBaseline{Total: 20, Errors: 10}, rollout.AnalysisBaseline)
+	require.Equal(t, "kic-canary.konghq.com", k8sutils.EnvValueByName(canary.Spec.Template.Spec.Containers[0].Env, consts.EnvVarControllerElectionID),
+		"the canary pods must not wait for the lead of the live pods to push configuration")
+
+	t.Log("keeping the step in progress until the canary pushes configuration")
+	createdOrUpdated, _, rollout = reconcile()
+	require.False(t, createdOrUpdated)
+	require.Equal(t, operatorv1alpha1.ControlPlaneRolloutPhaseProgressing, rollout.Phase)
+	require.Equal(t, "waiting for the canary pods to push configuration", rollout.Message)
+
+	t.Log("waiting for the manual promotion of the first step once the canary is healthy")
+	pushCounts = controlplaneutils.ConfigurationPushCounts{Successful: 30, Failed: 10}
+	createdOrUpdated, _, rollout = reconcile()
+	require.False(t, createdOrUpdated)
+	require.Equal(t, operatorv1alpha1.ControlPlaneRolloutPhaseAwaitingPromotion, rollout.Phase)
+
+	t.Log("progressing to the last step")
+	controlplane.Annotations = map[string]string{consts.ControlPlanePromoteCanaryAnnotation: "true"}
+	createdOrUpdated, _, rollout = reconcile()
+	require.False(t, createdOrUpdated)
+	require.Equal(t, int32(1), rollout.Step)
+	require.Nil(t, rollout.AnalysisBaseline)
+	require.NotContains(t, controlplane.Annotations, consts.ControlPlanePromoteCanaryAnnotation)
+	createdOrUpdated, _, rollout = reconcile()
+	require.False(t, createdOrUpdated)
+	require.NotNil(t, rollout.AnalysisBaseline)
+
+	t.Log("promoting the change to the live deployment once the last step is passed")
+	pushCounts = controlplaneutils.ConfigurationPushCounts{Successful: 40, Failed: 10}
+	createdOrUpdated, promoted, rollout := reconcile()
+	require.True(t, createdOrUpdated)
+	require.Equal(t, live.Name, promoted.Name)
+	require.Equal(t, operatorv1alpha1.ControlPlaneRolloutPhaseComplete, rollout.Phase)
+	require.Equal(t, "debug", k8sutils.EnvValueByName(promoted.Spec.Template.Spec.Containers[0].Env, "CONTROLLER_LOG_LEVEL"))
+	require.Nil(t, getCanary())
+	createdOrUpdated, _, rollout = reconcile()
+	require.False(t, createdOrUpdated)
+	require.Equal(t, operatorv1alpha1.ControlPlaneRolloutPhaseComplete, rollout.Phase)
+
+	t.Log("rolling back a change which canary exceeds the error rate during a step")
+	controlplane.Spec.Env = []corev1.EnvVar{{Name: "CONTROLLER_LOG_LEVEL", Value: "trace"}}
+	createdOrUpdated, _, _ = reconcile()
+	require.True(t, createdOrUpdated)
+	canary = getCanary()
+	require.NotNil(t, canary)
+	pushCounts = controlplaneutils.ConfigurationPushCounts{Successful: 10}
+	makeReady(canary)
+	_, _, rollout = reconcile()
+	require.NotNil(t, rollout.AnalysisBaseline)
+	pushCounts = controlplaneutils.ConfigurationPushCounts{Successful: 15, Failed: 5}
+	createdOrUpdated, current, rollout = reconcile()
+	require.True(t, createdOrUpdated)
+	require.Equal(t, operatorv1alpha1.ControlPlaneRolloutPhaseRolledBack, rollout.Phase)
+	require.NotEmpty(t, rollout.RolledBackRevision)
+	require.Equal(t, "debug", k8sutils.EnvValueByName(current.Spec.Template.Spec.Containers[0].Env, "CONTROLLER_LOG_LEVEL"))
+	require.Nil(t, getCanary())
+
+	t.Log("not rolling out the rolled back change again")
+	createdOrUpdated, _, rollout = reconcile()
+	require.False(t, createdOrUpdated)
+	require.Equal(t, operatorv1alpha1.ControlPlaneRolloutPhaseRolledBack, rollout.Phase)
+	require.Nil(t, getCanary())
+
+	t.Log("rolling out the image chosen by the automatic upgrades to a canary deployment")
+	controlplane.Spec.AutomaticUpgrades = &operatorv1alpha1.AutomaticUpgrades{Policy: operatorv1alpha1.UpgradePolicyPatch}
+	controlplane.Status.Version = "2.6.1"
+	createdOrUpdated, current, _ = reconcile()
+	require.True(t, createdOrUpdated)
+	canary = getCanary()
+	require.NotNil(t, canary)
+	require.Equal(t, consts.DefaultControlPlaneBaseImage+":2.6.1", canary.Spec.Template.Spec.Containers[0].Image)
+	require.NotEqual(t, canary.Spec.Template.Spec.Containers[0].Image, current.Spec.Template.Spec.Containers[0].Image,
+		"the upgrade must not be applied to the live deployment during the rollout")
+
+	t.Log("rolling back a change which canary pushes no configuration within the progress deadline")
+	makeReady(canary)
+	_, _, rollout = reconcile()
+	require.NotNil(t, rollout.AnalysisBaseline)
+	rollout.StepStartTime = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+	createdOrUpdated, _, rollout = reconcile()
+	require.True(t, createdOrUpdated)
+	require.Equal(t, operatorv1alpha1.ControlPlaneRolloutPhaseRolledBack, rollout.Phase)
+	require.Contains(t, rollout.Message, "no configuration pushes")
+	require.Nil(t, getCanary())
+}
+
+func TestControlPlaneIsSynced(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		previous *operatorv1alpha1.ControlPlaneSyncStatus
+		last     *controlplaneutils.ConfigurationPushCounts
+		counts   controlplaneutils.ConfigurationPushCounts
+		expected bool
+	}{
+		{
+			name: "no pushes yet",
+		},
+		{
+			name:     "first successful pushes",
+			counts:   controlplaneutils.ConfigurationPushCounts{Successful: 2},
+			expected: true,
+		},
+		{
+			name:     "new successful pushes",
+			previous: &operatorv1alpha1.ControlPlaneSyncStatus{},
+			last:     &controlplaneutils.ConfigurationPushCounts{Successful: 2, Failed: 1},
+			counts:   controlplaneutils.ConfigurationPushCounts{Successful: 4, Failed: 1},
+			expected: true,
+		},
+		{
+			name:     "new failed pushes",
+			previous: &operatorv1alpha1.ControlPlaneSyncStatus{Synced: true},
+			last:     &controlplaneutils.ConfigurationPushCounts{Successful: 4, Failed: 1},
+			counts:   controlplaneutils.ConfigurationPushCounts{Successful: 5, Failed: 2},
+		},
+		{
+			name:     "no new pushes",
+			previous: &operatorv1alpha1.ControlPlaneSyncStatus{Synced: true},
+			last:     &controlplaneutils.ConfigurationPushCounts{Successful: 4, Failed: 1},
+			counts:   controlplaneutils.ConfigurationPushCounts{Successful: 4, Failed: 1},
+			expected: true,
+		},
+		{
+			name:     "restarted pods",
+			previous: &operatorv1alpha1.ControlPlaneSyncStatus{},
+			last:     &controlplaneutils.ConfigurationPushCounts{Successful: 40, Failed: 10},
+			counts:   controlplaneutils.ConfigurationPushCounts{Successful: 2},
+			expected: true,
+		},
+		{
+			name:     "pushes observed for the first time since the status was reported",
+			previous: &operatorv1alpha1.ControlPlaneSyncStatus{},
+			counts:   controlplaneutils.ConfigurationPushCounts{Successful: 40, Failed: 10},
+		},
+		{
+			name:     "pushes observed after the errors reading the metrics",
+			previous: &operatorv1alpha1.ControlPlaneSyncStatus{Error: "connection refused"},
+			counts:   controlplaneutils.ConfigurationPushCounts{Successful: 40},
+			expected: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, controlplaneIsSynced(tt.previous, tt.last, tt.counts))
+		})
+	}
+}
+
+func TestNewControlPlaneSyncStatus(t *testing.T) {
+	previous := newControlPlaneSyncStatus(nil, true, "")
+	require.True(t, previous.Synced)
+	require.NotNil(t, previous.LastTransitionTime)
+
+	status := newControlPlaneSyncStatus(previous, true, "")
+	require.Equal(t, previous, status, "the transition time must be kept when nothing changed")
+
+	previous.LastTransitionTime = &metav1.Time{Time: previous.LastTransitionTime.Add(-time.Hour)}
+	status = newControlPlaneSyncStatus(previous, false, "")
+	require.False(t, status.Synced)
+	require.True(t, status.LastTransitionTime.After(previous.LastTransitionTime.Time))
+
+	status = newControlPlaneSyncStatus(previous, true, "connection refused")
+	require.Equal(t, "connection refused", status.Error)
+	require.True(t, status.LastTransitionTime.After(previous.LastTransitionTime.Time))
+}
@@ -1,114 +1,66 @@
 package controllers
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"reflect"
+	"strings"
+	"sync"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
 	"github.com/kong/gateway-operator/internal/consts"
-	dataplaneutils "github.com/kong/gateway-operator/internal/utils/dataplane"
+	controlplaneutils "github.com/kong/gateway-operator/internal/utils/controlplane"
+	"github.com/kong/gateway-operator/internal/utils/image"
 	k8sutils "github.com/kong/gateway-operator/internal/utils/kubernetes"
+	"github.com/kong/gateway-operator/internal/versions"
 )
 
 // -----------------------------------------------------------------------------
 // ControlPlane - Private Functions
 // -----------------------------------------------------------------------------
 
-// setControlPlaneDefaults updates the environment variables of control plane
-// and returns true if env field is changed.
-func setControlPlaneDefaults(
-	spec *operatorv1alpha1.ControlPlaneDeploymentOptions,
-	namespace string, dataplaneServiceName string,
-	dontOverride map[string]struct{},
-) bool {
-	changed := false
-
-	// set env POD_NAMESPACE. should be always from `metadata.namespace` of pod.
-	envSourceMetadataNamespace := &corev1.EnvVarSource{
-		FieldRef: &corev1.ObjectFieldSelector{
-			APIVersion: "v1",
-			FieldPath:  "metadata.namespace",
-		},
-	}
-	if !reflect.DeepEqual(envSourceMetadataNamespace, envVarSourceByName(spec.Env, "POD_NAMESPACE")) {
-		spec.Env = updateEnvSource(spec.Env, "POD_NAMESPACE", envSourceMetadataNamespace)
-		changed = true
-	}
-
-	// set env POD_NAME. should be always from `metadata.name` of pod.
-	envSourceMetadataName := &corev1.EnvVarSource{
-		FieldRef: &corev1.ObjectFieldSelector{
-			APIVersion: "v1",
-			FieldPath:  "metadata.name",
-		},
-	}
-	if !reflect.DeepEqual(envSourceMetadataName, envVarSourceByName(spec.Env, "POD_NAME")) {
-		spec.Env = updateEnvSource(spec.Env, "POD_NAME", envSourceMetadataName)
-		changed = true
-	}
-
-	if namespace != "" && dataplaneServiceName != "" {
-		if _, isOverrideDisabled := dontOverride["CONTROLLER_PUBLISH_SERVICE"]; !isOverrideDisabled {
-			publishService := controllerPublishService(dataplaneServiceName, namespace)
-			if envValueByName(spec.Env, "CONTROLLER_PUBLISH_SERVICE") != publishService {
-				spec.Env = updateEnv(spec.Env, "CONTROLLER_PUBLISH_SERVICE", controllerPublishService(dataplaneServiceName, namespace))
-				changed = true
-			}
-		}
-		if _, isOverrideDisabled := dontOverride["CONTROLLER_KONG_ADMIN_URL"]; !isOverrideDisabled {
-			kongAdminURL := controllerKongAdminURL(dataplaneServiceName, namespace)
-			if envValueByName(spec.Env, "CONTROLLER_KONG_ADMIN_URL") != kongAdminURL {
-				spec.Env = updateEnv(spec.Env, "CONTROLLER_KONG_ADMIN_URL", kongAdminURL)
-				changed = true
-			}
-		}
-	}
-
-	if _, isOverrideDisabled := dontOverride["CONTROLLER_KONG_ADMIN_TLS_CLIENT_CERT_FILE"]; !isOverrideDisabled {
-		spec.Env = updateEnv(spec.Env, "CONTROLLER_KONG_ADMIN_TLS_CLIENT_CERT_FILE", "/var/cluster-certificate/tls.crt")
-	}
-	if _, isOverrideDisabled := dontOverride["CONTROLLER_KONG_ADMIN_TLS_CLIENT_KEY_FILE"]; !isOverrideDisabled {
-		spec.Env = updateEnv(spec.Env, "CONTROLLER_KONG_ADMIN_TLS_CLIENT_KEY_FILE", "/var/cluster-certificate/tls.key")
-	}
-	if _, isOverrideDisabled := dontOverride["CONTROLLER_KONG_ADMIN_CA_CERT_FILE"]; !isOverrideDisabled {
-		spec.Env = updateEnv(spec.Env, "CONTROLLER_KONG_ADMIN_CA_CERT_FILE", "/var/cluster-certificate/ca.crt")
-	}
-
-	return changed
-}
-
+// setControlPlaneEnvOnDataPlaneChange updates the environment variables which
+// connect the control plane to its dataplanes and returns true if env field is
+// changed. The Service of the first dataplane is published, while the Kong
+// Admin APIs of all the dataplanes are configured.
 func setControlPlaneEnvOnDataPlaneChange(
 	spec *operatorv1alpha1.ControlPlaneDeploymentOptions,
 	namespace string,
-	dataplaneServiceName string,
+	dataplaneServiceNames []string,
 ) bool {
 	var changed bool
 
-	dataplaneIsSet := spec.DataPlane != nil && *spec.DataPlane != ""
+	dataplaneIsSet := len(dataplaneServiceNames) > 0
 	if dataplaneIsSet {
-		newPublishServiceValue := controllerPublishService(dataplaneServiceName, namespace)
-		if envValueByName(spec.Env, "CONTROLLER_PUBLISH_SERVICE") != newPublishServiceValue {
-			spec.Env = updateEnv(spec.Env, "CONTROLLER_PUBLISH_SERVICE", newPublishServiceValue)
+		newPublishServiceValue := controlplaneutils.PublishService(dataplaneServiceNames[0], namespace)
+		if k8sutils.EnvValueByName(spec.Env, "CONTROLLER_PUBLISH_SERVICE") != newPublishServiceValue {
+			spec.Env = k8sutils.UpdateEnv(spec.Env, "CONTROLLER_PUBLISH_SERVICE", newPublishServiceValue)
 			changed = true
 		}
-		newKongAdminURL := controllerKongAdminURL(dataplaneServiceName, namespace)
-		if envValueByName(spec.Env, "CONTROLLER_KONG_ADMIN_URL") != newKongAdminURL {
-			spec.Env = updateEnv(spec.Env, "CONTROLLER_KONG_ADMIN_URL", newKongAdminURL)
+		kongAdminURLs := make([]string, 0, len(dataplaneServiceNames))
+		for _, dataplaneServiceName := range dataplaneServiceNames {
+			kongAdminURLs = append(kongAdminURLs, controlplaneutils.KongAdminURL(dataplaneServiceName, namespace))
+		}
+		newKongAdminURL := strings.Join(kongAdminURLs, ",")
+		if k8sutils.EnvValueByName(spec.Env, "CONTROLLER_KONG_ADMIN_URL") != newKongAdminURL {
+			spec.Env = k8sutils.UpdateEnv(spec.Env, "CONTROLLER_KONG_ADMIN_URL", newKongAdminURL)
 			changed = true
 		}
 	} else {
-		if envValueByName(spec.Env, "CONTROLLER_PUBLISH_SERVICE") != "" {
-			spec.Env = rejectEnvByName(spec.Env, "CONTROLLER_PUBLISH_SERVICE")
+		if k8sutils.EnvValueByName(spec.Env, "CONTROLLER_PUBLISH_SERVICE") != "" {
+			spec.Env = k8sutils.RejectEnvByName(spec.Env, "CONTROLLER_PUBLISH_SERVICE")
 			changed = true
 		}
-		if envValueByName(spec.Env, "CONTROLLER_KONG_ADMIN_URL") != "" {
-			spec.Env = rejectEnvByName(spec.Env, "CONTROLLER_KONG_ADMIN_URL")
+		if k8sutils.EnvValueByName(spec.Env, "CONTROLLER_KONG_ADMIN_URL") != "" {
+			spec.Env = k8sutils.RejectEnvByName(spec.Env, "CONTROLLER_KONG_ADMIN_URL")
 			changed = true
 		}
 	}
@@ -116,93 +68,45 @@ func setControlPlaneEnvOnDataPlaneChange(
 	return changed
 }
 
-func controllerKongAdminURL(dataplaneName, dataplaneNamespace string) string {
-	return fmt.Sprintf("https://%s.%s.svc:%d",
-		dataplaneName, dataplaneNamespace, dataplaneutils.DefaultKongAdminPort)
-}
-
-func controllerPublishService(dataplaneName, dataplaneNamespace string) string {
-	return fmt.Sprintf("%s/%s", dataplaneNamespace, dataplaneName)
-}
-
-// envValueByName returns the value of the first env var with the given name.
-// If no env var with the given name is found, an empty string is returned.
-func envValueByName(env []corev1.EnvVar, name string) string {
-	for _, envVar := range env {
-		if envVar.Name == name {
-			return envVar.Value
-		}
-	}
-	return ""
-}
-
-// envVarSourceByName returns the ValueFrom of the first env var with the given name.
-// returns nil if env var is not found, or does not have a ValueFrom field.
-func envVarSourceByName(env []corev1.EnvVar, name string) *corev1.EnvVarSource {
-	for _, envVar := range env {
-		if envVar.Name == name {
-			return envVar.ValueFrom
-		}
-	}
-	return nil
+// controlplaneContainerImage returns the container image of the ControlPlane.
+// The default images are pulled from the mirror registry of the image policy,
+// if any.
+func controlplaneContainerImage(controlplane *operatorv1alpha1.ControlPlane, imagePolicy *image.Policy) string {
+	return containerImageForDeploymentOptions(&controlplane.Spec.DeploymentOptions,
+		imagePolicy.MirrorDefaultImage(consts.DefaultControlPlaneBaseImage),
+		imagePolicy.MirrorDefaultImage(consts.DefaultControlPlaneImage),
+		controlplane.Status.Version)
 }
 
-func updateEnv(envVars []corev1.EnvVar, name, val string) []corev1.EnvVar {
-	newEnvVars := make([]corev1.EnvVar, 0, len(envVars))
-	for _, envVar := range envVars {
-		if envVar.Name != name {
-			newEnvVars = append(newEnvVars, envVar)
-		}
+// controlplaneRBACVersion returns the semantic version of the ControlPlane
+// used to select its ClusterRole: the explicit SemanticVersion when set, the
+// tag of its container image otherwise. Images without tag or with the latest
+// tag use the ClusterRole of the latest KIC version.
+func controlplaneRBACVersion(controlplane *operatorv1alpha1.ControlPlane) (string, error) {
+	if semanticVersion := controlplane.Spec.SemanticVersion; semanticVersion != nil && *semanticVersion != "" {
+		return *semanticVersion, nil
 	}
 
-	newEnvVars = append(newEnvVars, corev1.EnvVar{
-		Name:  name,
-		Value: val,
-	})
-
-	return newEnvVars
-}
-
-// updateEnvSource updates env var with `name` to come from `envSource`.
-func updateEnvSource(envVars []corev1.EnvVar, name string, envSource *corev1.EnvVarSource) []corev1.EnvVar {
-	newEnvVars := make([]corev1.EnvVar, 0, len(envVars))
-	for _, envVar := range envVars {
-		if envVar.Name != name {
-			newEnvVars = append(newEnvVars, envVar)
-		}
+	ref, err := image.ParseReference(controlplaneContainerImage(controlplane, nil))
+	if err != nil {
+		return "", err
 	}
-
-	newEnvVars = append(newEnvVars, corev1.EnvVar{
-		Name:      name,
-		ValueFrom: envSource,
-	})
-
-	return newEnvVars
-}
-
-// rejectEnvByName returns a copy of the given env vars,
-// but with the env vars with the given name removed.
-func rejectEnvByName(envVars []corev1.EnvVar, name string) []corev1.EnvVar {
-	newEnvVars := make([]corev1.EnvVar, 0, len(envVars))
-	for _, envVar := range envVars {
-		if envVar.Name != name {
-			newEnvVars = append(newEnvVars, envVar)
-		}
+	if ref.Tag == "" || ref.Tag == "latest" {
+		return versions.Latest, nil
 	}
-	return newEnvVars
+	return ref.Tag, nil
 }
 
-func generateNewDeploymentForControlPlane(controlplane *operatorv1alpha1.ControlPlane, serviceAccountName,
-	certSecretName string) *appsv1.Deployment {
-	var controlplaneImage string
-	if controlplane.Spec.ContainerImage != nil {
-		controlplaneImage = *controlplane.Spec.ContainerImage
-		if controlplane.Spec.Version != nil {
-			controlplaneImage = fmt.Sprintf("%s:%s", controlplaneImage, *controlplane.Spec.Version)
-		}
-	} else {
-		controlplaneImage = consts.DefaultControlPlaneImage // TODO: https://github.com/Kong/gateway-operator/issues/20
-	}
+// generateNewDeploymentForControlPlane generates the Deployment of a
+// ControlPlane, connected to the Services of the provided dataplanes. The
+// environment related to the dataplanes is only set on the Deployment, never
+// on the ControlPlane.
+func generateNewDeploymentForControlPlane(controlplane *operatorv1alpha1.ControlPlane,
+	dataplaneNamespace string, dataplaneServiceNames []string,
+	serviceAccountName, certSecretName string, imagePolicy *image.Policy) *appsv1.Deployment {
+	controlplaneImage := controlplaneContainerImage(controlplane, imagePolicy)
+	deploymentOptions := controlplane.Spec.ControlPlaneDeploymentOptions.DeepCopy()
+	setControlPlaneEnvOnDataPlaneChange(deploymentOptions, dataplaneNamespace, dataplaneServiceNames)
 
 	deployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -227,6 +131,7 @@ func generateNewDeploymentForControlPlane(controlplane *operatorv1alpha1.Control
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName: serviceAccountName,
+					ImagePullSecrets:   imagePolicy.LocalObjectReferences(),
 					Volumes: []corev1.Volume{
 						{
 							Name: "cluster-certificate",
@@ -253,8 +158,8 @@ func generateNewDeploymentForControlPlane(controlplane *operatorv1alpha1.Control
 					},
 					Containers: []corev1.Container{{
 						Name:            consts.ControlPlaneControllerContainerName,
-						Env:             controlplane.Spec.Env,
-						EnvFrom:         controlplane.Spec.EnvFrom,
+						Env:             deploymentOptions.Env,
+						EnvFrom:         deploymentOptions.EnvFrom,
 						Image:           controlplaneImage,
 						ImagePullPolicy: corev1.PullIfNotPresent,
 						VolumeMounts: []corev1.VolumeMount{
@@ -318,10 +223,189 @@ func generateNewDeploymentForControlPlane(controlplane *operatorv1alpha1.Control
 	return deployment
 }
 
+// -----------------------------------------------------------------------------
+// ControlPlane - Private Functions - Configuration Sync
+// -----------------------------------------------------------------------------
+
+// fetchControlPlanePodConfigurationPushCounts returns the number of Kong
+// configuration pushes reported by the ingress controller on the metrics port
+// of a ControlPlane Pod. It's a variable to be replaced in tests, as the Pods
+// can't be reached from there.
+var fetchControlPlanePodConfigurationPushCounts = func(ctx context.Context, pod *corev1.Pod) (controlplaneutils.ConfigurationPushCounts, error) {
+	url := fmt.Sprintf("http://%s:%d/metrics", pod.Status.PodIP, consts.ControlPlaneMetricsPort)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return controlplaneutils.ConfigurationPushCounts{}, err
+	}
+	resp, err := controlplaneMetricsHTTPClient.Do(req)
+	if err != nil {
+		return controlplaneutils.ConfigurationPushCounts{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return controlplaneutils.ConfigurationPushCounts{}, fmt.Errorf("unexpected status code %d for %s", resp.StatusCode, url)
+	}
+	return controlplaneutils.ParseConfigurationPushCounts(resp.Body)
+}
+
+// configurationPushCounts keeps the last configuration push counts observed
+// for each ControlPlane, which tell the new pushes apart. They aren't recorded
+// in the ControlPlane status as they change on every push. They're only kept
+// in memory: they're forgotten once the ControlPlane is deleted, and reset
+// when the operator restarts or another replica takes the lead, after which
+// the ControlPlanes keep their sync status until their next pushes.
+type configurationPushCounts struct {
+	lock   sync.Mutex
+	counts map[types.NamespacedName]controlplaneutils.ConfigurationPushCounts
+}
+
+// swap records the counts observed for a ControlPlane and returns the previous
+// ones, or nil if there were none.
+func (c *configurationPushCounts) swap(key types.NamespacedName, counts controlplaneutils.ConfigurationPushCounts) *controlplaneutils.ConfigurationPushCounts {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.counts == nil {
+		c.counts = make(map[types.NamespacedName]controlplaneutils.ConfigurationPushCounts)
+	}
+	last, ok := c.counts[key]
+	c.counts[key] = counts
+	if !ok {
+		return nil
+	}
+	return &last
+}
+
+// delete forgets the counts observed for a ControlPlane.
+func (c *configurationPushCounts) delete(key types.NamespacedName) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.counts, key)
+}
+
+// controlplaneIsSynced tells whether a ControlPlane is synced from the number
+// of configuration pushes reported by its Pods and the last observed ones. The
+// ControlPlane is synced if the last pushes observed since then succeeded, and
+// stays as it was if there were none. The counts going down means the Pods
+// restarted, in which case all the pushes they report are new, as they are
+// when none were observed before the sync status was reported. Once reported,
+// the new pushes can't be told apart without the last observed counts, e.g.
+// after a restart of the operator, so the sync status stays as it was.
+func controlplaneIsSynced(
+	previous *operatorv1alpha1.ControlPlaneSyncStatus,
+	last *controlplaneutils.ConfigurationPushCounts,
+	counts controlplaneutils.ConfigurationPushCounts,
+) bool {
+	synced := previous != nil && previous.Synced
+	if last == nil {
+		if previous != nil && previous.Error == "" {
+			return synced
+		}
+		last = &controlplaneutils.ConfigurationPushCounts{}
+	}
+	if counts.Successful < last.Successful || counts.Failed < last.Failed {
+		last = &controlplaneutils.ConfigurationPushCounts{}
+	}
+
+	switch {
+	case counts.Failed > last.Failed:
+		return false
+	case counts.Successful > last.Successful:
+		return true
+	}
+	return synced
+}
+
+// newControlPlaneSyncStatus returns the synchronization status of a
+// ControlPlane, which transition time is kept from the previous status unless
+// the synced state or the error changed.
+func newControlPlaneSyncStatus(previous *operatorv1alpha1.ControlPlaneSyncStatus, synced bool, syncErr string) *operatorv1alpha1.ControlPlaneSyncStatus {
+	status := &operatorv1alpha1.ControlPlaneSyncStatus{
+		Synced: synced,
+		Error:  syncErr,
+	}
+	if previous != nil && previous.LastTransitionTime != nil && previous.Synced == synced && previous.Error == syncErr {
+		status.LastTransitionTime = previous.LastTransitionTime
+	} else {
+		now := metav1.Now()
+		status.LastTransitionTime = &now
+	}
+	return status
+}
+
 // -----------------------------------------------------------------------------
 // ControlPlane - Private Functions - Kubernetes Object Labels
 // -----------------------------------------------------------------------------
 
+// controlplaneRolloutIsInProgress returns whether a ControlPlane rollout in
+// the provided phase has yet to complete.
+func controlplaneRolloutIsInProgress(phase operatorv1alpha1.ControlPlaneRolloutPhase) bool {
+	switch phase {
+	case operatorv1alpha1.ControlPlaneRolloutPhaseAwaitingPromotion,
+		operatorv1alpha1.ControlPlaneRolloutPhaseProgressing:
+		return true
+	}
+	return false
+}
+
+// controlplaneUsesCanaryRollout tells whether the ControlPlane Deployment is
+// rolled out using the canary strategy.
+func controlplaneUsesCanaryRollout(controlplane *operatorv1alpha1.ControlPlane) bool {
+	rollout := controlplane.Spec.Rollout
+	return rollout != nil && rollout.Strategy.Canary != nil && len(rollout.Strategy.Canary.Steps) > 0
+}
+
+// generateCanaryDeploymentForControlPlane generates the canary Deployment of
+// the ControlPlane running the provided Pod template. Its Pods keep the app
+// label of the ControlPlane Pods, which the DataPlanes accept the connections
+// of, and are told apart from the live ones by the hash of their template.
+// The canary Pods run their own leader election, otherwise the live Pods keep
+// the lead and the canary ones never push any configuration to be analyzed.
+func generateCanaryDeploymentForControlPlane(
+	controlplane *operatorv1alpha1.ControlPlane,
+	template *corev1.PodTemplateSpec,
+	hash string,
+) *appsv1.Deployment {
+	selector := map[string]string{
+		"app":                                   controlplane.Name,
+		consts.ControlPlanePodTemplateHashLabel: hash,
+	}
+
+	podTemplate := template.DeepCopy()
+	if podTemplate.Labels == nil {
+		podTemplate.Labels = map[string]string{}
+	}
+	for k, v := range selector {
+		podTemplate.Labels[k] = v
+	}
+	for i := range podTemplate.Spec.Containers {
+		container := &podTemplate.Spec.Containers[i]
+		if container.Name == consts.ControlPlaneControllerContainerName {
+			container.Env = k8sutils.UpdateEnv(container.Env, consts.EnvVarControllerElectionID,
+				fmt.Sprintf("%s-canary.konghq.com", controlplane.Name))
+		}
+	}
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       controlplane.Namespace,
+			GenerateName:    fmt.Sprintf("%s-canary-%s-", consts.ControlPlanePrefix, controlplane.Name),
+			OwnerReferences: []metav1.OwnerReference{k8sutils.GenerateOwnerReferenceForObject(controlplane)},
+			Labels: map[string]string{
+				"app":                                controlplane.Name,
+				consts.ControlPlaneRolloutStateLabel: consts.ControlPlaneRolloutStateCanaryLabelValue,
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: selector},
+			Template: *podTemplate,
+		},
+	}
+	return deployment
+}
+
 func addLabelForControlPlane(obj client.Object) {
 	labels := obj.GetLabels()
 	if labels == nil {
@@ -344,5 +428,21 @@ func controlplaneSpecDeepEqual(spec1, spec2 *operatorv1alpha1.ControlPlaneDeploy
 		return false
 	}
 
+	if !reflect.DeepEqual(spec1.DataPlaneNamespace, spec2.DataPlaneNamespace) {
+		return false
+	}
+
+	if !reflect.DeepEqual(spec1.DataPlanes, spec2.DataPlanes) {
+		return false
+	}
+
+	if !reflect.DeepEqual(spec1.DataPlaneSelector, spec2.DataPlaneSelector) {
+		return false
+	}
+
+	if !reflect.DeepEqual(spec1.Rollout, spec2.Rollout) {
+		return false
+	}
+
 	return true
 }
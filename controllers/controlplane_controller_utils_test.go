@@ -1,181 +1,195 @@
 package controllers
 
 import (
-	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
 
 	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	"github.com/kong/gateway-operator/internal/consts"
+	"github.com/kong/gateway-operator/internal/utils/image"
+	k8sutils "github.com/kong/gateway-operator/internal/utils/kubernetes"
+	"github.com/kong/gateway-operator/internal/versions"
 )
 
-func TestSetControlPlaneDefaults(t *testing.T) {
+func TestSetControlPlaneEnvOnDataPlaneChange(t *testing.T) {
 	testCases := []struct {
-		name                 string
-		spec                 *operatorv1alpha1.ControlPlaneDeploymentOptions
-		namespace            string
-		dataplaceServiceName string
-		changed              bool
-		newSpec              *operatorv1alpha1.ControlPlaneDeploymentOptions
+		name                  string
+		env                   []corev1.EnvVar
+		dataplaneServiceNames []string
+		changed               bool
+		expectedEnv           []corev1.EnvVar
 	}{
 		{
-			name:    "no_envs_no_dataplane",
-			spec:    &operatorv1alpha1.ControlPlaneDeploymentOptions{},
-			changed: true,
-			newSpec: &operatorv1alpha1.ControlPlaneDeploymentOptions{
-				DeploymentOptions: operatorv1alpha1.DeploymentOptions{
-					Env: []corev1.EnvVar{
-						{Name: "CONTROLLER_KONG_ADMIN_TLS_SKIP_VERIFY", Value: "true"},
-						{
-							Name: "POD_NAMESPACE", ValueFrom: &corev1.EnvVarSource{
-								FieldRef: &corev1.ObjectFieldSelector{
-									APIVersion: "v1", FieldPath: "metadata.namespace",
-								},
-							},
-						},
-						{
-							Name: "POD_NAME", ValueFrom: &corev1.EnvVarSource{
-								FieldRef: &corev1.ObjectFieldSelector{
-									APIVersion: "v1", FieldPath: "metadata.name",
-								},
-							},
-						},
-					},
-				},
+			name:                  "single dataplane",
+			dataplaneServiceNames: []string{"kong-proxy"},
+			changed:               true,
+			expectedEnv: []corev1.EnvVar{
+				{Name: "CONTROLLER_PUBLISH_SERVICE", Value: "test-ns/kong-proxy"},
+				{Name: "CONTROLLER_KONG_ADMIN_URL", Value: "https://kong-proxy.test-ns.svc:8444"},
 			},
 		},
 		{
-			name:                 "no_envs_has_dataplane",
-			spec:                 &operatorv1alpha1.ControlPlaneDeploymentOptions{},
-			changed:              true,
-			namespace:            "test-ns",
-			dataplaceServiceName: "kong-proxy",
-			newSpec: &operatorv1alpha1.ControlPlaneDeploymentOptions{
-				DeploymentOptions: operatorv1alpha1.DeploymentOptions{
-					Env: []corev1.EnvVar{
-						{Name: "CONTROLLER_KONG_ADMIN_TLS_SKIP_VERIFY", Value: "true"},
-						{
-							Name: "POD_NAMESPACE", ValueFrom: &corev1.EnvVarSource{
-								FieldRef: &corev1.ObjectFieldSelector{
-									APIVersion: "v1", FieldPath: "metadata.namespace",
-								},
-							},
-						},
-						{
-							Name: "POD_NAME", ValueFrom: &corev1.EnvVarSource{
-								FieldRef: &corev1.ObjectFieldSelector{
-									APIVersion: "v1", FieldPath: "metadata.name",
-								},
-							},
-						},
-						{Name: "CONTROLLER_PUBLISH_SERVICE", Value: "test-ns/kong-proxy"},
-						{Name: "CONTROLLER_KONG_ADMIN_URL", Value: "https://kong-proxy.test-ns.svc:8444"},
-					},
-				},
+			name:                  "multiple dataplanes",
+			dataplaneServiceNames: []string{"kong-proxy", "kong-proxy-2"},
+			env: []corev1.EnvVar{
+				{Name: "CONTROLLER_PUBLISH_SERVICE", Value: "test-ns/kong-proxy"},
+				{Name: "CONTROLLER_KONG_ADMIN_URL", Value: "https://kong-proxy.test-ns.svc:8444"},
+			},
+			changed: true,
+			expectedEnv: []corev1.EnvVar{
+				{Name: "CONTROLLER_PUBLISH_SERVICE", Value: "test-ns/kong-proxy"},
+				{Name: "CONTROLLER_KONG_ADMIN_URL", Value: "https://kong-proxy.test-ns.svc:8444,https://kong-proxy-2.test-ns.svc:8444"},
 			},
 		},
 		{
-			name: "has_envs_and_dataplane",
-			spec: &operatorv1alpha1.ControlPlaneDeploymentOptions{
-				DeploymentOptions: operatorv1alpha1.DeploymentOptions{
-					Env: []corev1.EnvVar{
-						{Name: "TEST_ENV", Value: "test"},
-					},
-				},
+			name:                  "multiple dataplanes unchanged",
+			dataplaneServiceNames: []string{"kong-proxy", "kong-proxy-2"},
+			env: []corev1.EnvVar{
+				{Name: "CONTROLLER_PUBLISH_SERVICE", Value: "test-ns/kong-proxy"},
+				{Name: "CONTROLLER_KONG_ADMIN_URL", Value: "https://kong-proxy.test-ns.svc:8444,https://kong-proxy-2.test-ns.svc:8444"},
 			},
-			changed:              true,
-			namespace:            "test-ns",
-			dataplaceServiceName: "kong-proxy",
-			newSpec: &operatorv1alpha1.ControlPlaneDeploymentOptions{
-				DeploymentOptions: operatorv1alpha1.DeploymentOptions{
-					Env: []corev1.EnvVar{
-						{Name: "TEST_ENV", Value: "test"},
-						{Name: "CONTROLLER_KONG_ADMIN_TLS_SKIP_VERIFY", Value: "true"},
-						{
-							Name: "POD_NAMESPACE", ValueFrom: &corev1.EnvVarSource{
-								FieldRef: &corev1.ObjectFieldSelector{
-									APIVersion: "v1", FieldPath: "metadata.namespace",
-								},
-							},
-						},
-						{
-							Name: "POD_NAME", ValueFrom: &corev1.EnvVarSource{
-								FieldRef: &corev1.ObjectFieldSelector{
-									APIVersion: "v1", FieldPath: "metadata.name",
-								},
-							},
-						},
-						{Name: "CONTROLLER_PUBLISH_SERVICE", Value: "test-ns/kong-proxy"},
-						{Name: "CONTROLLER_KONG_ADMIN_URL", Value: "https://kong-proxy.test-ns.svc:8444"},
-					},
-				},
+			changed: false,
+			expectedEnv: []corev1.EnvVar{
+				{Name: "CONTROLLER_PUBLISH_SERVICE", Value: "test-ns/kong-proxy"},
+				{Name: "CONTROLLER_KONG_ADMIN_URL", Value: "https://kong-proxy.test-ns.svc:8444,https://kong-proxy-2.test-ns.svc:8444"},
 			},
 		},
 		{
-			name: "has_dataplane_env_unchanged",
-			spec: &operatorv1alpha1.ControlPlaneDeploymentOptions{
-				DeploymentOptions: operatorv1alpha1.DeploymentOptions{
-					Env: []corev1.EnvVar{
-						{Name: "CONTROLLER_KONG_ADMIN_TLS_SKIP_VERIFY", Value: "true"},
-						{
-							Name: "POD_NAMESPACE", ValueFrom: &corev1.EnvVarSource{
-								FieldRef: &corev1.ObjectFieldSelector{
-									APIVersion: "v1", FieldPath: "metadata.namespace",
-								},
-							},
-						},
-						{
-							Name: "POD_NAME", ValueFrom: &corev1.EnvVarSource{
-								FieldRef: &corev1.ObjectFieldSelector{
-									APIVersion: "v1", FieldPath: "metadata.name",
-								},
-							},
-						},
-						{Name: "CONTROLLER_PUBLISH_SERVICE", Value: "test-ns/kong-proxy"},
-						{Name: "CONTROLLER_KONG_ADMIN_URL", Value: "https://kong-proxy.test-ns.svc:8444"},
-					},
-				},
+			name: "no dataplanes",
+			env: []corev1.EnvVar{
+				{Name: "TEST_ENV", Value: "test"},
+				{Name: "CONTROLLER_PUBLISH_SERVICE", Value: "test-ns/kong-proxy"},
+				{Name: "CONTROLLER_KONG_ADMIN_URL", Value: "https://kong-proxy.test-ns.svc:8444"},
 			},
-			namespace:            "test-ns",
-			dataplaceServiceName: "kong-proxy",
-			changed:              false,
-			newSpec: &operatorv1alpha1.ControlPlaneDeploymentOptions{
-				DeploymentOptions: operatorv1alpha1.DeploymentOptions{
-					Env: []corev1.EnvVar{
-						{Name: "CONTROLLER_KONG_ADMIN_TLS_SKIP_VERIFY", Value: "true"},
-						{
-							Name: "POD_NAMESPACE", ValueFrom: &corev1.EnvVarSource{
-								FieldRef: &corev1.ObjectFieldSelector{
-									APIVersion: "v1", FieldPath: "metadata.namespace",
-								},
-							},
-						},
-						{
-							Name: "POD_NAME", ValueFrom: &corev1.EnvVarSource{
-								FieldRef: &corev1.ObjectFieldSelector{
-									APIVersion: "v1", FieldPath: "metadata.name",
-								},
-							},
-						},
-						{Name: "CONTROLLER_PUBLISH_SERVICE", Value: "test-ns/kong-proxy"},
-						{Name: "CONTROLLER_KONG_ADMIN_URL", Value: "https://kong-proxy.test-ns.svc:8444"},
-					},
-				},
+			changed: true,
+			expectedEnv: []corev1.EnvVar{
+				{Name: "TEST_ENV", Value: "test"},
 			},
 		},
 	}
 
-	for i, tc := range testCases {
-		index := i
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			spec := &operatorv1alpha1.ControlPlaneDeploymentOptions{
+				DeploymentOptions: operatorv1alpha1.DeploymentOptions{Env: tc.env},
+			}
+			changed := setControlPlaneEnvOnDataPlaneChange(spec, "test-ns", tc.dataplaneServiceNames)
+			require.Equal(t, tc.changed, changed)
+			require.Equal(t, tc.expectedEnv, spec.Env)
+		})
+	}
+}
+
+func TestControlPlaneRBACVersion(t *testing.T) {
+	const digest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	testCases := []struct {
+		name            string
+		containerImage  *string
+		version         *string
+		semanticVersion *string
+		expected        string
+		wantErr         bool
+	}{
+		{
+			name:     "default image",
+			expected: consts.DefaultControlPlaneTag,
+		},
+		{
+			name:           "image with registry port",
+			containerImage: pointer.String("registry:5000/kong/kic"),
+			version:        pointer.String("2.3"),
+			expected:       "2.3",
+		},
+		{
+			name:           "image with tag and digest",
+			containerImage: pointer.String("registry:5000/kong/kic:2.2.1@" + digest),
+			expected:       "2.2.1",
+		},
+		{
+			name:           "image pinned by digest",
+			containerImage: pointer.String("kong/kic@" + digest),
+			expected:       versions.Latest,
+		},
+		{
+			name:           "latest image",
+			containerImage: pointer.String("kong/kic:latest"),
+			expected:       versions.Latest,
+		},
+		{
+			name:            "explicit semantic version",
+			containerImage:  pointer.String("kong/kic@" + digest),
+			semanticVersion: pointer.String("2.1.1"),
+			expected:        "2.1.1",
+		},
+		{
+			name:           "invalid image",
+			containerImage: pointer.String("kong/kic:2.2:2.3"),
+			wantErr:        true,
+		},
+	}
+
+	for _, tc := range testCases {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
-			changed := setControlPlaneDefaults(tc.spec, tc.namespace, tc.dataplaceServiceName, map[string]struct{}{})
-			require.Equalf(t, tc.changed, changed,
-				"should return the same value for test case %d:%s", index, tc.name)
-			require.Truef(t, reflect.DeepEqual(tc.spec, tc.newSpec),
-				"the updated spec should be equal to expected for test case %d:%s\nexpected: %+v\nactual: %+v",
-				index, tc.name, tc.newSpec, tc.spec)
+			controlplane := &operatorv1alpha1.ControlPlane{}
+			controlplane.Spec.ContainerImage = tc.containerImage
+			controlplane.Spec.Version = tc.version
+			controlplane.Spec.SemanticVersion = tc.semanticVersion
+
+			version, err := controlplaneRBACVersion(controlplane)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, version)
 		})
 	}
 }
+
+func TestGenerateNewDeploymentForControlPlaneWithImagePolicy(t *testing.T) {
+	imagePolicy := &image.Policy{
+		MirrorRegistry:   "mirror.example.com:5000",
+		ImagePullSecrets: []string{"mirror-credentials"},
+	}
+
+	controlplane := &operatorv1alpha1.ControlPlane{}
+	deployment := generateNewDeploymentForControlPlane(controlplane, "", nil, "sa", "cert", imagePolicy)
+	require.Equal(t, "mirror.example.com:5000/"+consts.DefaultControlPlaneImage, deployment.Spec.Template.Spec.Containers[0].Image)
+	require.Equal(t, []corev1.LocalObjectReference{{Name: "mirror-credentials"}}, deployment.Spec.Template.Spec.ImagePullSecrets)
+
+	t.Log("the version is set on the mirrored default image")
+	controlplane.Spec.Version = pointer.String("2.6")
+	deployment = generateNewDeploymentForControlPlane(controlplane, "", nil, "sa", "cert", imagePolicy)
+	require.Equal(t, "mirror.example.com:5000/"+consts.DefaultControlPlaneBaseImage+":2.6", deployment.Spec.Template.Spec.Containers[0].Image)
+
+	t.Log("the container images set by users are not mirrored")
+	controlplane.Spec.ContainerImage = pointer.String("registry.example.com/kong/kic")
+	deployment = generateNewDeploymentForControlPlane(controlplane, "", nil, "sa", "cert", imagePolicy)
+	require.Equal(t, "registry.example.com/kong/kic:2.6", deployment.Spec.Template.Spec.Containers[0].Image)
+}
+
+func TestGenerateNewDeploymentForControlPlaneDataPlaneEnv(t *testing.T) {
+	controlplane := &operatorv1alpha1.ControlPlane{
+		Spec: operatorv1alpha1.ControlPlaneSpec{
+			ControlPlaneDeploymentOptions: operatorv1alpha1.ControlPlaneDeploymentOptions{
+				DeploymentOptions: operatorv1alpha1.DeploymentOptions{
+					Env: []corev1.EnvVar{{Name: "CONTROLLER_LOG_LEVEL", Value: "debug"}},
+				},
+			},
+		},
+	}
+	expectedSpec := controlplane.Spec.DeepCopy()
+
+	deployment := generateNewDeploymentForControlPlane(controlplane, "test-ns", []string{"dp-1", "dp-2"}, "sa", "cert", nil)
+	env := deployment.Spec.Template.Spec.Containers[0].Env
+	require.Equal(t, "debug", k8sutils.EnvValueByName(env, "CONTROLLER_LOG_LEVEL"))
+	require.Equal(t, "test-ns/dp-1", k8sutils.EnvValueByName(env, "CONTROLLER_PUBLISH_SERVICE"))
+	require.Equal(t, "https://dp-1.test-ns.svc:8444,https://dp-2.test-ns.svc:8444", k8sutils.EnvValueByName(env, "CONTROLLER_KONG_ADMIN_URL"))
+	require.Equal(t, expectedSpec, &controlplane.Spec, "the environment related to the dataplanes must not be set on the ControlPlane")
+}
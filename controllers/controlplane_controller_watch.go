@@ -5,13 +5,17 @@ import (
 	"reflect"
 
 	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
 	operatorerrors "github.com/kong/gateway-operator/internal/errors"
+	gatewayutils "github.com/kong/gateway-operator/internal/utils/gateway"
 	k8sutils "github.com/kong/gateway-operator/internal/utils/kubernetes"
 )
 
@@ -128,3 +132,104 @@ func (r *ControlPlaneReconciler) getControlplaneRequestFromRefUID(ctx context.Co
 
 	return
 }
+
+func (r *ControlPlaneReconciler) getControlplanesForReferenceGrant(obj client.Object) (recs []reconcile.Request) {
+	ctx := context.Background()
+
+	referenceGrant, ok := obj.(*gatewayv1alpha2.ReferenceGrant)
+	if !ok {
+		log.FromContext(ctx).Error(
+			operatorerrors.ErrUnexpectedObject,
+			"failed to run map funcs",
+			"expected", "ReferenceGrant", "found", reflect.TypeOf(obj),
+		)
+		return
+	}
+
+	controlplanes := &operatorv1alpha1.ControlPlaneList{}
+	if err := r.Client.List(ctx, controlplanes); err != nil {
+		log.FromContext(ctx).Error(err, "could not list controlplanes in map func")
+		return
+	}
+
+	for _, controlplane := range controlplanes.Items {
+		controlplane := controlplane
+		if controlplane.Namespace == referenceGrant.Namespace {
+			continue
+		}
+		if gatewayutils.GetDataPlaneNamespaceForControlPlane(&controlplane) == referenceGrant.Namespace {
+			recs = append(recs, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: controlplane.Namespace,
+					Name:      controlplane.Name,
+				},
+			})
+		}
+	}
+
+	return
+}
+
+func (r *ControlPlaneReconciler) getControlplanesForDataPlane(obj client.Object) (recs []reconcile.Request) {
+	ctx := context.Background()
+
+	dataplane, ok := obj.(*operatorv1alpha1.DataPlane)
+	if !ok {
+		log.FromContext(ctx).Error(
+			operatorerrors.ErrUnexpectedObject,
+			"failed to run map funcs",
+			"expected", "DataPlane", "found", reflect.TypeOf(obj),
+		)
+		return
+	}
+
+	controlplanes := &operatorv1alpha1.ControlPlaneList{}
+	if err := r.Client.List(ctx, controlplanes); err != nil {
+		log.FromContext(ctx).Error(err, "could not list controlplanes in map func")
+		return
+	}
+
+	for _, controlplane := range controlplanes.Items {
+		controlplane := controlplane
+		if gatewayutils.GetDataPlaneNamespaceForControlPlane(&controlplane) != dataplane.Namespace {
+			continue
+		}
+		if controlplaneReferencesDataPlane(&controlplane, dataplane) {
+			recs = append(recs, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: controlplane.Namespace,
+					Name:      controlplane.Name,
+				},
+			})
+		}
+	}
+
+	return
+}
+
+// controlplaneReferencesDataPlane returns true if the dataplane is referenced by
+// the controlplane by name, or is matched by its DataPlaneSelector. DataPlanes
+// listed in the controlplane status are considered referenced as well, so that
+// the controlplane is notified when a DataPlane stops matching its selector.
+func controlplaneReferencesDataPlane(controlplane *operatorv1alpha1.ControlPlane, dataplane *operatorv1alpha1.DataPlane) bool {
+	if controlplane.Spec.DataPlane != nil && *controlplane.Spec.DataPlane == dataplane.Name {
+		return true
+	}
+	for _, name := range controlplane.Spec.DataPlanes {
+		if name == dataplane.Name {
+			return true
+		}
+	}
+	for _, dataplaneStatus := range controlplane.Status.DataPlanes {
+		if dataplaneStatus.Name == dataplane.Name {
+			return true
+		}
+	}
+	if controlplane.Spec.DataPlaneSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(controlplane.Spec.DataPlaneSelector)
+		if err == nil && selector.Matches(labels.Set(dataplane.Labels)) {
+			return true
+		}
+	}
+	return false
+}
@@ -4,18 +4,25 @@ import (
 	"context"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	"github.com/kong/gateway-operator/internal/consts"
 	dataplaneutils "github.com/kong/gateway-operator/internal/utils/dataplane"
+	"github.com/kong/gateway-operator/internal/utils/image"
 	k8sutils "github.com/kong/gateway-operator/internal/utils/kubernetes"
 	dataplanevalidation "github.com/kong/gateway-operator/internal/validation/dataplane"
+	"github.com/kong/gateway-operator/internal/versions"
 )
 
 // -----------------------------------------------------------------------------
@@ -29,12 +36,35 @@ type DataPlaneReconciler struct {
 	eventRecorder            record.EventRecorder
 	ClusterCASecretName      string
 	ClusterCASecretNamespace string
+
+	// VersionCatalog loads the version catalog used by AutomaticUpgrades.
+	// The embedded catalog is used when nil.
+	VersionCatalog *versions.CatalogLoader
+
+	// ImagePolicy is the operator-level policy of the container images: it
+	// rewrites the default images to a mirror registry and sets the
+	// imagePullSecrets of the generated resources.
+	ImagePolicy *image.Policy
+
+	// adminAPICertificate is the mTLS client certificate of the operator for
+	// the Kong Admin API of the DataPlanes.
+	adminAPICertificate adminAPIClientCertificate
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *DataPlaneReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.eventRecorder = mgr.GetEventRecorderFor("dataplane")
 
+	for index, indexer := range map[string]client.IndexerFunc{
+		dataplaneClusterControlPlaneIndex: indexDataPlaneOnClusterControlPlane,
+		dataplaneSecretsIndex:             indexDataPlaneOnSecrets,
+		dataplaneConfigMapsIndex:          indexDataPlaneOnConfigMaps,
+	} {
+		if err := mgr.GetFieldIndexer().IndexField(context.Background(), &operatorv1alpha1.DataPlane{}, index, indexer); err != nil {
+			return err
+		}
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		// watch Dataplane objects
 		For(&operatorv1alpha1.DataPlane{}).
@@ -44,6 +74,22 @@ func (r *DataPlaneReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&corev1.Service{}).
 		// watch for changes in Deployments created by the dataplane controller
 		Owns(&appsv1.Deployment{}).
+		// watch for changes in Jobs created by the dataplane controller
+		Owns(&batchv1.Job{}).
+		// watch for changes in the hybrid mode control planes of the dataplanes
+		Watches(
+			&source.Kind{Type: &operatorv1alpha1.DataPlane{}},
+			handler.EnqueueRequestsFromMapFunc(r.getDataPlanesForClusterControlPlane)).
+		// watch for changes in the Secrets holding the licenses or the
+		// declarative configuration of the dataplanes
+		Watches(
+			&source.Kind{Type: &corev1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(r.getDataPlanesForSecret)).
+		// watch for changes in the ConfigMaps holding the declarative
+		// configuration of the dataplanes
+		Watches(
+			&source.Kind{Type: &corev1.ConfigMap{}},
+			handler.EnqueueRequestsFromMapFunc(r.getDataPlanesForConfigMap)).
 		Complete(r)
 }
 
@@ -64,6 +110,11 @@ func (r *DataPlaneReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, err
 	}
 
+	if restore, ok := dataplane.Annotations[consts.GatewayRestoreInProgressAnnotation]; ok {
+		debug(log, "DataPlane is being restored by GatewayRestore "+restore+", nothing to do", dataplane)
+		return ctrl.Result{}, nil // requeue will be triggered by the removal of the annotation
+	}
+
 	k8sutils.InitReady(dataplane)
 
 	debug(log, "validating DataPlane resource conditions", dataplane)
@@ -93,22 +144,17 @@ func (r *DataPlaneReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, nil // no need to requeue, the update will trigger.
 	}
 
-	debug(log, "validating DataPlane configuration", dataplane)
+	debug(log, "setting the DataPlane defaults", dataplane)
+	// the defaults are stored by the mutating admission webhook, they are only
+	// set in memory here for the DataPlanes stored while it was not running,
+	// without updating the DataPlane to avoid conflicting with its users.
 	if len(dataplane.Spec.Env) == 0 && len(dataplane.Spec.EnvFrom) == 0 {
-		debug(log, "no ENV config found for DataPlane resource, setting defaults", dataplane)
 		dataplaneutils.SetDataPlaneDefaults(&dataplane.Spec.DataPlaneDeploymentOptions)
-		if err := r.Client.Update(ctx, dataplane); err != nil {
-			if k8serrors.IsConflict(err) {
-				debug(log, "conflict found when updating DataPlane resource, retrying", dataplane)
-				return ctrl.Result{Requeue: true, RequeueAfter: requeueWithoutBackoff}, nil
-			}
-			return ctrl.Result{}, err
-		}
-		return ctrl.Result{}, nil // no need to requeue, the update will trigger.
 	}
 
+	debug(log, "validating DataPlane configuration", dataplane)
 	// validate dataplane
-	err = dataplanevalidation.NewValidator(r.Client).Validate(dataplane)
+	err = dataplanevalidation.NewValidator(r.Client).Validate(ctx, dataplane)
 	if err != nil {
 		info(log, "failed to validate dataplane: "+err.Error(), dataplane)
 		r.eventRecorder.Event(dataplane, "Warning", "ValidationFailed", err.Error())
@@ -117,6 +163,25 @@ func (r *DataPlaneReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, markErr
 	}
 
+	debug(log, "resolving the DataPlane version", dataplane)
+	updated, err := r.ensureDataPlaneVersionStatus(ctx, dataplane)
+	if err != nil {
+		r.eventRecorder.Event(dataplane, "Warning", "AutomaticUpgradeFailed", err.Error())
+		return ctrl.Result{}, err
+	}
+	if updated {
+		return ctrl.Result{}, nil // no need to requeue, the status update will trigger.
+	}
+
+	debug(log, "checking the license of DataPlane resource", dataplane)
+	updated, licenseRequeueAfter, err := r.ensureDataPlaneLicenseStatus(ctx, dataplane)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if updated {
+		return ctrl.Result{}, nil // no need to requeue, the status update will trigger.
+	}
+
 	debug(log, "ensuring mTLS certificate", dataplane)
 	createdOrUpdated, certSecret, err := r.ensureCertificate(ctx, dataplane, dataplaneService.Name)
 	if err != nil {
@@ -126,21 +191,116 @@ func (r *DataPlaneReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, nil // requeue will be triggered by the creation or update of the owned object
 	}
 
-	debug(log, "looking for existing deployments for DataPlane resource", dataplane)
-	createdOrUpdated, dataplaneDeployment, err := r.ensureDeploymentForDataPlane(ctx, dataplane, certSecret.Name)
+	debug(log, "configuring the DataPlane role in the Kong cluster", dataplane)
+	ready, clusterEnv, err := r.ensureClusterRoleForDataPlane(ctx, dataplane)
+	if err != nil || !ready {
+		// requeue will be triggered by the creation of the owned Service, or by
+		// the update of the control plane.
+		return ctrl.Result{}, err
+	}
+
+	if dataplane.Spec.Database != nil {
+		debug(log, "migrating the database of DataPlane resource before rolling it out", dataplane)
+		migrated, err := r.ensureDatabaseMigratedForRollout(ctx, dataplane, certSecret.Name, clusterEnv)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !migrated {
+			debug(log, "database migrations for DataPlane not yet complete, waiting", dataplane)
+			return ctrl.Result{}, nil // requeue will be triggered by the update of the owned Job
+		}
+	} else if err := r.ensureDatabaseMigrationsRemoved(ctx, dataplane); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var (
+		dataplaneDeployment *appsv1.Deployment
+		rolloutStatus       *operatorv1alpha1.DataPlaneRolloutStatus
+	)
+	switch {
+	case dataplaneUsesBlueGreenRollout(dataplane):
+		debug(log, "rolling out deployments for DataPlane resource using the blue/green strategy", dataplane)
+		createdOrUpdated, dataplaneDeployment, rolloutStatus, err = r.ensureBlueGreenDeploymentForDataPlane(ctx, dataplane, dataplaneService, certSecret.Name, clusterEnv)
+	case dataplaneUsesCanaryRollout(dataplane):
+		debug(log, "rolling out deployments for DataPlane resource using the canary strategy", dataplane)
+		createdOrUpdated, dataplaneDeployment, rolloutStatus, err = r.ensureCanaryDeploymentForDataPlane(ctx, dataplane, dataplaneService, certSecret.Name, clusterEnv)
+	default:
+		debug(log, "looking for existing deployments for DataPlane resource", dataplane)
+		createdOrUpdated, dataplaneDeployment, err = r.ensureDeploymentForDataPlane(ctx, dataplane, dataplaneService, certSecret.Name, clusterEnv)
+	}
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+	if err := r.ensureDataPlaneRolloutStatus(ctx, dataplane, rolloutStatus); err != nil {
+		return ctrl.Result{}, err
+	}
 	if createdOrUpdated {
 		return ctrl.Result{}, nil // requeue will be triggered by the creation or update of the owned object
 	}
 
-	// TODO: updates need to update owned deployment https://github.com/Kong/gateway-operator/issues/27
+	debug(log, "reporting the state of the DataPlane deployment", dataplane)
+	if err := r.ensureDataPlaneDeploymentStatus(ctx, dataplane, dataplaneDeployment, dataplaneService, certSecret); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var result ctrl.Result
+	if dataplane.Spec.AutomaticUpgrades != nil {
+		// neither new versions in the catalog nor maintenance windows trigger a requeue.
+		result.RequeueAfter = automaticUpgradesInterval
+	}
+	if dataplaneUsesCanaryRollout(dataplane) && rolloutStatus != nil &&
+		(rolloutStatus.Phase == operatorv1alpha1.DataPlaneRolloutPhaseProgressing ||
+			rolloutStatus.Phase == operatorv1alpha1.DataPlaneRolloutPhaseAwaitingPromotion) {
+		// the canary steps are timed and analyzed using metrics, none of which triggers a requeue.
+		result.RequeueAfter = canaryAnalysisInterval
+	}
+
+	if licenseRequeueAfter > 0 && (result.RequeueAfter == 0 || licenseRequeueAfter < result.RequeueAfter) {
+		// the license is checked again when it's about to expire.
+		result.RequeueAfter = licenseRequeueAfter
+	}
 
 	debug(log, "checking readiness of DataPlane deployments", dataplane)
-	if dataplaneDeployment.Status.Replicas == 0 || dataplaneDeployment.Status.AvailableReplicas < dataplaneDeployment.Status.Replicas {
+	if !deploymentIsReady(dataplaneDeployment) {
 		debug(log, "deployment for DataPlane not yet ready, waiting", dataplane)
-		return ctrl.Result{}, nil // no need to requeue, the update will trigger.
+		return result, nil // no need to requeue, the update will trigger.
+	}
+
+	debug(log, "loading the declarative configuration of DataPlane resource", dataplane)
+	if err := r.ensureDeclarativeConfigLoaded(ctx, dataplane); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if dataplane.Spec.Database != nil {
+		debug(log, "finishing the database migrations of DataPlane resource", dataplane)
+		if err := r.ensureDatabaseMigrationsFinished(ctx, dataplane, certSecret.Name, clusterEnv); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	debug(log, "checking the health of the DataPlane pods", dataplane)
+	updated, err = r.ensureDataPlanePodsStatus(ctx, dataplane)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if updated {
+		return ctrl.Result{}, nil // requeue will be triggered by the status update
+	}
+	// the status endpoint of the pods doesn't trigger a requeue.
+	if result.RequeueAfter == 0 || dataplaneHealthCheckInterval < result.RequeueAfter {
+		result.RequeueAfter = dataplaneHealthCheckInterval
+	}
+	if !r.ensureConfigurationLoadedCondition(dataplane) {
+		debug(log, "configuration of DataPlane not yet loaded, waiting", dataplane)
+		condition, _ := k8sutils.GetCondition(DataPlaneConditionTypeConfigurationLoaded, dataplane)
+		k8sutils.SetCondition(k8sutils.NewCondition(
+			DataPlaneConditionTypeProvisioned,
+			metav1.ConditionFalse,
+			k8sutils.ConditionReason(condition.Reason),
+			condition.Message,
+		), dataplane)
+		k8sutils.SetReady(dataplane)
+		return result, r.updateStatus(ctx, dataplane)
 	}
 
 	r.ensureIsMarkedProvisioned(dataplane)
@@ -156,7 +316,7 @@ func (r *DataPlaneReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	}
 
 	debug(log, "reconciliation complete for DataPlane resource", dataplane)
-	return ctrl.Result{}, err
+	return result, err
 }
 
 // updateStatus Updates the resource status only when there are changes in the Conditions
@@ -14,6 +14,26 @@ const (
 	// not all Deployments (or Daemonsets) for the DataPlane have been provisioned
 	// successfully.
 	DataPlaneConditionTypeProvisioned k8sutils.ConditionType = "Provisioned"
+
+	// DataPlaneConditionTypeDatabaseMigrated is a condition type indicating
+	// whether or not the database of the DataPlane has been migrated to the
+	// version of its container image. It's only set for the DataPlanes using a
+	// database.
+	DataPlaneConditionTypeDatabaseMigrated k8sutils.ConditionType = "DatabaseMigrated"
+
+	// DataPlaneConditionTypeLicenseValid is a condition type indicating whether
+	// or not the Kong Enterprise license of the DataPlane is valid. It's only
+	// set for the DataPlanes with a license, and warns about its upcoming
+	// expiration.
+	DataPlaneConditionTypeLicenseValid k8sutils.ConditionType = "LicenseValid"
+
+	// DataPlaneConditionTypeConfigurationLoaded is a condition type indicating
+	// whether or not all the ready DataPlane Pods have loaded their Kong
+	// configuration, as reported by Kong on their status endpoint. Only the
+	// DataPlanes configured by the operator, through a declarative
+	// configuration or a database, wait for it to be provisioned: the others
+	// are configured by a ControlPlane once they are provisioned.
+	DataPlaneConditionTypeConfigurationLoaded k8sutils.ConditionType = "ConfigurationLoaded"
 )
 
 // -----------------------------------------------------------------------------
@@ -35,4 +55,67 @@ const (
 	// DataPlaneConditionValidationFailed is a reason which indicates validation of
 	// a dataplane is failed.
 	DataPlaneConditionValidationFailed k8sutils.ConditionReason = "ValidationFailed"
+
+	// DataPlaneConditionReasonWaitingForClusterControlPlane is a reason which
+	// indicates a hybrid mode data plane waits for its control plane to be
+	// provisioned.
+	DataPlaneConditionReasonWaitingForClusterControlPlane k8sutils.ConditionReason = "WaitingForClusterControlPlane"
+
+	// DataPlaneConditionReasonMigrationsRunning is a reason which indicates the
+	// database migrations of a DataPlane are running.
+	DataPlaneConditionReasonMigrationsRunning k8sutils.ConditionReason = "MigrationsRunning"
+
+	// DataPlaneConditionReasonMigrationsFailed is a reason which indicates the
+	// database migrations of a DataPlane have failed. They are run again once
+	// the failed Job is deleted.
+	DataPlaneConditionReasonMigrationsFailed k8sutils.ConditionReason = "MigrationsFailed"
+
+	// DataPlaneConditionReasonMigrationsAwaitingRollout is a reason which
+	// indicates the pending database migrations of a DataPlane are finished
+	// once all its Pods run the new container image.
+	DataPlaneConditionReasonMigrationsAwaitingRollout k8sutils.ConditionReason = "MigrationsAwaitingRollout"
+
+	// DataPlaneConditionReasonMigrationsComplete is a reason which indicates the
+	// database of a DataPlane is migrated to the version of its container image.
+	DataPlaneConditionReasonMigrationsComplete k8sutils.ConditionReason = "MigrationsComplete"
+
+	// DataPlaneConditionReasonLicenseValid is a reason which indicates the Kong
+	// Enterprise license of a DataPlane is valid.
+	DataPlaneConditionReasonLicenseValid k8sutils.ConditionReason = "LicenseValid"
+
+	// DataPlaneConditionReasonLicenseExpiringSoon is a reason which indicates
+	// the Kong Enterprise license of a DataPlane is still valid but expires
+	// soon, and must be renewed.
+	DataPlaneConditionReasonLicenseExpiringSoon k8sutils.ConditionReason = "LicenseExpiringSoon"
+
+	// DataPlaneConditionReasonLicenseExpired is a reason which indicates the
+	// Kong Enterprise license of a DataPlane is expired.
+	DataPlaneConditionReasonLicenseExpired k8sutils.ConditionReason = "LicenseExpired"
+
+	// DataPlaneConditionReasonLicenseNotFound is a reason which indicates the
+	// Secret holding the Kong Enterprise license of a DataPlane doesn't exist.
+	DataPlaneConditionReasonLicenseNotFound k8sutils.ConditionReason = "LicenseNotFound"
+
+	// DataPlaneConditionReasonLicenseInvalid is a reason which indicates the
+	// Secret holding the Kong Enterprise license of a DataPlane doesn't hold
+	// a valid license.
+	DataPlaneConditionReasonLicenseInvalid k8sutils.ConditionReason = "LicenseInvalid"
+
+	// DataPlaneConditionReasonConfigurationLoaded is a reason which indicates
+	// all the ready DataPlane Pods have loaded their Kong configuration.
+	DataPlaneConditionReasonConfigurationLoaded k8sutils.ConditionReason = "ConfigurationLoaded"
+
+	// DataPlaneConditionReasonConfigurationNotLoaded is a reason which
+	// indicates some ready DataPlane Pods haven't loaded their Kong
+	// configuration yet.
+	DataPlaneConditionReasonConfigurationNotLoaded k8sutils.ConditionReason = "ConfigurationNotLoaded"
+
+	// DataPlaneConditionReasonDatabaseUnreachable is a reason which indicates
+	// some ready DataPlane Pods can't reach their database.
+	DataPlaneConditionReasonDatabaseUnreachable k8sutils.ConditionReason = "DatabaseUnreachable"
+
+	// DataPlaneConditionReasonPodStatusUnavailable is a reason which indicates
+	// the health of some ready DataPlane Pods couldn't be read from their
+	// status endpoint.
+	DataPlaneConditionReasonPodStatusUnavailable k8sutils.ConditionReason = "PodStatusUnavailable"
 )
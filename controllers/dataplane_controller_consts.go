@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+// DataPlane - Rollouts
+// -----------------------------------------------------------------------------
+
+const (
+	// defaultCanaryProgressDeadline is the maximum time a canary step can take
+	// when the canary strategy doesn't set it.
+	defaultCanaryProgressDeadline = 10 * time.Minute
+
+	// canaryAnalysisInterval is the interval the canary Pods are checked at
+	// while a canary rollout is in progress.
+	canaryAnalysisInterval = 15 * time.Second
+)
+
+// dataplaneMetricsHTTPClient is the HTTP client used to fetch the metrics of
+// the DataPlane Pods.
+var dataplaneMetricsHTTPClient = &http.Client{Timeout: 5 * time.Second}
+
+// -----------------------------------------------------------------------------
+// DataPlane - Database Migrations
+// -----------------------------------------------------------------------------
+
+// dataplaneMigrationsPhase is a phase of the database migrations of a DataPlane,
+// each one being run by a Job.
+type dataplaneMigrationsPhase string
+
+const (
+	// dataplaneMigrationsPhaseBootstrap bootstraps the database and runs all
+	// the migrations, before the first Deployment of a DataPlane is created.
+	dataplaneMigrationsPhaseBootstrap dataplaneMigrationsPhase = "bootstrap"
+
+	// dataplaneMigrationsPhaseUp runs the migrations which are compatible with
+	// the Pods still running the previous container image, before a DataPlane
+	// is rolled out to a new container image.
+	dataplaneMigrationsPhaseUp dataplaneMigrationsPhase = "up"
+
+	// dataplaneMigrationsPhaseFinish runs the remaining migrations, once all
+	// the Pods of a DataPlane run the new container image.
+	dataplaneMigrationsPhaseFinish dataplaneMigrationsPhase = "finish"
+
+	// migrationsJobBackoffLimit is the number of retries of the migrations Jobs
+	// before they are considered failed.
+	migrationsJobBackoffLimit = 3
+)
+
+// dataplaneMigrationsCommands are the commands run by the migrations Jobs of
+// each phase. Bootstrapping an already bootstrapped database is a no-op, hence
+// the pending migrations are run as well in case the DataPlane was recreated.
+var dataplaneMigrationsCommands = map[dataplaneMigrationsPhase][]string{
+	dataplaneMigrationsPhaseBootstrap: {"/bin/sh", "-c", "kong migrations bootstrap && kong migrations up && kong migrations finish"},
+	dataplaneMigrationsPhaseUp:        {"kong", "migrations", "up"},
+	dataplaneMigrationsPhaseFinish:    {"kong", "migrations", "finish"},
+}
+
+// -----------------------------------------------------------------------------
+// DataPlane - Kong Enterprise License
+// -----------------------------------------------------------------------------
+
+// licenseExpirationWarningPeriod is the period before the expiration of the
+// license of a DataPlane during which its LicenseValid condition warns about
+// the upcoming expiration.
+const licenseExpirationWarningPeriod = 30 * 24 * time.Hour
+
+// -----------------------------------------------------------------------------
+// DataPlane - Health
+// -----------------------------------------------------------------------------
+
+// dataplaneHealthCheckInterval is the interval the health of the DataPlane
+// Pods is checked at until they have loaded their Kong configuration, as
+// Kong reporting it doesn't trigger a requeue.
+const dataplaneHealthCheckInterval = 10 * time.Second
+
+// -----------------------------------------------------------------------------
+// DataPlane - Kong Admin API
+// -----------------------------------------------------------------------------
+
+const (
+	// adminAPIClientCertificateSubject is the subject of the mTLS client
+	// certificate issued to the operator for the Kong Admin API of the
+	// DataPlanes.
+	adminAPIClientCertificateSubject = "kong-gateway-operator"
+
+	// dataplaneAdminAPITimeout is the timeout of the requests of the operator
+	// to the Kong Admin API of the DataPlane Pods.
+	dataplaneAdminAPITimeout = 10 * time.Second
+)
+
+// -----------------------------------------------------------------------------
+// DataPlane - Field Indexes
+// -----------------------------------------------------------------------------
+
+const (
+	// dataplaneClusterControlPlaneIndex is the field index of the hybrid mode
+	// DataPlanes on the name of the DataPlane they connect to as control plane.
+	dataplaneClusterControlPlaneIndex = "spec.clusterControlPlane"
+
+	// dataplaneSecretsIndex is the field index of the DataPlanes on the names
+	// of the Secrets holding their license or declarative configuration.
+	dataplaneSecretsIndex = "spec.secrets"
+
+	// dataplaneConfigMapsIndex is the field index of the DataPlanes on the
+	// names of the ConfigMaps holding their declarative configuration.
+	dataplaneConfigMapsIndex = "spec.configMaps"
+)
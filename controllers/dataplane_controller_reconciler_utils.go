@@ -4,16 +4,23 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	certificatesv1 "k8s.io/api/certificates/v1"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
 	"github.com/kong/gateway-operator/internal/consts"
+	dataplaneutils "github.com/kong/gateway-operator/internal/utils/dataplane"
 	k8sutils "github.com/kong/gateway-operator/internal/utils/kubernetes"
 	k8sresources "github.com/kong/gateway-operator/internal/utils/kubernetes/resources"
+	"github.com/kong/gateway-operator/internal/versions"
 )
 
 // -----------------------------------------------------------------------------
@@ -60,13 +67,190 @@ func (r *DataPlaneReconciler) ensureDataPlaneServiceStatus(
 	return r.Status().Update(ctx, dataplane)
 }
 
+func (r *DataPlaneReconciler) ensureDataPlaneRolloutStatus(
+	ctx context.Context,
+	dataplane *operatorv1alpha1.DataPlane,
+	rolloutStatus *operatorv1alpha1.DataPlaneRolloutStatus,
+) error {
+	if reflect.DeepEqual(dataplane.Status.Rollout, rolloutStatus) {
+		return nil
+	}
+	dataplane.Status.Rollout = rolloutStatus
+	return r.Status().Update(ctx, dataplane)
+}
+
+// ensureDataPlaneDeploymentStatus records the state of the Deployment, the
+// Service and the mTLS certificate of the DataPlane in its status, so that
+// they don't have to be looked up.
+func (r *DataPlaneReconciler) ensureDataPlaneDeploymentStatus(
+	ctx context.Context,
+	dataplane *operatorv1alpha1.DataPlane,
+	deployment *appsv1.Deployment,
+	service *corev1.Service,
+	certSecret *corev1.Secret,
+) error {
+	certificateExpiry, err := certificateExpiry(certSecret, dataplane.Status.CertificateExpiry)
+	if err != nil {
+		return err
+	}
+
+	status := dataplane.Status.DeepCopy()
+	status.ObservedGeneration = dataplane.Generation
+	status.Deployment = newDeploymentStatus(deployment, consts.DataPlaneProxyContainerName, versions.KongVersionForDataPlane(dataplane))
+	status.Addresses = serviceAddresses(service)
+	status.CertificateExpiry = certificateExpiry
+
+	if reflect.DeepEqual(&dataplane.Status, status) {
+		return nil
+	}
+	dataplane.Status = *status
+	return r.Status().Update(ctx, dataplane)
+}
+
+// ensureDataPlaneVersionStatus resolves the version chosen by the DataPlane's
+// AutomaticUpgrades and records it in the DataPlane status. No new version is
+// chosen while a rollout is in progress. It returns true when the status was
+// updated.
+func (r *DataPlaneReconciler) ensureDataPlaneVersionStatus(
+	ctx context.Context,
+	dataplane *operatorv1alpha1.DataPlane,
+) (bool, error) {
+	var version string
+	if dataplane.Spec.AutomaticUpgrades != nil {
+		if rollout := dataplane.Status.Rollout; dataplane.Status.Version != "" && rollout != nil && rolloutIsInProgress(rollout.Phase) {
+			return false, nil
+		}
+		catalog, err := r.VersionCatalog.Load(ctx)
+		if err != nil {
+			return false, err
+		}
+		version, err = resolveAutomaticUpgradeVersion(&dataplane.Spec.DeploymentOptions,
+			catalog.Kong, dataplane.Status.Version, consts.DefaultDataPlaneTag, time.Now())
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if dataplane.Status.Version == version {
+		return false, nil
+	}
+	dataplane.Status.Version = version
+	return true, r.Status().Update(ctx, dataplane)
+}
+
+// ensureDataPlaneLicenseStatus checks the Kong Enterprise license of the
+// DataPlane, records its expiration and checksum in the DataPlane status, and
+// sets the LicenseValid condition. It returns true when the status was
+// updated, and the duration after which the license must be checked again, as
+// neither its upcoming expiration nor its expiration trigger a requeue.
+func (r *DataPlaneReconciler) ensureDataPlaneLicenseStatus(
+	ctx context.Context,
+	dataplane *operatorv1alpha1.DataPlane,
+) (bool, time.Duration, error) {
+	if dataplane.Spec.License == nil {
+		removed := k8sutils.RemoveCondition(DataPlaneConditionTypeLicenseValid, dataplane)
+		if !removed && dataplane.Status.License == nil {
+			return false, 0, nil
+		}
+		dataplane.Status.License = nil
+		return true, 0, r.Status().Update(ctx, dataplane)
+	}
+
+	// the license was checked by the validation of the DataPlane, but the
+	// Secret may have been deleted or updated since: the changes of the Secret
+	// trigger a requeue.
+	secret := &corev1.Secret{}
+	secretName := dataplane.Spec.License.SecretRef.Name
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: dataplane.Namespace, Name: secretName}, secret); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return false, 0, err
+		}
+		return r.ensureDataPlaneLicenseIsMarkedInvalid(ctx, dataplane, DataPlaneConditionReasonLicenseNotFound,
+			fmt.Sprintf("license secret %s not found", secretName))
+	}
+	data := secret.Data[consts.DataPlaneLicenseSecretKey]
+	license, err := dataplaneutils.ParseLicense(data)
+	if err != nil {
+		return r.ensureDataPlaneLicenseIsMarkedInvalid(ctx, dataplane, DataPlaneConditionReasonLicenseInvalid,
+			fmt.Sprintf("invalid license in secret %s: %v", secretName, err))
+	}
+
+	var (
+		condition    metav1.Condition
+		requeueAfter time.Duration
+		now          = time.Now()
+		warningTime  = license.ExpirationTime.Add(-licenseExpirationWarningPeriod)
+		message      = fmt.Sprintf("license expires at %s", license.ExpirationTime.Format(time.RFC3339))
+	)
+	switch {
+	case license.Expired(now):
+		condition = k8sutils.NewCondition(DataPlaneConditionTypeLicenseValid, metav1.ConditionFalse,
+			DataPlaneConditionReasonLicenseExpired,
+			fmt.Sprintf("license expired at %s", license.ExpirationTime.Format(time.RFC3339)))
+	case now.Before(warningTime):
+		condition = k8sutils.NewCondition(DataPlaneConditionTypeLicenseValid, metav1.ConditionTrue,
+			DataPlaneConditionReasonLicenseValid, message)
+		requeueAfter = warningTime.Sub(now)
+	default:
+		condition = k8sutils.NewCondition(DataPlaneConditionTypeLicenseValid, metav1.ConditionTrue,
+			DataPlaneConditionReasonLicenseExpiringSoon, message)
+		requeueAfter = license.ExpirationTime.Sub(now)
+	}
+
+	condition.ObservedGeneration = dataplane.Generation
+	expirationTime := metav1.NewTime(license.ExpirationTime)
+	checksum := computeChecksum(data)
+	current, present := k8sutils.GetCondition(DataPlaneConditionTypeLicenseValid, dataplane)
+	if present && isSameDataPlaneCondition(current, condition) &&
+		dataplane.Status.License != nil &&
+		dataplane.Status.License.Checksum == checksum &&
+		dataplane.Status.License.ExpirationTime.Equal(&expirationTime) {
+		return false, requeueAfter, nil
+	}
+
+	if condition.Reason != current.Reason && condition.Reason != string(DataPlaneConditionReasonLicenseValid) {
+		r.eventRecorder.Event(dataplane, "Warning", condition.Reason, condition.Message)
+	}
+	k8sutils.SetCondition(condition, dataplane)
+	dataplane.Status.License = &operatorv1alpha1.DataPlaneLicenseStatus{
+		ExpirationTime: &expirationTime,
+		Checksum:       checksum,
+	}
+	return true, requeueAfter, r.Status().Update(ctx, dataplane)
+}
+
+// ensureDataPlaneLicenseIsMarkedInvalid sets the LicenseValid condition of a
+// DataPlane which license can't be read to false, and removes the status of
+// the previous license.
+func (r *DataPlaneReconciler) ensureDataPlaneLicenseIsMarkedInvalid(
+	ctx context.Context,
+	dataplane *operatorv1alpha1.DataPlane,
+	reason k8sutils.ConditionReason,
+	message string,
+) (bool, time.Duration, error) {
+	condition := k8sutils.NewCondition(DataPlaneConditionTypeLicenseValid, metav1.ConditionFalse, reason, message)
+	condition.ObservedGeneration = dataplane.Generation
+	current, present := k8sutils.GetCondition(DataPlaneConditionTypeLicenseValid, dataplane)
+	if present && isSameDataPlaneCondition(current, condition) && dataplane.Status.License == nil {
+		return false, 0, nil
+	}
+
+	if condition.Reason != current.Reason {
+		r.eventRecorder.Event(dataplane, "Warning", condition.Reason, condition.Message)
+	}
+	k8sutils.SetCondition(condition, dataplane)
+	dataplane.Status.License = nil
+	return true, 0, r.Status().Update(ctx, dataplane)
+}
+
 // isSameDataPlaneCondition returns true if two `metav1.Condition`s
-// indicates the same condition of a `DataPlane` resource.
+// indicates the same condition of the same generation of a `DataPlane` resource.
 func isSameDataPlaneCondition(condition1, condition2 metav1.Condition) bool {
 	return condition1.Type == condition2.Type &&
 		condition1.Status == condition2.Status &&
 		condition1.Reason == condition2.Reason &&
-		condition1.Message == condition2.Message
+		condition1.Message == condition2.Message &&
+		condition1.ObservedGeneration == condition2.ObservedGeneration
 }
 
 func (r *DataPlaneReconciler) ensureDataPlaneIsMarkedNotProvisioned(
@@ -118,9 +302,12 @@ func (r *DataPlaneReconciler) ensureCertificate(
 	dataplane *operatorv1alpha1.DataPlane,
 	serviceName string,
 ) (bool, *corev1.Secret, error) {
+	// the client authentication is used by the Kong hybrid mode data planes to
+	// connect to their control plane.
 	usages := []certificatesv1.KeyUsage{
 		certificatesv1.UsageKeyEncipherment,
 		certificatesv1.UsageDigitalSignature, certificatesv1.UsageServerAuth,
+		certificatesv1.UsageClientAuth,
 	}
 	return maybeCreateCertificateSecret(ctx,
 		dataplane,
@@ -134,7 +321,9 @@ func (r *DataPlaneReconciler) ensureCertificate(
 func (r *DataPlaneReconciler) ensureDeploymentForDataPlane(
 	ctx context.Context,
 	dataplane *operatorv1alpha1.DataPlane,
+	dataplaneService *corev1.Service,
 	certSecretName string,
+	clusterEnv []corev1.EnvVar,
 ) (createdOrUpdate bool, deploy *appsv1.Deployment, err error) {
 	deployments, err := k8sutils.ListDeploymentsForOwner(
 		ctx,
@@ -153,7 +342,19 @@ func (r *DataPlaneReconciler) ensureDeploymentForDataPlane(
 		return false, nil, fmt.Errorf("found %d deployments for DataPlane currently unsupported: expected 1 or less", count)
 	}
 
-	generatedDeployment := generateNewDeploymentForDataPlane(dataplane, certSecretName)
+	// the leftovers of a rollout are removed if the rollout strategy was unset
+	deleted, err := r.deletePreviewResourcesForDataPlane(ctx, dataplane)
+	if err != nil || deleted {
+		return deleted, nil, err
+	}
+	// the DataPlane Service routes the traffic to all the Pods of the DataPlane
+	// again, rather than to the ones of the Pod template hash of a rollout.
+	selectorUpdated, err := r.ensureServiceSelector(ctx, dataplaneService, generateNewServiceForDataplane(dataplane).Spec.Selector)
+	if err != nil || selectorUpdated {
+		return selectorUpdated, nil, err
+	}
+
+	generatedDeployment := generateNewDeploymentForDataPlane(dataplane, certSecretName, clusterEnv, r.ImagePolicy)
 	k8sutils.SetOwnerForObject(generatedDeployment, dataplane)
 	addLabelForDataplane(generatedDeployment)
 
@@ -173,13 +374,43 @@ func (r *DataPlaneReconciler) ensureDeploymentForDataPlane(
 			updated = true
 			container = k8sresources.GetPodContainerByName(&existingDeployment.Spec.Template.Spec, consts.DataPlaneProxyContainerName)
 		}
-		if !reflect.DeepEqual(container.Env, dataplane.Spec.Env) {
-			container.Env = dataplane.Spec.Env
+		generatedContainer := k8sresources.GetPodContainerByName(&generatedDeployment.Spec.Template.Spec, consts.DataPlaneProxyContainerName)
+		if container.Image != generatedContainer.Image {
+			container.Image = generatedContainer.Image
+			updated = true
+		}
+
+		if !reflect.DeepEqual(existingDeployment.Spec.Template.Spec.ImagePullSecrets, generatedDeployment.Spec.Template.Spec.ImagePullSecrets) {
+			existingDeployment.Spec.Template.Spec.ImagePullSecrets = generatedDeployment.Spec.Template.Spec.ImagePullSecrets
+			updated = true
+		}
+
+		if !reflect.DeepEqual(container.Env, generatedContainer.Env) {
+			container.Env = generatedContainer.Env
+			updated = true
+		}
+
+		if !reflect.DeepEqual(container.EnvFrom, generatedContainer.EnvFrom) {
+			container.EnvFrom = generatedContainer.EnvFrom
 			updated = true
 		}
 
-		if !reflect.DeepEqual(container.EnvFrom, dataplane.Spec.EnvFrom) {
-			container.EnvFrom = dataplane.Spec.EnvFrom
+		if ensurePodVolumeIsUpdated(&existingDeployment.Spec.Template.Spec, &generatedDeployment.Spec.Template.Spec,
+			consts.DataPlaneDeclarativeConfigVolumeName) {
+			updated = true
+		}
+
+		// the Pods are rolled when the license they run with changes.
+		checksum := generatedDeployment.Spec.Template.Annotations[consts.DataPlaneLicenseChecksumAnnotation]
+		if existingDeployment.Spec.Template.Annotations[consts.DataPlaneLicenseChecksumAnnotation] != checksum {
+			if checksum == "" {
+				delete(existingDeployment.Spec.Template.Annotations, consts.DataPlaneLicenseChecksumAnnotation)
+			} else {
+				if existingDeployment.Spec.Template.Annotations == nil {
+					existingDeployment.Spec.Template.Annotations = make(map[string]string)
+				}
+				existingDeployment.Spec.Template.Annotations[consts.DataPlaneLicenseChecksumAnnotation] = checksum
+			}
 			updated = true
 		}
 
@@ -229,3 +460,1081 @@ func (r *DataPlaneReconciler) ensureServiceForDataPlane(
 
 	return true, generatedService, r.Client.Create(ctx, generatedService)
 }
+
+// -----------------------------------------------------------------------------
+// DataPlaneReconciler - Hybrid Mode
+// -----------------------------------------------------------------------------
+
+// ensureClusterRoleForDataPlane configures the role of a DataPlane in a Kong
+// cluster. The cluster listener of a hybrid mode control plane is exposed by a
+// cluster Service, which the data planes connect to. It returns whether the
+// DataPlane is ready to be deployed, along with the environment variables
+// configuring its role. They are only set on its Deployments, never on the
+// DataPlane, as they depend on the Services of the control plane.
+func (r *DataPlaneReconciler) ensureClusterRoleForDataPlane(
+	ctx context.Context,
+	dataplane *operatorv1alpha1.DataPlane,
+) (bool, []corev1.EnvVar, error) {
+	var clusterControlPlane, clusterServerName string
+	switch dataplane.Spec.Role {
+	case operatorv1alpha1.DataPlaneRoleControlPlane:
+		created, clusterService, err := r.ensureClusterServiceForDataPlane(ctx, dataplane)
+		if err != nil || created {
+			return false, nil, err
+		}
+		if dataplane.Status.ClusterService != clusterService.Name {
+			dataplane.Status.ClusterService = clusterService.Name
+			return false, nil, r.Status().Update(ctx, dataplane)
+		}
+	case operatorv1alpha1.DataPlaneRoleDataPlane:
+		controlplane := &operatorv1alpha1.DataPlane{}
+		err := r.Client.Get(ctx, client.ObjectKey{Namespace: dataplane.Namespace, Name: dataplane.Spec.ClusterControlPlane}, controlplane)
+		if client.IgnoreNotFound(err) != nil {
+			return false, nil, err
+		}
+		if err != nil || controlplane.Spec.Role != operatorv1alpha1.DataPlaneRoleControlPlane ||
+			controlplane.Status.ClusterService == "" || controlplane.Status.Service == "" {
+			return false, nil, r.ensureDataPlaneIsMarkedNotProvisioned(ctx, dataplane,
+				DataPlaneConditionReasonWaitingForClusterControlPlane,
+				fmt.Sprintf("waiting for the hybrid mode control plane %s to be provisioned", dataplane.Spec.ClusterControlPlane))
+		}
+		clusterControlPlane = fmt.Sprintf("%s.%s.svc:%d", controlplane.Status.ClusterService, controlplane.Namespace, dataplaneutils.DefaultKongClusterPort)
+		// the certificate of the control plane is issued for its DataPlane Service.
+		clusterServerName = fmt.Sprintf("%s.%s.svc", controlplane.Status.Service, controlplane.Namespace)
+	default:
+		if err := r.deleteClusterServicesForDataPlane(ctx, dataplane); err != nil {
+			return false, nil, err
+		}
+		if dataplane.Status.ClusterService != "" {
+			dataplane.Status.ClusterService = ""
+			return false, nil, r.Status().Update(ctx, dataplane)
+		}
+		return true, nil, nil
+	}
+
+	return true, dataplaneutils.HybridEnvVars(&dataplane.Spec.DataPlaneDeploymentOptions, clusterControlPlane, clusterServerName), nil
+}
+
+// ensureClusterServiceForDataPlane ensures that the cluster Service of a
+// DataPlane running a Kong hybrid mode control plane exists.
+func (r *DataPlaneReconciler) ensureClusterServiceForDataPlane(
+	ctx context.Context,
+	dataplane *operatorv1alpha1.DataPlane,
+) (created bool, svc *corev1.Service, err error) {
+	services, err := k8sutils.ListServicesForOwner(
+		ctx,
+		r.Client,
+		consts.DataPlaneServiceTypeLabel,
+		consts.DataPlaneServiceTypeClusterLabelValue,
+		dataplane.Namespace,
+		dataplane.UID,
+	)
+	if err != nil {
+		return false, nil, err
+	}
+
+	count := len(services)
+	if count > 1 {
+		return false, nil, fmt.Errorf("found %d cluster services for DataPlane currently unsupported: expected 1 or less", count)
+	}
+	if count == 1 {
+		return false, &services[0], nil
+	}
+
+	generatedService := generateClusterServiceForDataPlane(dataplane)
+	k8sutils.SetOwnerForObject(generatedService, dataplane)
+	return true, generatedService, r.Client.Create(ctx, generatedService)
+}
+
+// deleteClusterServicesForDataPlane deletes the cluster Service of a DataPlane
+// which isn't a Kong hybrid mode control plane anymore.
+func (r *DataPlaneReconciler) deleteClusterServicesForDataPlane(
+	ctx context.Context,
+	dataplane *operatorv1alpha1.DataPlane,
+) error {
+	services, err := k8sutils.ListServicesForOwner(
+		ctx,
+		r.Client,
+		consts.DataPlaneServiceTypeLabel,
+		consts.DataPlaneServiceTypeClusterLabelValue,
+		dataplane.Namespace,
+		dataplane.UID,
+	)
+	if err != nil {
+		return err
+	}
+	for i := range services {
+		if err := r.Client.Delete(ctx, &services[i]); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// DataPlaneReconciler - Declarative Configuration
+// -----------------------------------------------------------------------------
+
+// ensureDeclarativeConfigLoaded hot-reloads the declarative Kong configuration
+// of a DataPlane in its ready Pods when it changes, and records its checksum in
+// the DataPlane status. The Pods which aren't ready yet load it from the file
+// mounted in them on startup.
+func (r *DataPlaneReconciler) ensureDeclarativeConfigLoaded(
+	ctx context.Context,
+	dataplane *operatorv1alpha1.DataPlane,
+) error {
+	if dataplane.Spec.DeclarativeConfig == nil {
+		if dataplane.Status.DeclarativeConfigChecksum == "" {
+			return nil
+		}
+		dataplane.Status.DeclarativeConfigChecksum = ""
+		return r.Status().Update(ctx, dataplane)
+	}
+
+	config, err := r.getDeclarativeConfigForDataPlane(ctx, dataplane)
+	if err != nil {
+		return err
+	}
+	checksum := computeChecksum(config)
+	if dataplane.Status.DeclarativeConfigChecksum == checksum {
+		return nil
+	}
+
+	pods, err := getReadyDataPlanePods(ctx, r.Client, dataplane)
+	if err != nil {
+		return err
+	}
+
+	if len(pods) > 0 {
+		httpClient, err := r.adminAPICertificate.newClientForDataPlane(ctx, r.Client, r.ClusterCASecretNamespace, r.ClusterCASecretName, dataplane)
+		if err != nil {
+			return err
+		}
+		for _, pod := range pods {
+			if err := postDataPlanePodDeclarativeConfig(ctx, httpClient, pod, config); err != nil {
+				err = fmt.Errorf("failed to reload the declarative config of pod %s: %w", pod.Name, err)
+				r.eventRecorder.Event(dataplane, "Warning", "DeclarativeConfigReloadFailed", err.Error())
+				return err
+			}
+		}
+	}
+
+	dataplane.Status.DeclarativeConfigChecksum = checksum
+	return r.Status().Update(ctx, dataplane)
+}
+
+// getDeclarativeConfigForDataPlane returns the declarative Kong configuration
+// of a DataPlane from its source.
+func (r *DataPlaneReconciler) getDeclarativeConfigForDataPlane(
+	ctx context.Context,
+	dataplane *operatorv1alpha1.DataPlane,
+) ([]byte, error) {
+	source := dataplane.Spec.DeclarativeConfig
+	switch {
+	case source.ConfigMapKeyRef != nil:
+		cm := &corev1.ConfigMap{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: dataplane.Namespace, Name: source.ConfigMapKeyRef.Name}, cm); err != nil {
+			return nil, err
+		}
+		return []byte(cm.Data[source.ConfigMapKeyRef.Key]), nil
+	case source.SecretKeyRef != nil:
+		secret := &corev1.Secret{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: dataplane.Namespace, Name: source.SecretKeyRef.Name}, secret); err != nil {
+			return nil, err
+		}
+		return secret.Data[source.SecretKeyRef.Key], nil
+	}
+	return nil, fmt.Errorf("no source of the declarative config set for DataPlane %s", dataplane.Name)
+}
+
+// -----------------------------------------------------------------------------
+// DataPlaneReconciler - Health
+// -----------------------------------------------------------------------------
+
+// ensureDataPlanePodsStatus reads the health reported by Kong on the status
+// endpoint of the ready DataPlane Pods, and records it in the DataPlane status.
+// It returns true if the status was updated.
+func (r *DataPlaneReconciler) ensureDataPlanePodsStatus(
+	ctx context.Context,
+	dataplane *operatorv1alpha1.DataPlane,
+) (bool, error) {
+	pods, err := getReadyDataPlanePods(ctx, r.Client, dataplane)
+	if err != nil {
+		return false, err
+	}
+
+	var podsStatus []operatorv1alpha1.DataPlanePodStatus
+	for _, pod := range pods {
+		podStatus := operatorv1alpha1.DataPlanePodStatus{Name: pod.Name}
+		kongStatus, err := fetchDataPlanePodKongStatus(ctx, pod)
+		if err != nil {
+			podStatus.Error = err.Error()
+		} else {
+			podStatus.ConfigurationHash = kongStatus.ConfigurationHash
+			podStatus.DatabaseReachable = kongStatus.DatabaseReachable
+			podStatus.Workers = int32(kongStatus.Workers)
+		}
+		podsStatus = append(podsStatus, podStatus)
+	}
+	sort.Slice(podsStatus, func(i, j int) bool { return podsStatus[i].Name < podsStatus[j].Name })
+
+	if reflect.DeepEqual(dataplane.Status.Pods, podsStatus) {
+		return false, nil
+	}
+	dataplane.Status.Pods = podsStatus
+	return true, r.Client.Status().Update(ctx, dataplane)
+}
+
+// ensureConfigurationLoadedCondition sets the ConfigurationLoaded condition of
+// the DataPlane from the health of its Pods recorded in its status, and
+// returns whether the DataPlane can be marked as provisioned. The condition is
+// set for every DataPlane, but only the DataPlanes configured by the operator
+// wait for their Pods to load their Kong configuration: the others are
+// configured by a ControlPlane once they are provisioned, hence their
+// readiness can't depend on it.
+func (r *DataPlaneReconciler) ensureConfigurationLoadedCondition(dataplane *operatorv1alpha1.DataPlane) bool {
+	usesDatabase := dataplane.Spec.Database != nil && dataplane.Spec.Role != operatorv1alpha1.DataPlaneRoleDataPlane
+	configuredByOperator := dataplane.Spec.DeclarativeConfig != nil || usesDatabase
+
+	condition := k8sutils.NewCondition(
+		DataPlaneConditionTypeConfigurationLoaded,
+		metav1.ConditionTrue,
+		DataPlaneConditionReasonConfigurationLoaded,
+		fmt.Sprintf("%d ready Pods have loaded their configuration", len(dataplane.Status.Pods)),
+	)
+	for _, pod := range dataplane.Status.Pods {
+		switch {
+		case pod.Error != "":
+			condition.Reason = string(DataPlaneConditionReasonPodStatusUnavailable)
+			condition.Message = fmt.Sprintf("failed to read the status of Pod %s: %s", pod.Name, pod.Error)
+		case usesDatabase && !pod.DatabaseReachable:
+			condition.Reason = string(DataPlaneConditionReasonDatabaseUnreachable)
+			condition.Message = fmt.Sprintf("Pod %s can't reach the database", pod.Name)
+		case !usesDatabase && pod.ConfigurationHash == "" && configuredByOperator:
+			condition.Reason = string(DataPlaneConditionReasonConfigurationNotLoaded)
+			condition.Message = fmt.Sprintf("Pod %s hasn't loaded the declarative configuration", pod.Name)
+		case !usesDatabase && pod.ConfigurationHash == "":
+			condition.Reason = string(DataPlaneConditionReasonConfigurationNotLoaded)
+			condition.Message = fmt.Sprintf("Pod %s hasn't been configured by its ControlPlane yet", pod.Name)
+		default:
+			continue
+		}
+		condition.Status = metav1.ConditionFalse
+		break
+	}
+	if len(dataplane.Status.Pods) == 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = string(DataPlaneConditionReasonConfigurationNotLoaded)
+		condition.Message = "no ready Pods"
+	}
+
+	k8sutils.SetCondition(condition, dataplane)
+	return !configuredByOperator || condition.Status == metav1.ConditionTrue
+}
+
+// -----------------------------------------------------------------------------
+// DataPlaneReconciler - Blue/Green Rollout
+// -----------------------------------------------------------------------------
+
+// ensureBlueGreenDeploymentForDataPlane rolls out the DataPlane spec using the
+// blue/green strategy. Changes aren't applied to the live Deployment: a preview
+// Deployment is created instead, exposed by a preview Service, and once it's
+// ready and promoted the DataPlane Service is switched over to it and the
+// previous live Deployment is deleted. The live Deployment is returned along
+// with the status of the rollout.
+func (r *DataPlaneReconciler) ensureBlueGreenDeploymentForDataPlane(
+	ctx context.Context,
+	dataplane *operatorv1alpha1.DataPlane,
+	dataplaneService *corev1.Service,
+	certSecretName string,
+	clusterEnv []corev1.EnvVar,
+) (createdOrUpdated bool, deploy *appsv1.Deployment, rolloutStatus *operatorv1alpha1.DataPlaneRolloutStatus, err error) {
+	generatedDeployment, hash, err := r.generateRolloutDeploymentForDataPlane(dataplane, certSecretName, clusterEnv)
+	if err != nil {
+		return false, nil, nil, err
+	}
+
+	created, liveDeployment, err := r.ensureLiveDeploymentForRollout(ctx, dataplane, generatedDeployment)
+	if err != nil {
+		return false, nil, nil, err
+	}
+	if created {
+		return true, liveDeployment, &operatorv1alpha1.DataPlaneRolloutStatus{
+			Phase:      operatorv1alpha1.DataPlaneRolloutPhaseComplete,
+			Deployment: liveDeployment.Name,
+		}, nil
+	}
+
+	// the DataPlane Service must only route the traffic to the live Pods. A live
+	// Deployment created before the rollout strategy was set doesn't have the
+	// Pod template hash in its selector: in such case the changes are rolled out
+	// anyway, to get a Deployment which Pods can be told apart.
+	updated, err := r.ensureServiceSelector(ctx, dataplaneService, liveDeployment.Spec.Selector.MatchLabels)
+	if err != nil || updated {
+		return updated, liveDeployment, nil, err
+	}
+
+	if liveDeployment.Spec.Selector.MatchLabels[consts.DataPlanePodTemplateHashLabel] == hash {
+		deleted, err := r.deletePreviewResourcesForDataPlane(ctx, dataplane)
+		return deleted, liveDeployment, &operatorv1alpha1.DataPlaneRolloutStatus{
+			Phase:      operatorv1alpha1.DataPlaneRolloutPhaseComplete,
+			Deployment: liveDeployment.Name,
+		}, err
+	}
+
+	rolloutStatus = &operatorv1alpha1.DataPlaneRolloutStatus{
+		Phase:      operatorv1alpha1.DataPlaneRolloutPhaseProvisioningPreview,
+		Deployment: liveDeployment.Name,
+	}
+
+	created, previewDeployment, err := r.ensurePreviewDeploymentForDataPlane(ctx, dataplane, generatedDeployment, hash)
+	if err != nil || created {
+		return created, liveDeployment, rolloutStatus, err
+	}
+	rolloutStatus.PreviewDeployment = previewDeployment.Name
+
+	created, previewService, err := r.ensurePreviewServiceForDataPlane(ctx, dataplane, previewDeployment)
+	if err != nil || created {
+		return created, liveDeployment, rolloutStatus, err
+	}
+	rolloutStatus.PreviewService = previewService.Name
+
+	if !deploymentIsReady(previewDeployment) {
+		return false, liveDeployment, rolloutStatus, nil
+	}
+
+	if !dataplanePreviewIsPromoted(dataplane) {
+		rolloutStatus.Phase = operatorv1alpha1.DataPlaneRolloutPhaseAwaitingPromotion
+		return false, liveDeployment, rolloutStatus, nil
+	}
+
+	if _, err := r.ensureServiceSelector(ctx, dataplaneService, previewDeployment.Spec.Selector.MatchLabels); err != nil {
+		return false, liveDeployment, rolloutStatus, err
+	}
+	if err := r.promotePreviewDeploymentForDataPlane(ctx, dataplane, liveDeployment, previewDeployment); err != nil {
+		return false, liveDeployment, rolloutStatus, err
+	}
+	return true, previewDeployment, &operatorv1alpha1.DataPlaneRolloutStatus{
+		Phase:      operatorv1alpha1.DataPlaneRolloutPhaseComplete,
+		Deployment: previewDeployment.Name,
+	}, nil
+}
+
+// generateRolloutDeploymentForDataPlane generates the Deployment of a DataPlane
+// using a rollout strategy, labeled with the hash of its Pod template which is
+// returned as well.
+func (r *DataPlaneReconciler) generateRolloutDeploymentForDataPlane(
+	dataplane *operatorv1alpha1.DataPlane,
+	certSecretName string,
+	clusterEnv []corev1.EnvVar,
+) (*appsv1.Deployment, string, error) {
+	generatedDeployment := generateNewDeploymentForDataPlane(dataplane, certSecretName, clusterEnv, r.ImagePolicy)
+	hash, err := computePodTemplateHash(&generatedDeployment.Spec.Template)
+	if err != nil {
+		return nil, "", err
+	}
+	setPodTemplateHashForDeployment(generatedDeployment, hash)
+	k8sutils.SetOwnerForObject(generatedDeployment, dataplane)
+	return generatedDeployment, hash, nil
+}
+
+// ensureLiveDeploymentForRollout returns the live Deployment of a DataPlane
+// using a rollout strategy. If there's none yet, it's created from the generated
+// Deployment as there's nothing to roll out.
+func (r *DataPlaneReconciler) ensureLiveDeploymentForRollout(
+	ctx context.Context,
+	dataplane *operatorv1alpha1.DataPlane,
+	generatedDeployment *appsv1.Deployment,
+) (created bool, deploy *appsv1.Deployment, err error) {
+	deployments, err := k8sutils.ListDeploymentsForOwner(
+		ctx,
+		r.Client,
+		consts.GatewayOperatorControlledLabel,
+		consts.DataPlaneManagedLabelValue,
+		dataplane.Namespace,
+		dataplane.UID,
+	)
+	if err != nil {
+		return false, nil, err
+	}
+
+	count := len(deployments)
+	if count > 1 {
+		return false, nil, fmt.Errorf("found %d deployments for DataPlane currently unsupported: expected 1 or less", count)
+	}
+
+	if count == 1 {
+		return false, &deployments[0], nil
+	}
+
+	liveDeployment := generatedDeployment.DeepCopy()
+	addLabelForDataplane(liveDeployment)
+	return true, liveDeployment, r.Client.Create(ctx, liveDeployment)
+}
+
+// ensurePreviewDeploymentForDataPlane ensures that the preview Deployment of
+// the DataPlane matches the generated Deployment. As the selector of a
+// Deployment can't be changed, an outdated preview Deployment is deleted
+// rather than updated.
+func (r *DataPlaneReconciler) ensurePreviewDeploymentForDataPlane(
+	ctx context.Context,
+	dataplane *operatorv1alpha1.DataPlane,
+	generatedDeployment *appsv1.Deployment,
+	hash string,
+) (createdOrDeleted bool, deploy *appsv1.Deployment, err error) {
+	deployments, err := k8sutils.ListDeploymentsForOwner(
+		ctx,
+		r.Client,
+		consts.DataPlaneRolloutStateLabel,
+		consts.DataPlaneRolloutStatePreviewLabelValue,
+		dataplane.Namespace,
+		dataplane.UID,
+	)
+	if err != nil {
+		return false, nil, err
+	}
+
+	var previewDeployment *appsv1.Deployment
+	for i := range deployments {
+		if deployments[i].Spec.Selector.MatchLabels[consts.DataPlanePodTemplateHashLabel] == hash && previewDeployment == nil {
+			previewDeployment = &deployments[i]
+			continue
+		}
+		if err := r.Client.Delete(ctx, &deployments[i]); client.IgnoreNotFound(err) != nil {
+			return false, nil, err
+		}
+		createdOrDeleted = true
+	}
+	if previewDeployment != nil || createdOrDeleted {
+		return createdOrDeleted, previewDeployment, nil
+	}
+
+	generatedDeployment.GenerateName = fmt.Sprintf("%s-preview-%s-", consts.DataPlanePrefix, dataplane.Name)
+	generatedDeployment.Labels[consts.DataPlaneRolloutStateLabel] = consts.DataPlaneRolloutStatePreviewLabelValue
+	return true, generatedDeployment, r.Client.Create(ctx, generatedDeployment)
+}
+
+// ensurePreviewServiceForDataPlane ensures that the preview Service of the
+// DataPlane exists and routes the traffic to the preview Deployment.
+func (r *DataPlaneReconciler) ensurePreviewServiceForDataPlane(
+	ctx context.Context,
+	dataplane *operatorv1alpha1.DataPlane,
+	previewDeployment *appsv1.Deployment,
+) (createdOrUpdated bool, svc *corev1.Service, err error) {
+	services, err := k8sutils.ListServicesForOwner(
+		ctx,
+		r.Client,
+		consts.DataPlaneRolloutStateLabel,
+		consts.DataPlaneRolloutStatePreviewLabelValue,
+		dataplane.Namespace,
+		dataplane.UID,
+	)
+	if err != nil {
+		return false, nil, err
+	}
+
+	count := len(services)
+	if count > 1 {
+		return false, nil, fmt.Errorf("found %d preview services for DataPlane currently unsupported: expected 1 or less", count)
+	}
+
+	if count == 1 {
+		existingService := &services[0]
+		updated, err := r.ensureServiceSelector(ctx, existingService, previewDeployment.Spec.Selector.MatchLabels)
+		return updated, existingService, err
+	}
+
+	generatedService := generatePreviewServiceForDataPlane(dataplane, previewDeployment.Spec.Selector.MatchLabels)
+	k8sutils.SetOwnerForObject(generatedService, dataplane)
+	return true, generatedService, r.Client.Create(ctx, generatedService)
+}
+
+// promotePreviewDeploymentForDataPlane makes the preview Deployment the live
+// one and deletes the previous live Deployment along with the preview Service.
+func (r *DataPlaneReconciler) promotePreviewDeploymentForDataPlane(
+	ctx context.Context,
+	dataplane *operatorv1alpha1.DataPlane,
+	liveDeployment *appsv1.Deployment,
+	previewDeployment *appsv1.Deployment,
+) error {
+	delete(previewDeployment.Labels, consts.DataPlaneRolloutStateLabel)
+	addLabelForDataplane(previewDeployment)
+	if err := r.Client.Update(ctx, previewDeployment); err != nil {
+		return err
+	}
+
+	if err := r.Client.Delete(ctx, liveDeployment); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+
+	if _, err := r.deletePreviewResourcesForDataPlane(ctx, dataplane); err != nil {
+		return err
+	}
+
+	return r.ensurePromoteAnnotationIsRemoved(ctx, dataplane)
+}
+
+// ensurePromoteAnnotationIsRemoved removes the annotation used to promote a
+// rollout from the DataPlane: a manual promotion only applies to the rollout
+// (or the canary step) it was given for.
+func (r *DataPlaneReconciler) ensurePromoteAnnotationIsRemoved(
+	ctx context.Context,
+	dataplane *operatorv1alpha1.DataPlane,
+) error {
+	if _, ok := dataplane.Annotations[consts.DataPlanePromotePreviewAnnotation]; !ok {
+		return nil
+	}
+
+	// only the metadata of a fresh copy of the DataPlane is patched: the
+	// in-memory DataPlane holds the defaults and the generated configuration,
+	// which must not be stored in its spec.
+	current := &operatorv1alpha1.DataPlane{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(dataplane), current); err != nil {
+		return err
+	}
+	if _, ok := current.Annotations[consts.DataPlanePromotePreviewAnnotation]; ok {
+		patched := current.DeepCopy()
+		delete(patched.Annotations, consts.DataPlanePromotePreviewAnnotation)
+		if err := r.Client.Patch(ctx, patched, client.MergeFrom(current)); err != nil {
+			return err
+		}
+	}
+	delete(dataplane.Annotations, consts.DataPlanePromotePreviewAnnotation)
+	return nil
+}
+
+// deletePreviewResourcesForDataPlane deletes the preview Deployments and
+// Services of the DataPlane.
+func (r *DataPlaneReconciler) deletePreviewResourcesForDataPlane(
+	ctx context.Context,
+	dataplane *operatorv1alpha1.DataPlane,
+) (deleted bool, err error) {
+	deployments, err := k8sutils.ListDeploymentsForOwner(
+		ctx,
+		r.Client,
+		consts.DataPlaneRolloutStateLabel,
+		consts.DataPlaneRolloutStatePreviewLabelValue,
+		dataplane.Namespace,
+		dataplane.UID,
+	)
+	if err != nil {
+		return false, err
+	}
+	for i := range deployments {
+		if err := r.Client.Delete(ctx, &deployments[i]); client.IgnoreNotFound(err) != nil {
+			return deleted, err
+		}
+		deleted = true
+	}
+
+	services, err := k8sutils.ListServicesForOwner(
+		ctx,
+		r.Client,
+		consts.DataPlaneRolloutStateLabel,
+		consts.DataPlaneRolloutStatePreviewLabelValue,
+		dataplane.Namespace,
+		dataplane.UID,
+	)
+	if err != nil {
+		return deleted, err
+	}
+	for i := range services {
+		if err := r.Client.Delete(ctx, &services[i]); client.IgnoreNotFound(err) != nil {
+			return deleted, err
+		}
+		deleted = true
+	}
+
+	return deleted, nil
+}
+
+// ensureServiceSelector ensures that the Service routes the traffic to the
+// Pods matching the provided selector.
+func (r *DataPlaneReconciler) ensureServiceSelector(
+	ctx context.Context,
+	service *corev1.Service,
+	selector map[string]string,
+) (bool, error) {
+	if reflect.DeepEqual(service.Spec.Selector, selector) {
+		return false, nil
+	}
+	service.Spec.Selector = selector
+	return true, r.Client.Update(ctx, service)
+}
+
+// -----------------------------------------------------------------------------
+// DataPlaneReconciler - Canary Rollout
+// -----------------------------------------------------------------------------
+
+// ensureCanaryDeploymentForDataPlane rolls out the DataPlane spec using the
+// canary strategy. Changes aren't applied to the live Deployment: a canary
+// Deployment is created instead, which Pods receive a share of the DataPlane
+// Service traffic. The number of canary Pods is increased following the steps
+// of the strategy as long as the canary Pods are ready and pass the analysis,
+// and the canary Deployment replaces the live one once the last step is
+// passed. Otherwise, the canary Deployment is deleted. The DataPlane Service
+// only routes the traffic to the Pods of both Deployments while a canary step
+// is in progress. The live Deployment is returned along with the status of
+// the rollout.
+func (r *DataPlaneReconciler) ensureCanaryDeploymentForDataPlane(
+	ctx context.Context,
+	dataplane *operatorv1alpha1.DataPlane,
+	dataplaneService *corev1.Service,
+	certSecretName string,
+	clusterEnv []corev1.EnvVar,
+) (createdOrUpdated bool, deploy *appsv1.Deployment, rolloutStatus *operatorv1alpha1.DataPlaneRolloutStatus, err error) {
+	canary := dataplane.Spec.Rollout.Strategy.Canary
+	previousStatus := dataplane.Status.Rollout
+
+	generatedDeployment, hash, err := r.generateRolloutDeploymentForDataPlane(dataplane, certSecretName, clusterEnv)
+	if err != nil {
+		return false, nil, nil, err
+	}
+
+	created, liveDeployment, err := r.ensureLiveDeploymentForRollout(ctx, dataplane, generatedDeployment)
+	if err != nil {
+		return false, nil, nil, err
+	}
+	if created {
+		return true, liveDeployment, &operatorv1alpha1.DataPlaneRolloutStatus{
+			Phase:      operatorv1alpha1.DataPlaneRolloutPhaseComplete,
+			Deployment: liveDeployment.Name,
+		}, nil
+	}
+
+	// without a canary step in progress, the DataPlane Service only routes the
+	// traffic to the live Pods.
+	if liveDeployment.Spec.Selector.MatchLabels[consts.DataPlanePodTemplateHashLabel] == hash {
+		updated, err := r.ensureServiceSelector(ctx, dataplaneService, liveDeployment.Spec.Selector.MatchLabels)
+		if err != nil || updated {
+			return updated, liveDeployment, previousStatus, err
+		}
+		deleted, err := r.deletePreviewResourcesForDataPlane(ctx, dataplane)
+		return deleted, liveDeployment, &operatorv1alpha1.DataPlaneRolloutStatus{
+			Phase:      operatorv1alpha1.DataPlaneRolloutPhaseComplete,
+			Deployment: liveDeployment.Name,
+		}, err
+	}
+
+	if previousStatus != nil && previousStatus.RolledBackRevision == hash {
+		updated, err := r.ensureServiceSelector(ctx, dataplaneService, liveDeployment.Spec.Selector.MatchLabels)
+		if err != nil || updated {
+			return updated, liveDeployment, previousStatus, err
+		}
+		deleted, err := r.deletePreviewResourcesForDataPlane(ctx, dataplane)
+		return deleted, liveDeployment, previousStatus, err
+	}
+
+	liveReplicas := int32(1)
+	if liveDeployment.Spec.Replicas != nil {
+		liveReplicas = *liveDeployment.Spec.Replicas
+	}
+
+	now := metav1.Now()
+	rolloutStatus = &operatorv1alpha1.DataPlaneRolloutStatus{
+		Phase:         operatorv1alpha1.DataPlaneRolloutPhaseProgressing,
+		Deployment:    liveDeployment.Name,
+		StepStartTime: &now,
+	}
+
+	replicas := canaryReplicas(liveReplicas, canary.Steps[0].Weight)
+	generatedDeployment.Spec.Replicas = &replicas
+	created, canaryDeployment, err := r.ensurePreviewDeploymentForDataPlane(ctx, dataplane, generatedDeployment, hash)
+	if err != nil || canaryDeployment == nil {
+		return created, liveDeployment, rolloutStatus, err
+	}
+	rolloutStatus.PreviewDeployment = canaryDeployment.Name
+	if created {
+		return true, liveDeployment, rolloutStatus, nil
+	}
+
+	// the DataPlane Service routes the traffic to both the live and canary Pods
+	updated, err := r.ensureServiceSelector(ctx, dataplaneService, map[string]string{"app": dataplane.Name})
+	if err != nil || updated {
+		return updated, liveDeployment, previousStatus, err
+	}
+
+	if previousStatus != nil && previousStatus.PreviewDeployment == canaryDeployment.Name && previousStatus.StepStartTime != nil {
+		rolloutStatus.Step = previousStatus.Step
+		rolloutStatus.StepStartTime = previousStatus.StepStartTime
+		rolloutStatus.AnalysisBaseline = previousStatus.AnalysisBaseline
+	}
+	if int(rolloutStatus.Step) >= len(canary.Steps) {
+		// the steps were changed during the rollout
+		rolloutStatus.Step = int32(len(canary.Steps) - 1)
+	}
+	step := canary.Steps[rolloutStatus.Step]
+
+	replicas = canaryReplicas(liveReplicas, step.Weight)
+	if canaryDeployment.Spec.Replicas == nil || *canaryDeployment.Spec.Replicas != replicas {
+		canaryDeployment.Spec.Replicas = &replicas
+		return true, liveDeployment, rolloutStatus, r.Client.Update(ctx, canaryDeployment)
+	}
+
+	deadline := canaryProgressDeadline(canary)
+	deadlineExceeded := time.Since(rolloutStatus.StepStartTime.Time) > deadline
+
+	if !deploymentIsReady(canaryDeployment) {
+		if deadlineExceeded {
+			return r.rollbackCanaryForDataPlane(ctx, dataplane, dataplaneService, liveDeployment, hash,
+				fmt.Sprintf("canary pods were not ready within %s", deadline))
+		}
+		rolloutStatus.Message = "waiting for the canary pods to be ready"
+		return false, liveDeployment, rolloutStatus, nil
+	}
+
+	if canary.Analysis != nil {
+		counts, err := r.getHTTPResponseCountsForDeployment(ctx, canaryDeployment)
+		if err != nil {
+			if deadlineExceeded {
+				return r.rollbackCanaryForDataPlane(ctx, dataplane, dataplaneService, liveDeployment, hash,
+					fmt.Sprintf("canary pods could not be analyzed within %s: %v", deadline, err))
+			}
+			rolloutStatus.Message = fmt.Sprintf("unable to analyze the canary pods: %v", err)
+			return false, liveDeployment, rolloutStatus, nil
+		}
+		// the counters of the Pods only grow, so the analysis of a step only
+		// accounts for the requests served since the step started.
+		current := operatorv1alpha1.CanaryAnalysisBaseline{Total: int64(counts.Total), Errors: int64(counts.ServerErrors)}
+		errorRate, analyzed, ok := canaryAnalysisErrorRate(rolloutStatus.AnalysisBaseline, current)
+		if !ok {
+			rolloutStatus.AnalysisBaseline = &current
+			rolloutStatus.Message = "collecting the metrics of the canary pods"
+			return false, liveDeployment, rolloutStatus, nil
+		}
+		if analyzed == 0 {
+			if deadlineExceeded {
+				return r.rollbackCanaryForDataPlane(ctx, dataplane, dataplaneService, liveDeployment, hash,
+					fmt.Sprintf("canary pods served no requests within %s", deadline))
+			}
+			rolloutStatus.Message = "waiting for the canary pods to serve requests"
+			return false, liveDeployment, rolloutStatus, nil
+		}
+		if errorRate > float64(canary.Analysis.MaxErrorRate) {
+			return r.rollbackCanaryForDataPlane(ctx, dataplane, dataplaneService, liveDeployment, hash,
+				fmt.Sprintf("canary pods error rate %.2f%% exceeds %d%%", errorRate, canary.Analysis.MaxErrorRate))
+		}
+	}
+
+	if step.Pause == nil {
+		if !dataplaneHasPromoteAnnotation(dataplane) {
+			rolloutStatus.Phase = operatorv1alpha1.DataPlaneRolloutPhaseAwaitingPromotion
+			return false, liveDeployment, rolloutStatus, nil
+		}
+	} else if time.Since(rolloutStatus.StepStartTime.Time) < step.Pause.Duration {
+		return false, liveDeployment, rolloutStatus, nil
+	}
+
+	if int(rolloutStatus.Step) < len(canary.Steps)-1 {
+		rolloutStatus.Step++
+		rolloutStatus.StepStartTime = &now
+		rolloutStatus.AnalysisBaseline = nil
+		return false, liveDeployment, rolloutStatus, r.ensurePromoteAnnotationIsRemoved(ctx, dataplane)
+	}
+
+	canaryDeployment.Spec.Replicas = liveDeployment.Spec.Replicas
+	if _, err := r.ensureServiceSelector(ctx, dataplaneService, canaryDeployment.Spec.Selector.MatchLabels); err != nil {
+		return false, liveDeployment, rolloutStatus, err
+	}
+	if err := r.promotePreviewDeploymentForDataPlane(ctx, dataplane, liveDeployment, canaryDeployment); err != nil {
+		return false, liveDeployment, rolloutStatus, err
+	}
+	return true, canaryDeployment, &operatorv1alpha1.DataPlaneRolloutStatus{
+		Phase:      operatorv1alpha1.DataPlaneRolloutPhaseComplete,
+		Deployment: canaryDeployment.Name,
+	}, nil
+}
+
+// rollbackCanaryForDataPlane routes the traffic of the DataPlane Service to
+// the live Pods only, and deletes the canary Deployment of the DataPlane. The
+// rolled back changes are recorded in the rollout status so that they're not
+// rolled out again until the DataPlane changes.
+func (r *DataPlaneReconciler) rollbackCanaryForDataPlane(
+	ctx context.Context,
+	dataplane *operatorv1alpha1.DataPlane,
+	dataplaneService *corev1.Service,
+	liveDeployment *appsv1.Deployment,
+	hash string,
+	reason string,
+) (bool, *appsv1.Deployment, *operatorv1alpha1.DataPlaneRolloutStatus, error) {
+	r.eventRecorder.Event(dataplane, "Warning", "RolledBack", reason)
+	_, err := r.ensureServiceSelector(ctx, dataplaneService, liveDeployment.Spec.Selector.MatchLabels)
+	if err == nil {
+		_, err = r.deletePreviewResourcesForDataPlane(ctx, dataplane)
+	}
+	return true, liveDeployment, &operatorv1alpha1.DataPlaneRolloutStatus{
+		Phase:              operatorv1alpha1.DataPlaneRolloutPhaseRolledBack,
+		Deployment:         liveDeployment.Name,
+		RolledBackRevision: hash,
+		Message:            reason,
+	}, err
+}
+
+// getHTTPResponseCountsForDeployment returns the number of HTTP responses
+// served by the running Pods of a DataPlane Deployment.
+func (r *DataPlaneReconciler) getHTTPResponseCountsForDeployment(
+	ctx context.Context,
+	deployment *appsv1.Deployment,
+) (dataplaneutils.HTTPResponseCounts, error) {
+	var counts dataplaneutils.HTTPResponseCounts
+
+	pods := &corev1.PodList{}
+	if err := r.Client.List(
+		ctx,
+		pods,
+		client.InNamespace(deployment.Namespace),
+		client.MatchingLabels(deployment.Spec.Selector.MatchLabels),
+	); err != nil {
+		return counts, err
+	}
+
+	running := 0
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" {
+			continue
+		}
+		podCounts, err := fetchDataPlanePodHTTPResponseCounts(ctx, pod)
+		if err != nil {
+			return counts, fmt.Errorf("failed to fetch metrics of pod %s: %w", pod.Name, err)
+		}
+		counts.Add(podCounts)
+		running++
+	}
+	if running == 0 {
+		return counts, fmt.Errorf("no running pods found for deployment %s", deployment.Name)
+	}
+
+	return counts, nil
+}
+
+// -----------------------------------------------------------------------------
+// DataPlaneReconciler - Database Migrations
+// -----------------------------------------------------------------------------
+
+// ensureDatabaseMigratedForRollout runs the database migrations which must be
+// complete before the Deployments of a DataPlane are rolled out to its container
+// image: all of them when the DataPlane has no Deployment yet, or the ones
+// compatible with the Pods running the previous image otherwise. It returns
+// whether the Deployments can be rolled out.
+func (r *DataPlaneReconciler) ensureDatabaseMigratedForRollout(
+	ctx context.Context,
+	dataplane *operatorv1alpha1.DataPlane,
+	certSecretName string,
+	clusterEnv []corev1.EnvVar,
+) (bool, error) {
+	generatedDeployment := generateNewDeploymentForDataPlane(dataplane, certSecretName, clusterEnv, r.ImagePolicy)
+	generatedContainer := k8sresources.GetPodContainerByName(&generatedDeployment.Spec.Template.Spec, consts.DataPlaneProxyContainerName)
+
+	deployments, err := k8sutils.ListDeploymentsForOwner(
+		ctx,
+		r.Client,
+		consts.GatewayOperatorControlledLabel,
+		consts.DataPlaneManagedLabelValue,
+		dataplane.Namespace,
+		dataplane.UID,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	phase := dataplaneMigrationsPhaseBootstrap
+	if len(deployments) > 0 {
+		if deploymentsAreRolledOutToImage(deployments, generatedContainer.Image) {
+			return true, nil
+		}
+		phase = dataplaneMigrationsPhaseUp
+	}
+
+	job, err := r.ensureMigrationsJobForDataPlane(ctx, dataplane, generatedDeployment, phase)
+	if err != nil {
+		return false, err
+	}
+
+	migrated := jobHasSucceeded(job)
+	switch {
+	case migrated && phase == dataplaneMigrationsPhaseUp:
+		k8sutils.SetCondition(k8sutils.NewCondition(
+			DataPlaneConditionTypeDatabaseMigrated,
+			metav1.ConditionFalse,
+			DataPlaneConditionReasonMigrationsAwaitingRollout,
+			"migrations are finished once all pods run the new container image",
+		), dataplane)
+	case migrated:
+		k8sutils.SetCondition(k8sutils.NewCondition(
+			DataPlaneConditionTypeDatabaseMigrated,
+			metav1.ConditionTrue,
+			DataPlaneConditionReasonMigrationsComplete,
+			"database is migrated to the version of the container image",
+		), dataplane)
+	default:
+		setMigrationsJobCondition(dataplane, job)
+	}
+	return migrated, r.updateStatus(ctx, dataplane)
+}
+
+// ensureDatabaseMigrationsFinished finishes the database migrations of a
+// DataPlane once all its Deployments are rolled out to its container image,
+// and then deletes the migrations Jobs of the previous images.
+func (r *DataPlaneReconciler) ensureDatabaseMigrationsFinished(
+	ctx context.Context,
+	dataplane *operatorv1alpha1.DataPlane,
+	certSecretName string,
+	clusterEnv []corev1.EnvVar,
+) error {
+	generatedDeployment := generateNewDeploymentForDataPlane(dataplane, certSecretName, clusterEnv, r.ImagePolicy)
+	generatedContainer := k8sresources.GetPodContainerByName(&generatedDeployment.Spec.Template.Spec, consts.DataPlaneProxyContainerName)
+	imageHash := computeImageHash(generatedContainer.Image)
+
+	deployments, err := k8sutils.ListDeploymentsForOwner(
+		ctx,
+		r.Client,
+		consts.GatewayOperatorControlledLabel,
+		consts.DataPlaneManagedLabelValue,
+		dataplane.Namespace,
+		dataplane.UID,
+	)
+	if err != nil {
+		return err
+	}
+	if !deploymentsAreRolledOutToImage(deployments, generatedContainer.Image) {
+		// the condition set before the rollout is kept until it's complete.
+		return nil
+	}
+
+	jobs, err := r.listMigrationsJobsForDataPlane(ctx, dataplane)
+	if err != nil {
+		return err
+	}
+	// the migrations only need to be finished after an up phase, the bootstrap
+	// phase finishes them already.
+	migratedUp := false
+	for i := range jobs {
+		if jobs[i].Labels[consts.DataPlaneMigrationsImageHashLabel] == imageHash &&
+			jobs[i].Labels[consts.DataPlaneMigrationsPhaseLabel] == string(dataplaneMigrationsPhaseUp) {
+			migratedUp = true
+		}
+	}
+	if migratedUp {
+		job, err := r.ensureMigrationsJobForDataPlane(ctx, dataplane, generatedDeployment, dataplaneMigrationsPhaseFinish)
+		if err != nil {
+			return err
+		}
+		if !jobHasSucceeded(job) {
+			setMigrationsJobCondition(dataplane, job)
+			return nil
+		}
+	}
+
+	k8sutils.SetCondition(k8sutils.NewCondition(
+		DataPlaneConditionTypeDatabaseMigrated,
+		metav1.ConditionTrue,
+		DataPlaneConditionReasonMigrationsComplete,
+		"database is migrated to the version of the container image",
+	), dataplane)
+
+	for i := range jobs {
+		if jobs[i].Labels[consts.DataPlaneMigrationsImageHashLabel] == imageHash {
+			continue
+		}
+		if err := r.deleteMigrationsJob(ctx, &jobs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureDatabaseMigrationsRemoved deletes the migrations Jobs and the
+// DatabaseMigrated condition of a DataPlane which doesn't use a database.
+func (r *DataPlaneReconciler) ensureDatabaseMigrationsRemoved(
+	ctx context.Context,
+	dataplane *operatorv1alpha1.DataPlane,
+) error {
+	jobs, err := r.listMigrationsJobsForDataPlane(ctx, dataplane)
+	if err != nil {
+		return err
+	}
+	for i := range jobs {
+		if err := r.deleteMigrationsJob(ctx, &jobs[i]); err != nil {
+			return err
+		}
+	}
+
+	conditions := make([]metav1.Condition, 0, len(dataplane.Status.Conditions))
+	for _, condition := range dataplane.Status.Conditions {
+		if condition.Type != string(DataPlaneConditionTypeDatabaseMigrated) {
+			conditions = append(conditions, condition)
+		}
+	}
+	dataplane.Status.Conditions = conditions
+	return nil
+}
+
+// ensureMigrationsJobForDataPlane returns the Job running the provided phase of
+// the database migrations for the container image of the generated Deployment,
+// which is created if it doesn't exist yet.
+func (r *DataPlaneReconciler) ensureMigrationsJobForDataPlane(
+	ctx context.Context,
+	dataplane *operatorv1alpha1.DataPlane,
+	generatedDeployment *appsv1.Deployment,
+	phase dataplaneMigrationsPhase,
+) (*batchv1.Job, error) {
+	generatedJob := generateMigrationsJobForDataPlane(dataplane, generatedDeployment, phase)
+	k8sutils.SetOwnerForObject(generatedJob, dataplane)
+	addLabelForDataplane(generatedJob)
+
+	jobs, err := r.listMigrationsJobsForDataPlane(ctx, dataplane)
+	if err != nil {
+		return nil, err
+	}
+	for i := range jobs {
+		if jobs[i].Labels[consts.DataPlaneMigrationsPhaseLabel] == string(phase) &&
+			jobs[i].Labels[consts.DataPlaneMigrationsImageHashLabel] == generatedJob.Labels[consts.DataPlaneMigrationsImageHashLabel] {
+			return &jobs[i], nil
+		}
+	}
+
+	return generatedJob, r.Client.Create(ctx, generatedJob)
+}
+
+// listMigrationsJobsForDataPlane lists the Jobs running the database
+// migrations of a DataPlane.
+func (r *DataPlaneReconciler) listMigrationsJobsForDataPlane(
+	ctx context.Context,
+	dataplane *operatorv1alpha1.DataPlane,
+) ([]batchv1.Job, error) {
+	return k8sutils.ListJobsForOwner(
+		ctx,
+		r.Client,
+		consts.GatewayOperatorControlledLabel,
+		consts.DataPlaneManagedLabelValue,
+		dataplane.Namespace,
+		dataplane.UID,
+	)
+}
+
+// deleteMigrationsJob deletes a migrations Job along with its Pods, which
+// would be orphaned otherwise.
+func (r *DataPlaneReconciler) deleteMigrationsJob(ctx context.Context, job *batchv1.Job) error {
+	return client.IgnoreNotFound(r.Client.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)))
+}
+
+// setMigrationsJobCondition sets the DatabaseMigrated condition of a DataPlane
+// which migrations Job hasn't succeeded yet.
+func setMigrationsJobCondition(dataplane *operatorv1alpha1.DataPlane, job *batchv1.Job) {
+	if jobHasFailed(job) {
+		k8sutils.SetCondition(k8sutils.NewCondition(
+			DataPlaneConditionTypeDatabaseMigrated,
+			metav1.ConditionFalse,
+			DataPlaneConditionReasonMigrationsFailed,
+			fmt.Sprintf("migrations job %s failed, delete it to run the migrations again", job.Name),
+		), dataplane)
+		return
+	}
+	k8sutils.SetCondition(k8sutils.NewCondition(
+		DataPlaneConditionTypeDatabaseMigrated,
+		metav1.ConditionFalse,
+		DataPlaneConditionReasonMigrationsRunning,
+		fmt.Sprintf("migrations job %s is running", job.Name),
+	), dataplane)
+}
@@ -0,0 +1,1071 @@
+package controllers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	"github.com/kong/gateway-operator/internal/consts"
+	dataplaneutils "github.com/kong/gateway-operator/internal/utils/dataplane"
+	k8sutils "github.com/kong/gateway-operator/internal/utils/kubernetes"
+	"github.com/kong/gateway-operator/internal/versions"
+)
+
+func TestEnsureBlueGreenDeploymentForDataPlane(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, operatorv1alpha1.AddToScheme(scheme))
+
+	dataplane := &operatorv1alpha1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kong", UID: "dataplane-uid"},
+		Spec: operatorv1alpha1.DataPlaneSpec{
+			DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+				DeploymentOptions: operatorv1alpha1.DeploymentOptions{
+					Env: []corev1.EnvVar{{Name: "KONG_DATABASE", Value: "off"}},
+				},
+				Rollout: &operatorv1alpha1.DataPlaneRollout{
+					Strategy: operatorv1alpha1.DataPlaneRolloutStrategy{
+						BlueGreen: &operatorv1alpha1.BlueGreenStrategy{
+							Promotion: operatorv1alpha1.ManualPromotion,
+						},
+					},
+				},
+			},
+		},
+	}
+	dataplaneService := generateNewServiceForDataplane(dataplane)
+	dataplaneService.Name = "dataplane-kong"
+	addLabelForDataplane(dataplaneService)
+	k8sutils.SetOwnerForObject(dataplaneService, dataplane)
+
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(dataplane, dataplaneService).Build()
+	r := &DataPlaneReconciler{Client: c}
+
+	listDeployments := func(label, value string) []appsv1.Deployment {
+		deployments, err := k8sutils.ListDeploymentsForOwner(ctx, c, label, value, dataplane.Namespace, dataplane.UID)
+		require.NoError(t, err)
+		return deployments
+	}
+
+	t.Log("creating the first live deployment without preview")
+	createdOrUpdated, live, rollout, err := r.ensureBlueGreenDeploymentForDataPlane(ctx, dataplane, dataplaneService, "cert", nil)
+	require.NoError(t, err)
+	require.True(t, createdOrUpdated)
+	require.Equal(t, operatorv1alpha1.DataPlaneRolloutPhaseComplete, rollout.Phase)
+	require.Equal(t, live.Name, rollout.Deployment)
+
+	t.Log("routing the dataplane service traffic to the live deployment")
+	createdOrUpdated, _, _, err = r.ensureBlueGreenDeploymentForDataPlane(ctx, dataplane, dataplaneService, "cert", nil)
+	require.NoError(t, err)
+	require.True(t, createdOrUpdated)
+	require.Equal(t, live.Spec.Selector.MatchLabels, dataplaneService.Spec.Selector)
+
+	createdOrUpdated, _, rollout, err = r.ensureBlueGreenDeploymentForDataPlane(ctx, dataplane, dataplaneService, "cert", nil)
+	require.NoError(t, err)
+	require.False(t, createdOrUpdated)
+	require.Equal(t, operatorv1alpha1.DataPlaneRolloutPhaseComplete, rollout.Phase)
+
+	t.Log("rolling out a change to a preview deployment exposed by a preview service")
+	dataplane.Spec.Env = append(dataplane.Spec.Env, corev1.EnvVar{Name: "KONG_LOG_LEVEL", Value: "debug"})
+	createdOrUpdated, _, rollout, err = r.ensureBlueGreenDeploymentForDataPlane(ctx, dataplane, dataplaneService, "cert", nil)
+	require.NoError(t, err)
+	require.True(t, createdOrUpdated)
+	require.Equal(t, operatorv1alpha1.DataPlaneRolloutPhaseProvisioningPreview, rollout.Phase)
+	previews := listDeployments(consts.DataPlaneRolloutStateLabel, consts.DataPlaneRolloutStatePreviewLabelValue)
+	require.Len(t, previews, 1)
+	preview := previews[0]
+
+	createdOrUpdated, _, _, err = r.ensureBlueGreenDeploymentForDataPlane(ctx, dataplane, dataplaneService, "cert", nil)
+	require.NoError(t, err)
+	require.True(t, createdOrUpdated)
+
+	createdOrUpdated, _, rollout, err = r.ensureBlueGreenDeploymentForDataPlane(ctx, dataplane, dataplaneService, "cert", nil)
+	require.NoError(t, err)
+	require.False(t, createdOrUpdated)
+	require.Equal(t, operatorv1alpha1.DataPlaneRolloutPhaseProvisioningPreview, rollout.Phase)
+	require.Equal(t, preview.Name, rollout.PreviewDeployment)
+	require.NotEmpty(t, rollout.PreviewService)
+	previewService := &corev1.Service{}
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: dataplane.Namespace, Name: rollout.PreviewService}, previewService))
+	require.Equal(t, preview.Spec.Selector.MatchLabels, previewService.Spec.Selector)
+	require.Equal(t, live.Spec.Selector.MatchLabels, dataplaneService.Spec.Selector)
+
+	t.Log("waiting for the promotion of the ready preview deployment")
+	preview.Status.Replicas = 1
+	preview.Status.AvailableReplicas = 1
+	require.NoError(t, c.Status().Update(ctx, &preview))
+	createdOrUpdated, _, rollout, err = r.ensureBlueGreenDeploymentForDataPlane(ctx, dataplane, dataplaneService, "cert", nil)
+	require.NoError(t, err)
+	require.False(t, createdOrUpdated)
+	require.Equal(t, operatorv1alpha1.DataPlaneRolloutPhaseAwaitingPromotion, rollout.Phase)
+
+	t.Log("promoting the preview deployment")
+	dataplane.Annotations = map[string]string{consts.DataPlanePromotePreviewAnnotation: "true"}
+	// the in-memory DataPlane holds the defaults and the generated
+	// configuration, which must not be stored when removing the annotation.
+	stored := dataplane.DeepCopy()
+	stored.Spec.Env = nil
+	require.NoError(t, c.Update(ctx, stored))
+	createdOrUpdated, live, rollout, err = r.ensureBlueGreenDeploymentForDataPlane(ctx, dataplane, dataplaneService, "cert", nil)
+	require.NoError(t, err)
+	require.True(t, createdOrUpdated)
+	require.Equal(t, preview.Name, live.Name)
+	require.Equal(t, operatorv1alpha1.DataPlaneRolloutPhaseComplete, rollout.Phase)
+	require.Equal(t, preview.Spec.Selector.MatchLabels, dataplaneService.Spec.Selector)
+	require.NotContains(t, dataplane.Annotations, consts.DataPlanePromotePreviewAnnotation)
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(dataplane), stored))
+	require.NotContains(t, stored.Annotations, consts.DataPlanePromotePreviewAnnotation)
+	require.Empty(t, stored.Spec.Env)
+
+	lives := listDeployments(consts.GatewayOperatorControlledLabel, consts.DataPlaneManagedLabelValue)
+	require.Len(t, lives, 1)
+	require.Equal(t, preview.Name, lives[0].Name)
+	require.Empty(t, listDeployments(consts.DataPlaneRolloutStateLabel, consts.DataPlaneRolloutStatePreviewLabelValue))
+	require.Error(t, c.Get(ctx, client.ObjectKeyFromObject(previewService), &corev1.Service{}))
+
+	createdOrUpdated, _, rollout, err = r.ensureBlueGreenDeploymentForDataPlane(ctx, dataplane, dataplaneService, "cert", nil)
+	require.NoError(t, err)
+	require.False(t, createdOrUpdated)
+	require.Equal(t, operatorv1alpha1.DataPlaneRolloutPhaseComplete, rollout.Phase)
+
+	t.Log("routing the dataplane service traffic to all the pods once the rollout strategy is unset")
+	dataplane.Spec.Rollout = nil
+	createdOrUpdated, _, err = r.ensureDeploymentForDataPlane(ctx, dataplane, dataplaneService, "cert", nil)
+	require.NoError(t, err)
+	require.True(t, createdOrUpdated)
+	require.Equal(t, map[string]string{"app": dataplane.Name}, dataplaneService.Spec.Selector)
+	require.Equal(t, "kong", live.Spec.Template.Labels["app"], "the pods of the live deployment must still be selected")
+}
+
+func TestEnsureCanaryDeploymentForDataPlane(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, operatorv1alpha1.AddToScheme(scheme))
+
+	responseCounts := dataplaneutils.HTTPResponseCounts{Total: 100, ServerErrors: 1}
+	fetchMetrics := fetchDataPlanePodHTTPResponseCounts
+	fetchDataPlanePodHTTPResponseCounts = func(context.Context, *corev1.Pod) (dataplaneutils.HTTPResponseCounts, error) {
+		return responseCounts, nil
+	}
+	defer func() { fetchDataPlanePodHTTPResponseCounts = fetchMetrics }()
+
+	dataplane := &operatorv1alpha1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kong", UID: "dataplane-uid"},
+		Spec: operatorv1alpha1.DataPlaneSpec{
+			DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+				DeploymentOptions: operatorv1alpha1.DeploymentOptions{
+					Env: []corev1.EnvVar{{Name: "KONG_DATABASE", Value: "off"}},
+				},
+				Rollout: &operatorv1alpha1.DataPlaneRollout{
+					Strategy: operatorv1alpha1.DataPlaneRolloutStrategy{
+						Canary: &operatorv1alpha1.CanaryStrategy{
+							Steps: []operatorv1alpha1.CanaryStep{
+								{Weight: 50},
+								{Weight: 100, Pause: &metav1.Duration{}},
+							},
+							Analysis: &operatorv1alpha1.CanaryAnalysis{MaxErrorRate: 10},
+						},
+					},
+				},
+			},
+		},
+	}
+	dataplaneService := generateNewServiceForDataplane(dataplane)
+	dataplaneService.Name = "dataplane-kong"
+	addLabelForDataplane(dataplaneService)
+	k8sutils.SetOwnerForObject(dataplaneService, dataplane)
+
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(dataplane, dataplaneService).Build()
+	r := &DataPlaneReconciler{Client: c, eventRecorder: record.NewFakeRecorder(10)}
+
+	reconcile := func() (bool, *appsv1.Deployment, *operatorv1alpha1.DataPlaneRolloutStatus) {
+		createdOrUpdated, live, rollout, err := r.ensureCanaryDeploymentForDataPlane(ctx, dataplane, dataplaneService, "cert", nil)
+		require.NoError(t, err)
+		dataplane.Status.Rollout = rollout
+		return createdOrUpdated, live, rollout
+	}
+	getCanary := func() *appsv1.Deployment {
+		canaries, err := k8sutils.ListDeploymentsForOwner(ctx, c,
+			consts.DataPlaneRolloutStateLabel, consts.DataPlaneRolloutStatePreviewLabelValue, dataplane.Namespace, dataplane.UID)
+		require.NoError(t, err)
+		if len(canaries) == 0 {
+			return nil
+		}
+		require.Len(t, canaries, 1)
+		return &canaries[0]
+	}
+	makeReady := func(deployment *appsv1.Deployment) {
+		deployment.Status.Replicas = *deployment.Spec.Replicas
+		deployment.Status.AvailableReplicas = *deployment.Spec.Replicas
+		require.NoError(t, c.Status().Update(ctx, deployment))
+		require.NoError(t, c.Create(ctx, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: deployment.Namespace,
+				Name:      deployment.Name + "-pod",
+				Labels:    deployment.Spec.Selector.MatchLabels,
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.1"},
+		}))
+	}
+
+	t.Log("creating the first live deployment without canary")
+	createdOrUpdated, live, rollout := reconcile()
+	require.True(t, createdOrUpdated)
+	require.Equal(t, operatorv1alpha1.DataPlaneRolloutPhaseComplete, rollout.Phase)
+	t.Log("routing the traffic of the dataplane service to the live pods only")
+	createdOrUpdated, _, _ = reconcile()
+	require.True(t, createdOrUpdated)
+	require.Equal(t, live.Spec.Selector.MatchLabels, dataplaneService.Spec.Selector)
+	createdOrUpdated, _, rollout = reconcile()
+	require.False(t, createdOrUpdated)
+	require.Equal(t, operatorv1alpha1.DataPlaneRolloutPhaseComplete, rollout.Phase)
+
+	t.Log("rolling out a change to a canary deployment")
+	dataplane.Spec.Env = append(dataplane.Spec.Env, corev1.EnvVar{Name: "KONG_LOG_LEVEL", Value: "debug"})
+	createdOrUpdated, _, rollout = reconcile()
+	require.True(t, createdOrUpdated)
+	require.Equal(t, operatorv1alpha1.DataPlaneRolloutPhaseProgressing, rollout.Phase)
+	canary := getCanary()
+	require.NotNil(t, canary)
+	require.Equal(t, int32(1), *canary.Spec.Replicas)
+
+	t.Log("routing the traffic of the dataplane service to both the live and canary pods")
+	createdOrUpdated, _, _ = reconcile()
+	require.True(t, createdOrUpdated)
+	require.Equal(t, map[string]string{"app": dataplane.Name}, dataplaneService.Spec.Selector)
+
+	createdOrUpdated, _, rollout = reconcile()
+	require.False(t, createdOrUpdated)
+	require.Equal(t, operatorv1alpha1.DataPlaneRolloutPhaseProgressing, rollout.Phase)
+	require.Equal(t, canary.Name, rollout.PreviewDeployment)
+	require.Equal(t, int32(0), rollout.Step)
+
+	t.Log("collecting the metrics of the canary once it's ready, ignoring the errors served before")
+	responseCounts = dataplaneutils.HTTPResponseCounts{Total: 100, ServerErrors: 50}
+	makeReady(canary)
+	createdOrUpdated, _, rollout = reconcile()
+	require.False(t, createdOrUpdated)
+	require.Equal(t, operatorv1alpha1.DataPlaneRolloutPhaseProgressing, rollout.Phase)
+	require.Equal(t, &operatorv1alpha1.CanaryAnalysisBaseline{Total: 100, Errors: 50}, rollout.AnalysisBaseline)
+
+	t.Log("waiting for the manual promotion of the first step once the canary is healthy")
+	responseCounts = dataplaneutils.HTTPResponseCounts{Total: 200, ServerErrors: 55}
+	createdOrUpdated, _, rollout = reconcile()
+	require.False(t, createdOrUpdated)
+	require.Equal(t, operatorv1alpha1.DataPlaneRolloutPhaseAwaitingPromotion, rollout.Phase)
+
+	t.Log("progressing to the last step")
+	dataplane.Annotations = map[string]string{consts.DataPlanePromotePreviewAnnotation: "true"}
+	createdOrUpdated, _, rollout = reconcile()
+	require.False(t, createdOrUpdated)
+	require.Equal(t, operatorv1alpha1.DataPlaneRolloutPhaseProgressing, rollout.Phase)
+	require.Equal(t, int32(1), rollout.Step)
+	require.Nil(t, rollout.AnalysisBaseline)
+	require.NotContains(t, dataplane.Annotations, consts.DataPlanePromotePreviewAnnotation)
+	createdOrUpdated, _, rollout = reconcile()
+	require.False(t, createdOrUpdated)
+	require.Equal(t, &operatorv1alpha1.CanaryAnalysisBaseline{Total: 200, Errors: 55}, rollout.AnalysisBaseline)
+
+	t.Log("promoting the canary once the last step is passed")
+	responseCounts = dataplaneutils.HTTPResponseCounts{Total: 300, ServerErrors: 55}
+	createdOrUpdated, promoted, rollout := reconcile()
+	require.True(t, createdOrUpdated)
+	require.Equal(t, canary.Name, promoted.Name)
+	require.NotEqual(t, live.Name, promoted.Name)
+	require.Equal(t, operatorv1alpha1.DataPlaneRolloutPhaseComplete, rollout.Phase)
+	require.Equal(t, promoted.Spec.Selector.MatchLabels, dataplaneService.Spec.Selector)
+	require.Nil(t, getCanary())
+
+	t.Log("rolling back a change which canary exceeds the error rate during a step")
+	dataplane.Spec.Env = append(dataplane.Spec.Env, corev1.EnvVar{Name: "KONG_NGINX_WORKER_PROCESSES", Value: "1"})
+	createdOrUpdated, _, _ = reconcile()
+	require.True(t, createdOrUpdated)
+	canary = getCanary()
+	require.NotNil(t, canary)
+	createdOrUpdated, _, _ = reconcile()
+	require.True(t, createdOrUpdated)
+	responseCounts = dataplaneutils.HTTPResponseCounts{Total: 100, ServerErrors: 0}
+	makeReady(canary)
+	_, _, rollout = reconcile()
+	require.NotNil(t, rollout.AnalysisBaseline)
+	responseCounts = dataplaneutils.HTTPResponseCounts{Total: 200, ServerErrors: 20}
+	createdOrUpdated, live, rollout = reconcile()
+	require.True(t, createdOrUpdated)
+	require.Equal(t, promoted.Name, live.Name)
+	require.Equal(t, operatorv1alpha1.DataPlaneRolloutPhaseRolledBack, rollout.Phase)
+	require.NotEmpty(t, rollout.RolledBackRevision)
+	require.Equal(t, live.Spec.Selector.MatchLabels, dataplaneService.Spec.Selector)
+	require.Nil(t, getCanary())
+
+	t.Log("not rolling out the rolled back change again")
+	createdOrUpdated, _, rollout = reconcile()
+	require.False(t, createdOrUpdated)
+	require.Equal(t, operatorv1alpha1.DataPlaneRolloutPhaseRolledBack, rollout.Phase)
+	require.Nil(t, getCanary())
+}
+
+func TestCanaryAnalysisErrorRate(t *testing.T) {
+	_, _, ok := canaryAnalysisErrorRate(nil, operatorv1alpha1.CanaryAnalysisBaseline{Total: 10})
+	require.False(t, ok, "the baseline must be set first")
+
+	baseline := &operatorv1alpha1.CanaryAnalysisBaseline{Total: 100, Errors: 50}
+	_, analyzed, ok := canaryAnalysisErrorRate(baseline, operatorv1alpha1.CanaryAnalysisBaseline{Total: 100, Errors: 50})
+	require.True(t, ok)
+	require.Zero(t, analyzed, "nothing happened since the baseline: there's not enough data yet")
+	rate, analyzed, ok := canaryAnalysisErrorRate(baseline, operatorv1alpha1.CanaryAnalysisBaseline{Total: 200, Errors: 60})
+	require.True(t, ok)
+	require.Equal(t, int64(100), analyzed)
+	require.Equal(t, float64(10), rate)
+
+	_, _, ok = canaryAnalysisErrorRate(baseline, operatorv1alpha1.CanaryAnalysisBaseline{Total: 20, Errors: 1})
+	require.False(t, ok, "the baseline must be reset once the counters go down")
+}
+
+func TestCanaryReplicas(t *testing.T) {
+	require.Equal(t, int32(1), canaryReplicas(1, 10))
+	require.Equal(t, int32(1), canaryReplicas(1, 50))
+	require.Equal(t, int32(1), canaryReplicas(3, 25))
+	require.Equal(t, int32(3), canaryReplicas(3, 50))
+	require.Equal(t, int32(3), canaryReplicas(3, 100))
+	require.Equal(t, 10*time.Minute, canaryProgressDeadline(&operatorv1alpha1.CanaryStrategy{}))
+}
+
+func TestEnsureDataPlaneVersionStatus(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, operatorv1alpha1.AddToScheme(scheme))
+
+	dataplane := &operatorv1alpha1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kong", UID: "dataplane-uid"},
+		Spec: operatorv1alpha1.DataPlaneSpec{
+			DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+				DeploymentOptions: operatorv1alpha1.DeploymentOptions{
+					AutomaticUpgrades: &operatorv1alpha1.AutomaticUpgrades{
+						Policy: operatorv1alpha1.UpgradePolicyPatch,
+					},
+				},
+			},
+		},
+	}
+	catalog := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kong-system", Name: "version-catalog"},
+		Data: map[string]string{
+			versions.CatalogConfigMapKey: "kong:\n  versions: [\"2.8.0\", \"2.8.1\", \"3.0.0\"]\n",
+		},
+	}
+
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(dataplane, catalog).Build()
+	r := &DataPlaneReconciler{
+		Client: c,
+		VersionCatalog: &versions.CatalogLoader{
+			Client:    c,
+			ConfigMap: &types.NamespacedName{Namespace: catalog.Namespace, Name: catalog.Name},
+		},
+	}
+
+	t.Log("resolving the first version from the default version")
+	updated, err := r.ensureDataPlaneVersionStatus(ctx, dataplane)
+	require.NoError(t, err)
+	require.True(t, updated)
+	require.Equal(t, "2.8.1", dataplane.Status.Version)
+	require.Equal(t, consts.DefaultDataPlaneBaseImage+":2.8.1",
+		generateNewDeploymentForDataPlane(dataplane, "cert", nil, nil).Spec.Template.Spec.Containers[0].Image)
+
+	updated, err = r.ensureDataPlaneVersionStatus(ctx, dataplane)
+	require.NoError(t, err)
+	require.False(t, updated)
+
+	t.Log("upgrading to a new patch version added to the catalog")
+	catalog.Data[versions.CatalogConfigMapKey] = "kong:\n  versions: [\"2.8.0\", \"2.8.1\", \"2.8.2\", \"3.0.0\"]\n"
+	require.NoError(t, c.Update(ctx, catalog))
+	updated, err = r.ensureDataPlaneVersionStatus(ctx, dataplane)
+	require.NoError(t, err)
+	require.True(t, updated)
+	require.Equal(t, "2.8.2", dataplane.Status.Version)
+
+	t.Log("holding the upgrades while a rollout is in progress")
+	catalog.Data[versions.CatalogConfigMapKey] = "kong:\n  versions: [\"2.8.0\", \"2.8.1\", \"2.8.2\", \"2.8.3\", \"3.0.0\"]\n"
+	require.NoError(t, c.Update(ctx, catalog))
+	dataplane.Status.Rollout = &operatorv1alpha1.DataPlaneRolloutStatus{Phase: operatorv1alpha1.DataPlaneRolloutPhaseAwaitingPromotion}
+	updated, err = r.ensureDataPlaneVersionStatus(ctx, dataplane)
+	require.NoError(t, err)
+	require.False(t, updated)
+	require.Equal(t, "2.8.2", dataplane.Status.Version)
+	dataplane.Status.Rollout.Phase = operatorv1alpha1.DataPlaneRolloutPhaseComplete
+	updated, err = r.ensureDataPlaneVersionStatus(ctx, dataplane)
+	require.NoError(t, err)
+	require.True(t, updated)
+	require.Equal(t, "2.8.3", dataplane.Status.Version)
+
+	t.Log("clearing the version when automatic upgrades are disabled")
+	dataplane.Spec.AutomaticUpgrades = nil
+	updated, err = r.ensureDataPlaneVersionStatus(ctx, dataplane)
+	require.NoError(t, err)
+	require.True(t, updated)
+	require.Empty(t, dataplane.Status.Version)
+	require.Equal(t, consts.DefaultDataPlaneImage,
+		generateNewDeploymentForDataPlane(dataplane, "cert", nil, nil).Spec.Template.Spec.Containers[0].Image)
+}
+
+func TestEnsureDataPlaneDeploymentStatus(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, operatorv1alpha1.AddToScheme(scheme))
+
+	dataplane := &operatorv1alpha1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kong", UID: "dataplane-uid", Generation: 2},
+	}
+	deployment := generateNewDeploymentForDataPlane(dataplane, "cert", nil, nil)
+	deployment.Name = "dataplane-kong-abcde"
+	deployment.Status = appsv1.DeploymentStatus{Replicas: 2, ReadyReplicas: 1}
+	service := &corev1.Service{
+		Spec: corev1.ServiceSpec{ClusterIP: "10.96.0.10"},
+		Status: corev1.ServiceStatus{
+			LoadBalancer: corev1.LoadBalancerStatus{
+				Ingress: []corev1.LoadBalancerIngress{{IP: "172.18.0.100"}, {Hostname: "kong.example.com"}},
+			},
+		},
+	}
+	certSecret := newTestCASecret(t)
+
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(dataplane).Build()
+	r := &DataPlaneReconciler{Client: c}
+
+	t.Log("reporting the state of the deployment, service and certificate")
+	require.NoError(t, r.ensureDataPlaneDeploymentStatus(ctx, dataplane, deployment, service, certSecret))
+	require.Equal(t, int64(2), dataplane.Status.ObservedGeneration)
+	require.Equal(t, &operatorv1alpha1.DeploymentStatus{
+		Name:          "dataplane-kong-abcde",
+		Replicas:      2,
+		ReadyReplicas: 1,
+		Image:         consts.DefaultDataPlaneImage,
+		Version:       consts.DefaultDataPlaneTag,
+	}, dataplane.Status.Deployment)
+	require.Equal(t, []string{"172.18.0.100", "kong.example.com", "10.96.0.10"}, dataplane.Status.Addresses)
+	require.NotNil(t, dataplane.Status.CertificateExpiry)
+	require.True(t, dataplane.Status.CertificateExpiry.After(time.Now()))
+
+	t.Log("keeping the status as it is once stored")
+	stored := &operatorv1alpha1.DataPlane{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(dataplane), stored))
+	require.Equal(t, dataplane.Status.Deployment, stored.Status.Deployment)
+	require.NoError(t, r.ensureDataPlaneDeploymentStatus(ctx, stored, deployment, service, certSecret))
+	require.Equal(t, dataplane.ResourceVersion, stored.ResourceVersion)
+}
+
+func TestEnsureDataPlaneLicenseStatus(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, operatorv1alpha1.AddToScheme(scheme))
+
+	licenseExpiringIn := func(days int) []byte {
+		expirationDate := time.Now().UTC().AddDate(0, 0, days).Format("2006-01-02")
+		return []byte(`{"license":{"payload":{"customer":"Kong","license_expiration_date":"` + expirationDate +
+			`","license_key":"key"},"signature":"6985","version":"1"}}`)
+	}
+	licenseSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kong-license"},
+		Data:       map[string][]byte{consts.DataPlaneLicenseSecretKey: licenseExpiringIn(90)},
+	}
+	dataplane := &operatorv1alpha1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kong", UID: "dataplane-uid"},
+		Spec: operatorv1alpha1.DataPlaneSpec{
+			DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+				License: &operatorv1alpha1.DataPlaneLicense{
+					SecretRef: corev1.LocalObjectReference{Name: licenseSecret.Name},
+				},
+			},
+		},
+	}
+
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(dataplane, licenseSecret).Build()
+	r := &DataPlaneReconciler{Client: c, eventRecorder: record.NewFakeRecorder(10)}
+
+	requireLicenseCondition := func(status metav1.ConditionStatus, reason k8sutils.ConditionReason) {
+		condition, ok := k8sutils.GetCondition(DataPlaneConditionTypeLicenseValid, dataplane)
+		require.True(t, ok)
+		require.Equal(t, status, condition.Status)
+		require.Equal(t, string(reason), condition.Reason)
+	}
+
+	t.Log("recording the status of a valid license")
+	updated, requeueAfter, err := r.ensureDataPlaneLicenseStatus(ctx, dataplane)
+	require.NoError(t, err)
+	require.True(t, updated)
+	requireLicenseCondition(metav1.ConditionTrue, DataPlaneConditionReasonLicenseValid)
+	require.NotNil(t, dataplane.Status.License)
+	require.Equal(t, computeChecksum(licenseSecret.Data[consts.DataPlaneLicenseSecretKey]), dataplane.Status.License.Checksum)
+	require.Greater(t, requeueAfter, 50*24*time.Hour)
+	require.Less(t, requeueAfter, 61*24*time.Hour)
+
+	updated, _, err = r.ensureDataPlaneLicenseStatus(ctx, dataplane)
+	require.NoError(t, err)
+	require.False(t, updated)
+
+	t.Log("injecting the license in the deployment")
+	_, deployment, err := r.ensureDeploymentForDataPlane(ctx, dataplane, generateNewServiceForDataplane(dataplane), "certificate", nil)
+	require.NoError(t, err)
+	require.Equal(t, dataplane.Status.License.Checksum, deployment.Spec.Template.Annotations[consts.DataPlaneLicenseChecksumAnnotation])
+	container := deployment.Spec.Template.Spec.Containers[0]
+	require.Equal(t, licenseSecret.Name, k8sutils.EnvVarSourceByName(container.Env, consts.EnvVarKongLicenseData).SecretKeyRef.Name)
+
+	t.Log("warning about the upcoming expiration of a renewed license")
+	licenseSecret.Data[consts.DataPlaneLicenseSecretKey] = licenseExpiringIn(10)
+	require.NoError(t, c.Update(ctx, licenseSecret))
+	updated, requeueAfter, err = r.ensureDataPlaneLicenseStatus(ctx, dataplane)
+	require.NoError(t, err)
+	require.True(t, updated)
+	requireLicenseCondition(metav1.ConditionTrue, DataPlaneConditionReasonLicenseExpiringSoon)
+	require.Less(t, requeueAfter, 11*24*time.Hour)
+
+	t.Log("rolling the deployment to the renewed license")
+	updated, deployment, err = r.ensureDeploymentForDataPlane(ctx, dataplane, generateNewServiceForDataplane(dataplane), "certificate", nil)
+	require.NoError(t, err)
+	require.True(t, updated)
+	require.Equal(t, dataplane.Status.License.Checksum, deployment.Spec.Template.Annotations[consts.DataPlaneLicenseChecksumAnnotation])
+
+	t.Log("reporting an expired license")
+	licenseSecret.Data[consts.DataPlaneLicenseSecretKey] = licenseExpiringIn(-1)
+	require.NoError(t, c.Update(ctx, licenseSecret))
+	updated, requeueAfter, err = r.ensureDataPlaneLicenseStatus(ctx, dataplane)
+	require.NoError(t, err)
+	require.True(t, updated)
+	requireLicenseCondition(metav1.ConditionFalse, DataPlaneConditionReasonLicenseExpired)
+	require.Zero(t, requeueAfter)
+
+	t.Log("reporting a license secret which content is invalid")
+	licenseSecret.Data[consts.DataPlaneLicenseSecretKey] = []byte("invalid")
+	require.NoError(t, c.Update(ctx, licenseSecret))
+	updated, requeueAfter, err = r.ensureDataPlaneLicenseStatus(ctx, dataplane)
+	require.NoError(t, err)
+	require.True(t, updated)
+	requireLicenseCondition(metav1.ConditionFalse, DataPlaneConditionReasonLicenseInvalid)
+	require.Zero(t, requeueAfter)
+	require.Nil(t, dataplane.Status.License)
+	updated, _, err = r.ensureDataPlaneLicenseStatus(ctx, dataplane)
+	require.NoError(t, err)
+	require.False(t, updated)
+
+	t.Log("reporting a missing license secret")
+	require.NoError(t, c.Delete(ctx, licenseSecret))
+	updated, _, err = r.ensureDataPlaneLicenseStatus(ctx, dataplane)
+	require.NoError(t, err)
+	require.True(t, updated)
+	requireLicenseCondition(metav1.ConditionFalse, DataPlaneConditionReasonLicenseNotFound)
+
+	t.Log("removing the license status along with the license")
+	dataplane.Spec.License = nil
+	updated, _, err = r.ensureDataPlaneLicenseStatus(ctx, dataplane)
+	require.NoError(t, err)
+	require.True(t, updated)
+	_, ok := k8sutils.GetCondition(DataPlaneConditionTypeLicenseValid, dataplane)
+	require.False(t, ok)
+	require.Nil(t, dataplane.Status.License)
+}
+
+func TestEnsureDatabaseMigrationsForDataPlane(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, operatorv1alpha1.AddToScheme(scheme))
+
+	dataplane := &operatorv1alpha1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kong", UID: "dataplane-uid"},
+		Spec: operatorv1alpha1.DataPlaneSpec{
+			DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+				DeploymentOptions: operatorv1alpha1.DeploymentOptions{
+					ContainerImage: pointer.String("kong:2.8.1"),
+				},
+				Database: &operatorv1alpha1.DataPlaneDatabase{
+					Postgres: operatorv1alpha1.PostgresDatabase{
+						ConnectionSecretRef: corev1.LocalObjectReference{Name: "postgres"},
+					},
+				},
+			},
+		},
+	}
+
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(dataplane).Build()
+	r := &DataPlaneReconciler{Client: c}
+
+	listJobs := func() []batchv1.Job {
+		jobs, err := r.listMigrationsJobsForDataPlane(ctx, dataplane)
+		require.NoError(t, err)
+		return jobs
+	}
+	completeJob := func(phase dataplaneMigrationsPhase) {
+		for _, job := range listJobs() {
+			if job.Labels[consts.DataPlaneMigrationsPhaseLabel] == string(phase) && !jobHasSucceeded(&job) {
+				job.Status.Conditions = append(job.Status.Conditions, batchv1.JobCondition{Type: batchv1.JobComplete, Status: corev1.ConditionTrue})
+				require.NoError(t, c.Status().Update(ctx, &job))
+			}
+		}
+	}
+	rollOutDeployment := func(deployment *appsv1.Deployment) {
+		deployment.Status.Replicas = 1
+		deployment.Status.UpdatedReplicas = 1
+		deployment.Status.AvailableReplicas = 1
+		require.NoError(t, c.Status().Update(ctx, deployment))
+	}
+	requireMigratedCondition := func(status metav1.ConditionStatus, reason k8sutils.ConditionReason) {
+		condition, ok := k8sutils.GetCondition(DataPlaneConditionTypeDatabaseMigrated, dataplane)
+		require.True(t, ok)
+		require.Equal(t, status, condition.Status)
+		require.Equal(t, string(reason), condition.Reason)
+	}
+
+	t.Log("bootstrapping the database before creating the first deployment")
+	migrated, err := r.ensureDatabaseMigratedForRollout(ctx, dataplane, "cert", nil)
+	require.NoError(t, err)
+	require.False(t, migrated)
+	requireMigratedCondition(metav1.ConditionFalse, DataPlaneConditionReasonMigrationsRunning)
+	jobs := listJobs()
+	require.Len(t, jobs, 1)
+	require.Equal(t, string(dataplaneMigrationsPhaseBootstrap), jobs[0].Labels[consts.DataPlaneMigrationsPhaseLabel])
+	require.Equal(t, "kong:2.8.1", jobs[0].Spec.Template.Spec.Containers[0].Image)
+	require.Equal(t, corev1.RestartPolicyNever, jobs[0].Spec.Template.Spec.RestartPolicy)
+	require.Contains(t, jobs[0].Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: consts.EnvVarKongDatabase, Value: consts.KongDatabasePostgres})
+
+	completeJob(dataplaneMigrationsPhaseBootstrap)
+	migrated, err = r.ensureDatabaseMigratedForRollout(ctx, dataplane, "cert", nil)
+	require.NoError(t, err)
+	require.True(t, migrated)
+	requireMigratedCondition(metav1.ConditionTrue, DataPlaneConditionReasonMigrationsComplete)
+
+	_, deployment, err := r.ensureDeploymentForDataPlane(ctx, dataplane, generateNewServiceForDataplane(dataplane), "cert", nil)
+	require.NoError(t, err)
+	rollOutDeployment(deployment)
+	require.NoError(t, r.ensureDatabaseMigrationsFinished(ctx, dataplane, "cert", nil))
+	require.Len(t, listJobs(), 1)
+
+	t.Log("running the migrations up before rolling out a new container image")
+	dataplane.Spec.ContainerImage = pointer.String("kong:3.0.0")
+	migrated, err = r.ensureDatabaseMigratedForRollout(ctx, dataplane, "cert", nil)
+	require.NoError(t, err)
+	require.False(t, migrated)
+	require.Len(t, listJobs(), 2)
+
+	completeJob(dataplaneMigrationsPhaseUp)
+	migrated, err = r.ensureDatabaseMigratedForRollout(ctx, dataplane, "cert", nil)
+	require.NoError(t, err)
+	require.True(t, migrated)
+	requireMigratedCondition(metav1.ConditionFalse, DataPlaneConditionReasonMigrationsAwaitingRollout)
+
+	t.Log("finishing the migrations once the new container image is rolled out")
+	_, deployment, err = r.ensureDeploymentForDataPlane(ctx, dataplane, generateNewServiceForDataplane(dataplane), "cert", nil)
+	require.NoError(t, err)
+	deployment.Status.UpdatedReplicas = 0
+	require.NoError(t, c.Status().Update(ctx, deployment))
+	require.NoError(t, r.ensureDatabaseMigrationsFinished(ctx, dataplane, "cert", nil))
+	require.Len(t, listJobs(), 2)
+	requireMigratedCondition(metav1.ConditionFalse, DataPlaneConditionReasonMigrationsAwaitingRollout)
+
+	rollOutDeployment(deployment)
+	require.NoError(t, r.ensureDatabaseMigrationsFinished(ctx, dataplane, "cert", nil))
+	require.Len(t, listJobs(), 3)
+	requireMigratedCondition(metav1.ConditionFalse, DataPlaneConditionReasonMigrationsRunning)
+
+	completeJob(dataplaneMigrationsPhaseFinish)
+	require.NoError(t, r.ensureDatabaseMigrationsFinished(ctx, dataplane, "cert", nil))
+	requireMigratedCondition(metav1.ConditionTrue, DataPlaneConditionReasonMigrationsComplete)
+	jobs = listJobs()
+	require.Len(t, jobs, 2, "the jobs of the previous container image should be deleted")
+	for _, job := range jobs {
+		require.Equal(t, "kong:3.0.0", job.Spec.Template.Spec.Containers[0].Image)
+	}
+
+	t.Log("removing the migrations when the database is unset")
+	dataplane.Spec.Database = nil
+	require.NoError(t, r.ensureDatabaseMigrationsRemoved(ctx, dataplane))
+	require.Empty(t, listJobs())
+	_, ok := k8sutils.GetCondition(DataPlaneConditionTypeDatabaseMigrated, dataplane)
+	require.False(t, ok)
+}
+
+func TestEnsureClusterRoleForDataPlane(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, operatorv1alpha1.AddToScheme(scheme))
+
+	controlplane := &operatorv1alpha1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kong-cp", UID: "controlplane-uid"},
+		Spec: operatorv1alpha1.DataPlaneSpec{
+			DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+				Role: operatorv1alpha1.DataPlaneRoleControlPlane,
+				Database: &operatorv1alpha1.DataPlaneDatabase{
+					Postgres: operatorv1alpha1.PostgresDatabase{
+						ConnectionSecretRef: corev1.LocalObjectReference{Name: "postgres"},
+					},
+				},
+			},
+		},
+		Status: operatorv1alpha1.DataPlaneStatus{Service: "dataplane-kong-cp-abcde"},
+	}
+	dataplane := &operatorv1alpha1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kong-dp", UID: "dataplane-uid"},
+		Spec: operatorv1alpha1.DataPlaneSpec{
+			DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+				DeploymentOptions: operatorv1alpha1.DeploymentOptions{
+					Env: []corev1.EnvVar{{Name: "KONG_CLUSTER_LISTEN", Value: "off"}},
+				},
+				Role:                operatorv1alpha1.DataPlaneRoleDataPlane,
+				ClusterControlPlane: controlplane.Name,
+			},
+		},
+	}
+
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(controlplane, dataplane).Build()
+	r := &DataPlaneReconciler{Client: c}
+
+	listClusterServices := func() []corev1.Service {
+		services, err := k8sutils.ListServicesForOwner(ctx, c, consts.DataPlaneServiceTypeLabel,
+			consts.DataPlaneServiceTypeClusterLabelValue, controlplane.Namespace, controlplane.UID)
+		require.NoError(t, err)
+		return services
+	}
+
+	t.Log("waiting for the control plane to be provisioned")
+	ready, _, err := r.ensureClusterRoleForDataPlane(ctx, dataplane)
+	require.NoError(t, err)
+	require.False(t, ready)
+	condition, ok := k8sutils.GetCondition(DataPlaneConditionTypeProvisioned, dataplane)
+	require.True(t, ok)
+	require.Equal(t, string(DataPlaneConditionReasonWaitingForClusterControlPlane), condition.Reason)
+
+	t.Log("exposing the cluster listener of the control plane")
+	ready, _, err = r.ensureClusterRoleForDataPlane(ctx, controlplane)
+	require.NoError(t, err)
+	require.False(t, ready)
+	clusterServices := listClusterServices()
+	require.Len(t, clusterServices, 1)
+	require.Equal(t, int32(dataplaneutils.DefaultKongClusterPort), clusterServices[0].Spec.Ports[0].Port)
+
+	ready, _, err = r.ensureClusterRoleForDataPlane(ctx, controlplane)
+	require.NoError(t, err)
+	require.False(t, ready)
+	require.Equal(t, clusterServices[0].Name, controlplane.Status.ClusterService)
+
+	ready, clusterEnv, err := r.ensureClusterRoleForDataPlane(ctx, controlplane)
+	require.NoError(t, err)
+	require.True(t, ready)
+	require.Empty(t, controlplane.Spec.Env, "the cluster environment must not be set on the DataPlane")
+	env := generateNewDeploymentForDataPlane(controlplane, "cert", clusterEnv, nil).Spec.Template.Spec.Containers[0].Env
+	require.Equal(t, "control_plane", k8sutils.EnvValueByName(env, "KONG_ROLE"))
+	require.Equal(t, "0.0.0.0:8005", k8sutils.EnvValueByName(env, "KONG_CLUSTER_LISTEN"))
+	require.Equal(t, "pki", k8sutils.EnvValueByName(env, "KONG_CLUSTER_MTLS"))
+
+	t.Log("connecting the data plane to the cluster service of the control plane")
+	ready, clusterEnv, err = r.ensureClusterRoleForDataPlane(ctx, dataplane)
+	require.NoError(t, err)
+	require.True(t, ready)
+	require.Equal(t, []corev1.EnvVar{{Name: "KONG_CLUSTER_LISTEN", Value: "off"}}, dataplane.Spec.Env,
+		"the cluster environment must not be set on the DataPlane")
+	env = generateNewDeploymentForDataPlane(dataplane, "cert", clusterEnv, nil).Spec.Template.Spec.Containers[0].Env
+	require.Equal(t, "data_plane", k8sutils.EnvValueByName(env, "KONG_ROLE"))
+	require.Equal(t, clusterServices[0].Name+".default.svc:8005", k8sutils.EnvValueByName(env, "KONG_CLUSTER_CONTROL_PLANE"))
+	require.Equal(t, "dataplane-kong-cp-abcde.default.svc", k8sutils.EnvValueByName(env, "KONG_CLUSTER_SERVER_NAME"))
+	require.Equal(t, "/var/cluster-certificate/tls.crt", k8sutils.EnvValueByName(env, "KONG_CLUSTER_CERT"))
+	require.Equal(t, "off", k8sutils.EnvValueByName(env, "KONG_CLUSTER_LISTEN"))
+
+	t.Log("dropping the cluster environment once the data plane becomes traditional")
+	traditional := dataplane.DeepCopy()
+	traditional.Spec.Role = operatorv1alpha1.DataPlaneRoleTraditional
+	ready, clusterEnv, err = r.ensureClusterRoleForDataPlane(ctx, traditional)
+	require.NoError(t, err)
+	require.True(t, ready)
+	env = generateNewDeploymentForDataPlane(traditional, "cert", clusterEnv, nil).Spec.Template.Spec.Containers[0].Env
+	require.Empty(t, k8sutils.EnvValueByName(env, "KONG_ROLE"))
+	require.Empty(t, k8sutils.EnvValueByName(env, "KONG_CLUSTER_CONTROL_PLANE"))
+
+	t.Log("deleting the cluster service once the control plane becomes traditional")
+	controlplane.Spec.Role = operatorv1alpha1.DataPlaneRoleTraditional
+	ready, _, err = r.ensureClusterRoleForDataPlane(ctx, controlplane)
+	require.NoError(t, err)
+	require.False(t, ready)
+	require.Empty(t, listClusterServices())
+	require.Empty(t, controlplane.Status.ClusterService)
+
+	ready, _, err = r.ensureClusterRoleForDataPlane(ctx, controlplane)
+	require.NoError(t, err)
+	require.True(t, ready)
+}
+
+func TestEnsureDeclarativeConfigLoaded(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, operatorv1alpha1.AddToScheme(scheme))
+
+	var postedConfigs map[string][]byte
+	postConfig := postDataPlanePodDeclarativeConfig
+	postDataPlanePodDeclarativeConfig = func(_ context.Context, httpClient *http.Client, pod *corev1.Pod, config []byte) error {
+		require.NotNil(t, httpClient)
+		postedConfigs[pod.Name] = config
+		return nil
+	}
+	defer func() { postDataPlanePodDeclarativeConfig = postConfig }()
+
+	ca := newTestCASecret(t)
+	config := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kong-config"},
+		Data:       map[string]string{"config": `_format_version: "3.0"`},
+	}
+	dataplane := &operatorv1alpha1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kong", UID: "dataplane-uid"},
+		Spec: operatorv1alpha1.DataPlaneSpec{
+			DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+				DeclarativeConfig: &operatorv1alpha1.DataPlaneDeclarativeConfig{
+					ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: config.Name},
+						Key:                  "config",
+					},
+				},
+			},
+		},
+		Status: operatorv1alpha1.DataPlaneStatus{Service: "kong-proxy"},
+	}
+	newPod := func(name string, ready corev1.ConditionStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Labels: map[string]string{"app": dataplane.Name}},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				PodIP:      "10.0.0.1",
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+			},
+		}
+	}
+
+	getDeclarativeConfigVolume := func(deployment *appsv1.Deployment) *corev1.Volume {
+		for i, volume := range deployment.Spec.Template.Spec.Volumes {
+			if volume.Name == consts.DataPlaneDeclarativeConfigVolumeName {
+				return &deployment.Spec.Template.Spec.Volumes[i]
+			}
+		}
+		return nil
+	}
+
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).
+		WithObjects(dataplane, config, ca, newPod("ready", corev1.ConditionTrue), newPod("starting", corev1.ConditionFalse)).
+		Build()
+	r := &DataPlaneReconciler{
+		Client:                   c,
+		ClusterCASecretName:      ca.Name,
+		ClusterCASecretNamespace: ca.Namespace,
+		eventRecorder:            record.NewFakeRecorder(10),
+	}
+
+	t.Log("mounting the declarative config in the deployment")
+	_, deployment, err := r.ensureDeploymentForDataPlane(ctx, dataplane, generateNewServiceForDataplane(dataplane), "certificate", nil)
+	require.NoError(t, err)
+	volume := getDeclarativeConfigVolume(deployment)
+	require.NotNil(t, volume)
+	require.Equal(t, config.Name, volume.ConfigMap.Name)
+	require.Equal(t, []corev1.KeyToPath{{Key: "config", Path: consts.DataPlaneDeclarativeConfigFileName}}, volume.ConfigMap.Items)
+	container := deployment.Spec.Template.Spec.Containers[0]
+	require.Equal(t, "/var/declarative-config/kong.yml", k8sutils.EnvValueByName(container.Env, consts.EnvVarKongDeclarativeConfig))
+
+	t.Log("loading the declarative config in the ready pods")
+	postedConfigs = map[string][]byte{}
+	require.NoError(t, r.ensureDeclarativeConfigLoaded(ctx, dataplane))
+	require.Equal(t, map[string][]byte{"ready": []byte(config.Data["config"])}, postedConfigs)
+	require.Equal(t, computeChecksum([]byte(config.Data["config"])), dataplane.Status.DeclarativeConfigChecksum)
+
+	t.Log("skipping the pods once the declarative config is loaded")
+	postedConfigs = map[string][]byte{}
+	require.NoError(t, r.ensureDeclarativeConfigLoaded(ctx, dataplane))
+	require.Empty(t, postedConfigs)
+
+	t.Log("reloading the updated declarative config")
+	config.Data["config"] = `_format_version: "3.0"
+services:
+- url: http://echo.default.svc:1027
+`
+	require.NoError(t, c.Update(ctx, config))
+	require.NoError(t, r.ensureDeclarativeConfigLoaded(ctx, dataplane))
+	require.Equal(t, map[string][]byte{"ready": []byte(config.Data["config"])}, postedConfigs)
+
+	t.Log("switching the deployment to a declarative config from a secret")
+	dataplane.Spec.DeclarativeConfig = &operatorv1alpha1.DataPlaneDeclarativeConfig{
+		SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "kong-config"},
+			Key:                  "config",
+		},
+	}
+	updated, deployment, err := r.ensureDeploymentForDataPlane(ctx, dataplane, generateNewServiceForDataplane(dataplane), "certificate", nil)
+	require.NoError(t, err)
+	require.True(t, updated)
+	volume = getDeclarativeConfigVolume(deployment)
+	require.NotNil(t, volume)
+	require.Nil(t, volume.ConfigMap)
+	require.Equal(t, "kong-config", volume.Secret.SecretName)
+
+	t.Log("removing the declarative config")
+	dataplane.Spec.DeclarativeConfig = nil
+	updated, deployment, err = r.ensureDeploymentForDataPlane(ctx, dataplane, generateNewServiceForDataplane(dataplane), "certificate", nil)
+	require.NoError(t, err)
+	require.True(t, updated)
+	require.Nil(t, getDeclarativeConfigVolume(deployment))
+	require.NoError(t, r.ensureDeclarativeConfigLoaded(ctx, dataplane))
+	require.Empty(t, dataplane.Status.DeclarativeConfigChecksum)
+}
+
+func TestEnsureConfigurationLoadedCondition(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, operatorv1alpha1.AddToScheme(scheme))
+
+	kongStatuses := map[string]dataplaneutils.KongStatus{}
+	fetchStatus := fetchDataPlanePodKongStatus
+	fetchDataPlanePodKongStatus = func(_ context.Context, pod *corev1.Pod) (dataplaneutils.KongStatus, error) {
+		status, ok := kongStatuses[pod.Name]
+		if !ok {
+			return dataplaneutils.KongStatus{}, fmt.Errorf("connection refused")
+		}
+		return status, nil
+	}
+	defer func() { fetchDataPlanePodKongStatus = fetchStatus }()
+
+	dataplane := &operatorv1alpha1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kong"},
+	}
+	newPod := func(name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Labels: map[string]string{"app": dataplane.Name}},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				PodIP:      "10.0.0.1",
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+		}
+	}
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).
+		WithObjects(dataplane, newPod("kong-b"), newPod("kong-a")).
+		Build()
+	r := &DataPlaneReconciler{Client: c}
+
+	requireCondition := func(status metav1.ConditionStatus, reason k8sutils.ConditionReason) {
+		t.Helper()
+		condition, ok := k8sutils.GetCondition(DataPlaneConditionTypeConfigurationLoaded, dataplane)
+		require.True(t, ok)
+		require.Equal(t, status, condition.Status)
+		require.Equal(t, string(reason), condition.Reason)
+	}
+
+	t.Log("recording the health of the ready pods")
+	kongStatuses["kong-a"] = dataplaneutils.KongStatus{ConfigurationHash: "b2c1f5c4", DatabaseReachable: true, Workers: 2}
+	updated, err := r.ensureDataPlanePodsStatus(ctx, dataplane)
+	require.NoError(t, err)
+	require.True(t, updated)
+	require.Equal(t, []operatorv1alpha1.DataPlanePodStatus{
+		{Name: "kong-a", ConfigurationHash: "b2c1f5c4", DatabaseReachable: true, Workers: 2},
+		{Name: "kong-b", Error: "connection refused"},
+	}, dataplane.Status.Pods)
+	updated, err = r.ensureDataPlanePodsStatus(ctx, dataplane)
+	require.NoError(t, err)
+	require.False(t, updated)
+
+	t.Log("reporting the health of the DataPlanes configured by a ControlPlane without waiting for it")
+	require.True(t, r.ensureConfigurationLoadedCondition(dataplane))
+	requireCondition(metav1.ConditionFalse, DataPlaneConditionReasonPodStatusUnavailable)
+
+	t.Log("waiting for the pods to report their status")
+	dataplane.Spec.DeclarativeConfig = &operatorv1alpha1.DataPlaneDeclarativeConfig{
+		ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "kong-config"},
+			Key:                  "config",
+		},
+	}
+	require.False(t, r.ensureConfigurationLoadedCondition(dataplane))
+	requireCondition(metav1.ConditionFalse, DataPlaneConditionReasonPodStatusUnavailable)
+
+	t.Log("waiting for the pods to load the declarative configuration")
+	kongStatuses["kong-b"] = dataplaneutils.KongStatus{DatabaseReachable: true, Workers: 2}
+	_, err = r.ensureDataPlanePodsStatus(ctx, dataplane)
+	require.NoError(t, err)
+	require.False(t, r.ensureConfigurationLoadedCondition(dataplane))
+	requireCondition(metav1.ConditionFalse, DataPlaneConditionReasonConfigurationNotLoaded)
+
+	t.Log("marking the configuration as loaded by all the pods")
+	kongStatuses["kong-b"] = kongStatuses["kong-a"]
+	_, err = r.ensureDataPlanePodsStatus(ctx, dataplane)
+	require.NoError(t, err)
+	require.True(t, r.ensureConfigurationLoadedCondition(dataplane))
+	requireCondition(metav1.ConditionTrue, DataPlaneConditionReasonConfigurationLoaded)
+
+	t.Log("waiting for the pods to reach the database")
+	dataplane.Spec.DeclarativeConfig = nil
+	dataplane.Spec.Database = &operatorv1alpha1.DataPlaneDatabase{}
+	kongStatuses["kong-b"] = dataplaneutils.KongStatus{Workers: 2}
+	_, err = r.ensureDataPlanePodsStatus(ctx, dataplane)
+	require.NoError(t, err)
+	require.False(t, r.ensureConfigurationLoadedCondition(dataplane))
+	requireCondition(metav1.ConditionFalse, DataPlaneConditionReasonDatabaseUnreachable)
+
+	t.Log("waiting for ready pods")
+	require.NoError(t, c.DeleteAllOf(ctx, &corev1.Pod{}, client.InNamespace("default")))
+	updated, err = r.ensureDataPlanePodsStatus(ctx, dataplane)
+	require.NoError(t, err)
+	require.True(t, updated)
+	require.Empty(t, dataplane.Status.Pods)
+	require.False(t, r.ensureConfigurationLoadedCondition(dataplane))
+	requireCondition(metav1.ConditionFalse, DataPlaneConditionReasonConfigurationNotLoaded)
+}
+
+// newTestCASecret returns a Secret holding a self-signed CA able to sign the
+// certificates issued by the operator.
+func newTestCASecret(t *testing.T) *corev1.Secret {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Kong Gateway Operator CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &priv.PublicKey, priv)
+	require.NoError(t, err)
+	privDer, err := x509.MarshalECPrivateKey(priv)
+	require.NoError(t, err)
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kong-system", Name: "kong-operator-ca"},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			"tls.crt": pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			"tls.key": pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: privDer}),
+		},
+	}
+}
@@ -1,32 +1,58 @@
 package controllers
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"net/http"
+	"reflect"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
 	"github.com/kong/gateway-operator/internal/consts"
 	dataplaneutils "github.com/kong/gateway-operator/internal/utils/dataplane"
+	"github.com/kong/gateway-operator/internal/utils/image"
+	k8sutils "github.com/kong/gateway-operator/internal/utils/kubernetes"
+	k8sresources "github.com/kong/gateway-operator/internal/utils/kubernetes/resources"
 )
 
 // -----------------------------------------------------------------------------
 // DataPlane - Private Functions - Generators
 // -----------------------------------------------------------------------------
 
-func generateNewDeploymentForDataPlane(dataplane *operatorv1alpha1.DataPlane, certSecretName string) *appsv1.Deployment {
-	var dataplaneImage string
-	if dataplane.Spec.ContainerImage != nil {
-		dataplaneImage = *dataplane.Spec.ContainerImage
-		if dataplane.Spec.Version != nil {
-			dataplaneImage = fmt.Sprintf("%s:%s", dataplaneImage, *dataplane.Spec.Version)
-		}
-	} else {
-		dataplaneImage = consts.DefaultDataPlaneImage // TODO: https://github.com/Kong/gateway-operator/issues/20
+// generateNewDeploymentForDataPlane generates the Deployment of a DataPlane.
+// The provided environment variables configuring its role in a Kong cluster
+// take precedence over the ones of the DataPlane.
+func generateNewDeploymentForDataPlane(
+	dataplane *operatorv1alpha1.DataPlane,
+	certSecretName string,
+	clusterEnv []corev1.EnvVar,
+	imagePolicy *image.Policy,
+) *appsv1.Deployment {
+	dataplaneImage := containerImageForDeploymentOptions(&dataplane.Spec.DeploymentOptions,
+		imagePolicy.MirrorDefaultImage(consts.DefaultDataPlaneBaseImage),
+		imagePolicy.MirrorDefaultImage(consts.DefaultDataPlaneImage),
+		dataplane.Status.Version)
+
+	env := append(dataplaneutils.DatabaseEnvVars(&dataplane.Spec.DataPlaneDeploymentOptions),
+		dataplaneutils.LicenseEnvVars(&dataplane.Spec.DataPlaneDeploymentOptions)...)
+	env = append(env, dataplaneutils.DeclarativeConfigEnvVars(&dataplane.Spec.DataPlaneDeploymentOptions)...)
+	env = append(env, dataplane.Spec.Env...)
+	for _, envVar := range clusterEnv {
+		env = k8sutils.UpdateEnv(env, envVar.Name, envVar.Value)
 	}
 
 	deployment := &appsv1.Deployment{
@@ -50,6 +76,7 @@ func generateNewDeploymentForDataPlane(dataplane *operatorv1alpha1.DataPlane, ce
 					},
 				},
 				Spec: corev1.PodSpec{
+					ImagePullSecrets: imagePolicy.LocalObjectReferences(),
 					Volumes: []corev1.Volume{
 						{
 							Name: "cluster-certificate",
@@ -83,7 +110,7 @@ func generateNewDeploymentForDataPlane(dataplane *operatorv1alpha1.DataPlane, ce
 								MountPath: "/var/cluster-certificate",
 							},
 						},
-						Env:             dataplane.Spec.Env,
+						Env:             env,
 						EnvFrom:         dataplane.Spec.EnvFrom,
 						Image:           dataplaneImage,
 						ImagePullPolicy: corev1.PullIfNotPresent,
@@ -139,9 +166,147 @@ func generateNewDeploymentForDataPlane(dataplane *operatorv1alpha1.DataPlane, ce
 			},
 		},
 	}
+
+	if dataplane.Spec.DeclarativeConfig != nil {
+		setDeclarativeConfigVolumeForDeployment(deployment, dataplane.Spec.DeclarativeConfig)
+	}
+	if dataplane.Spec.License != nil && dataplane.Status.License != nil {
+		deployment.Spec.Template.Annotations = map[string]string{
+			consts.DataPlaneLicenseChecksumAnnotation: dataplane.Status.License.Checksum,
+		}
+	}
 	return deployment
 }
 
+// setDeclarativeConfigVolumeForDeployment mounts the declarative Kong
+// configuration of a DataPlane in the proxy container of its Deployment. The
+// default mode of the volume is set, as it's defaulted by the API server, so
+// that the volume can be compared with the existing one.
+func setDeclarativeConfigVolumeForDeployment(deployment *appsv1.Deployment, source *operatorv1alpha1.DataPlaneDeclarativeConfig) {
+	volume := corev1.Volume{Name: consts.DataPlaneDeclarativeConfigVolumeName}
+	switch {
+	case source.ConfigMapKeyRef != nil:
+		volume.ConfigMap = &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: source.ConfigMapKeyRef.LocalObjectReference,
+			Items:                []corev1.KeyToPath{{Key: source.ConfigMapKeyRef.Key, Path: consts.DataPlaneDeclarativeConfigFileName}},
+			DefaultMode:          pointer.Int32(corev1.ConfigMapVolumeSourceDefaultMode),
+		}
+	case source.SecretKeyRef != nil:
+		volume.Secret = &corev1.SecretVolumeSource{
+			SecretName:  source.SecretKeyRef.Name,
+			Items:       []corev1.KeyToPath{{Key: source.SecretKeyRef.Key, Path: consts.DataPlaneDeclarativeConfigFileName}},
+			DefaultMode: pointer.Int32(corev1.SecretVolumeSourceDefaultMode),
+		}
+	default:
+		return
+	}
+
+	podSpec := &deployment.Spec.Template.Spec
+	podSpec.Volumes = append(podSpec.Volumes, volume)
+	container := k8sresources.GetPodContainerByName(podSpec, consts.DataPlaneProxyContainerName)
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      consts.DataPlaneDeclarativeConfigVolumeName,
+		ReadOnly:  true,
+		MountPath: consts.DataPlaneDeclarativeConfigMountPath,
+	})
+}
+
+// ensurePodVolumeIsUpdated updates the volume with the provided name, and its
+// mount in the proxy container, of the existing Pod spec to match the
+// generated one. The volume is removed if it isn't generated anymore. It
+// returns whether the existing Pod spec was updated.
+func ensurePodVolumeIsUpdated(existing, generated *corev1.PodSpec, volumeName string) bool {
+	updated := false
+
+	existingVolumes, existingVolume := removePodVolume(existing.Volumes, volumeName)
+	_, generatedVolume := removePodVolume(generated.Volumes, volumeName)
+	if !reflect.DeepEqual(existingVolume, generatedVolume) {
+		if generatedVolume != nil {
+			existingVolumes = append(existingVolumes, *generatedVolume)
+		}
+		existing.Volumes = existingVolumes
+		updated = true
+	}
+
+	container := k8sresources.GetPodContainerByName(existing, consts.DataPlaneProxyContainerName)
+	generatedContainer := k8sresources.GetPodContainerByName(generated, consts.DataPlaneProxyContainerName)
+	existingMounts, existingMount := removeVolumeMount(container.VolumeMounts, volumeName)
+	_, generatedMount := removeVolumeMount(generatedContainer.VolumeMounts, volumeName)
+	if !reflect.DeepEqual(existingMount, generatedMount) {
+		if generatedMount != nil {
+			existingMounts = append(existingMounts, *generatedMount)
+		}
+		container.VolumeMounts = existingMounts
+		updated = true
+	}
+
+	return updated
+}
+
+// removePodVolume returns the volumes without the one with the provided name,
+// which is returned as well if it was found.
+func removePodVolume(volumes []corev1.Volume, name string) ([]corev1.Volume, *corev1.Volume) {
+	var removed *corev1.Volume
+	remaining := make([]corev1.Volume, 0, len(volumes))
+	for i := range volumes {
+		if volumes[i].Name == name {
+			removed = &volumes[i]
+			continue
+		}
+		remaining = append(remaining, volumes[i])
+	}
+	return remaining, removed
+}
+
+// removeVolumeMount returns the volume mounts without the one of the volume
+// with the provided name, which is returned as well if it was found.
+func removeVolumeMount(mounts []corev1.VolumeMount, name string) ([]corev1.VolumeMount, *corev1.VolumeMount) {
+	var removed *corev1.VolumeMount
+	remaining := make([]corev1.VolumeMount, 0, len(mounts))
+	for i := range mounts {
+		if mounts[i].Name == name {
+			removed = &mounts[i]
+			continue
+		}
+		remaining = append(remaining, mounts[i])
+	}
+	return remaining, removed
+}
+
+// generateMigrationsJobForDataPlane generates the Job running a phase of the
+// database migrations of a DataPlane. Its Pod runs the container image and the
+// configuration of the provided Deployment generated for the DataPlane.
+func generateMigrationsJobForDataPlane(
+	dataplane *operatorv1alpha1.DataPlane,
+	generatedDeployment *appsv1.Deployment,
+	phase dataplaneMigrationsPhase,
+) *batchv1.Job {
+	podSpec := generatedDeployment.Spec.Template.Spec.DeepCopy()
+	podSpec.RestartPolicy = corev1.RestartPolicyNever
+	container := k8sresources.GetPodContainerByName(podSpec, consts.DataPlaneProxyContainerName)
+	container.Command = dataplaneMigrationsCommands[phase]
+	container.Ports = nil
+	container.ReadinessProbe = nil
+	container.Lifecycle = nil
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:    dataplane.Namespace,
+			GenerateName: fmt.Sprintf("%s-%s-migrations-%s-", consts.DataPlanePrefix, dataplane.Name, phase),
+			Labels: map[string]string{
+				consts.DataPlaneMigrationsPhaseLabel:     string(phase),
+				consts.DataPlaneMigrationsImageHashLabel: computeImageHash(container.Image),
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: pointer.Int32(migrationsJobBackoffLimit),
+			Template: corev1.PodTemplateSpec{
+				Spec: *podSpec,
+			},
+		},
+	}
+}
+
 func generateNewServiceForDataplane(dataplane *operatorv1alpha1.DataPlane) *corev1.Service {
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
@@ -174,6 +339,364 @@ func generateNewServiceForDataplane(dataplane *operatorv1alpha1.DataPlane) *core
 	}
 }
 
+func generatePreviewServiceForDataPlane(dataplane *operatorv1alpha1.DataPlane, selector map[string]string) *corev1.Service {
+	service := generateNewServiceForDataplane(dataplane)
+	service.GenerateName = fmt.Sprintf("%s-preview-%s-", consts.DataPlanePrefix, dataplane.Name)
+	service.Labels = map[string]string{
+		consts.DataPlaneRolloutStateLabel: consts.DataPlaneRolloutStatePreviewLabelValue,
+	}
+	// the preview Service is only meant to be used from within the cluster,
+	// to check the preview Deployment before promoting it.
+	service.Spec.Type = corev1.ServiceTypeClusterIP
+	service.Spec.Selector = selector
+	return service
+}
+
+// generateClusterServiceForDataPlane generates the Service exposing the cluster
+// listener of a DataPlane running a Kong hybrid mode control plane, for the
+// connections of its data planes.
+func generateClusterServiceForDataPlane(dataplane *operatorv1alpha1.DataPlane) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:    dataplane.Namespace,
+			GenerateName: fmt.Sprintf("%s-cluster-%s-", consts.DataPlanePrefix, dataplane.Name),
+			Labels: map[string]string{
+				consts.DataPlaneServiceTypeLabel: consts.DataPlaneServiceTypeClusterLabelValue,
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeClusterIP,
+			Selector: map[string]string{"app": dataplane.Name},
+			Ports: []corev1.ServicePort{
+				{
+					Name:     "cluster",
+					Protocol: corev1.ProtocolTCP,
+					Port:     dataplaneutils.DefaultKongClusterPort,
+				},
+			},
+		},
+	}
+}
+
+// serviceAddresses returns the addresses of the provided Service: the IPs and
+// hostnames of its load balancer, followed by its ClusterIP.
+func serviceAddresses(service *corev1.Service) []string {
+	var addresses []string
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			addresses = append(addresses, ingress.IP)
+		}
+		if ingress.Hostname != "" {
+			addresses = append(addresses, ingress.Hostname)
+		}
+	}
+	if service.Spec.ClusterIP != "" && service.Spec.ClusterIP != corev1.ClusterIPNone {
+		addresses = append(addresses, service.Spec.ClusterIP)
+	}
+	return addresses
+}
+
+// -----------------------------------------------------------------------------
+// DataPlane - Private Functions - Rollouts
+// -----------------------------------------------------------------------------
+
+// dataplaneUsesBlueGreenRollout returns true if the changes of the DataPlane
+// must be rolled out with the blue/green strategy.
+func dataplaneUsesBlueGreenRollout(dataplane *operatorv1alpha1.DataPlane) bool {
+	return dataplane.Spec.Rollout != nil && dataplane.Spec.Rollout.Strategy.BlueGreen != nil
+}
+
+// dataplaneUsesCanaryRollout returns true if the changes of the DataPlane must
+// be rolled out with the canary strategy.
+func dataplaneUsesCanaryRollout(dataplane *operatorv1alpha1.DataPlane) bool {
+	return dataplane.Spec.Rollout != nil && dataplane.Spec.Rollout.Strategy.Canary != nil
+}
+
+// dataplanePreviewIsPromoted returns true if the preview Deployment of the
+// DataPlane can be promoted once it's ready.
+func dataplanePreviewIsPromoted(dataplane *operatorv1alpha1.DataPlane) bool {
+	if dataplane.Spec.Rollout.Strategy.BlueGreen.Promotion != operatorv1alpha1.ManualPromotion {
+		return true
+	}
+	return dataplaneHasPromoteAnnotation(dataplane)
+}
+
+// dataplaneHasPromoteAnnotation returns true if the DataPlane was annotated to
+// manually promote its rollout.
+func dataplaneHasPromoteAnnotation(dataplane *operatorv1alpha1.DataPlane) bool {
+	return dataplane.Annotations[consts.DataPlanePromotePreviewAnnotation] == "true"
+}
+
+// canaryReplicas returns the number of canary Pods to run along with the live
+// ones for the canary Pods to be the provided percentage of all the DataPlane
+// Pods. At least one canary Pod is run.
+func canaryReplicas(liveReplicas int32, weight int32) int32 {
+	if weight >= 100 {
+		return liveReplicas
+	}
+	replicas := int32(math.Ceil(float64(liveReplicas) * float64(weight) / float64(100-weight)))
+	if replicas < 1 {
+		return 1
+	}
+	return replicas
+}
+
+// canaryProgressDeadline returns the maximum time a canary step can take for
+// the canary Pods to become ready and pass the analysis.
+func canaryProgressDeadline(canary *operatorv1alpha1.CanaryStrategy) time.Duration {
+	if canary.ProgressDeadline == nil {
+		return defaultCanaryProgressDeadline
+	}
+	return canary.ProgressDeadline.Duration
+}
+
+// canaryAnalysisErrorRate returns the percentage of errors of the canary Pods
+// since the analysis baseline of the current canary step, along with the
+// number of operations it accounts for. It returns false if the baseline must
+// be (re)set: there's none yet, or the counters went down since the canary
+// Pods were restarted or scaled down. When no operation was made since the
+// baseline, there's not enough data to analyze the canary Pods yet.
+func canaryAnalysisErrorRate(
+	baseline *operatorv1alpha1.CanaryAnalysisBaseline,
+	current operatorv1alpha1.CanaryAnalysisBaseline,
+) (float64, int64, bool) {
+	if baseline == nil || current.Total < baseline.Total || current.Errors < baseline.Errors {
+		return 0, 0, false
+	}
+	total := current.Total - baseline.Total
+	if total == 0 {
+		return 0, 0, true
+	}
+	return float64(current.Errors-baseline.Errors) / float64(total) * 100, total, true
+}
+
+// fetchDataPlanePodHTTPResponseCounts returns the number of HTTP responses
+// reported by Kong on the status port of a DataPlane Pod. It's a variable to
+// be replaced in tests, as the Pods can't be reached from there.
+var fetchDataPlanePodHTTPResponseCounts = func(ctx context.Context, pod *corev1.Pod) (dataplaneutils.HTTPResponseCounts, error) {
+	url := fmt.Sprintf("http://%s:%d/metrics", pod.Status.PodIP, consts.DataPlaneMetricsPort)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return dataplaneutils.HTTPResponseCounts{}, err
+	}
+	resp, err := dataplaneMetricsHTTPClient.Do(req)
+	if err != nil {
+		return dataplaneutils.HTTPResponseCounts{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return dataplaneutils.HTTPResponseCounts{}, fmt.Errorf("unexpected status code %d for %s: is the Prometheus plugin enabled?", resp.StatusCode, url)
+	}
+	return dataplaneutils.ParseHTTPResponseCounts(resp.Body)
+}
+
+// fetchDataPlanePodKongStatus returns the health reported by Kong on the
+// /status endpoint of the status port of a DataPlane Pod. It's a variable to
+// be replaced in tests, as the Pods can't be reached from there.
+var fetchDataPlanePodKongStatus = func(ctx context.Context, pod *corev1.Pod) (dataplaneutils.KongStatus, error) {
+	url := fmt.Sprintf("http://%s:%d/status", pod.Status.PodIP, consts.DataPlaneMetricsPort)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return dataplaneutils.KongStatus{}, err
+	}
+	resp, err := dataplaneMetricsHTTPClient.Do(req)
+	if err != nil {
+		return dataplaneutils.KongStatus{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return dataplaneutils.KongStatus{}, fmt.Errorf("unexpected status code %d for %s", resp.StatusCode, url)
+	}
+	return dataplaneutils.ParseKongStatus(resp.Body)
+}
+
+// postDataPlanePodDeclarativeConfig loads the provided declarative Kong
+// configuration in a DataPlane Pod, through the /config endpoint of its Kong
+// Admin API. It's a variable to be replaced in tests, as the Pods can't be
+// reached from there.
+var postDataPlanePodDeclarativeConfig = func(ctx context.Context, httpClient *http.Client, pod *corev1.Pod, config []byte) error {
+	body, err := json.Marshal(map[string]string{"config": string(config)})
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("https://%s:%d/config", pod.Status.PodIP, consts.DataPlaneAdminAPIPort)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status code %d for %s: %s", resp.StatusCode, url, message)
+	}
+	return nil
+}
+
+// getDataPlanePodDeclarativeConfig returns the declarative Kong configuration
+// a DB-less DataPlane Pod is running, through the /config endpoint of its Kong
+// Admin API. It's a variable to be replaced in tests, as the Pods can't be
+// reached from there.
+var getDataPlanePodDeclarativeConfig = func(ctx context.Context, httpClient *http.Client, pod *corev1.Pod) ([]byte, error) {
+	url := fmt.Sprintf("https://%s:%d/config", pod.Status.PodIP, consts.DataPlaneAdminAPIPort)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("unexpected status code %d for %s: %s", resp.StatusCode, url, message)
+	}
+	var body struct {
+		Config string `json:"config"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode the response of %s: %w", url, err)
+	}
+	return []byte(body.Config), nil
+}
+
+// computePodTemplateHash returns a hash of the provided Pod template, used to
+// tell apart the Deployments created while rolling out a DataPlane.
+func computePodTemplateHash(template *corev1.PodTemplateSpec) (string, error) {
+	b, err := json.Marshal(template)
+	if err != nil {
+		return "", err
+	}
+	hasher := fnv.New32a()
+	if _, err := hasher.Write(b); err != nil {
+		return "", err
+	}
+	return rand.SafeEncodeString(fmt.Sprint(hasher.Sum32())), nil
+}
+
+// computeImageHash returns a hash of the provided container image, used to
+// label the migrations Jobs of a DataPlane as images don't fit in labels.
+func computeImageHash(image string) string {
+	hasher := fnv.New32a()
+	_, _ = hasher.Write([]byte(image))
+	return rand.SafeEncodeString(fmt.Sprint(hasher.Sum32()))
+}
+
+// computeChecksum computes the checksum of the data referenced by a DataPlane,
+// such as its Kong Enterprise license or declarative configuration.
+func computeChecksum(data []byte) string {
+	hasher := fnv.New32a()
+	_, _ = hasher.Write(data)
+	return rand.SafeEncodeString(fmt.Sprint(hasher.Sum32()))
+}
+
+// setPodTemplateHashForDeployment labels the Deployment, its Pods and its
+// selector with the provided Pod template hash, so that the Pods of the
+// Deployment can be selected separately from the other DataPlane Pods.
+func setPodTemplateHashForDeployment(deployment *appsv1.Deployment, hash string) {
+	deployment.Labels[consts.DataPlanePodTemplateHashLabel] = hash
+	deployment.Spec.Selector.MatchLabels[consts.DataPlanePodTemplateHashLabel] = hash
+	deployment.Spec.Template.Labels[consts.DataPlanePodTemplateHashLabel] = hash
+}
+
+// deploymentIsReady returns true if all the replicas of the Deployment are
+// available.
+func deploymentIsReady(deployment *appsv1.Deployment) bool {
+	return deployment.Status.Replicas > 0 && deployment.Status.AvailableReplicas >= deployment.Status.Replicas
+}
+
+// podIsReady returns true if the Pod is running and ready.
+func podIsReady(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// getReadyDataPlanePods returns the ready Pods of the DataPlane which can be
+// reached through their Pod IP.
+func getReadyDataPlanePods(
+	ctx context.Context,
+	c client.Client,
+	dataplane *operatorv1alpha1.DataPlane,
+) ([]*corev1.Pod, error) {
+	pods := &corev1.PodList{}
+	if err := c.List(
+		ctx,
+		pods,
+		client.InNamespace(dataplane.Namespace),
+		client.MatchingLabels{"app": dataplane.Name},
+	); err != nil {
+		return nil, err
+	}
+
+	var ready []*corev1.Pod
+	for i := range pods.Items {
+		if pod := &pods.Items[i]; podIsReady(pod) && pod.Status.PodIP != "" {
+			ready = append(ready, pod)
+		}
+	}
+	return ready, nil
+}
+
+// deploymentIsRolledOut returns true if all the replicas of the Deployment
+// run its current Pod template and are available.
+func deploymentIsRolledOut(deployment *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	return deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas == replicas &&
+		deployment.Status.Replicas == replicas &&
+		deployment.Status.AvailableReplicas == replicas
+}
+
+// deploymentsAreRolledOutToImage returns true if all the provided DataPlane
+// Deployments are rolled out and run the provided container image.
+func deploymentsAreRolledOutToImage(deployments []appsv1.Deployment, image string) bool {
+	for i := range deployments {
+		container := k8sresources.GetPodContainerByName(&deployments[i].Spec.Template.Spec, consts.DataPlaneProxyContainerName)
+		if container == nil || container.Image != image || !deploymentIsRolledOut(&deployments[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// jobHasSucceeded returns true if the Job has completed successfully.
+func jobHasSucceeded(job *batchv1.Job) bool {
+	return jobHasCondition(job, batchv1.JobComplete)
+}
+
+// jobHasFailed returns true if the Job has failed, after all its retries.
+func jobHasFailed(job *batchv1.Job) bool {
+	return jobHasCondition(job, batchv1.JobFailed)
+}
+
+func jobHasCondition(job *batchv1.Job, conditionType batchv1.JobConditionType) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == conditionType && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
 // -----------------------------------------------------------------------------
 // DataPlane - Private Functions - Kubernetes Object Labels
 // -----------------------------------------------------------------------------
@@ -192,5 +715,14 @@ func addLabelForDataplane(obj client.Object) {
 // -----------------------------------------------------------------------------
 
 func dataplaneSpecDeepEqual(spec1, spec2 *operatorv1alpha1.DataPlaneDeploymentOptions) bool {
-	return deploymentOptionsDeepEqual(&spec1.DeploymentOptions, &spec2.DeploymentOptions)
+	if !deploymentOptionsDeepEqual(&spec1.DeploymentOptions, &spec2.DeploymentOptions) {
+		return false
+	}
+
+	return reflect.DeepEqual(spec1.Rollout, spec2.Rollout) &&
+		reflect.DeepEqual(spec1.Database, spec2.Database) &&
+		reflect.DeepEqual(spec1.License, spec2.License) &&
+		reflect.DeepEqual(spec1.DeclarativeConfig, spec2.DeclarativeConfig) &&
+		spec1.Role == spec2.Role &&
+		spec1.ClusterControlPlane == spec2.ClusterControlPlane
 }
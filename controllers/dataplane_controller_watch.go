@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	operatorerrors "github.com/kong/gateway-operator/internal/errors"
+)
+
+// -----------------------------------------------------------------------------
+// DataPlaneReconciler - Watch Map Funcs
+// -----------------------------------------------------------------------------
+
+// getDataPlanesForClusterControlPlane returns the hybrid mode data planes
+// connecting to the provided DataPlane, which are configured using the
+// Services of their control plane.
+func (r *DataPlaneReconciler) getDataPlanesForClusterControlPlane(obj client.Object) []reconcile.Request {
+	ctx := context.Background()
+
+	controlplane, ok := obj.(*operatorv1alpha1.DataPlane)
+	if !ok {
+		log.FromContext(ctx).Error(
+			operatorerrors.ErrUnexpectedObject,
+			"failed to run map funcs",
+			"expected", "DataPlane", "found", reflect.TypeOf(obj),
+		)
+		return nil
+	}
+
+	return r.getDataPlanesForIndex(ctx, controlplane.Namespace, dataplaneClusterControlPlaneIndex, controlplane.Name)
+}
+
+// indexDataPlaneOnClusterControlPlane indexes the hybrid mode DataPlanes on
+// the name of the DataPlane they connect to as control plane.
+func indexDataPlaneOnClusterControlPlane(obj client.Object) []string {
+	dataplane, ok := obj.(*operatorv1alpha1.DataPlane)
+	if !ok || dataplane.Spec.Role != operatorv1alpha1.DataPlaneRoleDataPlane ||
+		dataplane.Spec.ClusterControlPlane == "" {
+		return nil
+	}
+	return []string{dataplane.Spec.ClusterControlPlane}
+}
+
+// getDataPlanesForSecret returns the DataPlanes using the Kong Enterprise
+// license or the declarative Kong configuration held by the provided Secret,
+// so that their status and Pods are updated when it changes.
+func (r *DataPlaneReconciler) getDataPlanesForSecret(obj client.Object) []reconcile.Request {
+	ctx := context.Background()
+
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		log.FromContext(ctx).Error(
+			operatorerrors.ErrUnexpectedObject,
+			"failed to run map funcs",
+			"expected", "Secret", "found", reflect.TypeOf(obj),
+		)
+		return nil
+	}
+
+	return r.getDataPlanesForIndex(ctx, secret.Namespace, dataplaneSecretsIndex, secret.Name)
+}
+
+// getDataPlanesForConfigMap returns the DataPlanes using the declarative Kong
+// configuration held by the provided ConfigMap, so that their Pods are
+// reloaded when it changes.
+func (r *DataPlaneReconciler) getDataPlanesForConfigMap(obj client.Object) []reconcile.Request {
+	ctx := context.Background()
+
+	cm, ok := obj.(*corev1.ConfigMap)
+	if !ok {
+		log.FromContext(ctx).Error(
+			operatorerrors.ErrUnexpectedObject,
+			"failed to run map funcs",
+			"expected", "ConfigMap", "found", reflect.TypeOf(obj),
+		)
+		return nil
+	}
+
+	return r.getDataPlanesForIndex(ctx, cm.Namespace, dataplaneConfigMapsIndex, cm.Name)
+}
+
+// getDataPlanesForIndex returns the requests of the DataPlanes of the
+// namespace indexed on the provided value.
+func (r *DataPlaneReconciler) getDataPlanesForIndex(
+	ctx context.Context,
+	namespace, index, value string,
+) (recs []reconcile.Request) {
+	dataplanes := &operatorv1alpha1.DataPlaneList{}
+	if err := r.Client.List(ctx, dataplanes,
+		client.InNamespace(namespace),
+		client.MatchingFields{index: value},
+	); err != nil {
+		log.FromContext(ctx).Error(err, "could not list dataplanes in map func")
+		return
+	}
+
+	for _, dataplane := range dataplanes.Items {
+		recs = append(recs, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: dataplane.Namespace,
+				Name:      dataplane.Name,
+			},
+		})
+	}
+
+	return
+}
+
+// indexDataPlaneOnSecrets indexes the DataPlanes on the names of the Secrets
+// holding their license or declarative configuration.
+func indexDataPlaneOnSecrets(obj client.Object) (names []string) {
+	dataplane, ok := obj.(*operatorv1alpha1.DataPlane)
+	if !ok {
+		return nil
+	}
+	if dataplane.Spec.License != nil {
+		names = append(names, dataplane.Spec.License.SecretRef.Name)
+	}
+	if source := dataplane.Spec.DeclarativeConfig; source != nil && source.SecretKeyRef != nil {
+		names = append(names, source.SecretKeyRef.Name)
+	}
+	return names
+}
+
+// indexDataPlaneOnConfigMaps indexes the DataPlanes on the names of the
+// ConfigMaps holding their declarative configuration.
+func indexDataPlaneOnConfigMaps(obj client.Object) []string {
+	dataplane, ok := obj.(*operatorv1alpha1.DataPlane)
+	if !ok {
+		return nil
+	}
+	if source := dataplane.Spec.DeclarativeConfig; source != nil && source.ConfigMapKeyRef != nil {
+		return []string{source.ConfigMapKeyRef.Name}
+	}
+	return nil
+}
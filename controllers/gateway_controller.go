@@ -4,15 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -34,16 +38,27 @@ import (
 // GatewayReconciler reconciles a Gateway object
 type GatewayReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme        *runtime.Scheme
+	eventRecorder record.EventRecorder
+
+	// ReferenceGrantEnabled indicates whether the ReferenceGrant CRD is installed
+	// in the cluster and ReferenceGrants have to be watched.
+	ReferenceGrantEnabled bool
+
+	// GatewayAPIV1beta1Enabled indicates whether the cluster serves the v1beta1
+	// version of Gateways and GatewayClasses, which have to be watched instead of
+	// the v1alpha2 ones.
+	GatewayAPIV1beta1Enabled bool
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *GatewayReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.eventRecorder = mgr.GetEventRecorderFor("gateway")
 
-	return ctrl.NewControllerManagedBy(mgr).
+	blder := ctrl.NewControllerManagedBy(mgr).
 		// watch Gateway objects, filtering out any Gateways which are not configured with
 		// a supported GatewayClass controller name.
-		For(&gatewayv1alpha2.Gateway{},
+		For(gatewayutils.GatewayForVersion(r.GatewayAPIV1beta1Enabled),
 			builder.WithPredicates(predicate.NewPredicateFuncs(r.gatewayHasMatchingGatewayClass))).
 		// watch for changes in dataplanes created by the gateway controller
 		Owns(&operatorv1alpha1.DataPlane{}).
@@ -51,6 +66,14 @@ func (r *GatewayReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&operatorv1alpha1.ControlPlane{}).
 		// watch for changes in networkpolicies created by the gateway controller
 		Owns(&networkingv1.NetworkPolicy{}).
+		// watch for changes in dataplanes and controlplanes shared between gateways,
+		// which are not owned by any of them, and enqueue the gateways using them.
+		Watches(
+			&source.Kind{Type: &operatorv1alpha1.DataPlane{}},
+			handler.EnqueueRequestsFromMapFunc(r.listGatewaysForSharedDataPlane)).
+		Watches(
+			&source.Kind{Type: &operatorv1alpha1.ControlPlane{}},
+			handler.EnqueueRequestsFromMapFunc(r.listGatewaysForSharedDataPlane)).
 		// watch for updates to GatewayConfigurations, if any configuration targets a
 		// Gateway that is supported, enqueue that Gateway.
 		Watches(
@@ -60,10 +83,19 @@ func (r *GatewayReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		// watch for updates to GatewayClasses, if any GatewayClasses change, enqueue
 		// reconciliation for all supported gateway objects which reference it.
 		Watches(
-			&source.Kind{Type: &gatewayv1alpha2.GatewayClass{}},
+			&source.Kind{Type: gatewayutils.GatewayClassForVersion(r.GatewayAPIV1beta1Enabled)},
 			handler.EnqueueRequestsFromMapFunc(r.listGatewaysForGatewayClass),
-			builder.WithPredicates(predicate.NewPredicateFuncs(r.gatewayClassMatchesController))).
-		Complete(r)
+			builder.WithPredicates(predicate.NewPredicateFuncs(r.gatewayClassMatchesController)))
+
+	if r.ReferenceGrantEnabled {
+		// watch for updates to ReferenceGrants, enqueue the Gateways in the namespaces
+		// which may be permitted to reference objects in the ReferenceGrant namespace.
+		blder.Watches(
+			&source.Kind{Type: &gatewayv1alpha2.ReferenceGrant{}},
+			handler.EnqueueRequestsFromMapFunc(r.listGatewaysForReferenceGrant))
+	}
+
+	return blder.Complete(r)
 }
 
 // Reconcile moves the current state of an object to the intended state.
@@ -79,6 +111,20 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return ctrl.Result{}, err
 	}
 
+	if !gateway.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(gateway.Gateway, string(GatewayFinalizerReleaseSharedDataPlane)) {
+			debug(log, "releasing the shared dataplane used by the gateway", gateway)
+			return ctrl.Result{}, r.releaseSharedDataPlane(ctx, gateway)
+		}
+		debug(log, "gateway is being deleted, nothing to do", gateway)
+		return ctrl.Result{}, nil
+	}
+
+	if restore, ok := gateway.Annotations[consts.GatewayRestoreInProgressAnnotation]; ok {
+		debug(log, "gateway is being restored by GatewayRestore "+restore+", nothing to do", gateway)
+		return ctrl.Result{}, nil // requeue will be triggered by the removal of the annotation
+	}
+
 	k8sutils.InitReady(gateway)
 
 	debug(log, "checking gatewayclass", gateway)
@@ -101,14 +147,100 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		k8sutils.SetCondition(condition, gateway)
 	}
 
+	debug(log, "resolving references of gateway listeners", gateway)
+	if err := r.ensureListenersReferencesResolved(ctx, gateway); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	debug(log, "determining configuration", gateway)
 	gatewayConfig, err := r.getOrCreateGatewayConfiguration(ctx, gatewayClass)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+	attachedGatewayConfigs, err := r.getAttachedGatewayConfigurations(ctx, gateway)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			info(log, "GatewayConfiguration referenced by the gateway not found: "+err.Error(), gateway)
+			r.eventRecorder.Event(gateway.Gateway, "Warning", "GatewayConfigurationNotFound", err.Error())
+			return ctrl.Result{}, nil // requeue will be triggered by the creation of the gateway configuration
+		}
+		return ctrl.Result{}, err
+	}
+
+	shared := getSharedDataPlane(gateway, gatewayClass, gatewayConfig)
+	if shared != nil {
+		if k8sutils.EnsureFinalizersInMetadata(&gateway.ObjectMeta, string(GatewayFinalizerReleaseSharedDataPlane)) {
+			debug(log, "adding the finalizer to release the shared dataplane", gateway)
+			return ctrl.Result{}, r.Client.Update(ctx, gateway.Gateway) // requeue will be triggered by the update of the gateway
+		}
+
+		if shared.namespace != gateway.Namespace {
+			debug(log, "checking the gateway is permitted to use the shared dataplane", gateway)
+			granted, err := r.isSharedDataPlaneGranted(ctx, gateway.Namespace, shared)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if !granted {
+				message := fmt.Sprintf("use of the dataplane shared in namespace %s is not permitted by any ReferenceGrant", shared.namespace)
+				r.eventRecorder.Event(gateway.Gateway, "Warning", string(GatewayRefNotPermittedReason), message)
+				k8sutils.SetCondition(createDataPlaneCondition(metav1.ConditionFalse, GatewayRefNotPermittedReason, message), gateway)
+				return ctrl.Result{}, r.updateStatus(ctx, gateway) // requeue will be triggered by the creation of the ReferenceGrant
+			}
+		}
+
+		debug(log, "checking listeners conflicts with the gateways sharing the dataplane", gateway)
+		conflicted, err := r.ensureListenersConflictsReported(ctx, gateway, shared)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if len(conflicted) > 0 {
+			k8sutils.SetCondition(k8sutils.NewCondition(GatewayListenersType, metav1.ConditionFalse, GatewayListenersConflictedReason,
+				fmt.Sprintf("listeners %s conflict with the gateways sharing the dataplane", strings.Join(conflicted, ", "))), gateway)
+		} else {
+			k8sutils.SetCondition(k8sutils.NewCondition(GatewayListenersType, metav1.ConditionTrue, k8sutils.ResourceReadyReason, ""), gateway)
+		}
+	} else {
+		k8sutils.RemoveCondition(GatewayListenersType, gateway)
+		if controllerutil.ContainsFinalizer(gateway.Gateway, string(GatewayFinalizerReleaseSharedDataPlane)) {
+			debug(log, "sharing has been disabled, releasing the shared dataplane", gateway)
+			return ctrl.Result{}, r.releaseSharedDataPlane(ctx, gateway)
+		}
+	}
+
+	gatewayConfigs := []*operatorv1alpha1.GatewayConfiguration{gatewayConfig}
+	if len(attachedGatewayConfigs) > 0 {
+		if shared != nil {
+			// the shared dataplane and controlplane are configured by the gatewayclass
+			// for all the gateways using them.
+			r.eventRecorder.Event(gateway.Gateway, "Warning", "GatewayConfigurationIgnored",
+				"the configuration of the gateway is ignored as the gateway shares the dataplane of its gatewayclass")
+		} else {
+			debug(log, "merging the configuration of the gateway over the one of its gatewayclass", gateway)
+			gatewayConfigs = append(gatewayConfigs, attachedGatewayConfigs...)
+			gatewayConfig, err = mergeGatewayConfigurations(gatewayConfig, attachedGatewayConfigs...)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	}
+
+	debug(log, "checking the compatibility of the dataplane and controlplane versions", gateway)
+	if err := ensureGatewayConfigVersionsCompatible(gatewayConfig); errors.Is(err, operatorerrors.ErrIncompatibleVersions) {
+		info(log, "unsupported dataplane and controlplane versions: "+err.Error(), gateway)
+		r.eventRecorder.Event(gateway.Gateway, "Warning", string(GatewayIncompatibleVersionsReason), err.Error())
+		k8sutils.SetCondition(createControlPlaneCondition(metav1.ConditionFalse, GatewayIncompatibleVersionsReason, err.Error()), gateway)
+		k8sutils.RemoveCondition(GatewayVersionsCompatibleType, gateway)
+		return ctrl.Result{}, r.updateStatus(ctx, gateway) // requeue will be triggered by the update of the gateway configuration
+	} else if setGatewayVersionsCompatibleCondition(gateway, err) && err != nil {
+		// the event is only recorded when the condition changes, as the
+		// compatibility stays unknown on every reconciliation, and the
+		// condition is persisted right away not to record it again.
+		r.eventRecorder.Event(gateway.Gateway, "Warning", string(GatewayUnknownVersionCompatibilityReason), err.Error())
+		return ctrl.Result{}, r.updateStatus(ctx, gateway) // requeue will be triggered by the update of the gateway status
+	}
 
 	// Dataplane
-	dataplane := r.provisionDataPlane(ctx, gateway, gatewayConfig)
+	dataplane := r.provisionDataPlane(ctx, gateway, gatewayConfig, gatewayConfigs, shared)
 
 	if !k8sutils.IsValidCondition(DataPlaneReadyType, gateway) {
 		err := r.updateStatus(ctx, gateway) // requeue will be triggered by the update of the dataplane status
@@ -138,7 +270,7 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	}
 
 	// ControlPlane
-	controlplane := r.provisionControlPlane(ctx, gatewayClass, gateway, gatewayConfig, dataplane, services)
+	controlplane := r.provisionControlPlane(ctx, gatewayClass, gateway, gatewayConfig, gatewayConfigs, dataplane, services, shared)
 
 	if !k8sutils.IsValidCondition(ControlPlaneReadyType, gateway) {
 		err := r.updateStatus(ctx, gateway)
@@ -147,7 +279,7 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 
 	// DataPlane NetworkPolicies
 	debug(log, "ensuring DataPlane's NetworkPolicy is created", gateway)
-	createdOrUpdated, err := r.ensureDataPlaneHasNetworkPolicy(ctx, gateway, dataplane, controlplane)
+	createdOrUpdated, err := r.ensureDataPlaneHasNetworkPolicy(ctx, gateway, dataplane, controlplane, shared)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -178,16 +310,26 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	return ctrl.Result{}, err
 }
 
-func (r *GatewayReconciler) provisionDataPlane(ctx context.Context, gateway *gatewayDecorator, gatewayConfig *operatorv1alpha1.GatewayConfiguration) *operatorv1alpha1.DataPlane {
+func (r *GatewayReconciler) provisionDataPlane(
+	ctx context.Context,
+	gateway *gatewayDecorator,
+	gatewayConfig *operatorv1alpha1.GatewayConfiguration,
+	gatewayConfigs []*operatorv1alpha1.GatewayConfiguration,
+	shared *sharedDataPlane,
+) *operatorv1alpha1.DataPlane {
 	log := log.FromContext(ctx).WithName("gateway")
 
 	r.setDataplaneGatewayConfigDefaults(gatewayConfig)
 	debug(log, "looking for associated dataplanes", gateway)
-	dataplanes, err := gatewayutils.ListDataPlanesForGateway(
-		ctx,
-		r.Client,
-		gateway.Gateway,
+	var (
+		dataplanes []operatorv1alpha1.DataPlane
+		err        error
 	)
+	if shared != nil {
+		dataplanes, err = gatewayutils.ListSharedDataPlanes(ctx, r.Client, shared.namespace, shared.key)
+	} else {
+		dataplanes, err = gatewayutils.ListDataPlanesForGateway(ctx, r.Client, gateway.Gateway)
+	}
 	if err != nil {
 		k8sutils.SetCondition(createDataPlaneCondition(metav1.ConditionFalse, k8sutils.UnableToProvisionReason, err.Error()), gateway)
 		return nil
@@ -200,8 +342,10 @@ func (r *GatewayReconciler) provisionDataPlane(ctx context.Context, gateway *gat
 		return nil
 	}
 	if count == 0 {
-		err = r.createDataPlane(ctx, gateway, gatewayConfig)
-		if err != nil {
+		err = r.createDataPlane(ctx, gateway, gatewayConfig, gatewayConfigs, shared)
+		// a shared dataplane may have been created by another gateway at once,
+		// it's used once it's found.
+		if err != nil && !k8serrors.IsAlreadyExists(err) {
 			k8sutils.SetCondition(createDataPlaneCondition(metav1.ConditionFalse, k8sutils.UnableToProvisionReason, err.Error()), gateway)
 		} else {
 			k8sutils.SetCondition(createDataPlaneCondition(metav1.ConditionFalse, k8sutils.ResourceCreatedOrUpdatedReason, k8sutils.ResourceCreatedMessage), gateway)
@@ -210,19 +354,34 @@ func (r *GatewayReconciler) provisionDataPlane(ctx context.Context, gateway *gat
 	}
 	dataplane := dataplanes[0].DeepCopy()
 
+	if shared != nil && gatewayutils.AddSharedDataPlaneGateway(dataplane, gateway.Gateway) {
+		debug(log, "registering the gateway as a user of the shared dataplane", gateway)
+		if err := r.Client.Update(ctx, dataplane); err != nil {
+			k8sutils.SetCondition(createDataPlaneCondition(metav1.ConditionFalse, k8sutils.UnableToProvisionReason, err.Error()), gateway)
+			return nil
+		}
+	}
+
 	debug(log, "ensuring dataplane config is up to date", gateway)
+	generationsUpdated := gatewayutils.SetGatewayConfigurationGenerations(dataplane, gatewayConfigs...)
+	specUpdated := false
 	if gatewayConfig.Spec.DataPlaneDeploymentOptions != nil {
 		if !dataplaneSpecDeepEqual(&dataplane.Spec.DataPlaneDeploymentOptions, gatewayConfig.Spec.DataPlaneDeploymentOptions) {
 			debug(log, "dataplane config is out of date, updating", gateway)
 			dataplane.Spec.DataPlaneDeploymentOptions = *gatewayConfig.Spec.DataPlaneDeploymentOptions
-			err = r.Client.Update(ctx, dataplane)
-			if err != nil {
-				k8sutils.SetCondition(createDataPlaneCondition(metav1.ConditionFalse, k8sutils.UnableToProvisionReason, err.Error()), gateway)
-				return nil
-			}
-			k8sutils.SetCondition(createDataPlaneCondition(metav1.ConditionFalse, k8sutils.ResourceCreatedOrUpdatedReason, k8sutils.ResourceUpdatedMessage), gateway)
+			specUpdated = true
+		}
+	}
+	if specUpdated || generationsUpdated {
+		err = r.Client.Update(ctx, dataplane)
+		if err != nil {
+			k8sutils.SetCondition(createDataPlaneCondition(metav1.ConditionFalse, k8sutils.UnableToProvisionReason, err.Error()), gateway)
+			return nil
 		}
 	}
+	if specUpdated {
+		k8sutils.SetCondition(createDataPlaneCondition(metav1.ConditionFalse, k8sutils.ResourceCreatedOrUpdatedReason, k8sutils.ResourceUpdatedMessage), gateway)
+	}
 
 	debug(log, "waiting for dataplane readiness", gateway)
 
@@ -239,15 +398,25 @@ func (r *GatewayReconciler) provisionControlPlane(
 	gatewayClass *gatewayv1alpha2.GatewayClass,
 	gateway *gatewayDecorator,
 	gatewayConfig *operatorv1alpha1.GatewayConfiguration,
+	gatewayConfigs []*operatorv1alpha1.GatewayConfiguration,
 	dataplane *operatorv1alpha1.DataPlane,
 	services []corev1.Service,
+	shared *sharedDataPlane,
 ) *operatorv1alpha1.ControlPlane {
 	log := log.FromContext(ctx).WithName("gateway")
 
-	r.setControlplaneGatewayConfigDefaults(gateway, gatewayConfig, dataplane.Name, services[0].Name)
+	r.setControlplaneGatewayConfigDefaults(gatewayConfig, dataplane.Namespace, dataplane.Name, services[0].Name)
 
 	debug(log, "looking for associated controlplanes", gateway)
-	controlplanes, err := gatewayutils.ListControlPlanesForGateway(ctx, r.Client, gateway.Gateway)
+	var (
+		controlplanes []operatorv1alpha1.ControlPlane
+		err           error
+	)
+	if shared != nil {
+		controlplanes, err = gatewayutils.ListSharedControlPlanes(ctx, r.Client, shared.namespace, shared.key)
+	} else {
+		controlplanes, err = gatewayutils.ListControlPlanesForGateway(ctx, r.Client, gateway.Gateway)
+	}
 	if err != nil {
 		k8sutils.SetCondition(createControlPlaneCondition(metav1.ConditionFalse, k8sutils.UnableToProvisionReason, err.Error()), gateway)
 		return nil
@@ -260,8 +429,8 @@ func (r *GatewayReconciler) provisionControlPlane(
 		return nil
 	}
 	if count == 0 {
-		err := r.createControlPlane(ctx, gatewayClass, gateway, gatewayConfig, dataplane.Name)
-		if err != nil {
+		err := r.createControlPlane(ctx, gatewayClass, gateway, gatewayConfig, gatewayConfigs, dataplane.Name, shared)
+		if err != nil && !k8serrors.IsAlreadyExists(err) {
 			k8sutils.SetCondition(createControlPlaneCondition(metav1.ConditionFalse, k8sutils.UnableToProvisionReason, err.Error()), gateway)
 		} else {
 			k8sutils.SetCondition(createControlPlaneCondition(metav1.ConditionFalse, k8sutils.ResourceCreatedOrUpdatedReason, k8sutils.ResourceCreatedMessage), gateway)
@@ -271,18 +440,25 @@ func (r *GatewayReconciler) provisionControlPlane(
 	controlplane := controlplanes[0].DeepCopy()
 
 	debug(log, "ensuring controlplane config is up to date", gateway)
+	generationsUpdated := gatewayutils.SetGatewayConfigurationGenerations(controlplane, gatewayConfigs...)
+	specUpdated := false
 	if gatewayConfig.Spec.ControlPlaneDeploymentOptions != nil {
 		if !controlplaneSpecDeepEqual(&controlplane.Spec.ControlPlaneDeploymentOptions, gatewayConfig.Spec.ControlPlaneDeploymentOptions) {
 			debug(log, "controlplane config is out of date, updating", gateway)
 			controlplane.Spec.ControlPlaneDeploymentOptions = *gatewayConfig.Spec.ControlPlaneDeploymentOptions
-			err = r.Client.Update(ctx, controlplane)
-			if err != nil {
-				k8sutils.SetCondition(createControlPlaneCondition(metav1.ConditionFalse, k8sutils.UnableToProvisionReason, err.Error()), gateway)
-				return nil
-			}
-			k8sutils.SetCondition(createControlPlaneCondition(metav1.ConditionFalse, k8sutils.ResourceCreatedOrUpdatedReason, k8sutils.ResourceUpdatedMessage), gateway)
+			specUpdated = true
 		}
 	}
+	if specUpdated || generationsUpdated {
+		err = r.Client.Update(ctx, controlplane)
+		if err != nil {
+			k8sutils.SetCondition(createControlPlaneCondition(metav1.ConditionFalse, k8sutils.UnableToProvisionReason, err.Error()), gateway)
+			return nil
+		}
+	}
+	if specUpdated {
+		k8sutils.SetCondition(createControlPlaneCondition(metav1.ConditionFalse, k8sutils.ResourceCreatedOrUpdatedReason, k8sutils.ResourceUpdatedMessage), gateway)
+	}
 
 	debug(log, "waiting for controlplane readiness", gateway)
 	if !k8sutils.IsReady(controlplane) {
@@ -302,7 +478,16 @@ func createControlPlaneCondition(status metav1.ConditionStatus, reason k8sutils.
 	return k8sutils.NewCondition(ControlPlaneReadyType, status, reason, message)
 }
 
+func createListenerConflictedCondition(status metav1.ConditionStatus, reason gatewayv1alpha2.ListenerConditionReason, message string) metav1.Condition {
+	return k8sutils.NewCondition(k8sutils.ConditionType(gatewayv1alpha2.ListenerConditionConflicted), status, k8sutils.ConditionReason(reason), message)
+}
+
+func createListenerResolvedRefsCondition(status metav1.ConditionStatus, reason gatewayv1alpha2.ListenerConditionReason, message string) metav1.Condition {
+	return k8sutils.NewCondition(k8sutils.ConditionType(gatewayv1alpha2.ListenerConditionResolvedRefs), status, k8sutils.ConditionReason(reason), message)
+}
+
 // updateStatus Updates the resource status only when there are changes in the Conditions
+// or in the status of the listeners
 func (r *GatewayReconciler) updateStatus(ctx context.Context, updated *gatewayDecorator) error {
 	current := newGateway()
 	err := r.Client.Get(ctx, client.ObjectKeyFromObject(updated.Gateway), current.Gateway)
@@ -310,7 +495,7 @@ func (r *GatewayReconciler) updateStatus(ctx context.Context, updated *gatewayDe
 	if err != nil && !k8serrors.IsNotFound(err) {
 		return err
 	}
-	if k8sutils.NeedsUpdate(current, updated) {
+	if k8sutils.NeedsUpdate(current, updated) || !reflect.DeepEqual(current.Status.Listeners, updated.Status.Listeners) {
 		return r.Client.Status().Update(ctx, updated.Gateway)
 	}
 	return nil
@@ -23,6 +23,14 @@ const (
 
 	// DataPlaneReadyType the DataPlane is deployed and Ready
 	DataPlaneReadyType k8sutils.ConditionType = "DataPlaneReady"
+
+	// GatewayListenersType the listeners of the Gateway don't conflict with the
+	// ones of the Gateways sharing its DataPlane
+	GatewayListenersType k8sutils.ConditionType = "GatewayListeners"
+
+	// GatewayVersionsCompatibleType the ControlPlane version configured for the
+	// Gateway supports the DataPlane version
+	GatewayVersionsCompatibleType k8sutils.ConditionType = "VersionsCompatible"
 )
 
 // -----------------------------------------------------------------------------
@@ -32,6 +40,24 @@ const (
 const (
 	// GatewayServiceErrorReason the Gateway Service is not properly configured
 	GatewayServiceErrorReason k8sutils.ConditionReason = "GatewayServiceError"
+
+	// GatewayIncompatibleVersionsReason the ControlPlane version configured for
+	// the Gateway does not support the DataPlane version
+	GatewayIncompatibleVersionsReason k8sutils.ConditionReason = "IncompatibleVersions"
+
+	// GatewayUnknownVersionCompatibilityReason the compatibility of the
+	// ControlPlane and DataPlane versions configured for the Gateway can't be
+	// determined, e.g. for Kong Enterprise tags. It's a warning reason of the
+	// VersionsCompatible condition, which stays True not to block the Gateway
+	GatewayUnknownVersionCompatibilityReason k8sutils.ConditionReason = "UnknownVersionCompatibility"
+
+	// GatewayListenersConflictedReason some listeners of the Gateway conflict
+	// with the ones of the Gateways sharing its DataPlane
+	GatewayListenersConflictedReason k8sutils.ConditionReason = "ListenersConflicted"
+
+	// GatewayRefNotPermittedReason the Gateway is not permitted to use the
+	// DataPlane shared in another namespace by any ReferenceGrant
+	GatewayRefNotPermittedReason k8sutils.ConditionReason = "RefNotPermitted"
 )
 
 // gatewayDecorator Decorator object to add additional functionality to the base k8s Gateway
@@ -52,3 +78,23 @@ func newGateway() *gatewayDecorator {
 		new(gatewayv1alpha2.Gateway),
 	}
 }
+
+// listenerStatusDecorator Decorator object to add additional functionality to the
+// status of the Gateway's listeners, whose conditions are observed for the
+// generation of the Gateway
+type listenerStatusDecorator struct {
+	*gatewayv1alpha2.ListenerStatus
+	generation int64
+}
+
+func (l *listenerStatusDecorator) GetConditions() []metav1.Condition {
+	return l.Conditions
+}
+
+func (l *listenerStatusDecorator) SetConditions(conditions []metav1.Condition) {
+	l.Conditions = conditions
+}
+
+func (l *listenerStatusDecorator) GetGeneration() int64 {
+	return l.generation
+}
@@ -0,0 +1,13 @@
+package controllers
+
+// -----------------------------------------------------------------------------
+// Gateway - Finalizers
+// -----------------------------------------------------------------------------
+
+// GatewayFinalizer defines finalizers added by gateway controller.
+type GatewayFinalizer string
+
+const (
+	// GatewayFinalizerReleaseSharedDataPlane is the finalizer to release the shared dataplane used by the gateway on deleting.
+	GatewayFinalizerReleaseSharedDataPlane GatewayFinalizer = "gateway-operator.konghq.com/release-shared-dataplane"
+)
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
@@ -19,6 +21,7 @@ import (
 	operatorerrors "github.com/kong/gateway-operator/internal/errors"
 	gatewayutils "github.com/kong/gateway-operator/internal/utils/gateway"
 	k8sutils "github.com/kong/gateway-operator/internal/utils/kubernetes"
+	"github.com/kong/gateway-operator/internal/versions"
 	"github.com/kong/gateway-operator/pkg/vars"
 )
 
@@ -29,6 +32,8 @@ import (
 func (r *GatewayReconciler) createDataPlane(ctx context.Context,
 	gateway *gatewayDecorator,
 	gatewayConfig *operatorv1alpha1.GatewayConfiguration,
+	gatewayConfigs []*operatorv1alpha1.GatewayConfiguration,
+	shared *sharedDataPlane,
 ) error {
 	dataplane := &operatorv1alpha1.DataPlane{
 		ObjectMeta: metav1.ObjectMeta{
@@ -39,8 +44,19 @@ func (r *GatewayReconciler) createDataPlane(ctx context.Context,
 	if gatewayConfig.Spec.DataPlaneDeploymentOptions != nil {
 		dataplane.Spec.DataPlaneDeploymentOptions = *gatewayConfig.Spec.DataPlaneDeploymentOptions
 	}
-	k8sutils.SetOwnerForObject(dataplane, gateway)
+	if shared != nil {
+		// shared dataplanes are not owned by any of the gateways using them, their
+		// lifecycle is tied to the last gateway releasing them instead.
+		dataplane.Namespace = shared.namespace
+		dataplane.Name = shared.name
+		dataplane.GenerateName = ""
+		gatewayutils.LabelObjectAsShared(dataplane, shared.key)
+		gatewayutils.AddSharedDataPlaneGateway(dataplane, gateway.Gateway)
+	} else {
+		k8sutils.SetOwnerForObject(dataplane, gateway)
+	}
 	gatewayutils.LabelObjectAsGatewayManaged(dataplane)
+	gatewayutils.SetGatewayConfigurationGenerations(dataplane, gatewayConfigs...)
 	return r.Client.Create(ctx, dataplane)
 }
 
@@ -49,7 +65,9 @@ func (r *GatewayReconciler) createControlPlane(
 	gatewayClass *gatewayv1alpha2.GatewayClass,
 	gateway *gatewayDecorator,
 	gatewayConfig *operatorv1alpha1.GatewayConfiguration,
+	gatewayConfigs []*operatorv1alpha1.GatewayConfiguration,
 	dataplaneName string,
+	shared *sharedDataPlane,
 ) error {
 	controlplane := &operatorv1alpha1.ControlPlane{
 		ObjectMeta: metav1.ObjectMeta{
@@ -66,11 +84,85 @@ func (r *GatewayReconciler) createControlPlane(
 	if controlplane.Spec.DataPlane == nil {
 		controlplane.Spec.DataPlane = &dataplaneName
 	}
-	k8sutils.SetOwnerForObject(controlplane, gateway)
+	if shared != nil {
+		controlplane.Namespace = shared.namespace
+		controlplane.Name = shared.name
+		controlplane.GenerateName = ""
+		gatewayutils.LabelObjectAsShared(controlplane, shared.key)
+	} else {
+		k8sutils.SetOwnerForObject(controlplane, gateway)
+	}
 	gatewayutils.LabelObjectAsGatewayManaged(controlplane)
+	gatewayutils.SetGatewayConfigurationGenerations(controlplane, gatewayConfigs...)
 	return r.Client.Create(ctx, controlplane)
 }
 
+// ensureGatewayConfigVersionsCompatible picks the version of the ControlPlane
+// or the DataPlane matching the other one when only one of them is set in the
+// GatewayConfiguration, then checks that the ControlPlane version supports the
+// DataPlane version. It returns an ErrIncompatibleVersions error for
+// unsupported combinations and an ErrUnknownVersionCompatibility error when
+// the compatibility of the versions can't be determined.
+func ensureGatewayConfigVersionsCompatible(gatewayConfig *operatorv1alpha1.GatewayConfiguration) error {
+	if gatewayConfig.Spec.DataPlaneDeploymentOptions == nil {
+		gatewayConfig.Spec.DataPlaneDeploymentOptions = new(operatorv1alpha1.DataPlaneDeploymentOptions)
+	}
+	if gatewayConfig.Spec.ControlPlaneDeploymentOptions == nil {
+		gatewayConfig.Spec.ControlPlaneDeploymentOptions = new(operatorv1alpha1.ControlPlaneDeploymentOptions)
+	}
+	dataplaneOpts := &gatewayConfig.Spec.DataPlaneDeploymentOptions.DeploymentOptions
+	controlplaneOpts := &gatewayConfig.Spec.ControlPlaneDeploymentOptions.DeploymentOptions
+
+	kongVersion, kongVersionSet := versions.VersionFromDeploymentOptions(dataplaneOpts)
+	kicVersion, kicVersionSet := versions.VersionFromDeploymentOptions(controlplaneOpts)
+	switch {
+	case kongVersionSet && !kicVersionSet && deploymentOptionsUseDefaultImage(controlplaneOpts):
+		if version, ok := versions.DefaultKICVersionForKong(kongVersion); ok {
+			controlplaneOpts.Version = &version
+			kicVersion, kicVersionSet = version, true
+		}
+	case kicVersionSet && !kongVersionSet && deploymentOptionsUseDefaultImage(dataplaneOpts):
+		if version, ok := versions.DefaultKongVersionForKIC(kicVersion); ok {
+			dataplaneOpts.Version = &version
+			kongVersion, kongVersionSet = version, true
+		}
+	}
+
+	// the compatibility of the versions which are not set can't be checked:
+	// they're either the default versions, which are compatible, or chosen by
+	// AutomaticUpgrades or the tag-less custom images.
+	if !kongVersionSet || !kicVersionSet {
+		return nil
+	}
+	return versions.CheckCompatibility(kicVersion, kongVersion)
+}
+
+// setGatewayVersionsCompatibleCondition reports the unknown compatibility of
+// the ControlPlane and DataPlane versions of the Gateway, returned by
+// ensureGatewayConfigVersionsCompatible as an ErrUnknownVersionCompatibility
+// error, through its VersionsCompatible condition, and removes the condition
+// once the versions are compatible. It returns true when the condition changed.
+func setGatewayVersionsCompatibleCondition(gateway *gatewayDecorator, compatibilityErr error) bool {
+	if compatibilityErr == nil {
+		return k8sutils.RemoveCondition(GatewayVersionsCompatibleType, gateway)
+	}
+
+	condition := k8sutils.NewCondition(GatewayVersionsCompatibleType, metav1.ConditionTrue,
+		GatewayUnknownVersionCompatibilityReason, compatibilityErr.Error())
+	if current, exists := k8sutils.GetCondition(GatewayVersionsCompatibleType, gateway); exists &&
+		current.Status == condition.Status && current.Reason == condition.Reason && current.Message == condition.Message {
+		return false
+	}
+	k8sutils.SetCondition(condition, gateway)
+	return true
+}
+
+// deploymentOptionsUseDefaultImage returns true when neither the container
+// image nor its version are configured by the DeploymentOptions.
+func deploymentOptionsUseDefaultImage(opts *operatorv1alpha1.DeploymentOptions) bool {
+	return opts.ContainerImage == nil && opts.Version == nil && opts.AutomaticUpgrades == nil
+}
+
 func (r *GatewayReconciler) ensureGatewayMarkedReady(ctx context.Context, gateway *gatewayDecorator, dataplane *operatorv1alpha1.DataPlane) error {
 	if !k8sutils.IsReady(gateway) {
 		services, err := k8sutils.ListServicesForOwner(
@@ -125,6 +217,99 @@ func (r *GatewayReconciler) ensureGatewayMarkedReady(ctx context.Context, gatewa
 	return nil
 }
 
+// ensureListenersReferencesResolved sets the ResolvedRefs condition on the
+// status of every listener of the Gateway, reporting certificate references
+// which are invalid or which point to other namespaces without being permitted
+// by a ReferenceGrant.
+func (r *GatewayReconciler) ensureListenersReferencesResolved(ctx context.Context, gateway *gatewayDecorator) error {
+	listenerStatuses := make([]gatewayv1alpha2.ListenerStatus, 0, len(gateway.Spec.Listeners))
+	for _, listener := range gateway.Spec.Listeners {
+		listenerStatus := gatewayv1alpha2.ListenerStatus{
+			Name:           listener.Name,
+			SupportedKinds: []gatewayv1alpha2.RouteGroupKind{},
+			Conditions:     []metav1.Condition{},
+		}
+		for _, existingStatus := range gateway.Status.Listeners {
+			if existingStatus.Name == listener.Name {
+				listenerStatus = *existingStatus.DeepCopy()
+				break
+			}
+		}
+
+		condition, err := r.resolveListenerReferences(ctx, gateway, listener)
+		if err != nil {
+			return err
+		}
+
+		decoratedStatus := &listenerStatusDecorator{&listenerStatus, gateway.Generation}
+		current, present := k8sutils.GetCondition(k8sutils.ConditionType(condition.Type), decoratedStatus)
+		if !present || current.Status != condition.Status || current.Reason != condition.Reason || current.Message != condition.Message ||
+			current.ObservedGeneration != gateway.Generation {
+			k8sutils.SetCondition(condition, decoratedStatus)
+		}
+		listenerStatuses = append(listenerStatuses, listenerStatus)
+	}
+
+	gateway.Status.Listeners = listenerStatuses
+	return nil
+}
+
+// resolveListenerReferences verifies the certificate references of a listener
+// and returns the ResolvedRefs condition which describes the result.
+func (r *GatewayReconciler) resolveListenerReferences(
+	ctx context.Context,
+	gateway *gatewayDecorator,
+	listener gatewayv1alpha2.Listener,
+) (metav1.Condition, error) {
+	if listener.TLS != nil {
+		for _, certificateRef := range listener.TLS.CertificateRefs {
+			if (certificateRef.Group != nil && *certificateRef.Group != "") ||
+				(certificateRef.Kind != nil && *certificateRef.Kind != "Secret") {
+				return createListenerResolvedRefsCondition(metav1.ConditionFalse, gatewayv1alpha2.ListenerReasonInvalidCertificateRef,
+					fmt.Sprintf("certificate reference %s is not a Secret", certificateRef.Name)), nil
+			}
+
+			secretNamespace := gateway.Namespace
+			if certificateRef.Namespace != nil && *certificateRef.Namespace != "" {
+				secretNamespace = string(*certificateRef.Namespace)
+			}
+
+			secretName := certificateRef.Name
+			granted, err := gatewayutils.IsReferenceGranted(ctx, r.Client,
+				gatewayv1alpha2.ReferenceGrantFrom{
+					Group:     gatewayv1alpha2.GroupName,
+					Kind:      "Gateway",
+					Namespace: gatewayv1alpha2.Namespace(gateway.Namespace),
+				},
+				gatewayv1alpha2.ReferenceGrantTo{
+					Group: "",
+					Kind:  "Secret",
+					Name:  &secretName,
+				},
+				secretNamespace,
+			)
+			if err != nil {
+				return metav1.Condition{}, err
+			}
+			if !granted {
+				return createListenerResolvedRefsCondition(metav1.ConditionFalse, gatewayv1alpha2.ListenerReasonRefNotPermitted,
+					fmt.Sprintf("reference to Secret %s/%s is not permitted by any ReferenceGrant", secretNamespace, secretName)), nil
+			}
+
+			secret := new(corev1.Secret)
+			if err := r.Client.Get(ctx, client.ObjectKey{Namespace: secretNamespace, Name: string(secretName)}, secret); err != nil {
+				if k8serrors.IsNotFound(err) {
+					return createListenerResolvedRefsCondition(metav1.ConditionFalse, gatewayv1alpha2.ListenerReasonInvalidCertificateRef,
+						fmt.Sprintf("referenced Secret %s/%s does not exist", secretNamespace, secretName)), nil
+				}
+				return metav1.Condition{}, err
+			}
+		}
+	}
+
+	return createListenerResolvedRefsCondition(metav1.ConditionTrue, gatewayv1alpha2.ListenerReasonResolvedRefs, ""), nil
+}
+
 func (r *GatewayReconciler) verifyGatewayClassSupport(ctx context.Context, gateway *gatewayv1alpha2.Gateway) (*gatewayv1alpha2.GatewayClass, error) {
 	if gateway.Spec.GatewayClassName == "" {
 		return nil, operatorerrors.ErrUnsupportedGateway
@@ -154,6 +339,68 @@ func (r *GatewayReconciler) getOrCreateGatewayConfiguration(ctx context.Context,
 	return gatewayConfig, nil
 }
 
+// getAttachedGatewayConfigurations returns the GatewayConfigurations applying
+// to the Gateway itself, in increasing order of precedence: the oldest one
+// attached to the Gateway through its targetRef, then the one the Gateway
+// references through its annotation.
+func (r *GatewayReconciler) getAttachedGatewayConfigurations(ctx context.Context, gateway *gatewayDecorator) ([]*operatorv1alpha1.GatewayConfiguration, error) {
+	var gatewayConfigs []*operatorv1alpha1.GatewayConfiguration
+
+	attached, err := gatewayutils.ListGatewayConfigurationsAttachedToGateway(ctx, r.Client, gateway.Gateway)
+	if err != nil {
+		return nil, err
+	}
+	if len(attached) > 0 {
+		gatewayConfigs = append(gatewayConfigs, &attached[0])
+	}
+
+	if name := gateway.Annotations[consts.GatewayConfigurationAnnotation]; name != "" {
+		gatewayConfig := new(operatorv1alpha1.GatewayConfiguration)
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: gateway.Namespace, Name: name}, gatewayConfig); err != nil {
+			return nil, err
+		}
+		gatewayConfigs = append(gatewayConfigs, gatewayConfig)
+	}
+
+	return gatewayConfigs, nil
+}
+
+// mergeGatewayConfigurations returns a copy of the GatewayConfiguration with
+// the specs of the overrides merged over its spec, in order, following the
+// semantics of strategic merge patches: the fields set in an override take
+// precedence, maps are merged, and lists are replaced unless Kubernetes merges
+// them by key. The sharing of the DataPlane can't be overridden.
+func mergeGatewayConfigurations(
+	gatewayConfig *operatorv1alpha1.GatewayConfiguration,
+	overrides ...*operatorv1alpha1.GatewayConfiguration,
+) (*operatorv1alpha1.GatewayConfiguration, error) {
+	merged := gatewayConfig.DeepCopy()
+	for _, override := range overrides {
+		overrideSpec := override.Spec.DeepCopy()
+		overrideSpec.SharedDataPlane = nil
+		overrideSpec.TargetRef = nil
+
+		original, err := json.Marshal(merged.Spec)
+		if err != nil {
+			return nil, err
+		}
+		patch, err := json.Marshal(overrideSpec)
+		if err != nil {
+			return nil, err
+		}
+		result, err := strategicpatch.StrategicMergePatch(original, patch, operatorv1alpha1.GatewayConfigurationSpec{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge GatewayConfiguration %s/%s: %w", override.Namespace, override.Name, err)
+		}
+		spec := operatorv1alpha1.GatewayConfigurationSpec{}
+		if err := json.Unmarshal(result, &spec); err != nil {
+			return nil, err
+		}
+		merged.Spec = spec
+	}
+	return merged, nil
+}
+
 func (r *GatewayReconciler) getGatewayConfigForGatewayClass(ctx context.Context, gatewayClass *gatewayv1alpha2.GatewayClass) (*operatorv1alpha1.GatewayConfiguration, error) {
 	if gatewayClass.Spec.ParametersRef == nil {
 		return nil, fmt.Errorf("%w, gatewayClass = %s", operatorerrors.ErrObjectMissingParametersRef, gatewayClass.Name)
@@ -195,8 +442,14 @@ func (r *GatewayReconciler) ensureDataPlaneHasNetworkPolicy(
 	gateway *gatewayDecorator,
 	dataplane *operatorv1alpha1.DataPlane,
 	controlplane *operatorv1alpha1.ControlPlane,
+	shared *sharedDataPlane,
 ) (createdOrUpdate bool, err error) {
-	networkPolicies, err := gatewayutils.ListNetworkPoliciesForGateway(ctx, r.Client, gateway.Gateway)
+	var networkPolicies []networkingv1.NetworkPolicy
+	if shared != nil {
+		networkPolicies, err = gatewayutils.ListSharedNetworkPolicies(ctx, r.Client, shared.namespace, shared.key)
+	} else {
+		networkPolicies, err = gatewayutils.ListNetworkPoliciesForGateway(ctx, r.Client, gateway.Gateway)
+	}
 	if err != nil {
 		return false, err
 	}
@@ -206,8 +459,12 @@ func (r *GatewayReconciler) ensureDataPlaneHasNetworkPolicy(
 		return false, fmt.Errorf("%w, got: %d, expected 1", operatorerrors.ErrTooManyDataPlaneNetworkPolicies, count)
 	}
 
-	generatedPolicy := generateDataPlaneNetworkPolicy(gateway.Namespace, dataplane, controlplane)
-	k8sutils.SetOwnerForObject(generatedPolicy, gateway)
+	generatedPolicy := generateDataPlaneNetworkPolicy(dataplane.Namespace, dataplane, controlplane)
+	if shared != nil {
+		gatewayutils.LabelObjectAsShared(generatedPolicy, shared.key)
+	} else {
+		k8sutils.SetOwnerForObject(generatedPolicy, gateway)
+	}
 	gatewayutils.LabelObjectAsGatewayManaged(generatedPolicy)
 
 	if count == 1 {
@@ -290,3 +547,253 @@ func generateDataPlaneNetworkPolicy(
 		},
 	}
 }
+
+// -----------------------------------------------------------------------------
+// GatewayReconciler - Shared DataPlanes
+// -----------------------------------------------------------------------------
+
+// sharedDataPlane identifies the DataPlane and ControlPlane pair shared between
+// the Gateways of a GatewayClass, whose GatewayConfiguration enables sharing.
+type sharedDataPlane struct {
+	// namespace is the namespace of the shared objects.
+	namespace string
+	// key is the value of the shared dataplane label set on the shared objects.
+	key string
+	// name is the name of the shared objects. It's derived from the
+	// GatewayClass so that the Gateways reconciled at once don't create
+	// several pairs.
+	name string
+	// scope is the scope in which the Gateways share the objects.
+	scope operatorv1alpha1.SharedDataPlaneScope
+}
+
+// getSharedDataPlane returns the shared DataPlane and ControlPlane pair the
+// Gateway has to use, or nil when the GatewayConfiguration does not enable
+// sharing and the Gateway gets a dedicated pair.
+func getSharedDataPlane(
+	gateway *gatewayDecorator,
+	gatewayClass *gatewayv1alpha2.GatewayClass,
+	gatewayConfig *operatorv1alpha1.GatewayConfiguration,
+) *sharedDataPlane {
+	if gatewayConfig.Spec.SharedDataPlane == nil {
+		return nil
+	}
+
+	shared := &sharedDataPlane{
+		namespace: gateway.Namespace,
+		key:       string(gatewayClass.UID),
+		name:      fmt.Sprintf("%s-%s", gatewayClass.Name, computeChecksum([]byte(gatewayClass.UID))),
+		scope:     gatewayConfig.Spec.SharedDataPlane.Scope,
+	}
+	if shared.scope == operatorv1alpha1.SharedDataPlaneScopeCluster && gatewayConfig.Namespace != "" {
+		shared.namespace = gatewayConfig.Namespace
+	}
+	return shared
+}
+
+// releaseSharedDataPlane removes the Gateway from the users of the shared
+// DataPlanes it was registered with. Once the last Gateway releases a shared
+// DataPlane, the DataPlane is deleted together with its ControlPlane and
+// NetworkPolicy. Finally the finalizer is removed from the Gateway.
+func (r *GatewayReconciler) releaseSharedDataPlane(ctx context.Context, gateway *gatewayDecorator) error {
+	dataplanes, err := gatewayutils.ListSharedDataPlanes(ctx, r.Client, "", "")
+	if err != nil {
+		return err
+	}
+
+	for i := range dataplanes {
+		dataplane := &dataplanes[i]
+		if !gatewayutils.RemoveSharedDataPlaneGateway(dataplane, gateway.Gateway) {
+			continue
+		}
+
+		if len(gatewayutils.GetSharedDataPlaneGateways(dataplane)) > 0 {
+			if err := r.Client.Update(ctx, dataplane); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := r.deleteSharedDataPlane(ctx, dataplane); err != nil {
+			return err
+		}
+	}
+
+	if k8sutils.RemoveFinalizerInMetadata(&gateway.ObjectMeta, string(GatewayFinalizerReleaseSharedDataPlane)) {
+		return r.Client.Update(ctx, gateway.Gateway)
+	}
+	return nil
+}
+
+// deleteSharedDataPlane deletes a shared DataPlane which is not used by any
+// Gateway anymore, together with the ControlPlane and the NetworkPolicy
+// provisioned for it.
+func (r *GatewayReconciler) deleteSharedDataPlane(ctx context.Context, dataplane *operatorv1alpha1.DataPlane) error {
+	key := dataplane.Labels[consts.GatewaySharedDataPlaneLabel]
+
+	controlplanes, err := gatewayutils.ListSharedControlPlanes(ctx, r.Client, dataplane.Namespace, key)
+	if err != nil {
+		return err
+	}
+	for i := range controlplanes {
+		controlplane := &controlplanes[i]
+		if controlplane.Spec.DataPlane == nil || *controlplane.Spec.DataPlane != dataplane.Name {
+			continue
+		}
+		if err := r.Client.Delete(ctx, controlplane); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+
+	networkPolicies, err := gatewayutils.ListSharedNetworkPolicies(ctx, r.Client, dataplane.Namespace, key)
+	if err != nil {
+		return err
+	}
+	for i := range networkPolicies {
+		networkPolicy := &networkPolicies[i]
+		if networkPolicy.Spec.PodSelector.MatchLabels["app"] != dataplane.Name {
+			continue
+		}
+		if err := r.Client.Delete(ctx, networkPolicy); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+
+	return client.IgnoreNotFound(r.Client.Delete(ctx, dataplane))
+}
+
+// ensureListenersConflictsReported sets the Conflicted condition on the status
+// of every listener of the Gateway, and returns the names of the conflicted
+// listeners. The listeners of all the Gateways sharing a DataPlane are merged,
+// and a listener conflicts when a Gateway created earlier already uses its
+// port with a different protocol, or with the same protocol and hostname.
+// Conflicts are always reported on the most recent Gateway. The Gateways of
+// other namespaces which aren't permitted to use the shared DataPlane are
+// ignored.
+func (r *GatewayReconciler) ensureListenersConflictsReported(
+	ctx context.Context,
+	gateway *gatewayDecorator,
+	shared *sharedDataPlane,
+) ([]string, error) {
+	opts := []client.ListOption{}
+	if shared.scope != operatorv1alpha1.SharedDataPlaneScopeCluster {
+		opts = append(opts, client.InNamespace(gateway.Namespace))
+	}
+	gatewayList := new(gatewayv1alpha2.GatewayList)
+	if err := r.Client.List(ctx, gatewayList, opts...); err != nil {
+		return nil, err
+	}
+
+	grantedNamespaces := make(map[string]bool)
+	precedingGateways := make([]gatewayv1alpha2.Gateway, 0, len(gatewayList.Items))
+	for _, other := range gatewayList.Items {
+		if other.Spec.GatewayClassName != gateway.Spec.GatewayClassName ||
+			!other.DeletionTimestamp.IsZero() ||
+			!gatewayPrecedes(&other, gateway.Gateway) {
+			continue
+		}
+		granted, ok := grantedNamespaces[other.Namespace]
+		if !ok {
+			var err error
+			granted, err = r.isSharedDataPlaneGranted(ctx, other.Namespace, shared)
+			if err != nil {
+				return nil, err
+			}
+			grantedNamespaces[other.Namespace] = granted
+		}
+		if granted {
+			precedingGateways = append(precedingGateways, other)
+		}
+	}
+
+	var conflicted []string
+	for i := range gateway.Status.Listeners {
+		listenerStatus := &gateway.Status.Listeners[i]
+		for _, listener := range gateway.Spec.Listeners {
+			if listener.Name != listenerStatus.Name {
+				continue
+			}
+
+			condition := createListenerConflictedCondition(metav1.ConditionFalse, gatewayv1alpha2.ListenerReasonNoConflicts, "")
+			if reason, message, found := findListenerConflict(listener, precedingGateways); found {
+				condition = createListenerConflictedCondition(metav1.ConditionTrue, reason, message)
+				conflicted = append(conflicted, string(listener.Name))
+			}
+
+			decoratedStatus := &listenerStatusDecorator{listenerStatus, gateway.Generation}
+			current, present := k8sutils.GetCondition(k8sutils.ConditionType(condition.Type), decoratedStatus)
+			if !present || current.Status != condition.Status || current.Reason != condition.Reason || current.Message != condition.Message ||
+				current.ObservedGeneration != gateway.Generation {
+				k8sutils.SetCondition(condition, decoratedStatus)
+			}
+		}
+	}
+
+	return conflicted, nil
+}
+
+// isSharedDataPlaneGranted returns true if the Gateways of the provided
+// namespace are permitted to use the shared DataPlane: sharing a DataPlane
+// across namespaces requires a ReferenceGrant in the namespace of the shared
+// DataPlane.
+func (r *GatewayReconciler) isSharedDataPlaneGranted(ctx context.Context, namespace string, shared *sharedDataPlane) (bool, error) {
+	dataplaneName := gatewayv1alpha2.ObjectName(shared.name)
+	return gatewayutils.IsReferenceGranted(ctx, r.Client,
+		gatewayv1alpha2.ReferenceGrantFrom{
+			Group:     gatewayv1alpha2.GroupName,
+			Kind:      "Gateway",
+			Namespace: gatewayv1alpha2.Namespace(namespace),
+		},
+		gatewayv1alpha2.ReferenceGrantTo{
+			Group: gatewayv1alpha2.Group(operatorv1alpha1.SchemeGroupVersion.Group),
+			Kind:  "DataPlane",
+			Name:  &dataplaneName,
+		},
+		shared.namespace,
+	)
+}
+
+// gatewayPrecedes returns true if the Gateway a has been created before the
+// Gateway b, using the namespace and name to order Gateways created at once.
+func gatewayPrecedes(a, b *gatewayv1alpha2.Gateway) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return client.ObjectKeyFromObject(a).String() < client.ObjectKeyFromObject(b).String()
+}
+
+// findListenerConflict looks for a listener of the provided Gateways which
+// conflicts with the given listener, and returns the reason and the message of
+// the conflict.
+func findListenerConflict(
+	listener gatewayv1alpha2.Listener,
+	gateways []gatewayv1alpha2.Gateway,
+) (gatewayv1alpha2.ListenerConditionReason, string, bool) {
+	for _, gateway := range gateways {
+		for _, other := range gateway.Spec.Listeners {
+			if other.Port != listener.Port {
+				continue
+			}
+			if other.Protocol != listener.Protocol {
+				return gatewayv1alpha2.ListenerReasonProtocolConflict,
+					fmt.Sprintf("port %d is already used with protocol %s by listener %s of Gateway %s/%s",
+						listener.Port, other.Protocol, other.Name, gateway.Namespace, gateway.Name), true
+			}
+			if listenerHostname(other) == listenerHostname(listener) {
+				return gatewayv1alpha2.ListenerReasonHostnameConflict,
+					fmt.Sprintf("port %d is already used with hostname %q by listener %s of Gateway %s/%s",
+						listener.Port, listenerHostname(listener), other.Name, gateway.Namespace, gateway.Name), true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// listenerHostname returns the hostname of a listener, which is empty when the
+// listener matches all the hostnames.
+func listenerHostname(listener gatewayv1alpha2.Listener) gatewayv1alpha2.Hostname {
+	if listener.Hostname == nil {
+		return ""
+	}
+	return *listener.Hostname
+}
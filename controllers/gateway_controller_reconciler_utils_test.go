@@ -0,0 +1,327 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	"github.com/kong/gateway-operator/internal/consts"
+	operatorerrors "github.com/kong/gateway-operator/internal/errors"
+	k8sutils "github.com/kong/gateway-operator/internal/utils/kubernetes"
+)
+
+func TestFindListenerConflict(t *testing.T) {
+	hostname := gatewayv1alpha2.Hostname("example.com")
+	otherHostname := gatewayv1alpha2.Hostname("example.org")
+
+	gateways := []gatewayv1alpha2.Gateway{{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "first"},
+		Spec: gatewayv1alpha2.GatewaySpec{
+			Listeners: []gatewayv1alpha2.Listener{
+				{Name: "http", Protocol: gatewayv1alpha2.HTTPProtocolType, Port: 80, Hostname: &hostname},
+				{Name: "https", Protocol: gatewayv1alpha2.HTTPSProtocolType, Port: 443},
+			},
+		},
+	}}
+
+	testCases := []struct {
+		name       string
+		listener   gatewayv1alpha2.Listener
+		conflicted bool
+		reason     gatewayv1alpha2.ListenerConditionReason
+	}{
+		{
+			name:     "different port",
+			listener: gatewayv1alpha2.Listener{Name: "http", Protocol: gatewayv1alpha2.HTTPProtocolType, Port: 8080},
+		},
+		{
+			name:     "same port and protocol with a different hostname",
+			listener: gatewayv1alpha2.Listener{Name: "http", Protocol: gatewayv1alpha2.HTTPProtocolType, Port: 80, Hostname: &otherHostname},
+		},
+		{
+			name:       "same port and protocol with the same hostname",
+			listener:   gatewayv1alpha2.Listener{Name: "http", Protocol: gatewayv1alpha2.HTTPProtocolType, Port: 80, Hostname: &hostname},
+			conflicted: true,
+			reason:     gatewayv1alpha2.ListenerReasonHostnameConflict,
+		},
+		{
+			name:       "same port and protocol without hostnames",
+			listener:   gatewayv1alpha2.Listener{Name: "https", Protocol: gatewayv1alpha2.HTTPSProtocolType, Port: 443},
+			conflicted: true,
+			reason:     gatewayv1alpha2.ListenerReasonHostnameConflict,
+		},
+		{
+			name:       "same port with a different protocol",
+			listener:   gatewayv1alpha2.Listener{Name: "tcp", Protocol: gatewayv1alpha2.TCPProtocolType, Port: 443},
+			conflicted: true,
+			reason:     gatewayv1alpha2.ListenerReasonProtocolConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			reason, _, conflicted := findListenerConflict(tc.listener, gateways)
+			require.Equal(t, tc.conflicted, conflicted)
+			require.Equal(t, tc.reason, reason)
+		})
+	}
+}
+
+func TestEnsureListenersConflictsReported(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, gatewayv1alpha2.AddToScheme(scheme))
+
+	newGatewayWithListeners := func(namespace, name string, created time.Time, listeners ...gatewayv1alpha2.Listener) *gatewayv1alpha2.Gateway {
+		gateway := &gatewayv1alpha2.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, CreationTimestamp: metav1.NewTime(created)},
+			Spec:       gatewayv1alpha2.GatewaySpec{GatewayClassName: "kong", Listeners: listeners},
+		}
+		for _, listener := range listeners {
+			gateway.Status.Listeners = append(gateway.Status.Listeners, gatewayv1alpha2.ListenerStatus{Name: listener.Name})
+		}
+		return gateway
+	}
+	http := gatewayv1alpha2.Listener{Name: "http", Protocol: gatewayv1alpha2.HTTPProtocolType, Port: 80}
+	https := gatewayv1alpha2.Listener{Name: "https", Protocol: gatewayv1alpha2.HTTPSProtocolType, Port: 443}
+	now := time.Now()
+
+	gatewayClass := &gatewayv1alpha2.GatewayClass{ObjectMeta: metav1.ObjectMeta{Name: "kong", UID: "gatewayclass-uid"}}
+	gatewayConfig := &operatorv1alpha1.GatewayConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kong-system", Name: "kong"},
+		Spec: operatorv1alpha1.GatewayConfigurationSpec{
+			SharedDataPlane: &operatorv1alpha1.SharedDataPlaneOptions{Scope: operatorv1alpha1.SharedDataPlaneScopeCluster},
+		},
+	}
+	gateway := &gatewayDecorator{newGatewayWithListeners("default", "third", now, http, https)}
+	shared := getSharedDataPlane(gateway, gatewayClass, gatewayConfig)
+	require.Equal(t, "kong-system", shared.namespace)
+	require.Equal(t, shared.name, getSharedDataPlane(&gatewayDecorator{newGatewayWithListeners("other", "second", now)}, gatewayClass, gatewayConfig).name,
+		"the gateways must share a dataplane with a deterministic name")
+
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newGatewayWithListeners("kong-system", "first", now.Add(-2*time.Hour), https),
+		newGatewayWithListeners("other", "second", now.Add(-time.Hour), http),
+		gateway.Gateway,
+	).Build()
+	r := &GatewayReconciler{Client: c}
+
+	t.Log("ignoring the gateways which are not permitted to use the shared dataplane")
+	conflicted, err := r.ensureListenersConflictsReported(ctx, gateway, shared)
+	require.NoError(t, err)
+	require.Equal(t, []string{"https"}, conflicted)
+
+	t.Log("reporting the conflicts with the gateways permitted by a ReferenceGrant")
+	require.NoError(t, c.Create(ctx, &gatewayv1alpha2.ReferenceGrant{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kong-system", Name: "shared-dataplane"},
+		Spec: gatewayv1alpha2.ReferenceGrantSpec{
+			From: []gatewayv1alpha2.ReferenceGrantFrom{{Group: gatewayv1alpha2.GroupName, Kind: "Gateway", Namespace: "other"}},
+			To:   []gatewayv1alpha2.ReferenceGrantTo{{Group: gatewayv1alpha2.Group(operatorv1alpha1.SchemeGroupVersion.Group), Kind: "DataPlane"}},
+		},
+	}))
+	conflicted, err = r.ensureListenersConflictsReported(ctx, gateway, shared)
+	require.NoError(t, err)
+	require.Equal(t, []string{"http", "https"}, conflicted)
+	condition, ok := k8sutils.GetCondition(k8sutils.ConditionType(gatewayv1alpha2.ListenerConditionConflicted),
+		&listenerStatusDecorator{&gateway.Status.Listeners[0], gateway.Generation})
+	require.True(t, ok)
+	require.Equal(t, metav1.ConditionTrue, condition.Status)
+}
+
+func TestEnsureGatewayConfigVersionsCompatible(t *testing.T) {
+	newGatewayConfig := func(kongVersion, kicVersion *string) *operatorv1alpha1.GatewayConfiguration {
+		gatewayConfig := &operatorv1alpha1.GatewayConfiguration{}
+		if kongVersion != nil {
+			gatewayConfig.Spec.DataPlaneDeploymentOptions = &operatorv1alpha1.DataPlaneDeploymentOptions{}
+			gatewayConfig.Spec.DataPlaneDeploymentOptions.Version = kongVersion
+		}
+		if kicVersion != nil {
+			gatewayConfig.Spec.ControlPlaneDeploymentOptions = &operatorv1alpha1.ControlPlaneDeploymentOptions{}
+			gatewayConfig.Spec.ControlPlaneDeploymentOptions.Version = kicVersion
+		}
+		return gatewayConfig
+	}
+
+	testCases := []struct {
+		name                string
+		gatewayConfig       *operatorv1alpha1.GatewayConfiguration
+		expectedKongVersion *string
+		expectedKICVersion  *string
+		expectedErr         error
+	}{
+		{
+			name:          "no versions set",
+			gatewayConfig: newGatewayConfig(nil, nil),
+		},
+		{
+			name:                "only the dataplane version set",
+			gatewayConfig:       newGatewayConfig(pointer.String("3.0"), nil),
+			expectedKongVersion: pointer.String("3.0"),
+			expectedKICVersion:  pointer.String("2.7"),
+		},
+		{
+			name:                "only the controlplane version set",
+			gatewayConfig:       newGatewayConfig(nil, pointer.String("2.3")),
+			expectedKongVersion: pointer.String(consts.DefaultDataPlaneTag),
+			expectedKICVersion:  pointer.String("2.3"),
+		},
+		{
+			name:                "incompatible versions",
+			gatewayConfig:       newGatewayConfig(pointer.String("3.0"), pointer.String("2.5")),
+			expectedKongVersion: pointer.String("3.0"),
+			expectedKICVersion:  pointer.String("2.5"),
+			expectedErr:         operatorerrors.ErrIncompatibleVersions,
+		},
+		{
+			name:                "unknown versions",
+			gatewayConfig:       newGatewayConfig(pointer.String("3.0"), pointer.String("latest")),
+			expectedKongVersion: pointer.String("3.0"),
+			expectedKICVersion:  pointer.String("latest"),
+			expectedErr:         operatorerrors.ErrUnknownVersionCompatibility,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := ensureGatewayConfigVersionsCompatible(tc.gatewayConfig)
+			if tc.expectedErr == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, tc.expectedErr)
+			}
+			require.Equal(t, tc.expectedKongVersion, tc.gatewayConfig.Spec.DataPlaneDeploymentOptions.Version)
+			require.Equal(t, tc.expectedKICVersion, tc.gatewayConfig.Spec.ControlPlaneDeploymentOptions.Version)
+		})
+	}
+}
+
+func TestSetGatewayVersionsCompatibleCondition(t *testing.T) {
+	gateway := newGateway()
+	unknownErr := fmt.Errorf("kong 3.0.0.0 and kic 2.7: %w", operatorerrors.ErrUnknownVersionCompatibility)
+
+	t.Log("reporting the unknown compatibility of the versions")
+	require.True(t, setGatewayVersionsCompatibleCondition(gateway, unknownErr))
+	condition, exists := k8sutils.GetCondition(GatewayVersionsCompatibleType, gateway)
+	require.True(t, exists)
+	require.Equal(t, metav1.ConditionTrue, condition.Status)
+	require.Equal(t, string(GatewayUnknownVersionCompatibilityReason), condition.Reason)
+	require.Equal(t, unknownErr.Error(), condition.Message)
+
+	t.Log("reporting the same unknown compatibility again doesn't change the condition")
+	require.False(t, setGatewayVersionsCompatibleCondition(gateway, unknownErr))
+
+	t.Log("reporting the unknown compatibility of other versions changes the condition")
+	otherErr := fmt.Errorf("kong 3.1.0.0 and kic 2.7: %w", operatorerrors.ErrUnknownVersionCompatibility)
+	require.True(t, setGatewayVersionsCompatibleCondition(gateway, otherErr))
+	condition, _ = k8sutils.GetCondition(GatewayVersionsCompatibleType, gateway)
+	require.Equal(t, otherErr.Error(), condition.Message)
+
+	t.Log("the condition is removed once the versions are compatible")
+	require.True(t, setGatewayVersionsCompatibleCondition(gateway, nil))
+	_, exists = k8sutils.GetCondition(GatewayVersionsCompatibleType, gateway)
+	require.False(t, exists)
+	require.False(t, setGatewayVersionsCompatibleCondition(gateway, nil))
+}
+
+func TestMergeGatewayConfigurations(t *testing.T) {
+	gatewayClassConfig := &operatorv1alpha1.GatewayConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kong-system", Name: "class"},
+		Spec: operatorv1alpha1.GatewayConfigurationSpec{
+			DataPlaneDeploymentOptions: &operatorv1alpha1.DataPlaneDeploymentOptions{
+				DeploymentOptions: operatorv1alpha1.DeploymentOptions{
+					ContainerImage: pointer.String("kong/kong-gateway"),
+					Version:        pointer.String("3.0"),
+					Env: []corev1.EnvVar{
+						{Name: "KONG_LOG_LEVEL", Value: "notice"},
+						{Name: "KONG_NGINX_WORKER_PROCESSES", Value: "2"},
+					},
+				},
+			},
+			ControlPlaneDeploymentOptions: &operatorv1alpha1.ControlPlaneDeploymentOptions{
+				DeploymentOptions: operatorv1alpha1.DeploymentOptions{
+					Version: pointer.String("2.7"),
+				},
+			},
+		},
+	}
+	attachedConfig := &operatorv1alpha1.GatewayConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "attached"},
+		Spec: operatorv1alpha1.GatewayConfigurationSpec{
+			DataPlaneDeploymentOptions: &operatorv1alpha1.DataPlaneDeploymentOptions{
+				DeploymentOptions: operatorv1alpha1.DeploymentOptions{
+					Version: pointer.String("3.1"),
+					Env:     []corev1.EnvVar{{Name: "KONG_LOG_LEVEL", Value: "debug"}},
+				},
+			},
+			TargetRef: &operatorv1alpha1.GatewayConfigurationTargetReference{
+				Kind: operatorv1alpha1.GatewayConfigurationTargetKindGateway,
+				Name: "kong",
+			},
+		},
+	}
+	annotationConfig := &operatorv1alpha1.GatewayConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "annotation"},
+		Spec: operatorv1alpha1.GatewayConfigurationSpec{
+			DataPlaneDeploymentOptions: &operatorv1alpha1.DataPlaneDeploymentOptions{
+				DeploymentOptions: operatorv1alpha1.DeploymentOptions{
+					Version: pointer.String("3.1.1"),
+				},
+			},
+			ControlPlaneDeploymentOptions: &operatorv1alpha1.ControlPlaneDeploymentOptions{
+				DeploymentOptions: operatorv1alpha1.DeploymentOptions{
+					Env: []corev1.EnvVar{{Name: "CONTROLLER_LOG_LEVEL", Value: "debug"}},
+				},
+			},
+			SharedDataPlane: &operatorv1alpha1.SharedDataPlaneOptions{Scope: operatorv1alpha1.SharedDataPlaneScopeNamespace},
+		},
+	}
+
+	t.Log("merging no configuration over the configuration of the gatewayclass")
+	merged, err := mergeGatewayConfigurations(gatewayClassConfig)
+	require.NoError(t, err)
+	require.Equal(t, gatewayClassConfig, merged)
+	merged, err = mergeGatewayConfigurations(gatewayClassConfig, &operatorv1alpha1.GatewayConfiguration{})
+	require.NoError(t, err)
+	require.Equal(t, gatewayClassConfig, merged)
+
+	t.Log("merging the configurations of the gateway in order of precedence")
+	merged, err = mergeGatewayConfigurations(gatewayClassConfig, attachedConfig, annotationConfig)
+	require.NoError(t, err)
+	require.Equal(t, gatewayClassConfig.ObjectMeta, merged.ObjectMeta)
+	require.Equal(t, &operatorv1alpha1.DataPlaneDeploymentOptions{
+		DeploymentOptions: operatorv1alpha1.DeploymentOptions{
+			ContainerImage: pointer.String("kong/kong-gateway"),
+			Version:        pointer.String("3.1.1"),
+			Env: []corev1.EnvVar{
+				{Name: "KONG_LOG_LEVEL", Value: "debug"},
+				{Name: "KONG_NGINX_WORKER_PROCESSES", Value: "2"},
+			},
+		},
+	}, merged.Spec.DataPlaneDeploymentOptions)
+	require.Equal(t, &operatorv1alpha1.ControlPlaneDeploymentOptions{
+		DeploymentOptions: operatorv1alpha1.DeploymentOptions{
+			Version: pointer.String("2.7"),
+			Env:     []corev1.EnvVar{{Name: "CONTROLLER_LOG_LEVEL", Value: "debug"}},
+		},
+	}, merged.Spec.ControlPlaneDeploymentOptions)
+	require.Nil(t, merged.Spec.SharedDataPlane)
+	require.Nil(t, merged.Spec.TargetRef)
+
+	t.Log("leaving the configurations untouched")
+	require.Equal(t, pointer.String("3.0"), gatewayClassConfig.Spec.DataPlaneDeploymentOptions.Version)
+	require.NotNil(t, annotationConfig.Spec.SharedDataPlane)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -13,8 +14,11 @@ import (
 	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	"github.com/kong/gateway-operator/internal/consts"
 	operatorerrors "github.com/kong/gateway-operator/internal/errors"
+	controlplaneutils "github.com/kong/gateway-operator/internal/utils/controlplane"
 	dataplaneutils "github.com/kong/gateway-operator/internal/utils/dataplane"
+	gatewayutils "github.com/kong/gateway-operator/internal/utils/gateway"
 	"github.com/kong/gateway-operator/pkg/vars"
 )
 
@@ -23,7 +27,7 @@ import (
 // -----------------------------------------------------------------------------
 
 func (r *GatewayReconciler) gatewayHasMatchingGatewayClass(obj client.Object) bool {
-	gateway, ok := obj.(*gatewayv1alpha2.Gateway)
+	gateway, ok := gatewayutils.AsV1alpha2Gateway(obj)
 	if !ok {
 		log.FromContext(context.Background()).Error(
 			operatorerrors.ErrUnexpectedObject,
@@ -46,7 +50,7 @@ func (r *GatewayReconciler) gatewayHasMatchingGatewayClass(obj client.Object) bo
 }
 
 func (r *GatewayReconciler) gatewayClassMatchesController(obj client.Object) bool {
-	gatewayClass, ok := obj.(*gatewayv1alpha2.GatewayClass)
+	gatewayClass, ok := gatewayutils.AsV1alpha2GatewayClass(obj)
 	if !ok {
 		log.FromContext(context.Background()).Error(
 			operatorerrors.ErrUnexpectedObject,
@@ -90,7 +94,7 @@ func (r *GatewayReconciler) gatewayConfigurationMatchesController(obj client.Obj
 // -----------------------------------------------------------------------------
 
 func (r *GatewayReconciler) listGatewaysForGatewayClass(obj client.Object) (recs []reconcile.Request) {
-	gatewayClass, ok := obj.(*gatewayv1alpha2.GatewayClass)
+	gatewayClass, ok := gatewayutils.AsV1alpha2GatewayClass(obj)
 	if !ok {
 		log.FromContext(context.Background()).Error(
 			operatorerrors.ErrUnexpectedObject,
@@ -149,6 +153,8 @@ func (r *GatewayReconciler) listGatewaysForGatewayConfig(obj client.Object) (rec
 		if gatewayClass.Spec.ParametersRef != nil &&
 			string(gatewayClass.Spec.ParametersRef.Group) == operatorv1alpha1.SchemeGroupVersion.Group &&
 			string(gatewayClass.Spec.ParametersRef.Kind) == "GatewayConfiguration" &&
+			gatewayClass.Spec.ParametersRef.Namespace != nil &&
+			string(*gatewayClass.Spec.ParametersRef.Namespace) == gatewayConfig.Namespace &&
 			gatewayClass.Spec.ParametersRef.Name == gatewayConfig.Name {
 			matchingGatewayClasses[gatewayClass.Name] = struct{}{}
 		}
@@ -165,7 +171,11 @@ func (r *GatewayReconciler) listGatewaysForGatewayConfig(obj client.Object) (rec
 	}
 
 	for _, gateway := range gatewayList.Items {
-		if _, ok := matchingGatewayClasses[string(gateway.Spec.GatewayClassName)]; ok {
+		// the gateways using the configuration through their gatewayclass, their
+		// targetRef or their annotation.
+		if _, ok := matchingGatewayClasses[string(gateway.Spec.GatewayClassName)]; ok ||
+			gatewayutils.GatewayConfigurationTargetsGateway(gatewayConfig, &gateway) ||
+			(gateway.Namespace == gatewayConfig.Namespace && gateway.Annotations[consts.GatewayConfigurationAnnotation] == gatewayConfig.Name) {
 			recs = append(recs, reconcile.Request{
 				NamespacedName: types.NamespacedName{
 					Namespace: gateway.Namespace,
@@ -178,6 +188,88 @@ func (r *GatewayReconciler) listGatewaysForGatewayConfig(obj client.Object) (rec
 	return
 }
 
+func (r *GatewayReconciler) listGatewaysForReferenceGrant(obj client.Object) (recs []reconcile.Request) {
+	ctx := context.Background()
+
+	referenceGrant, ok := obj.(*gatewayv1alpha2.ReferenceGrant)
+	if !ok {
+		log.FromContext(ctx).Error(
+			operatorerrors.ErrUnexpectedObject,
+			"failed to run map funcs",
+			"expected", "ReferenceGrant", "found", reflect.TypeOf(obj),
+		)
+		return
+	}
+
+	for _, from := range referenceGrant.Spec.From {
+		if from.Group != gatewayv1alpha2.GroupName || from.Kind != "Gateway" {
+			continue
+		}
+
+		gateways := new(gatewayv1alpha2.GatewayList)
+		if err := r.Client.List(ctx, gateways, client.InNamespace(string(from.Namespace))); err != nil {
+			log.FromContext(ctx).Error(err, "could not list gateways in map func")
+			return
+		}
+
+		for _, gateway := range gateways.Items {
+			recs = append(recs, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: gateway.Namespace,
+					Name:      gateway.Name,
+				},
+			})
+		}
+	}
+
+	return
+}
+
+func (r *GatewayReconciler) listGatewaysForSharedDataPlane(obj client.Object) (recs []reconcile.Request) {
+	ctx := context.Background()
+
+	if _, ok := obj.GetLabels()[consts.GatewaySharedDataPlaneLabel]; !ok {
+		return
+	}
+
+	var dataplane *operatorv1alpha1.DataPlane
+	switch o := obj.(type) {
+	case *operatorv1alpha1.DataPlane:
+		dataplane = o
+	case *operatorv1alpha1.ControlPlane:
+		if o.Spec.DataPlane == nil || *o.Spec.DataPlane == "" {
+			return
+		}
+		dataplane = new(operatorv1alpha1.DataPlane)
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: o.Namespace, Name: *o.Spec.DataPlane}, dataplane); err != nil {
+			log.FromContext(ctx).Error(err, "could not get shared dataplane in map func")
+			return
+		}
+	default:
+		log.FromContext(ctx).Error(
+			operatorerrors.ErrUnexpectedObject,
+			"failed to run map funcs",
+			"expected", "DataPlane or ControlPlane", "found", reflect.TypeOf(obj),
+		)
+		return
+	}
+
+	for _, gateway := range gatewayutils.GetSharedDataPlaneGateways(dataplane) {
+		namespace, name, found := strings.Cut(gateway, string(types.Separator))
+		if !found {
+			continue
+		}
+		recs = append(recs, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: namespace,
+				Name:      name,
+			},
+		})
+	}
+
+	return
+}
+
 func (r *GatewayReconciler) setDataplaneGatewayConfigDefaults(gatewayConfig *operatorv1alpha1.GatewayConfiguration) {
 	if gatewayConfig.Spec.DataPlaneDeploymentOptions == nil {
 		gatewayConfig.Spec.DataPlaneDeploymentOptions = new(operatorv1alpha1.DataPlaneDeploymentOptions)
@@ -185,7 +277,7 @@ func (r *GatewayReconciler) setDataplaneGatewayConfigDefaults(gatewayConfig *ope
 	dataplaneutils.SetDataPlaneDefaults(gatewayConfig.Spec.DataPlaneDeploymentOptions)
 }
 
-func (r *GatewayReconciler) setControlplaneGatewayConfigDefaults(gateway *gatewayDecorator, gatewayConfig *operatorv1alpha1.GatewayConfiguration, dataplaneName, dataplaneServiceName string) {
+func (r *GatewayReconciler) setControlplaneGatewayConfigDefaults(gatewayConfig *operatorv1alpha1.GatewayConfiguration, dataplaneNamespace, dataplaneName, dataplaneServiceName string) {
 	dontOverride := make(map[string]struct{})
 	if gatewayConfig.Spec.ControlPlaneDeploymentOptions == nil {
 		gatewayConfig.Spec.ControlPlaneDeploymentOptions = new(operatorv1alpha1.ControlPlaneDeploymentOptions)
@@ -198,5 +290,5 @@ func (r *GatewayReconciler) setControlplaneGatewayConfigDefaults(gateway *gatewa
 		dontOverride[env.Name] = struct{}{}
 	}
 
-	setControlPlaneDefaults(gatewayConfig.Spec.ControlPlaneDeploymentOptions, gateway.Namespace, dataplaneServiceName, dontOverride)
+	controlplaneutils.SetControlPlaneDefaults(gatewayConfig.Spec.ControlPlaneDeploymentOptions, dataplaneNamespace, dataplaneServiceName, dontOverride)
 }
@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	operatorerrors "github.com/kong/gateway-operator/internal/errors"
+	"github.com/kong/gateway-operator/internal/utils/image"
+	k8sutils "github.com/kong/gateway-operator/internal/utils/kubernetes"
+)
+
+// -----------------------------------------------------------------------------
+// GatewayBackupReconciler
+// -----------------------------------------------------------------------------
+
+// GatewayBackupReconciler reconciles a GatewayBackup object
+type GatewayBackupReconciler struct {
+	client.Client
+	Scheme                   *runtime.Scheme
+	eventRecorder            record.EventRecorder
+	ClusterCASecretName      string
+	ClusterCASecretNamespace string
+
+	// ImagePolicy is the operator-level policy of the container images: it
+	// rewrites the default images to a mirror registry and sets the
+	// imagePullSecrets of the generated resources.
+	ImagePolicy *image.Policy
+
+	// adminAPICertificate is the mTLS client certificate of the operator for
+	// the Kong Admin API of the DataPlanes.
+	adminAPICertificate adminAPIClientCertificate
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GatewayBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.eventRecorder = mgr.GetEventRecorderFor("gatewaybackup")
+
+	return ctrl.NewControllerManagedBy(mgr).
+		// watch GatewayBackup objects
+		For(&operatorv1alpha1.GatewayBackup{}).
+		// watch for changes in Jobs created by the gatewaybackup controller
+		Owns(&batchv1.Job{}).
+		// watch for changes in Secrets created by the gatewaybackup controller
+		Owns(&corev1.Secret{}).
+		Complete(r)
+}
+
+// -----------------------------------------------------------------------------
+// GatewayBackupReconciler - Reconciliation
+// -----------------------------------------------------------------------------
+
+// Reconcile moves the current state of an object to the intended state.
+func (r *GatewayBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx).WithName("GatewayBackup")
+
+	debug(log, "reconciling GatewayBackup resource", req)
+	backup := new(operatorv1alpha1.GatewayBackup)
+	if err := r.Client.Get(ctx, req.NamespacedName, backup); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !backup.DeletionTimestamp.IsZero() {
+		debug(log, "GatewayBackup is being deleted, nothing to do", backup)
+		return ctrl.Result{}, nil
+	}
+
+	if condition, ok := k8sutils.GetCondition(GatewayBackupConditionTypeCompleted, backup); ok &&
+		(condition.Status == metav1.ConditionTrue || condition.Reason == string(GatewayBackupConditionReasonFailed)) {
+		debug(log, "GatewayBackup is over, nothing to do", backup)
+		return ctrl.Result{}, nil
+	}
+
+	debug(log, "backing up the Gateway", backup)
+	completed, message, err := r.ensureGatewayBackedUp(ctx, backup)
+	if err != nil {
+		if errors.Is(err, operatorerrors.ErrBackupFailed) {
+			info(log, "GatewayBackup failed: "+err.Error(), backup)
+			r.eventRecorder.Event(backup, "Warning", string(GatewayBackupConditionReasonFailed), err.Error())
+			k8sutils.SetCondition(k8sutils.NewCondition(
+				GatewayBackupConditionTypeCompleted, metav1.ConditionFalse, GatewayBackupConditionReasonFailed, err.Error(),
+			), backup)
+			return ctrl.Result{}, r.Client.Status().Update(ctx, backup)
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !completed {
+		debug(log, "waiting for the snapshot to be copied to the volume", backup)
+		k8sutils.SetCondition(k8sutils.NewCondition(
+			GatewayBackupConditionTypeCompleted, metav1.ConditionFalse, GatewayBackupConditionReasonInProgress,
+			fmt.Sprintf("copying the snapshot to the volume %s", backup.Spec.Storage.PersistentVolumeClaim.ClaimName),
+		), backup)
+		return ctrl.Result{}, r.updateStatus(ctx, backup) // requeue will be triggered by the update of the owned Job
+	}
+
+	debug(log, "GatewayBackup completed", backup)
+	now := metav1.Now()
+	backup.Status.CompletionTime = &now
+	k8sutils.SetCondition(k8sutils.NewCondition(
+		GatewayBackupConditionTypeCompleted, metav1.ConditionTrue, GatewayBackupConditionReasonCompleted, message,
+	), backup)
+	return ctrl.Result{}, r.Client.Status().Update(ctx, backup)
+}
+
+// updateStatus updates the status of the GatewayBackup if its conditions
+// changed.
+func (r *GatewayBackupReconciler) updateStatus(ctx context.Context, updated *operatorv1alpha1.GatewayBackup) error {
+	current := &operatorv1alpha1.GatewayBackup{}
+
+	err := r.Client.Get(ctx, client.ObjectKeyFromObject(updated), current)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	if k8sutils.NeedsUpdate(current, updated) {
+		return r.Client.Status().Update(ctx, updated)
+	}
+
+	return nil
+}
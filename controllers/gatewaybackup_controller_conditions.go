@@ -0,0 +1,67 @@
+package controllers
+
+import k8sutils "github.com/kong/gateway-operator/internal/utils/kubernetes"
+
+// -----------------------------------------------------------------------------
+// GatewayBackup - Status Condition Types
+// -----------------------------------------------------------------------------
+
+const (
+	// GatewayBackupConditionTypeCompleted is a condition type indicating whether
+	// or not the snapshot of the Gateway of a GatewayBackup has been stored.
+	GatewayBackupConditionTypeCompleted k8sutils.ConditionType = "Completed"
+)
+
+// -----------------------------------------------------------------------------
+// GatewayBackup - Status Condition Reasons
+// -----------------------------------------------------------------------------
+
+const (
+	// GatewayBackupConditionReasonInProgress is a reason which indicates the
+	// snapshot of a GatewayBackup is being copied to its volume.
+	GatewayBackupConditionReasonInProgress k8sutils.ConditionReason = "InProgress"
+
+	// GatewayBackupConditionReasonCompleted is a reason which indicates the
+	// snapshot of a GatewayBackup has been stored.
+	GatewayBackupConditionReasonCompleted k8sutils.ConditionReason = "Completed"
+
+	// GatewayBackupConditionReasonFailed is a reason which indicates a
+	// GatewayBackup has failed. It isn't retried: the GatewayBackup has to be
+	// recreated.
+	GatewayBackupConditionReasonFailed k8sutils.ConditionReason = "Failed"
+)
+
+// -----------------------------------------------------------------------------
+// GatewayRestore - Status Condition Types
+// -----------------------------------------------------------------------------
+
+const (
+	// GatewayRestoreConditionTypeCompleted is a condition type indicating
+	// whether or not the Gateway of a GatewayRestore has been restored.
+	GatewayRestoreConditionTypeCompleted k8sutils.ConditionType = "Completed"
+)
+
+// -----------------------------------------------------------------------------
+// GatewayRestore - Status Condition Reasons
+// -----------------------------------------------------------------------------
+
+const (
+	// GatewayRestoreConditionReasonInProgress is a reason which indicates the
+	// snapshot of a GatewayRestore is being loaded, or its objects recreated.
+	GatewayRestoreConditionReasonInProgress k8sutils.ConditionReason = "InProgress"
+
+	// GatewayRestoreConditionReasonWaitingForDataPlane is a reason which
+	// indicates the objects of a GatewayRestore have been recreated, and the
+	// restore waits for the DataPlane to be ready to load the Kong
+	// configuration of the snapshot.
+	GatewayRestoreConditionReasonWaitingForDataPlane k8sutils.ConditionReason = "WaitingForDataPlane"
+
+	// GatewayRestoreConditionReasonCompleted is a reason which indicates the
+	// Gateway of a GatewayRestore has been restored.
+	GatewayRestoreConditionReasonCompleted k8sutils.ConditionReason = "Completed"
+
+	// GatewayRestoreConditionReasonFailed is a reason which indicates a
+	// GatewayRestore has failed. It isn't retried: the GatewayRestore has to be
+	// recreated.
+	GatewayRestoreConditionReasonFailed k8sutils.ConditionReason = "Failed"
+)
@@ -0,0 +1,50 @@
+package controllers
+
+import "time"
+
+// -----------------------------------------------------------------------------
+// GatewayBackup - Jobs
+// -----------------------------------------------------------------------------
+
+const (
+	// gatewayBackupJobBackoffLimit is the number of retries of the Jobs copying
+	// the snapshots from and to the volumes.
+	gatewayBackupJobBackoffLimit = 3
+
+	// gatewayBackupJobContainerName is the name of the container of the Jobs
+	// copying the snapshots from and to the volumes.
+	gatewayBackupJobContainerName = "snapshot"
+
+	// gatewayBackupVolumeName is the name of the volume storing the snapshots
+	// in the Pods of the Jobs.
+	gatewayBackupVolumeName = "backup"
+
+	// gatewayBackupSnapshotVolumeName is the name of the volume holding the
+	// snapshot Secret in the Pods of the Jobs.
+	gatewayBackupSnapshotVolumeName = "snapshot"
+
+	// gatewayRestoreFieldManager is the field manager of the Jobs writing the
+	// snapshots stored in volumes into the staging Secrets.
+	gatewayRestoreFieldManager = "gatewayrestore"
+)
+
+// -----------------------------------------------------------------------------
+// GatewayRestore - Requeue
+// -----------------------------------------------------------------------------
+
+// gatewayRestoreDataPlaneReadinessInterval is the interval at which a
+// GatewayRestore checks whether the restored DataPlane is ready to load the
+// Kong configuration of the snapshot.
+const gatewayRestoreDataPlaneReadinessInterval = 5 * time.Second
+
+// -----------------------------------------------------------------------------
+// GatewayRestore - Finalizers
+// -----------------------------------------------------------------------------
+
+// GatewayRestoreFinalizer defines finalizers added by gatewayrestore controller.
+type GatewayRestoreFinalizer string
+
+const (
+	// GatewayRestoreFinalizerReleaseRestoredObjects is the finalizer to release the objects restored by the gatewayrestore on deleting.
+	GatewayRestoreFinalizerReleaseRestoredObjects GatewayRestoreFinalizer = "gateway-operator.konghq.com/release-restored-objects"
+)
@@ -0,0 +1,19 @@
+package controllers
+
+// -----------------------------------------------------------------------------
+// GatewayBackupReconciler - RBAC Permissions
+// -----------------------------------------------------------------------------
+
+//+kubebuilder:rbac:groups=gateway-operator.konghq.com,resources=gatewaybackups,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=gateway-operator.konghq.com,resources=gatewaybackups/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=gateway-operator.konghq.com,resources=gatewaybackups/finalizers,verbs=update
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways,verbs=get;list;watch
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gatewayclasses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=gateway-operator.konghq.com,resources=gatewayconfigurations,verbs=get;list;watch
+//+kubebuilder:rbac:groups=gateway-operator.konghq.com,resources=dataplanes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=gateway-operator.konghq.com,resources=controlplanes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=create;get;list;watch;delete
+//+kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=create;get;list;watch;update;delete
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
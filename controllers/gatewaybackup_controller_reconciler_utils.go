@@ -0,0 +1,314 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	"github.com/kong/gateway-operator/internal/consts"
+	operatorerrors "github.com/kong/gateway-operator/internal/errors"
+	gatewayutils "github.com/kong/gateway-operator/internal/utils/gateway"
+	k8sutils "github.com/kong/gateway-operator/internal/utils/kubernetes"
+)
+
+// -----------------------------------------------------------------------------
+// GatewayBackupReconciler - Backup
+// -----------------------------------------------------------------------------
+
+// ensureGatewayBackedUp takes the snapshot of the Gateway of the GatewayBackup
+// and stores it. The snapshots stored in a volume are copied by a Job: false
+// is returned until it succeeds. The returned message tells which parts of the
+// Gateway couldn't be backed up.
+func (r *GatewayBackupReconciler) ensureGatewayBackedUp(
+	ctx context.Context,
+	backup *operatorv1alpha1.GatewayBackup,
+) (completed bool, message string, err error) {
+	if err := validateGatewayBackupStorage(&backup.Spec.Storage); err != nil {
+		return false, "", err
+	}
+
+	if storage := backup.Spec.Storage.Secret; storage != nil {
+		data, message, err := r.snapshotGateway(ctx, backup)
+		if err != nil {
+			return false, "", err
+		}
+		return true, message, r.ensureSnapshotSecret(ctx, backup, storage.Name, data)
+	}
+
+	jobs, err := k8sutils.ListJobsForOwner(
+		ctx,
+		r.Client,
+		consts.GatewayOperatorControlledLabel,
+		consts.GatewayBackupManagedLabelValue,
+		backup.Namespace,
+		backup.UID,
+	)
+	if err != nil {
+		return false, "", err
+	}
+	if len(jobs) > 1 {
+		return false, "", fmt.Errorf("found %d Jobs for GatewayBackup currently unsupported: expected 1 or less", len(jobs))
+	}
+
+	// the snapshot is staged in a Secret mounted by the Job copying it to the
+	// volume, and deleted once the copy is done.
+	stagingSecretName := fmt.Sprintf("%s-%s-snapshot", consts.GatewayBackupPrefix, backup.Name)
+	if len(jobs) == 0 {
+		data, _, err := r.snapshotGateway(ctx, backup)
+		if err != nil {
+			return false, "", err
+		}
+		if err := r.ensureSnapshotSecret(ctx, backup, stagingSecretName, data); err != nil {
+			return false, "", err
+		}
+
+		job := generateGatewayBackupJob(backup, stagingSecretName, r.ImagePolicy)
+		k8sutils.SetOwnerForObject(job, backup)
+		addLabelForGatewayBackup(job)
+		return false, "", r.Client.Create(ctx, job)
+	}
+
+	job := &jobs[0]
+	if jobHasFailed(job) {
+		return false, "", fmt.Errorf("%w: Job %s copying the snapshot to the volume failed", operatorerrors.ErrBackupFailed, job.Name)
+	}
+	if !jobHasSucceeded(job) {
+		return false, "", nil
+	}
+
+	stagingSecret := &corev1.Secret{}
+	stagingSecret.Namespace = backup.Namespace
+	stagingSecret.Name = stagingSecretName
+	if err := r.Client.Delete(ctx, stagingSecret); err != nil && !k8serrors.IsNotFound(err) {
+		return false, "", err
+	}
+	return true, fmt.Sprintf("snapshot stored in the file %s of the volume %s",
+		gatewayBackupVolumePath(backup), backup.Spec.Storage.PersistentVolumeClaim.ClaimName), nil
+}
+
+// ensureSnapshotSecret stores the snapshot of a GatewayBackup in the provided
+// Secret, which must be owned by the GatewayBackup if it already exists.
+func (r *GatewayBackupReconciler) ensureSnapshotSecret(
+	ctx context.Context,
+	backup *operatorv1alpha1.GatewayBackup,
+	name string,
+	data []byte,
+) error {
+	secret := &corev1.Secret{}
+	err := r.Client.Get(ctx, client.ObjectKey{Namespace: backup.Namespace, Name: name}, secret)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return err
+		}
+		secret.Namespace = backup.Namespace
+		secret.Name = name
+		secret.Type = corev1.SecretTypeOpaque
+		secret.Data = map[string][]byte{consts.GatewayBackupSnapshotKey: data}
+		k8sutils.SetOwnerForObject(secret, backup)
+		addLabelForGatewayBackup(secret)
+		return r.Client.Create(ctx, secret)
+	}
+
+	if !k8sutils.IsOwnedByRefUID(&secret.ObjectMeta, backup.UID) {
+		return fmt.Errorf("%w: Secret %s already exists", operatorerrors.ErrBackupFailed, name)
+	}
+	secret.Data = map[string][]byte{consts.GatewayBackupSnapshotKey: data}
+	return r.Client.Update(ctx, secret)
+}
+
+// snapshotGateway takes the snapshot of the Gateway of a GatewayBackup and
+// returns it encoded. The returned message tells which parts of the Gateway
+// couldn't be backed up.
+func (r *GatewayBackupReconciler) snapshotGateway(
+	ctx context.Context,
+	backup *operatorv1alpha1.GatewayBackup,
+) ([]byte, string, error) {
+	gateway := &gatewayv1alpha2.Gateway{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: backup.Namespace, Name: backup.Spec.GatewayName}, gateway); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, "", fmt.Errorf("%w: Gateway %s not found", operatorerrors.ErrBackupFailed, backup.Spec.GatewayName)
+		}
+		return nil, "", err
+	}
+	snapshot := &gatewayutils.Snapshot{
+		Gateway: &gatewayv1alpha2.Gateway{
+			ObjectMeta: gatewayutils.SnapshotObjectMeta(gateway.ObjectMeta),
+			Spec:       gateway.Spec,
+		},
+	}
+
+	if err := r.snapshotGatewayClass(ctx, gateway, snapshot); err != nil {
+		return nil, "", err
+	}
+
+	dataplanes, err := gatewayutils.ListDataPlanesForGateway(ctx, r.Client, gateway)
+	if err != nil {
+		return nil, "", err
+	}
+	var message string
+	if len(dataplanes) == 1 {
+		if message, err = r.snapshotDataPlane(ctx, backup, &dataplanes[0], snapshot); err != nil {
+			return nil, "", err
+		}
+	}
+
+	controlplanes, err := gatewayutils.ListControlPlanesForGateway(ctx, r.Client, gateway)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(controlplanes) == 1 {
+		controlplane := &controlplanes[0]
+		snapshot.ControlPlane = &operatorv1alpha1.ControlPlane{
+			ObjectMeta: gatewayutils.SnapshotObjectMeta(controlplane.ObjectMeta),
+			Spec:       controlplane.Spec,
+		}
+		if snapshot.ControlPlaneCertificate, err = r.snapshotCertificate(ctx, consts.ControlPlaneManagedLabelValue, controlplane); err != nil {
+			return nil, "", err
+		}
+	}
+
+	data, err := gatewayutils.EncodeSnapshot(snapshot)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, message, nil
+}
+
+// snapshotGatewayClass adds the GatewayClass of the Gateway to the snapshot,
+// along with the GatewayConfiguration it references.
+func (r *GatewayBackupReconciler) snapshotGatewayClass(
+	ctx context.Context,
+	gateway *gatewayv1alpha2.Gateway,
+	snapshot *gatewayutils.Snapshot,
+) error {
+	gatewayClass := &gatewayv1alpha2.GatewayClass{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: string(gateway.Spec.GatewayClassName)}, gatewayClass); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	snapshot.GatewayClass = &gatewayv1alpha2.GatewayClass{
+		ObjectMeta: gatewayutils.SnapshotObjectMeta(gatewayClass.ObjectMeta),
+		Spec:       gatewayClass.Spec,
+	}
+
+	ref := gatewayClass.Spec.ParametersRef
+	if ref == nil || ref.Namespace == nil ||
+		string(ref.Group) != operatorv1alpha1.SchemeGroupVersion.Group || string(ref.Kind) != "GatewayConfiguration" {
+		return nil
+	}
+	gatewayConfig := &operatorv1alpha1.GatewayConfiguration{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: string(*ref.Namespace), Name: ref.Name}, gatewayConfig); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	snapshot.GatewayConfiguration = &operatorv1alpha1.GatewayConfiguration{
+		ObjectMeta: gatewayutils.SnapshotObjectMeta(gatewayConfig.ObjectMeta),
+		Spec:       gatewayConfig.Spec,
+	}
+	return nil
+}
+
+// snapshotDataPlane adds the DataPlane of the Gateway to the snapshot, along
+// with its Services, its certificate and the Kong configuration it runs. The
+// Kong configuration can only be read from DB-less traditional DataPlanes
+// whose Kong Admin API is reachable: the backup is completed without it
+// otherwise, as the ControlPlane configures the restored DataPlane anyway. The
+// returned message tells why the Kong configuration isn't backed up.
+func (r *GatewayBackupReconciler) snapshotDataPlane(
+	ctx context.Context,
+	backup *operatorv1alpha1.GatewayBackup,
+	dataplane *operatorv1alpha1.DataPlane,
+	snapshot *gatewayutils.Snapshot,
+) (string, error) {
+	snapshot.DataPlane = &operatorv1alpha1.DataPlane{
+		ObjectMeta: gatewayutils.SnapshotObjectMeta(dataplane.ObjectMeta),
+		Spec:       dataplane.Spec,
+	}
+
+	services, err := k8sutils.ListServicesForOwner(
+		ctx,
+		r.Client,
+		consts.GatewayOperatorControlledLabel,
+		consts.DataPlaneManagedLabelValue,
+		dataplane.Namespace,
+		dataplane.UID,
+	)
+	if err != nil {
+		return "", err
+	}
+	for _, service := range services {
+		snapshot.DataPlaneServices = append(snapshot.DataPlaneServices, corev1.Service{
+			ObjectMeta: gatewayutils.SnapshotObjectMeta(service.ObjectMeta),
+			Spec:       service.Spec,
+		})
+	}
+
+	if snapshot.DataPlaneCertificate, err = r.snapshotCertificate(ctx, consts.DataPlaneManagedLabelValue, dataplane); err != nil {
+		return "", err
+	}
+
+	if dataplane.Spec.Database != nil || dataplane.Spec.Role == operatorv1alpha1.DataPlaneRoleDataPlane {
+		return "the Kong configuration of DataPlanes using a database or a control plane isn't backed up", nil
+	}
+	config, err := r.getDataPlaneKongConfig(ctx, dataplane)
+	if err != nil {
+		err = fmt.Errorf("failed to back up the Kong configuration of DataPlane %s: %w", dataplane.Name, err)
+		r.eventRecorder.Event(backup, "Warning", "KongConfigNotBackedUp", err.Error())
+		return err.Error(), nil
+	}
+	snapshot.KongConfig = string(config)
+	return "", nil
+}
+
+// snapshotCertificate returns the Secret holding the certificate issued for
+// the provided owner, or nil if it wasn't issued.
+func (r *GatewayBackupReconciler) snapshotCertificate(
+	ctx context.Context,
+	managedLabelValue string,
+	owner client.Object,
+) (*corev1.Secret, error) {
+	secrets, err := k8sutils.ListSecretsForOwner(
+		ctx,
+		r.Client,
+		consts.GatewayOperatorControlledLabel,
+		managedLabelValue,
+		owner.GetUID(),
+	)
+	if err != nil || len(secrets) != 1 {
+		return nil, err
+	}
+	return &corev1.Secret{
+		ObjectMeta: gatewayutils.SnapshotObjectMeta(secrets[0].ObjectMeta),
+		Type:       secrets[0].Type,
+		Data:       secrets[0].Data,
+	}, nil
+}
+
+// getDataPlaneKongConfig returns the Kong configuration run by the first
+// ready Pod of the DataPlane.
+func (r *GatewayBackupReconciler) getDataPlaneKongConfig(
+	ctx context.Context,
+	dataplane *operatorv1alpha1.DataPlane,
+) ([]byte, error) {
+	pods, err := getReadyDataPlanePods(ctx, r.Client, dataplane)
+	if err != nil {
+		return nil, err
+	}
+	if len(pods) == 0 {
+		return nil, fmt.Errorf("no ready Pod")
+	}
+	httpClient, err := r.adminAPICertificate.newClientForDataPlane(ctx, r.Client, r.ClusterCASecretNamespace, r.ClusterCASecretName, dataplane)
+	if err != nil {
+		return nil, err
+	}
+	return getDataPlanePodDeclarativeConfig(ctx, httpClient, pods[0])
+}
@@ -0,0 +1,522 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	"github.com/kong/gateway-operator/internal/consts"
+	operatorerrors "github.com/kong/gateway-operator/internal/errors"
+	gatewayutils "github.com/kong/gateway-operator/internal/utils/gateway"
+	k8sutils "github.com/kong/gateway-operator/internal/utils/kubernetes"
+)
+
+func TestGatewayBackupAndRestore(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, operatorv1alpha1.AddToScheme(scheme))
+	require.NoError(t, gatewayv1alpha2.AddToScheme(scheme))
+
+	const kongConfig = `{"_format_version":"3.0"}`
+	getConfig := getDataPlanePodDeclarativeConfig
+	getDataPlanePodDeclarativeConfig = func(_ context.Context, httpClient *http.Client, pod *corev1.Pod) ([]byte, error) {
+		require.NotNil(t, httpClient)
+		return []byte(kongConfig), nil
+	}
+	defer func() { getDataPlanePodDeclarativeConfig = getConfig }()
+
+	var postedConfigs map[string][]byte
+	postConfig := postDataPlanePodDeclarativeConfig
+	postDataPlanePodDeclarativeConfig = func(_ context.Context, httpClient *http.Client, pod *corev1.Pod, config []byte) error {
+		require.NotNil(t, httpClient)
+		postedConfigs[pod.Name] = config
+		return nil
+	}
+	defer func() { postDataPlanePodDeclarativeConfig = postConfig }()
+
+	ca := newTestCASecret(t)
+	namespace := gatewayv1alpha2.Namespace("default")
+	gatewayConfig := &operatorv1alpha1.GatewayConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kong"},
+	}
+	gatewayClass := &gatewayv1alpha2.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "kong"},
+		Spec: gatewayv1alpha2.GatewayClassSpec{
+			ControllerName: "konghq.com/gateway-operator",
+			ParametersRef: &gatewayv1alpha2.ParametersReference{
+				Group:     "gateway-operator.konghq.com",
+				Kind:      "GatewayConfiguration",
+				Name:      gatewayConfig.Name,
+				Namespace: &namespace,
+			},
+		},
+	}
+	gateway := &gatewayv1alpha2.Gateway{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "gateway.networking.k8s.io/v1alpha2", Kind: "Gateway"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kong", UID: "gateway-uid"},
+		Spec:       gatewayv1alpha2.GatewaySpec{GatewayClassName: "kong"},
+	}
+	dataplane := &operatorv1alpha1.DataPlane{
+		TypeMeta: metav1.TypeMeta{APIVersion: "gateway-operator.konghq.com/v1alpha1", Kind: "DataPlane"},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "kong-dp",
+			UID:       "dataplane-uid",
+			Labels:    map[string]string{consts.GatewayOperatorControlledLabel: consts.GatewayManagedLabelValue},
+		},
+		Status: operatorv1alpha1.DataPlaneStatus{Service: "kong-proxy"},
+	}
+	k8sutils.SetOwnerForObject(dataplane, gateway)
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "kong-proxy",
+			Labels:    map[string]string{consts.GatewayOperatorControlledLabel: consts.DataPlaneManagedLabelValue},
+		},
+		Spec: corev1.ServiceSpec{
+			Type:      corev1.ServiceTypeLoadBalancer,
+			ClusterIP: "10.96.0.10",
+			Ports:     []corev1.ServicePort{{Name: "proxy", Port: 80, NodePort: 30080}},
+		},
+	}
+	k8sutils.SetOwnerForObject(service, dataplane)
+	cert, key, err := issueCertificate("default", "kong-dp", "kong-proxy.default.svc",
+		[]certificatesv1.KeyUsage{certificatesv1.UsageServerAuth}, ca)
+	require.NoError(t, err)
+	certificate := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "dataplane-kong-dp-abcde",
+			Labels:    map[string]string{consts.GatewayOperatorControlledLabel: consts.DataPlaneManagedLabelValue},
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{"ca.crt": ca.Data["tls.crt"], "tls.crt": cert, "tls.key": key},
+	}
+	k8sutils.SetOwnerForObject(certificate, dataplane)
+	newPod := func(name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Labels: map[string]string{"app": dataplane.Name}},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				PodIP:      "10.0.0.1",
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+		}
+	}
+	backup := &operatorv1alpha1.GatewayBackup{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "gateway-operator.konghq.com/v1alpha1", Kind: "GatewayBackup"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kong-backup", UID: "backup-uid"},
+		Spec: operatorv1alpha1.GatewayBackupSpec{
+			GatewayName: gateway.Name,
+			Storage: operatorv1alpha1.GatewayBackupStorage{
+				Secret: &operatorv1alpha1.GatewayBackupSecretStorage{Name: "kong-snapshot"},
+			},
+		},
+	}
+
+	backupClient := fakeclient.NewClientBuilder().WithScheme(scheme).
+		WithObjects(ca, gatewayConfig, gatewayClass, gateway, dataplane, service, certificate, newPod("kong-dp-1"), backup).
+		Build()
+	backupReconciler := &GatewayBackupReconciler{
+		Client:                   backupClient,
+		ClusterCASecretName:      ca.Name,
+		ClusterCASecretNamespace: ca.Namespace,
+		eventRecorder:            record.NewFakeRecorder(10),
+	}
+
+	t.Log("backing up the gateway in a secret")
+	_, err = backupReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(backup)})
+	require.NoError(t, err)
+	require.NoError(t, backupClient.Get(ctx, client.ObjectKeyFromObject(backup), backup))
+	condition, ok := k8sutils.GetCondition(GatewayBackupConditionTypeCompleted, backup)
+	require.True(t, ok)
+	require.Equal(t, metav1.ConditionTrue, condition.Status)
+	require.NotNil(t, backup.Status.CompletionTime)
+
+	snapshotSecret := &corev1.Secret{}
+	require.NoError(t, backupClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "kong-snapshot"}, snapshotSecret))
+	require.True(t, k8sutils.IsOwnedByRefUID(snapshotSecret, backup.UID))
+	snapshot, err := gatewayutils.DecodeSnapshot(snapshotSecret.Data[consts.GatewayBackupSnapshotKey])
+	require.NoError(t, err)
+	require.Equal(t, gateway.Name, snapshot.Gateway.Name)
+	require.Empty(t, snapshot.Gateway.UID)
+	require.Equal(t, gatewayClass.Name, snapshot.GatewayClass.Name)
+	require.Equal(t, gatewayConfig.Name, snapshot.GatewayConfiguration.Name)
+	require.Equal(t, dataplane.Name, snapshot.DataPlane.Name)
+	require.Len(t, snapshot.DataPlaneServices, 1)
+	require.Equal(t, certificate.Name, snapshot.DataPlaneCertificate.Name)
+	require.Nil(t, snapshot.ControlPlane)
+	require.Equal(t, kongConfig, snapshot.KongConfig)
+
+	t.Log("failing the backup of a missing gateway")
+	missing := &operatorv1alpha1.GatewayBackup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "missing"},
+		Spec: operatorv1alpha1.GatewayBackupSpec{
+			GatewayName: "missing",
+			Storage:     backup.Spec.Storage,
+		},
+	}
+	require.NoError(t, backupClient.Create(ctx, missing))
+	_, err = backupReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(missing)})
+	require.NoError(t, err)
+	require.NoError(t, backupClient.Get(ctx, client.ObjectKeyFromObject(missing), missing))
+	condition, ok = k8sutils.GetCondition(GatewayBackupConditionTypeCompleted, missing)
+	require.True(t, ok)
+	require.Equal(t, string(GatewayBackupConditionReasonFailed), condition.Reason)
+
+	t.Log("restoring the gateway from the snapshot in a fresh cluster")
+	restore := &operatorv1alpha1.GatewayRestore{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "gateway-operator.konghq.com/v1alpha1", Kind: "GatewayRestore"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kong-restore", UID: "restore-uid"},
+		Spec: operatorv1alpha1.GatewayRestoreSpec{
+			Source: operatorv1alpha1.GatewayRestoreSource{
+				Storage: &operatorv1alpha1.GatewayBackupStorage{
+					Secret: &operatorv1alpha1.GatewayBackupSecretStorage{Name: "kong-snapshot"},
+				},
+			},
+		},
+	}
+	restoreClient := fakeclient.NewClientBuilder().WithScheme(scheme).
+		WithObjects(ca, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kong-snapshot"},
+			Data:       snapshotSecret.Data,
+		}, restore).
+		Build()
+	restoreReconciler := &GatewayRestoreReconciler{
+		Client:                     restoreClient,
+		GatewayClassRestoreEnabled: true,
+		ClusterCASecretName:        ca.Name,
+		ClusterCASecretNamespace:   ca.Namespace,
+		eventRecorder:              record.NewFakeRecorder(10),
+	}
+	reconcileRestore := func() (ctrl.Result, metav1.Condition) {
+		result, err := restoreReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(restore)})
+		require.NoError(t, err)
+		require.NoError(t, restoreClient.Get(ctx, client.ObjectKeyFromObject(restore), restore))
+		condition, ok := k8sutils.GetCondition(GatewayRestoreConditionTypeCompleted, restore)
+		require.True(t, ok)
+		return result, condition
+	}
+
+	_, err = restoreReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(restore)})
+	require.NoError(t, err)
+	require.NoError(t, restoreClient.Get(ctx, client.ObjectKeyFromObject(restore), restore))
+	require.Contains(t, restore.Finalizers, string(GatewayRestoreFinalizerReleaseRestoredObjects))
+	_, condition = reconcileRestore()
+	require.Equal(t, string(GatewayRestoreConditionReasonWaitingForDataPlane), condition.Reason)
+
+	restoredClass := &gatewayv1alpha2.GatewayClass{}
+	require.NoError(t, restoreClient.Get(ctx, client.ObjectKeyFromObject(gatewayClass), restoredClass))
+	require.Equal(t, gatewayClass.Spec.ParametersRef, restoredClass.Spec.ParametersRef)
+	require.NoError(t, restoreClient.Get(ctx, client.ObjectKeyFromObject(gatewayConfig), &operatorv1alpha1.GatewayConfiguration{}))
+
+	restoredGateway := &gatewayv1alpha2.Gateway{}
+	require.NoError(t, restoreClient.Get(ctx, client.ObjectKeyFromObject(gateway), restoredGateway))
+	require.Equal(t, "default/kong-restore", restoredGateway.Annotations[consts.GatewayRestoreInProgressAnnotation])
+
+	restoredDataPlane := &operatorv1alpha1.DataPlane{}
+	require.NoError(t, restoreClient.Get(ctx, client.ObjectKeyFromObject(dataplane), restoredDataPlane))
+	require.Equal(t, "default/kong-restore", restoredDataPlane.Annotations[consts.GatewayRestoreInProgressAnnotation])
+	require.Len(t, restoredDataPlane.OwnerReferences, 1)
+	require.Equal(t, "Gateway", restoredDataPlane.OwnerReferences[0].Kind)
+	require.Equal(t, gateway.Name, restoredDataPlane.OwnerReferences[0].Name)
+
+	restoredService := &corev1.Service{}
+	require.NoError(t, restoreClient.Get(ctx, client.ObjectKeyFromObject(service), restoredService))
+	require.Empty(t, restoredService.Spec.ClusterIP)
+	require.Zero(t, restoredService.Spec.Ports[0].NodePort)
+	require.Equal(t, "DataPlane", restoredService.OwnerReferences[0].Kind)
+
+	restoredCertificate := &corev1.Secret{}
+	require.NoError(t, restoreClient.Get(ctx, client.ObjectKeyFromObject(certificate), restoredCertificate))
+	require.Equal(t, certificate.Data, restoredCertificate.Data)
+
+	t.Log("waiting for the restored dataplane to be ready")
+	result, condition := reconcileRestore()
+	require.Equal(t, string(GatewayRestoreConditionReasonWaitingForDataPlane), condition.Reason)
+	require.Equal(t, gatewayRestoreDataPlaneReadinessInterval, result.RequeueAfter)
+	require.NoError(t, restoreClient.Get(ctx, client.ObjectKeyFromObject(gateway), restoredGateway))
+	require.NotContains(t, restoredGateway.Annotations, consts.GatewayRestoreInProgressAnnotation)
+	require.NoError(t, restoreClient.Get(ctx, client.ObjectKeyFromObject(dataplane), restoredDataPlane))
+	require.NotContains(t, restoredDataPlane.Annotations, consts.GatewayRestoreInProgressAnnotation)
+	restoredGatewayConfig := &operatorv1alpha1.GatewayConfiguration{}
+	require.NoError(t, restoreClient.Get(ctx, client.ObjectKeyFromObject(gatewayConfig), restoredGatewayConfig))
+	require.NotContains(t, restoredGatewayConfig.Annotations, consts.GatewayRestoreInProgressAnnotation)
+
+	t.Log("loading the kong configuration in the ready dataplane")
+	require.NoError(t, restoreClient.Create(ctx, newPod("kong-dp-2")))
+	postedConfigs = map[string][]byte{}
+	_, condition = reconcileRestore()
+	require.Equal(t, metav1.ConditionTrue, condition.Status)
+	require.Equal(t, string(GatewayRestoreConditionReasonCompleted), condition.Reason)
+	require.NotNil(t, restore.Status.CompletionTime)
+	require.Equal(t, map[string][]byte{"kong-dp-2": []byte(kongConfig)}, postedConfigs)
+
+	t.Log("failing a restore overwriting objects it didn't restore")
+	overwrite := &operatorv1alpha1.GatewayRestore{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "overwrite"},
+		Spec:       restore.Spec,
+	}
+	require.NoError(t, restoreClient.Create(ctx, overwrite))
+	reconcileOtherRestore := func(restore *operatorv1alpha1.GatewayRestore) {
+		_, err := restoreReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(restore)})
+		require.NoError(t, err)
+		err = restoreClient.Get(ctx, client.ObjectKeyFromObject(restore), restore)
+		require.True(t, err == nil || k8serrors.IsNotFound(err), err)
+	}
+	reconcileOtherRestore(overwrite)
+	reconcileOtherRestore(overwrite)
+	condition, ok = k8sutils.GetCondition(GatewayRestoreConditionTypeCompleted, overwrite)
+	require.True(t, ok)
+	require.Equal(t, string(GatewayRestoreConditionReasonFailed), condition.Reason)
+
+	t.Log("releasing the objects restored before a restore failed")
+	require.NoError(t, restoreClient.Delete(ctx, restoredGateway))
+	require.NoError(t, restoreClient.Delete(ctx, restoredDataPlane))
+	require.NoError(t, restoreClient.Create(ctx, &operatorv1alpha1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{Namespace: dataplane.Namespace, Name: dataplane.Name},
+	}))
+	partial := &operatorv1alpha1.GatewayRestore{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "partial"},
+		Spec:       restore.Spec,
+	}
+	require.NoError(t, restoreClient.Create(ctx, partial))
+	reconcileOtherRestore(partial)
+	reconcileOtherRestore(partial)
+	condition, ok = k8sutils.GetCondition(GatewayRestoreConditionTypeCompleted, partial)
+	require.True(t, ok)
+	require.Equal(t, string(GatewayRestoreConditionReasonFailed), condition.Reason)
+	require.NoError(t, restoreClient.Get(ctx, client.ObjectKeyFromObject(gateway), restoredGateway))
+	require.Equal(t, "default/partial", restoredGateway.Annotations[consts.GatewayRestoreInProgressAnnotation])
+	reconcileOtherRestore(partial)
+	require.NoError(t, restoreClient.Get(ctx, client.ObjectKeyFromObject(gateway), restoredGateway))
+	require.NotContains(t, restoredGateway.Annotations, consts.GatewayRestoreInProgressAnnotation,
+		"the gateway restored before the failure must be reconciled by its controller")
+
+	t.Log("releasing the restored objects when a restore in progress is deleted")
+	require.NoError(t, restoreClient.Delete(ctx, restoredGateway))
+	require.NoError(t, restoreClient.Get(ctx, client.ObjectKeyFromObject(dataplane), restoredDataPlane))
+	require.NoError(t, restoreClient.Delete(ctx, restoredDataPlane))
+	deleted := &operatorv1alpha1.GatewayRestore{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "deleted"},
+		Spec:       restore.Spec,
+	}
+	require.NoError(t, restoreClient.Create(ctx, deleted))
+	reconcileOtherRestore(deleted)
+	reconcileOtherRestore(deleted)
+	require.NoError(t, restoreClient.Get(ctx, client.ObjectKeyFromObject(dataplane), restoredDataPlane))
+	require.Equal(t, "default/deleted", restoredDataPlane.Annotations[consts.GatewayRestoreInProgressAnnotation])
+	require.NoError(t, restoreClient.Delete(ctx, deleted))
+	reconcileOtherRestore(deleted)
+	require.NoError(t, restoreClient.Get(ctx, client.ObjectKeyFromObject(dataplane), restoredDataPlane))
+	require.NotContains(t, restoredDataPlane.Annotations, consts.GatewayRestoreInProgressAnnotation)
+	require.NoError(t, restoreClient.Get(ctx, client.ObjectKeyFromObject(gateway), restoredGateway))
+	require.NotContains(t, restoredGateway.Annotations, consts.GatewayRestoreInProgressAnnotation)
+	require.True(t, k8serrors.IsNotFound(restoreClient.Get(ctx, client.ObjectKeyFromObject(deleted), deleted)),
+		"the GatewayRestore must be deleted once its finalizer is removed")
+}
+
+func TestGenerateGatewayBackupJob(t *testing.T) {
+	backup := &operatorv1alpha1.GatewayBackup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kong-backup"},
+		Spec: operatorv1alpha1.GatewayBackupSpec{
+			GatewayName: "kong",
+			Storage: operatorv1alpha1.GatewayBackupStorage{
+				PersistentVolumeClaim: &operatorv1alpha1.GatewayBackupVolumeStorage{ClaimName: "backups"},
+			},
+		},
+	}
+
+	job := generateGatewayBackupJob(backup, "gatewaybackup-kong-backup-snapshot", nil)
+	require.Equal(t, "gatewaybackup-kong-backup-", job.GenerateName)
+	require.Equal(t, pointer.Int32(gatewayBackupJobBackoffLimit), job.Spec.BackoffLimit)
+	podSpec := job.Spec.Template.Spec
+	require.Equal(t, corev1.RestartPolicyNever, podSpec.RestartPolicy)
+	require.Len(t, podSpec.Volumes, 2)
+	require.Equal(t, "backups", podSpec.Volumes[0].PersistentVolumeClaim.ClaimName)
+	require.Equal(t, "gatewaybackup-kong-backup-snapshot", podSpec.Volumes[1].Secret.SecretName)
+	require.Equal(t, []string{"cp", "/var/gateway-snapshot/snapshot.json", "/var/gateway-backup/kong-backup.json"},
+		podSpec.Containers[0].Command)
+
+	backup.Spec.Storage.PersistentVolumeClaim.Path = "kong.json"
+	job = generateGatewayBackupJob(backup, "gatewaybackup-kong-backup-snapshot", nil)
+	require.Equal(t, "/var/gateway-backup/kong.json", job.Spec.Template.Spec.Containers[0].Command[2])
+
+	restore := &operatorv1alpha1.GatewayRestore{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kong-restore"},
+	}
+	job = generateGatewayRestoreJob(restore, "backups", "kong.json", "gatewayrestore-kong-restore-snapshot",
+		"gatewayrestore-kong-restore-snapshot", nil)
+	require.Equal(t, "gatewayrestore-kong-restore-", job.GenerateName)
+	podSpec = job.Spec.Template.Spec
+	require.Equal(t, "gatewayrestore-kong-restore-snapshot", podSpec.ServiceAccountName)
+	require.True(t, podSpec.Volumes[0].PersistentVolumeClaim.ReadOnly)
+	container := podSpec.Containers[0]
+	require.Equal(t, consts.DefaultGatewayRestoreJobImage, container.Image)
+	require.Equal(t, []string{"gatewayrestore-kong-restore-snapshot", "default", "/var/gateway-backup/kong.json"},
+		container.Command[3:], "the names and the path must be passed as parameters of the script")
+	require.NotContains(t, container.Command[2], "kong.json", "the path must not be interpreted by the shell")
+	require.NotContains(t, container.Command[2], "cat ")
+
+	role := generateGatewayRestoreRole(restore, "gatewayrestore-kong-restore-snapshot", "gatewayrestore-kong-restore-snapshot")
+	require.Len(t, role.Rules, 1)
+	require.Equal(t, []string{"gatewayrestore-kong-restore-snapshot"}, role.Rules[0].ResourceNames)
+	require.Equal(t, []string{"get", "patch"}, role.Rules[0].Verbs)
+}
+
+func TestGatewayRestoreReadSnapshotFromVolume(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, operatorv1alpha1.AddToScheme(scheme))
+
+	restore := &operatorv1alpha1.GatewayRestore{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "gateway-operator.konghq.com/v1alpha1", Kind: "GatewayRestore"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kong-restore", UID: "restore-uid"},
+	}
+	fakeClient := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(restore).Build()
+	reconciler := &GatewayRestoreReconciler{Client: fakeClient, Scheme: scheme}
+
+	t.Log("staging the snapshot through a Job with a ServiceAccount scoped to the staging Secret")
+	data, err := reconciler.readSnapshotFromVolume(ctx, restore, "backups", "kong.json")
+	require.NoError(t, err)
+	require.Nil(t, data)
+	name := client.ObjectKey{Namespace: "default", Name: "gatewayrestore-kong-restore-snapshot"}
+	stagingSecret := &corev1.Secret{}
+	require.NoError(t, fakeClient.Get(ctx, name, stagingSecret))
+	require.True(t, k8sutils.IsOwnedByRefUID(stagingSecret, restore.UID))
+	require.Empty(t, stagingSecret.Data)
+	require.NoError(t, fakeClient.Get(ctx, name, &corev1.ServiceAccount{}))
+	require.NoError(t, fakeClient.Get(ctx, name, &rbacv1.Role{}))
+	require.NoError(t, fakeClient.Get(ctx, name, &rbacv1.RoleBinding{}))
+	jobs := &batchv1.JobList{}
+	require.NoError(t, fakeClient.List(ctx, jobs))
+	require.Len(t, jobs.Items, 1)
+	require.Equal(t, name.Name, jobs.Items[0].Spec.Template.Spec.ServiceAccountName)
+
+	t.Log("waiting for the Job to write the snapshot")
+	data, err = reconciler.readSnapshotFromVolume(ctx, restore, "backups", "kong.json")
+	require.NoError(t, err)
+	require.Nil(t, data)
+
+	t.Log("reading the snapshot written by the Job")
+	stagingSecret.Data = map[string][]byte{consts.GatewayBackupSnapshotKey: []byte("{}")}
+	require.NoError(t, fakeClient.Update(ctx, stagingSecret))
+	data, err = reconciler.readSnapshotFromVolume(ctx, restore, "backups", "kong.json")
+	require.NoError(t, err)
+	require.Equal(t, []byte("{}"), data)
+
+	t.Log("failing when the Job failed")
+	stagingSecret.Data = nil
+	require.NoError(t, fakeClient.Update(ctx, stagingSecret))
+	job := &jobs.Items[0]
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+	require.NoError(t, fakeClient.Status().Update(ctx, job))
+	_, err = reconciler.readSnapshotFromVolume(ctx, restore, "backups", "kong.json")
+	require.ErrorIs(t, err, operatorerrors.ErrBackupFailed)
+
+	t.Log("failing when the staging Secret isn't owned by the GatewayRestore")
+	other := restore.DeepCopy()
+	other.Name, other.UID = "other-restore", "other-uid"
+	require.NoError(t, fakeClient.Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gatewayrestore-other-restore-snapshot"},
+	}))
+	_, err = reconciler.readSnapshotFromVolume(ctx, other, "backups", "kong.json")
+	require.ErrorIs(t, err, operatorerrors.ErrBackupFailed)
+}
+
+func TestGatewayRestoreTargetNamespace(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, operatorv1alpha1.AddToScheme(scheme))
+	require.NoError(t, gatewayv1alpha2.AddToScheme(scheme))
+
+	restore := &operatorv1alpha1.GatewayRestore{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "kong-restore"},
+		Spec:       operatorv1alpha1.GatewayRestoreSpec{TargetNamespace: "team-b"},
+	}
+	fakeClient := fakeclient.NewClientBuilder().WithScheme(scheme).Build()
+	reconciler := &GatewayRestoreReconciler{Client: fakeClient, Scheme: scheme}
+
+	t.Log("restoring into the namespace of the GatewayRestore")
+	require.NoError(t, reconciler.ensureTargetNamespaceGranted(ctx, restore, "team-a"))
+
+	t.Log("failing to restore into another namespace without a ReferenceGrant")
+	err := reconciler.ensureTargetNamespaceGranted(ctx, restore, "team-b")
+	require.ErrorIs(t, err, operatorerrors.ErrBackupFailed)
+
+	t.Log("restoring into another namespace granting the GatewayRestores of the namespace")
+	require.NoError(t, fakeClient.Create(ctx, &gatewayv1alpha2.ReferenceGrant{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-b", Name: "restores"},
+		Spec: gatewayv1alpha2.ReferenceGrantSpec{
+			From: []gatewayv1alpha2.ReferenceGrantFrom{{
+				Group:     "gateway-operator.konghq.com",
+				Kind:      "GatewayRestore",
+				Namespace: "team-a",
+			}},
+			To: []gatewayv1alpha2.ReferenceGrantTo{{Group: gatewayv1alpha2.GroupName, Kind: "Gateway"}},
+		},
+	}))
+	require.NoError(t, reconciler.ensureTargetNamespaceGranted(ctx, restore, "team-b"))
+
+	t.Log("failing to restore a missing GatewayClass when its restore is disabled")
+	snapshot := &gatewayutils.Snapshot{
+		GatewayClass: &gatewayv1alpha2.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "kong"},
+			Spec:       gatewayv1alpha2.GatewayClassSpec{ControllerName: "konghq.com/gateway-operator"},
+		},
+	}
+	err = reconciler.ensureGatewayClassRestored(ctx, restore, "team-b", snapshot)
+	require.ErrorIs(t, err, operatorerrors.ErrBackupFailed)
+	gatewayClasses := &gatewayv1alpha2.GatewayClassList{}
+	require.NoError(t, fakeClient.List(ctx, gatewayClasses))
+	require.Empty(t, gatewayClasses.Items)
+
+	t.Log("restoring a missing GatewayClass when its restore is enabled")
+	reconciler.GatewayClassRestoreEnabled = true
+	require.NoError(t, reconciler.ensureGatewayClassRestored(ctx, restore, "team-b", snapshot))
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKey{Name: "kong"}, &gatewayv1alpha2.GatewayClass{}))
+
+	t.Log("failing to restore a GatewayClass which GatewayConfiguration exists and wasn't restored by the GatewayRestore")
+	require.NoError(t, fakeClient.Create(ctx, &operatorv1alpha1.GatewayConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-b", Name: "kong-config"},
+	}))
+	snapshot = &gatewayutils.Snapshot{
+		GatewayClass: &gatewayv1alpha2.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "kong-configured"},
+			Spec: gatewayv1alpha2.GatewayClassSpec{
+				ControllerName: "konghq.com/gateway-operator",
+				ParametersRef: &gatewayv1alpha2.ParametersReference{
+					Group: "gateway-operator.konghq.com",
+					Kind:  "GatewayConfiguration",
+					Name:  "kong-config",
+				},
+			},
+		},
+		GatewayConfiguration: &operatorv1alpha1.GatewayConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "kong-config"},
+		},
+	}
+	err = reconciler.ensureGatewayClassRestored(ctx, restore, "team-b", snapshot)
+	require.ErrorIs(t, err, operatorerrors.ErrBackupFailed)
+	require.True(t, k8serrors.IsNotFound(fakeClient.Get(ctx, client.ObjectKey{Name: "kong-configured"}, &gatewayv1alpha2.GatewayClass{})))
+}
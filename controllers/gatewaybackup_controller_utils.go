@@ -0,0 +1,269 @@
+package controllers
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"path"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	"github.com/kong/gateway-operator/internal/consts"
+	operatorerrors "github.com/kong/gateway-operator/internal/errors"
+	"github.com/kong/gateway-operator/internal/utils/image"
+)
+
+// -----------------------------------------------------------------------------
+// GatewayBackup - Private Functions - Validation
+// -----------------------------------------------------------------------------
+
+// validateGatewayBackupStorage checks that exactly one storage is set.
+func validateGatewayBackupStorage(storage *operatorv1alpha1.GatewayBackupStorage) error {
+	if (storage.Secret == nil) == (storage.PersistentVolumeClaim == nil) {
+		return fmt.Errorf("%w: exactly one of the secret and persistentVolumeClaim storages must be set", operatorerrors.ErrBackupFailed)
+	}
+	return nil
+}
+
+// gatewayBackupVolumePath returns the name of the file of the volume the
+// snapshot of a GatewayBackup is stored in.
+func gatewayBackupVolumePath(backup *operatorv1alpha1.GatewayBackup) string {
+	if p := backup.Spec.Storage.PersistentVolumeClaim.Path; p != "" {
+		return p
+	}
+	return backup.Name + ".json"
+}
+
+// -----------------------------------------------------------------------------
+// GatewayBackup - Private Functions - Generators
+// -----------------------------------------------------------------------------
+
+// generateGatewayBackupJob generates the Job copying the snapshot staged in the
+// provided Secret to the volume of a GatewayBackup.
+func generateGatewayBackupJob(
+	backup *operatorv1alpha1.GatewayBackup,
+	stagingSecretName string,
+	imagePolicy *image.Policy,
+) *batchv1.Job {
+	job := generateGatewaySnapshotJob(
+		backup.Namespace,
+		fmt.Sprintf("%s-%s-", consts.GatewayBackupPrefix, backup.Name),
+		backup.Spec.Storage.PersistentVolumeClaim.ClaimName,
+		[]string{
+			"cp",
+			path.Join(consts.GatewayBackupSnapshotMountPath, consts.GatewayBackupSnapshotKey),
+			path.Join(consts.GatewayBackupVolumeMountPath, gatewayBackupVolumePath(backup)),
+		},
+		imagePolicy,
+	)
+
+	podSpec := &job.Spec.Template.Spec
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: gatewayBackupSnapshotVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{SecretName: stagingSecretName},
+		},
+	})
+	container := &podSpec.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      gatewayBackupSnapshotVolumeName,
+		ReadOnly:  true,
+		MountPath: consts.GatewayBackupSnapshotMountPath,
+	})
+	return job
+}
+
+// generateGatewayRestoreJob generates the Job writing the snapshot stored in
+// the provided file of a volume into the staging Secret of a GatewayRestore.
+// It runs with the provided ServiceAccount, only allowed to update that
+// Secret, for the snapshot never to go through the logs of its Pod.
+func generateGatewayRestoreJob(
+	restore *operatorv1alpha1.GatewayRestore,
+	claimName, filePath, stagingSecretName, serviceAccountName string,
+	imagePolicy *image.Policy,
+) *batchv1.Job {
+	// the names and the path are passed as positional parameters of the
+	// script for them not to be interpreted by the shell.
+	script := fmt.Sprintf(
+		`kubectl create secret generic "$0" --namespace "$1" --from-file=%s="$2" --dry-run=client -o yaml | `+
+			`kubectl apply --namespace "$1" --server-side --force-conflicts --field-manager=%s -f -`,
+		consts.GatewayBackupSnapshotKey, gatewayRestoreFieldManager,
+	)
+	job := generateGatewaySnapshotJob(
+		restore.Namespace,
+		fmt.Sprintf("%s-%s-", consts.GatewayRestorePrefix, restore.Name),
+		claimName,
+		[]string{
+			"/bin/sh", "-c", script,
+			stagingSecretName, restore.Namespace, path.Join(consts.GatewayBackupVolumeMountPath, filePath),
+		},
+		imagePolicy,
+	)
+
+	podSpec := &job.Spec.Template.Spec
+	podSpec.ServiceAccountName = serviceAccountName
+	podSpec.AutomountServiceAccountToken = pointer.Bool(true)
+	podSpec.Volumes[0].PersistentVolumeClaim.ReadOnly = true
+	container := &podSpec.Containers[0]
+	container.Image = imagePolicy.MirrorDefaultImage(consts.DefaultGatewayRestoreJobImage)
+	container.VolumeMounts[0].ReadOnly = true
+	return job
+}
+
+// generateGatewayRestoreServiceAccount generates the ServiceAccount of the Job
+// writing the snapshot stored in a volume into the staging Secret of a
+// GatewayRestore.
+func generateGatewayRestoreServiceAccount(restore *operatorv1alpha1.GatewayRestore, name string) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Namespace: restore.Namespace, Name: name},
+	}
+}
+
+// generateGatewayRestoreRole generates the Role only allowing to update the
+// staging Secret of a GatewayRestore.
+func generateGatewayRestoreRole(restore *operatorv1alpha1.GatewayRestore, name, stagingSecretName string) *rbacv1.Role {
+	return &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{Namespace: restore.Namespace, Name: name},
+		Rules: []rbacv1.PolicyRule{{
+			APIGroups:     []string{""},
+			Resources:     []string{"secrets"},
+			ResourceNames: []string{stagingSecretName},
+			Verbs:         []string{"get", "patch"},
+		}},
+	}
+}
+
+// generateGatewayRestoreRoleBinding generates the RoleBinding granting the
+// Role of a GatewayRestore to its ServiceAccount.
+func generateGatewayRestoreRoleBinding(restore *operatorv1alpha1.GatewayRestore, name string) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Namespace: restore.Namespace, Name: name},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     name,
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Namespace: restore.Namespace,
+			Name:      name,
+		}},
+	}
+}
+
+// generateGatewaySnapshotJob generates a Job running the provided command in
+// a Pod mounting the volume storing the snapshots. The default DataPlane
+// image is used, as it's already pulled by the nodes running DataPlanes.
+func generateGatewaySnapshotJob(
+	namespace, generateName, claimName string,
+	command []string,
+	imagePolicy *image.Policy,
+) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:    namespace,
+			GenerateName: generateName,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: pointer.Int32(gatewayBackupJobBackoffLimit),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy:    corev1.RestartPolicyNever,
+					ImagePullSecrets: imagePolicy.LocalObjectReferences(),
+					Volumes: []corev1.Volume{{
+						Name: gatewayBackupVolumeName,
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName},
+						},
+					}},
+					Containers: []corev1.Container{{
+						Name:            gatewayBackupJobContainerName,
+						Image:           imagePolicy.MirrorDefaultImage(consts.DefaultDataPlaneImage),
+						ImagePullPolicy: corev1.PullIfNotPresent,
+						Command:         command,
+						VolumeMounts: []corev1.VolumeMount{{
+							Name:      gatewayBackupVolumeName,
+							MountPath: consts.GatewayBackupVolumeMountPath,
+						}},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// -----------------------------------------------------------------------------
+// GatewayRestore - Private Functions - Helpers
+// -----------------------------------------------------------------------------
+
+// gatewayRestoreTargetNamespace returns the namespace the Gateway of a
+// GatewayRestore is restored into.
+func gatewayRestoreTargetNamespace(restore *operatorv1alpha1.GatewayRestore) string {
+	if restore.Spec.TargetNamespace != "" {
+		return restore.Spec.TargetNamespace
+	}
+	return restore.Namespace
+}
+
+// gatewayRestoreSnapshotName returns the name of the staging Secret of a
+// GatewayRestore, also used by the ServiceAccount, Role and RoleBinding of the
+// Job writing the snapshot stored in a volume into it.
+func gatewayRestoreSnapshotName(restore *operatorv1alpha1.GatewayRestore) string {
+	return fmt.Sprintf("%s-%s-snapshot", consts.GatewayRestorePrefix, restore.Name)
+}
+
+// gatewayRestoreKey returns the value of the annotation set on the objects
+// being restored by a GatewayRestore.
+func gatewayRestoreKey(restore *operatorv1alpha1.GatewayRestore) string {
+	return restore.Namespace + "/" + restore.Name
+}
+
+// certificateIsIssuedByCA returns true if the certificate of the provided TLS
+// Secret has been issued by the CA of the provided Secret.
+func certificateIsIssuedByCA(secret, ca *corev1.Secret) bool {
+	block, _ := pem.Decode(secret.Data["tls.crt"])
+	if block == nil {
+		return false
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return false
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(ca.Data["tls.crt"]) {
+		return false
+	}
+	_, err = cert.Verify(x509.VerifyOptions{
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	return err == nil
+}
+
+// -----------------------------------------------------------------------------
+// GatewayBackup - Private Functions - Labels
+// -----------------------------------------------------------------------------
+
+func addLabelForGatewayBackup(obj client.Object) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[consts.GatewayOperatorControlledLabel] = consts.GatewayBackupManagedLabelValue
+	obj.SetLabels(labels)
+}
+
+func addLabelForGatewayRestore(obj client.Object) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[consts.GatewayOperatorControlledLabel] = consts.GatewayRestoreManagedLabelValue
+	obj.SetLabels(labels)
+}
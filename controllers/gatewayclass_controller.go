@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"context"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	gatewayutils "github.com/kong/gateway-operator/internal/utils/gateway"
+	k8sutils "github.com/kong/gateway-operator/internal/utils/kubernetes"
+	"github.com/kong/gateway-operator/pkg/vars"
+)
+
+// -----------------------------------------------------------------------------
+// GatewayClassReconciler
+// -----------------------------------------------------------------------------
+
+// GatewayClassReconciler reconciles a GatewayClass object
+type GatewayClassReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// GatewayAPIV1beta1Enabled indicates whether the cluster serves the v1beta1
+	// version of Gateways and GatewayClasses, which have to be watched instead of
+	// the v1alpha2 ones.
+	GatewayAPIV1beta1Enabled bool
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GatewayClassReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		// watch GatewayClass objects, filtering out any GatewayClasses which are not
+		// configured with the controller name of this operator.
+		For(gatewayutils.GatewayClassForVersion(r.GatewayAPIV1beta1Enabled),
+			builder.WithPredicates(predicate.NewPredicateFuncs(r.gatewayClassMatchesController))).
+		// watch for updates to GatewayConfigurations, enqueue the GatewayClasses
+		// which reference them, as their parameters may have become (in)valid.
+		Watches(
+			&source.Kind{Type: &operatorv1alpha1.GatewayConfiguration{}},
+			handler.EnqueueRequestsFromMapFunc(r.listGatewayClassesForGatewayConfig)).
+		// watch for updates to Gateways, enqueue their GatewayClass so that the
+		// finalizer reflects whether the class is still in use.
+		Watches(
+			&source.Kind{Type: gatewayutils.GatewayForVersion(r.GatewayAPIV1beta1Enabled)},
+			handler.EnqueueRequestsFromMapFunc(r.getGatewayClassForGateway)).
+		Complete(r)
+}
+
+// Reconcile moves the current state of an object to the intended state.
+func (r *GatewayClassReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx).WithName("gatewayclass")
+
+	debug(log, "reconciling gatewayclass resource", req)
+	gatewayClass := newGatewayClass()
+	if err := r.Client.Get(ctx, req.NamespacedName, gatewayClass.GatewayClass); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if string(gatewayClass.Spec.ControllerName) != vars.ControllerName {
+		debug(log, "resource not supported, ignoring", gatewayClass, "ExpectedControllerName", vars.ControllerName)
+		return ctrl.Result{}, nil
+	}
+
+	debug(log, "ensuring the gatewayclass finalizer reflects the gateways using it", gatewayClass)
+	updated, err := r.ensureGatewaysExistFinalizer(ctx, gatewayClass)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if updated {
+		return ctrl.Result{}, nil // requeue will be triggered by the update of the gatewayclass
+	}
+
+	if !gatewayClass.DeletionTimestamp.IsZero() {
+		debug(log, "gatewayclass is being deleted", gatewayClass)
+		return ctrl.Result{}, nil
+	}
+
+	debug(log, "validating gatewayclass parameters", gatewayClass)
+	condition, err := r.getAcceptedCondition(ctx, gatewayClass)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	current, present := k8sutils.GetCondition(k8sutils.ConditionType(condition.Type), gatewayClass)
+	if present &&
+		current.Status == condition.Status &&
+		current.Reason == condition.Reason &&
+		current.Message == condition.Message &&
+		current.ObservedGeneration == condition.ObservedGeneration {
+		debug(log, "gatewayclass status is up to date", gatewayClass)
+		return ctrl.Result{}, nil
+	}
+
+	k8sutils.SetCondition(condition, gatewayClass)
+	if err := r.Client.Status().Update(ctx, gatewayClass.GatewayClass); err != nil {
+		if k8serrors.IsConflict(err) {
+			debug(log, "conflict found when updating gatewayclass status, retrying", gatewayClass)
+			return ctrl.Result{Requeue: true, RequeueAfter: requeueWithoutBackoff}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	debug(log, "reconciliation complete for gatewayclass resource", gatewayClass)
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,25 @@
+package controllers
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// gatewayClassDecorator Decorator object to add additional functionality to the base k8s GatewayClass
+type gatewayClassDecorator struct {
+	*gatewayv1alpha2.GatewayClass
+}
+
+func (g *gatewayClassDecorator) GetConditions() []metav1.Condition {
+	return g.Status.Conditions
+}
+
+func (g *gatewayClassDecorator) SetConditions(conditions []metav1.Condition) {
+	g.Status.Conditions = conditions
+}
+
+func newGatewayClass() *gatewayClassDecorator {
+	return &gatewayClassDecorator{
+		new(gatewayv1alpha2.GatewayClass),
+	}
+}
@@ -0,0 +1,9 @@
+package controllers
+
+// -----------------------------------------------------------------------------
+// GatewayClassReconciler - RBAC Permissions
+// -----------------------------------------------------------------------------
+
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gatewayclasses,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gatewayclasses/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gatewayclasses/finalizers,verbs=update
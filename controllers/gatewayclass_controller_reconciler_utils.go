@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	k8sutils "github.com/kong/gateway-operator/internal/utils/kubernetes"
+)
+
+// -----------------------------------------------------------------------------
+// GatewayClassReconciler - Reconciler Helpers
+// -----------------------------------------------------------------------------
+
+// ensureGatewaysExistFinalizer adds the gateway-exists finalizer to the
+// GatewayClass while any Gateway uses it, and removes it once no Gateway does
+// anymore. It returns true if the GatewayClass has been updated.
+func (r *GatewayClassReconciler) ensureGatewaysExistFinalizer(ctx context.Context, gatewayClass *gatewayClassDecorator) (bool, error) {
+	gateways, err := r.listGatewaysForGatewayClass(ctx, gatewayClass.Name)
+	if err != nil {
+		return false, err
+	}
+
+	var updated bool
+	switch {
+	case len(gateways) > 0 && gatewayClass.DeletionTimestamp.IsZero():
+		updated = k8sutils.EnsureFinalizersInMetadata(&gatewayClass.ObjectMeta, gatewayv1alpha2.GatewayClassFinalizerGatewaysExist)
+	case len(gateways) == 0:
+		updated = k8sutils.RemoveFinalizerInMetadata(&gatewayClass.ObjectMeta, gatewayv1alpha2.GatewayClassFinalizerGatewaysExist)
+	}
+	if updated {
+		return true, r.Client.Update(ctx, gatewayClass.GatewayClass)
+	}
+
+	return false, nil
+}
+
+// getAcceptedCondition validates the parametersRef of the GatewayClass and
+// returns the Accepted condition which describes the result.
+func (r *GatewayClassReconciler) getAcceptedCondition(ctx context.Context, gatewayClass *gatewayClassDecorator) (metav1.Condition, error) {
+	newAcceptedCondition := func(status metav1.ConditionStatus, reason gatewayv1alpha2.GatewayClassConditionReason, message string) metav1.Condition {
+		condition := k8sutils.NewCondition(
+			k8sutils.ConditionType(gatewayv1alpha2.GatewayClassConditionStatusAccepted),
+			status,
+			k8sutils.ConditionReason(reason),
+			message,
+		)
+		condition.ObservedGeneration = gatewayClass.Generation
+		return condition
+	}
+
+	parametersRef := gatewayClass.Spec.ParametersRef
+	if parametersRef == nil {
+		return newAcceptedCondition(metav1.ConditionTrue, gatewayv1alpha2.GatewayClassReasonAccepted, ""), nil
+	}
+
+	if string(parametersRef.Group) != operatorv1alpha1.SchemeGroupVersion.Group ||
+		string(parametersRef.Kind) != "GatewayConfiguration" {
+		return newAcceptedCondition(metav1.ConditionFalse, gatewayv1alpha2.GatewayClassReasonInvalidParameters,
+			fmt.Sprintf("controller only supports %s %s resources for GatewayClass parametersRef",
+				operatorv1alpha1.SchemeGroupVersion.Group, "GatewayConfiguration")), nil
+	}
+
+	if parametersRef.Namespace == nil || *parametersRef.Namespace == "" || parametersRef.Name == "" {
+		return newAcceptedCondition(metav1.ConditionFalse, gatewayv1alpha2.GatewayClassReasonInvalidParameters,
+			"both namespace and name must be provided in parametersRef"), nil
+	}
+
+	gatewayConfig := new(operatorv1alpha1.GatewayConfiguration)
+	if err := r.Client.Get(ctx, client.ObjectKey{
+		Namespace: string(*parametersRef.Namespace),
+		Name:      parametersRef.Name,
+	}, gatewayConfig); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return newAcceptedCondition(metav1.ConditionFalse, gatewayv1alpha2.GatewayClassReasonInvalidParameters,
+				fmt.Sprintf("GatewayConfiguration %s/%s not found", *parametersRef.Namespace, parametersRef.Name)), nil
+		}
+		return metav1.Condition{}, err
+	}
+
+	return newAcceptedCondition(metav1.ConditionTrue, gatewayv1alpha2.GatewayClassReasonAccepted, ""), nil
+}
+
+// listGatewaysForGatewayClass returns the Gateways which use the GatewayClass
+// with the provided name.
+func (r *GatewayClassReconciler) listGatewaysForGatewayClass(ctx context.Context, gatewayClassName string) ([]gatewayv1alpha2.Gateway, error) {
+	gatewayList := new(gatewayv1alpha2.GatewayList)
+	if err := r.Client.List(ctx, gatewayList); err != nil {
+		return nil, err
+	}
+
+	gateways := make([]gatewayv1alpha2.Gateway, 0)
+	for _, gateway := range gatewayList.Items {
+		if string(gateway.Spec.GatewayClassName) == gatewayClassName {
+			gateways = append(gateways, gateway)
+		}
+	}
+
+	return gateways, nil
+}
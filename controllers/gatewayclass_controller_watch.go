@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	operatorerrors "github.com/kong/gateway-operator/internal/errors"
+	gatewayutils "github.com/kong/gateway-operator/internal/utils/gateway"
+	"github.com/kong/gateway-operator/pkg/vars"
+)
+
+// -----------------------------------------------------------------------------
+// GatewayClassReconciler - Watch Predicates
+// -----------------------------------------------------------------------------
+
+func (r *GatewayClassReconciler) gatewayClassMatchesController(obj client.Object) bool {
+	gatewayClass, ok := gatewayutils.AsV1alpha2GatewayClass(obj)
+	if !ok {
+		log.FromContext(context.Background()).Error(
+			operatorerrors.ErrUnexpectedObject,
+			"failed to run predicate function",
+			"expected", "GatewayClass", "found", reflect.TypeOf(obj),
+		)
+		return false
+	}
+
+	return string(gatewayClass.Spec.ControllerName) == vars.ControllerName
+}
+
+// -----------------------------------------------------------------------------
+// GatewayClassReconciler - Watch Map Funcs
+// -----------------------------------------------------------------------------
+
+func (r *GatewayClassReconciler) getGatewayClassForGateway(obj client.Object) (recs []reconcile.Request) {
+	gateway, ok := gatewayutils.AsV1alpha2Gateway(obj)
+	if !ok {
+		log.FromContext(context.Background()).Error(
+			operatorerrors.ErrUnexpectedObject,
+			"failed to run map funcs",
+			"expected", "Gateway", "found", reflect.TypeOf(obj),
+		)
+		return
+	}
+
+	if gateway.Spec.GatewayClassName == "" {
+		return
+	}
+
+	return []reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Name: string(gateway.Spec.GatewayClassName),
+			},
+		},
+	}
+}
+
+func (r *GatewayClassReconciler) listGatewayClassesForGatewayConfig(obj client.Object) (recs []reconcile.Request) {
+	ctx := context.Background()
+
+	gatewayConfig, ok := obj.(*operatorv1alpha1.GatewayConfiguration)
+	if !ok {
+		log.FromContext(ctx).Error(
+			operatorerrors.ErrUnexpectedObject,
+			"failed to run map funcs",
+			"expected", "GatewayConfiguration", "found", reflect.TypeOf(obj),
+		)
+		return
+	}
+
+	gatewayClassList := new(gatewayv1alpha2.GatewayClassList)
+	if err := r.Client.List(ctx, gatewayClassList); err != nil {
+		log.FromContext(ctx).Error(err, "could not list gatewayclasses in map func")
+		return
+	}
+
+	for _, gatewayClass := range gatewayClassList.Items {
+		parametersRef := gatewayClass.Spec.ParametersRef
+		if parametersRef != nil &&
+			string(parametersRef.Group) == operatorv1alpha1.SchemeGroupVersion.Group &&
+			string(parametersRef.Kind) == "GatewayConfiguration" &&
+			parametersRef.Namespace != nil &&
+			string(*parametersRef.Namespace) == gatewayConfig.Namespace &&
+			parametersRef.Name == gatewayConfig.Name {
+			recs = append(recs, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name: gatewayClass.Name,
+				},
+			})
+		}
+	}
+
+	return
+}
@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	gatewayutils "github.com/kong/gateway-operator/internal/utils/gateway"
+)
+
+// -----------------------------------------------------------------------------
+// GatewayConfigurationReconciler
+// -----------------------------------------------------------------------------
+
+// GatewayConfigurationReconciler reconciles the status of a GatewayConfiguration
+// object: it reports the GatewayClasses and Gateways using the configuration,
+// whether the DataPlanes and ControlPlanes of those Gateways have converged to
+// its current generation, and whether its spec is valid.
+type GatewayConfigurationReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// GatewayAPIV1beta1Enabled indicates whether the cluster serves the v1beta1
+	// version of Gateways and GatewayClasses, which have to be watched instead of
+	// the v1alpha2 ones.
+	GatewayAPIV1beta1Enabled bool
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GatewayConfigurationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		// watch GatewayConfiguration objects
+		For(&operatorv1alpha1.GatewayConfiguration{}).
+		// watch for updates to GatewayClasses, enqueue the GatewayConfigurations
+		// they use as their parameters.
+		Watches(
+			&source.Kind{Type: gatewayutils.GatewayClassForVersion(r.GatewayAPIV1beta1Enabled)},
+			handler.EnqueueRequestsFromMapFunc(r.getGatewayConfigForGatewayClass)).
+		// watch for updates to Gateways, enqueue the GatewayConfigurations used
+		// by their GatewayClass or attached to them.
+		Watches(
+			&source.Kind{Type: gatewayutils.GatewayForVersion(r.GatewayAPIV1beta1Enabled)},
+			handler.EnqueueRequestsFromMapFunc(r.listGatewayConfigsForGateway)).
+		// watch for updates to the dataplanes and controlplanes of the Gateways,
+		// enqueue the GatewayConfigurations they have been configured with.
+		Watches(
+			&source.Kind{Type: &operatorv1alpha1.DataPlane{}},
+			handler.EnqueueRequestsFromMapFunc(r.listGatewayConfigsForGatewayManagedObject)).
+		Watches(
+			&source.Kind{Type: &operatorv1alpha1.ControlPlane{}},
+			handler.EnqueueRequestsFromMapFunc(r.listGatewayConfigsForGatewayManagedObject)).
+		Complete(r)
+}
+
+// -----------------------------------------------------------------------------
+// GatewayConfigurationReconciler - Reconciliation
+// -----------------------------------------------------------------------------
+
+// Reconcile moves the current state of an object to the intended state.
+func (r *GatewayConfigurationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx).WithName("GatewayConfiguration")
+
+	debug(log, "reconciling GatewayConfiguration resource", req)
+	gatewayConfig := new(operatorv1alpha1.GatewayConfiguration)
+	if err := r.Client.Get(ctx, req.NamespacedName, gatewayConfig); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !gatewayConfig.DeletionTimestamp.IsZero() {
+		debug(log, "GatewayConfiguration is being deleted, nothing to do", gatewayConfig)
+		return ctrl.Result{}, nil
+	}
+
+	current := gatewayConfig.Status.DeepCopy()
+
+	debug(log, "validating GatewayConfiguration", gatewayConfig)
+	r.ensureValidCondition(ctx, gatewayConfig)
+
+	debug(log, "looking for the GatewayClasses and Gateways using the GatewayConfiguration", gatewayConfig)
+	if err := r.ensureGatewaysStatus(ctx, gatewayConfig); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	debug(log, "checking the convergence of the dataplanes and controlplanes", gatewayConfig)
+	if err := r.ensureDownstreamStatus(ctx, gatewayConfig); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if reflect.DeepEqual(current, &gatewayConfig.Status) {
+		debug(log, "GatewayConfiguration status is up to date", gatewayConfig)
+		return ctrl.Result{}, nil
+	}
+
+	debug(log, "updating GatewayConfiguration status", gatewayConfig)
+	if err := r.Client.Status().Update(ctx, gatewayConfig); err != nil {
+		if k8serrors.IsConflict(err) {
+			debug(log, "conflict found when updating GatewayConfiguration status, retrying", gatewayConfig)
+			return ctrl.Result{Requeue: true, RequeueAfter: requeueWithoutBackoff}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	debug(log, "reconciliation complete for GatewayConfiguration resource", gatewayConfig)
+	return ctrl.Result{}, nil
+}
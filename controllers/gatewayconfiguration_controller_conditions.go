@@ -0,0 +1,44 @@
+package controllers
+
+import k8sutils "github.com/kong/gateway-operator/internal/utils/kubernetes"
+
+// -----------------------------------------------------------------------------
+// GatewayConfiguration - Status Condition Types
+// -----------------------------------------------------------------------------
+
+const (
+	// GatewayConfigurationConditionTypeValid is a condition type indicating
+	// whether or not the spec of a GatewayConfiguration is valid.
+	GatewayConfigurationConditionTypeValid k8sutils.ConditionType = "Valid"
+
+	// GatewayConfigurationConditionTypeConverged is a condition type indicating
+	// whether or not all the DataPlanes and ControlPlanes of the Gateways using
+	// a GatewayConfiguration have converged to its current generation.
+	GatewayConfigurationConditionTypeConverged k8sutils.ConditionType = "Converged"
+)
+
+// -----------------------------------------------------------------------------
+// GatewayConfiguration - Status Condition Reasons
+// -----------------------------------------------------------------------------
+
+const (
+	// GatewayConfigurationConditionReasonValid is a reason which indicates the
+	// spec of a GatewayConfiguration is valid.
+	GatewayConfigurationConditionReasonValid k8sutils.ConditionReason = "Valid"
+
+	// GatewayConfigurationConditionReasonInvalid is a reason which indicates
+	// the spec of a GatewayConfiguration is invalid. The message of the
+	// condition holds the validation error.
+	GatewayConfigurationConditionReasonInvalid k8sutils.ConditionReason = "Invalid"
+
+	// GatewayConfigurationConditionReasonConverged is a reason which indicates
+	// all the DataPlanes and ControlPlanes of the Gateways using a
+	// GatewayConfiguration have converged to its current generation.
+	GatewayConfigurationConditionReasonConverged k8sutils.ConditionReason = "Converged"
+
+	// GatewayConfigurationConditionReasonConverging is a reason which indicates
+	// some DataPlanes or ControlPlanes of the Gateways using a
+	// GatewayConfiguration are not yet configured with its current generation,
+	// or not ready.
+	GatewayConfigurationConditionReasonConverging k8sutils.ConditionReason = "Converging"
+)
@@ -0,0 +1,233 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	"github.com/kong/gateway-operator/internal/consts"
+	gatewayutils "github.com/kong/gateway-operator/internal/utils/gateway"
+	k8sutils "github.com/kong/gateway-operator/internal/utils/kubernetes"
+	gatewayconfigvalidation "github.com/kong/gateway-operator/internal/validation/gatewayconfiguration"
+	"github.com/kong/gateway-operator/pkg/vars"
+)
+
+// -----------------------------------------------------------------------------
+// GatewayConfigurationReconciler - Reconciler Helpers
+// -----------------------------------------------------------------------------
+
+// ensureValidCondition sets the Valid condition of the GatewayConfiguration
+// to the result of the validation of its spec.
+func (r *GatewayConfigurationReconciler) ensureValidCondition(ctx context.Context, gatewayConfig *operatorv1alpha1.GatewayConfiguration) {
+	condition := k8sutils.NewCondition(GatewayConfigurationConditionTypeValid, metav1.ConditionTrue, GatewayConfigurationConditionReasonValid, "")
+	if err := gatewayconfigvalidation.NewValidator(r.Client).Validate(ctx, gatewayConfig); err != nil {
+		condition = k8sutils.NewCondition(GatewayConfigurationConditionTypeValid, metav1.ConditionFalse, GatewayConfigurationConditionReasonInvalid, err.Error())
+	}
+	setGatewayConfigurationCondition(gatewayConfig, condition)
+}
+
+// ensureGatewaysStatus records in the status of the GatewayConfiguration the
+// GatewayClasses of this operator using it as their parameters, the Gateways
+// of those GatewayClasses, and the Gateways using it through their targetRef
+// or their annotation.
+func (r *GatewayConfigurationReconciler) ensureGatewaysStatus(ctx context.Context, gatewayConfig *operatorv1alpha1.GatewayConfiguration) error {
+	gatewayClassList := new(gatewayv1alpha2.GatewayClassList)
+	if err := r.Client.List(ctx, gatewayClassList); err != nil {
+		return err
+	}
+
+	var gatewayClasses []string
+	matchingGatewayClasses := make(map[string]struct{})
+	for _, gatewayClass := range gatewayClassList.Items {
+		if string(gatewayClass.Spec.ControllerName) != vars.ControllerName {
+			continue
+		}
+		if key, ok := gatewayConfigKeyForGatewayClass(&gatewayClass); ok && key == client.ObjectKeyFromObject(gatewayConfig) {
+			gatewayClasses = append(gatewayClasses, gatewayClass.Name)
+			matchingGatewayClasses[gatewayClass.Name] = struct{}{}
+		}
+	}
+	sort.Strings(gatewayClasses)
+	gatewayConfig.Status.GatewayClasses = gatewayClasses
+
+	gatewayList := new(gatewayv1alpha2.GatewayList)
+	if err := r.Client.List(ctx, gatewayList); err != nil {
+		return err
+	}
+
+	var gateways []operatorv1alpha1.GatewayConfigurationObjectReference
+	for _, gateway := range gatewayList.Items {
+		gateway := gateway
+		used, err := r.gatewayUsesGatewayConfig(ctx, &gateway, gatewayConfig, matchingGatewayClasses)
+		if err != nil {
+			return err
+		}
+		if used {
+			gateways = append(gateways, operatorv1alpha1.GatewayConfigurationObjectReference{
+				Namespace: gateway.Namespace,
+				Name:      gateway.Name,
+			})
+		}
+	}
+	sortGatewayConfigurationObjectReferences(gateways)
+	gatewayConfig.Status.Gateways = gateways
+
+	return nil
+}
+
+// gatewayUsesGatewayConfig returns true if the Gateway uses the
+// GatewayConfiguration: through its GatewayClass, among the provided ones, or
+// through its annotation, or when it's the oldest GatewayConfiguration
+// attached to the Gateway through its targetRef.
+func (r *GatewayConfigurationReconciler) gatewayUsesGatewayConfig(
+	ctx context.Context,
+	gateway *gatewayv1alpha2.Gateway,
+	gatewayConfig *operatorv1alpha1.GatewayConfiguration,
+	gatewayClasses map[string]struct{},
+) (bool, error) {
+	if _, ok := gatewayClasses[string(gateway.Spec.GatewayClassName)]; ok {
+		return true, nil
+	}
+	if gateway.Namespace == gatewayConfig.Namespace && gateway.Annotations[consts.GatewayConfigurationAnnotation] == gatewayConfig.Name {
+		return true, nil
+	}
+	if !gatewayutils.GatewayConfigurationTargetsGateway(gatewayConfig, gateway) {
+		return false, nil
+	}
+
+	attached, err := gatewayutils.ListGatewayConfigurationsAttachedToGateway(ctx, r.Client, gateway)
+	if err != nil {
+		return false, err
+	}
+	return len(attached) > 0 && attached[0].Name == gatewayConfig.Name, nil
+}
+
+// ensureDownstreamStatus records in the status of the GatewayConfiguration the
+// DataPlanes and ControlPlanes configured with it by the gateway controller,
+// and whether they have converged to its current generation, then sets the
+// Converged condition accordingly.
+func (r *GatewayConfigurationReconciler) ensureDownstreamStatus(ctx context.Context, gatewayConfig *operatorv1alpha1.GatewayConfiguration) error {
+	dataplanes, err := gatewayutils.ListDataPlanesForGatewayConfiguration(ctx, r.Client, gatewayConfig)
+	if err != nil {
+		return err
+	}
+	controlplanes, err := gatewayutils.ListControlPlanesForGatewayConfiguration(ctx, r.Client, gatewayConfig)
+	if err != nil {
+		return err
+	}
+
+	var dataplanesStatus []operatorv1alpha1.GatewayConfigurationDownstreamStatus
+	for i := range dataplanes {
+		dataplanesStatus = append(dataplanesStatus, newGatewayConfigurationDownstreamStatus(gatewayConfig, &dataplanes[i]))
+	}
+	sortGatewayConfigurationDownstreamStatus(dataplanesStatus)
+	gatewayConfig.Status.DataPlanes = dataplanesStatus
+
+	var controlplanesStatus []operatorv1alpha1.GatewayConfigurationDownstreamStatus
+	for i := range controlplanes {
+		controlplanesStatus = append(controlplanesStatus, newGatewayConfigurationDownstreamStatus(gatewayConfig, &controlplanes[i]))
+	}
+	sortGatewayConfigurationDownstreamStatus(controlplanesStatus)
+	gatewayConfig.Status.ControlPlanes = controlplanesStatus
+
+	var converging []string
+	for _, status := range dataplanesStatus {
+		if !status.Converged {
+			converging = append(converging, fmt.Sprintf("DataPlane %s/%s", status.Namespace, status.Name))
+		}
+	}
+	for _, status := range controlplanesStatus {
+		if !status.Converged {
+			converging = append(converging, fmt.Sprintf("ControlPlane %s/%s", status.Namespace, status.Name))
+		}
+	}
+
+	condition := k8sutils.NewCondition(GatewayConfigurationConditionTypeConverged, metav1.ConditionTrue, GatewayConfigurationConditionReasonConverged, "")
+	if len(converging) > 0 {
+		condition = k8sutils.NewCondition(
+			GatewayConfigurationConditionTypeConverged, metav1.ConditionFalse, GatewayConfigurationConditionReasonConverging,
+			fmt.Sprintf("waiting for %s to converge", strings.Join(converging, ", ")),
+		)
+	}
+	setGatewayConfigurationCondition(gatewayConfig, condition)
+
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// GatewayConfigurationReconciler - Private Functions
+// -----------------------------------------------------------------------------
+
+// gatewayConfigKeyForGatewayClass returns the namespace/name pair of the
+// GatewayConfiguration the GatewayClass uses as its parameters, if any.
+func gatewayConfigKeyForGatewayClass(gatewayClass *gatewayv1alpha2.GatewayClass) (client.ObjectKey, bool) {
+	parametersRef := gatewayClass.Spec.ParametersRef
+	if parametersRef == nil ||
+		string(parametersRef.Group) != operatorv1alpha1.SchemeGroupVersion.Group ||
+		string(parametersRef.Kind) != "GatewayConfiguration" ||
+		parametersRef.Namespace == nil ||
+		*parametersRef.Namespace == "" ||
+		parametersRef.Name == "" {
+		return client.ObjectKey{}, false
+	}
+	return client.ObjectKey{Namespace: string(*parametersRef.Namespace), Name: parametersRef.Name}, true
+}
+
+// newGatewayConfigurationDownstreamStatus returns the status of a DataPlane or
+// a ControlPlane configured with the GatewayConfiguration: it has converged
+// once it has been configured with the current generation of the
+// GatewayConfiguration and is ready for its own current generation.
+func newGatewayConfigurationDownstreamStatus(
+	gatewayConfig *operatorv1alpha1.GatewayConfiguration,
+	obj interface {
+		client.Object
+		k8sutils.ConditionsAware
+	},
+) operatorv1alpha1.GatewayConfigurationDownstreamStatus {
+	generation := gatewayutils.GetGatewayConfigurationGenerations(obj)[client.ObjectKeyFromObject(gatewayConfig)]
+	return operatorv1alpha1.GatewayConfigurationDownstreamStatus{
+		Namespace:          obj.GetNamespace(),
+		Name:               obj.GetName(),
+		ObservedGeneration: generation,
+		Converged:          generation == gatewayConfig.Generation && k8sutils.IsReady(obj),
+	}
+}
+
+// setGatewayConfigurationCondition sets the condition on the GatewayConfiguration
+// unless an identical condition is already set, so that the transition time of
+// the condition is kept.
+func setGatewayConfigurationCondition(gatewayConfig *operatorv1alpha1.GatewayConfiguration, condition metav1.Condition) {
+	current, ok := k8sutils.GetCondition(k8sutils.ConditionType(condition.Type), gatewayConfig)
+	if ok &&
+		current.Status == condition.Status &&
+		current.Reason == condition.Reason &&
+		current.Message == condition.Message &&
+		current.ObservedGeneration == gatewayConfig.Generation {
+		return
+	}
+	k8sutils.SetCondition(condition, gatewayConfig)
+}
+
+func sortGatewayConfigurationObjectReferences(refs []operatorv1alpha1.GatewayConfigurationObjectReference) {
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Namespace != refs[j].Namespace {
+			return refs[i].Namespace < refs[j].Namespace
+		}
+		return refs[i].Name < refs[j].Name
+	})
+}
+
+func sortGatewayConfigurationDownstreamStatus(status []operatorv1alpha1.GatewayConfigurationDownstreamStatus) {
+	sort.Slice(status, func(i, j int) bool {
+		if status[i].Namespace != status[j].Namespace {
+			return status[i].Namespace < status[j].Namespace
+		}
+		return status[i].Name < status[j].Name
+	})
+}
@@ -0,0 +1,185 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	"github.com/kong/gateway-operator/internal/consts"
+	k8sutils "github.com/kong/gateway-operator/internal/utils/kubernetes"
+	"github.com/kong/gateway-operator/pkg/vars"
+)
+
+func TestGatewayConfigurationReconcilerStatus(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, operatorv1alpha1.AddToScheme(scheme))
+	require.NoError(t, gatewayv1alpha2.AddToScheme(scheme))
+
+	gatewayConfig := &operatorv1alpha1.GatewayConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kong-system", Name: "config", Generation: 2},
+		Spec: operatorv1alpha1.GatewayConfigurationSpec{
+			TargetRef: &operatorv1alpha1.GatewayConfigurationTargetReference{
+				Kind: operatorv1alpha1.GatewayConfigurationTargetKindGateway,
+				Name: "targeted",
+			},
+		},
+	}
+	newGatewayClass := func(name, controllerName, configName string) *gatewayv1alpha2.GatewayClass {
+		return &gatewayv1alpha2.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: gatewayv1alpha2.GatewayClassSpec{
+				ControllerName: gatewayv1alpha2.GatewayController(controllerName),
+				ParametersRef: &gatewayv1alpha2.ParametersReference{
+					Group:     gatewayv1alpha2.Group(operatorv1alpha1.SchemeGroupVersion.Group),
+					Kind:      "GatewayConfiguration",
+					Namespace: (*gatewayv1alpha2.Namespace)(pointer.String("kong-system")),
+					Name:      configName,
+				},
+			},
+		}
+	}
+	newGateway := func(namespace, name, gatewayClassName string) *gatewayv1alpha2.Gateway {
+		return &gatewayv1alpha2.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec:       gatewayv1alpha2.GatewaySpec{GatewayClassName: gatewayv1alpha2.ObjectName(gatewayClassName)},
+		}
+	}
+	newObjectMeta := func(namespace, name, generations string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Namespace:  namespace,
+			Name:       name,
+			Generation: 1,
+			Labels: map[string]string{
+				consts.GatewayOperatorControlledLabel: consts.GatewayManagedLabelValue,
+			},
+			Annotations: map[string]string{
+				consts.GatewayConfigurationGenerationsAnnotation: generations,
+			},
+		}
+	}
+	ready := []metav1.Condition{{Type: string(k8sutils.ReadyType), Status: metav1.ConditionTrue, ObservedGeneration: 1}}
+
+	c := fakeclient.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			gatewayConfig,
+			newGatewayClass("kong-b", vars.ControllerName, "config"),
+			newGatewayClass("kong-a", vars.ControllerName, "config"),
+			newGatewayClass("kong-other-config", vars.ControllerName, "other"),
+			newGatewayClass("other-controller", "example.com/other", "config"),
+			newGateway("ns-b", "gateway", "kong-a"),
+			newGateway("ns-a", "gateway", "kong-b"),
+			newGateway("ns-a", "other-config", "kong-other-config"),
+			newGateway("ns-a", "other-controller", "other-controller"),
+			newGateway("kong-system", "targeted", "kong-other-config"),
+			&gatewayv1alpha2.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "kong-system",
+					Name:        "annotated",
+					Annotations: map[string]string{consts.GatewayConfigurationAnnotation: "config"},
+				},
+				Spec: gatewayv1alpha2.GatewaySpec{GatewayClassName: "kong-other-config"},
+			},
+			&gatewayv1alpha2.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "ns-a",
+					Name:        "annotated",
+					Annotations: map[string]string{consts.GatewayConfigurationAnnotation: "config"},
+				},
+				Spec: gatewayv1alpha2.GatewaySpec{GatewayClassName: "kong-other-config"},
+			},
+			&operatorv1alpha1.DataPlane{
+				ObjectMeta: newObjectMeta("ns-a", "dataplane", "kong-system/config=2"),
+				Status:     operatorv1alpha1.DataPlaneStatus{Conditions: ready},
+			},
+			&operatorv1alpha1.DataPlane{
+				ObjectMeta: newObjectMeta("ns-b", "dataplane", "kong-system/config=1"),
+				Status:     operatorv1alpha1.DataPlaneStatus{Conditions: ready},
+			},
+			&operatorv1alpha1.DataPlane{
+				ObjectMeta: newObjectMeta("ns-a", "other-config", "kong-system/other=1"),
+				Status:     operatorv1alpha1.DataPlaneStatus{Conditions: ready},
+			},
+			&operatorv1alpha1.ControlPlane{
+				ObjectMeta: newObjectMeta("ns-a", "controlplane", "kong-system/config=2"),
+			},
+		).
+		Build()
+	r := &GatewayConfigurationReconciler{Client: c}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(gatewayConfig)}
+
+	t.Log("reporting the consumers of the gatewayconfiguration and their convergence")
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, req.NamespacedName, gatewayConfig))
+	require.Equal(t, []string{"kong-a", "kong-b"}, gatewayConfig.Status.GatewayClasses)
+	require.Equal(t, []operatorv1alpha1.GatewayConfigurationObjectReference{
+		{Namespace: "kong-system", Name: "annotated"},
+		{Namespace: "kong-system", Name: "targeted"},
+		{Namespace: "ns-a", Name: "gateway"},
+		{Namespace: "ns-b", Name: "gateway"},
+	}, gatewayConfig.Status.Gateways)
+	require.Equal(t, []operatorv1alpha1.GatewayConfigurationDownstreamStatus{
+		{Namespace: "ns-a", Name: "dataplane", ObservedGeneration: 2, Converged: true},
+		{Namespace: "ns-b", Name: "dataplane", ObservedGeneration: 1, Converged: false},
+	}, gatewayConfig.Status.DataPlanes)
+	require.Equal(t, []operatorv1alpha1.GatewayConfigurationDownstreamStatus{
+		{Namespace: "ns-a", Name: "controlplane", ObservedGeneration: 2, Converged: false},
+	}, gatewayConfig.Status.ControlPlanes)
+	require.True(t, k8sutils.IsValidCondition(GatewayConfigurationConditionTypeValid, gatewayConfig))
+	converged, ok := k8sutils.GetCondition(GatewayConfigurationConditionTypeConverged, gatewayConfig)
+	require.True(t, ok)
+	require.Equal(t, metav1.ConditionFalse, converged.Status)
+	require.Equal(t, string(GatewayConfigurationConditionReasonConverging), converged.Reason)
+	require.Equal(t, "waiting for DataPlane ns-b/dataplane, ControlPlane ns-a/controlplane to converge", converged.Message)
+	require.Equal(t, int64(2), converged.ObservedGeneration)
+
+	t.Log("keeping the status when nothing changed")
+	resourceVersion := gatewayConfig.ResourceVersion
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, req.NamespacedName, gatewayConfig))
+	require.Equal(t, resourceVersion, gatewayConfig.ResourceVersion)
+
+	t.Log("reporting the convergence once all the dataplanes and controlplanes are configured and ready")
+	dataplane := &operatorv1alpha1.DataPlane{}
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "ns-b", Name: "dataplane"}, dataplane))
+	dataplane.Annotations[consts.GatewayConfigurationGenerationsAnnotation] = "kong-system/config=2"
+	require.NoError(t, c.Update(ctx, dataplane))
+	controlplane := &operatorv1alpha1.ControlPlane{}
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "ns-a", Name: "controlplane"}, controlplane))
+	controlplane.Status.Conditions = ready
+	require.NoError(t, c.Status().Update(ctx, controlplane))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, req.NamespacedName, gatewayConfig))
+	require.True(t, k8sutils.IsValidCondition(GatewayConfigurationConditionTypeConverged, gatewayConfig))
+
+	t.Log("reporting the validation errors of the gatewayconfiguration")
+	gatewayConfig.Spec.ControlPlaneDeploymentOptions = &operatorv1alpha1.ControlPlaneDeploymentOptions{
+		DataPlane: pointer.String("dataplane"),
+	}
+	gatewayConfig.Generation = 3
+	require.NoError(t, c.Update(ctx, gatewayConfig))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, req.NamespacedName, gatewayConfig))
+	valid, ok := k8sutils.GetCondition(GatewayConfigurationConditionTypeValid, gatewayConfig)
+	require.True(t, ok)
+	require.Equal(t, metav1.ConditionFalse, valid.Status)
+	require.Equal(t, string(GatewayConfigurationConditionReasonInvalid), valid.Reason)
+	require.Contains(t, valid.Message, "dataplane can't be set")
+	require.False(t, k8sutils.IsValidCondition(GatewayConfigurationConditionTypeConverged, gatewayConfig))
+}
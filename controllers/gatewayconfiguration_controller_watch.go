@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	"github.com/kong/gateway-operator/internal/consts"
+	operatorerrors "github.com/kong/gateway-operator/internal/errors"
+	gatewayutils "github.com/kong/gateway-operator/internal/utils/gateway"
+)
+
+// -----------------------------------------------------------------------------
+// GatewayConfigurationReconciler - Watch Map Funcs
+// -----------------------------------------------------------------------------
+
+func (r *GatewayConfigurationReconciler) getGatewayConfigForGatewayClass(obj client.Object) (recs []reconcile.Request) {
+	gatewayClass, ok := gatewayutils.AsV1alpha2GatewayClass(obj)
+	if !ok {
+		log.FromContext(context.Background()).Error(
+			operatorerrors.ErrUnexpectedObject,
+			"failed to run map funcs",
+			"expected", "GatewayClass", "found", reflect.TypeOf(obj),
+		)
+		return
+	}
+
+	if key, ok := gatewayConfigKeyForGatewayClass(gatewayClass); ok {
+		recs = append(recs, reconcile.Request{NamespacedName: key})
+	}
+	return
+}
+
+func (r *GatewayConfigurationReconciler) listGatewayConfigsForGateway(obj client.Object) (recs []reconcile.Request) {
+	ctx := context.Background()
+
+	gateway, ok := gatewayutils.AsV1alpha2Gateway(obj)
+	if !ok {
+		log.FromContext(ctx).Error(
+			operatorerrors.ErrUnexpectedObject,
+			"failed to run map funcs",
+			"expected", "Gateway", "found", reflect.TypeOf(obj),
+		)
+		return
+	}
+
+	if name := gateway.Annotations[consts.GatewayConfigurationAnnotation]; name != "" {
+		recs = append(recs, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: gateway.Namespace,
+				Name:      name,
+			},
+		})
+	}
+
+	attached, err := gatewayutils.ListGatewayConfigurationsAttachedToGateway(ctx, r.Client, gateway)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "could not list gatewayconfigurations in map func")
+		return
+	}
+	for _, gatewayConfig := range attached {
+		recs = append(recs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&gatewayConfig)})
+	}
+
+	if gateway.Spec.GatewayClassName == "" {
+		return
+	}
+
+	gatewayClass := new(gatewayv1alpha2.GatewayClass)
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: string(gateway.Spec.GatewayClassName)}, gatewayClass); err != nil {
+		log.FromContext(ctx).Error(err, "could not get gatewayclass in map func")
+		return
+	}
+
+	if key, ok := gatewayConfigKeyForGatewayClass(gatewayClass); ok {
+		recs = append(recs, reconcile.Request{NamespacedName: key})
+	}
+	return
+}
+
+func (r *GatewayConfigurationReconciler) listGatewayConfigsForGatewayManagedObject(obj client.Object) (recs []reconcile.Request) {
+	for key := range gatewayutils.GetGatewayConfigurationGenerations(obj) {
+		recs = append(recs, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: key.Namespace,
+				Name:      key.Name,
+			},
+		})
+	}
+	return
+}
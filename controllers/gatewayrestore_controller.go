@@ -0,0 +1,202 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	operatorerrors "github.com/kong/gateway-operator/internal/errors"
+	"github.com/kong/gateway-operator/internal/utils/image"
+	k8sutils "github.com/kong/gateway-operator/internal/utils/kubernetes"
+)
+
+// -----------------------------------------------------------------------------
+// GatewayRestoreReconciler
+// -----------------------------------------------------------------------------
+
+// GatewayRestoreReconciler reconciles a GatewayRestore object
+type GatewayRestoreReconciler struct {
+	client.Client
+	Scheme                   *runtime.Scheme
+	eventRecorder            record.EventRecorder
+	ClusterCASecretName      string
+	ClusterCASecretNamespace string
+
+	// ImagePolicy is the operator-level policy of the container images: it
+	// rewrites the default images to a mirror registry and sets the
+	// imagePullSecrets of the generated resources.
+	ImagePolicy *image.Policy
+
+	// GatewayClassRestoreEnabled allows the GatewayRestores to create the
+	// cluster-scoped GatewayClasses of their snapshots missing from the
+	// cluster. The restore fails on a missing GatewayClass otherwise.
+	GatewayClassRestoreEnabled bool
+
+	// adminAPICertificate is the mTLS client certificate of the operator for
+	// the Kong Admin API of the DataPlanes.
+	adminAPICertificate adminAPIClientCertificate
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GatewayRestoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.eventRecorder = mgr.GetEventRecorderFor("gatewayrestore")
+
+	return ctrl.NewControllerManagedBy(mgr).
+		// watch GatewayRestore objects
+		For(&operatorv1alpha1.GatewayRestore{}).
+		// watch for changes in Jobs created by the gatewayrestore controller
+		Owns(&batchv1.Job{}).
+		// watch for changes in Secrets created by the gatewayrestore controller
+		Owns(&corev1.Secret{}).
+		Complete(r)
+}
+
+// -----------------------------------------------------------------------------
+// GatewayRestoreReconciler - Reconciliation
+// -----------------------------------------------------------------------------
+
+// Reconcile moves the current state of an object to the intended state.
+func (r *GatewayRestoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx).WithName("GatewayRestore")
+
+	debug(log, "reconciling GatewayRestore resource", req)
+	restore := new(operatorv1alpha1.GatewayRestore)
+	if err := r.Client.Get(ctx, req.NamespacedName, restore); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !restore.DeletionTimestamp.IsZero() {
+		debug(log, "GatewayRestore is being deleted, releasing the restored objects", restore)
+		if err := r.ensureRestoredObjectsReleased(ctx, restore); err != nil {
+			return ctrl.Result{}, err
+		}
+		if k8sutils.RemoveFinalizerInMetadata(&restore.ObjectMeta, string(GatewayRestoreFinalizerReleaseRestoredObjects)) {
+			return ctrl.Result{}, r.Client.Update(ctx, restore)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	condition, ok := k8sutils.GetCondition(GatewayRestoreConditionTypeCompleted, restore)
+	if ok && condition.Reason == string(GatewayRestoreConditionReasonFailed) {
+		// the objects restored before the failure are handed over to their
+		// controllers, which skip them as long as they're being restored.
+		debug(log, "GatewayRestore failed, releasing the restored objects", restore)
+		return ctrl.Result{}, r.ensureRestoredObjectsReleased(ctx, restore)
+	}
+	if ok && condition.Status == metav1.ConditionTrue {
+		debug(log, "GatewayRestore is over, nothing to do", restore)
+		return ctrl.Result{}, nil
+	}
+
+	// ensure the gatewayrestore has a finalizer to release the restored objects on delete.
+	if k8sutils.EnsureFinalizersInMetadata(&restore.ObjectMeta, string(GatewayRestoreFinalizerReleaseRestoredObjects)) {
+		info(log, "update metadata of GatewayRestore to set finalizer", restore)
+		return ctrl.Result{}, r.Client.Update(ctx, restore)
+	}
+
+	result, err := r.ensureGatewayRestored(ctx, log, restore, condition.Reason)
+	if err != nil && errors.Is(err, operatorerrors.ErrBackupFailed) {
+		info(log, "GatewayRestore failed: "+err.Error(), restore)
+		r.eventRecorder.Event(restore, "Warning", string(GatewayRestoreConditionReasonFailed), err.Error())
+		k8sutils.SetCondition(k8sutils.NewCondition(
+			GatewayRestoreConditionTypeCompleted, metav1.ConditionFalse, GatewayRestoreConditionReasonFailed, err.Error(),
+		), restore)
+		return ctrl.Result{}, r.Client.Status().Update(ctx, restore) // requeue will be triggered by the status update
+	}
+	return result, err
+}
+
+// ensureGatewayRestored runs the phase of the GatewayRestore the provided
+// reason of its Completed condition is at: the objects of the snapshot are
+// recreated first, then released to their controllers, and the Kong
+// configuration of the snapshot is loaded once the DataPlane is ready.
+func (r *GatewayRestoreReconciler) ensureGatewayRestored(
+	ctx context.Context,
+	log logr.Logger,
+	restore *operatorv1alpha1.GatewayRestore,
+	reason string,
+) (ctrl.Result, error) {
+	debug(log, "loading the snapshot", restore)
+	snapshot, err := r.loadSnapshot(ctx, restore)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if snapshot == nil {
+		debug(log, "waiting for the snapshot to be read from the volume", restore)
+		k8sutils.SetCondition(k8sutils.NewCondition(
+			GatewayRestoreConditionTypeCompleted, metav1.ConditionFalse, GatewayRestoreConditionReasonInProgress,
+			"reading the snapshot from the volume",
+		), restore)
+		return ctrl.Result{}, r.updateStatus(ctx, restore) // requeue will be triggered by the update of the owned Job
+	}
+
+	if reason != string(GatewayRestoreConditionReasonWaitingForDataPlane) {
+		debug(log, "restoring the objects of the snapshot", restore)
+		if err := r.ensureSnapshotRestored(ctx, restore, snapshot); err != nil {
+			return ctrl.Result{}, err
+		}
+		k8sutils.SetCondition(k8sutils.NewCondition(
+			GatewayRestoreConditionTypeCompleted, metav1.ConditionFalse, GatewayRestoreConditionReasonWaitingForDataPlane,
+			fmt.Sprintf("Gateway %s restored, waiting for its DataPlane to be ready", snapshot.Gateway.Name),
+		), restore)
+		return ctrl.Result{}, r.Client.Status().Update(ctx, restore) // requeue will be triggered by the status update
+	}
+
+	debug(log, "releasing the restored objects", restore)
+	if err := r.ensureRestoredObjectsReleased(ctx, restore); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	debug(log, "loading the Kong configuration of the snapshot", restore)
+	loaded, message, err := r.ensureKongConfigLoaded(ctx, restore, snapshot)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !loaded {
+		debug(log, "waiting for the DataPlane to be ready", restore)
+		return ctrl.Result{RequeueAfter: gatewayRestoreDataPlaneReadinessInterval}, nil
+	}
+
+	debug(log, "GatewayRestore completed", restore)
+	if message == "" {
+		message = fmt.Sprintf("Gateway %s restored", snapshot.Gateway.Name)
+	}
+	now := metav1.Now()
+	restore.Status.CompletionTime = &now
+	k8sutils.SetCondition(k8sutils.NewCondition(
+		GatewayRestoreConditionTypeCompleted, metav1.ConditionTrue, GatewayRestoreConditionReasonCompleted, message,
+	), restore)
+	return ctrl.Result{}, r.Client.Status().Update(ctx, restore)
+}
+
+// updateStatus updates the status of the GatewayRestore if its conditions
+// changed.
+func (r *GatewayRestoreReconciler) updateStatus(ctx context.Context, updated *operatorv1alpha1.GatewayRestore) error {
+	current := &operatorv1alpha1.GatewayRestore{}
+
+	err := r.Client.Get(ctx, client.ObjectKeyFromObject(updated), current)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	if k8sutils.NeedsUpdate(current, updated) {
+		return r.Client.Status().Update(ctx, updated)
+	}
+
+	return nil
+}
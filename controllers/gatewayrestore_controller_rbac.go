@@ -0,0 +1,24 @@
+package controllers
+
+// -----------------------------------------------------------------------------
+// GatewayRestoreReconciler - RBAC Permissions
+// -----------------------------------------------------------------------------
+
+//+kubebuilder:rbac:groups=gateway-operator.konghq.com,resources=gatewayrestores,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=gateway-operator.konghq.com,resources=gatewayrestores/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=gateway-operator.konghq.com,resources=gatewayrestores/finalizers,verbs=update
+//+kubebuilder:rbac:groups=gateway-operator.konghq.com,resources=gatewaybackups,verbs=get;list;watch
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways,verbs=create;get;list;watch;update;patch
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gatewayclasses,verbs=create;get;list;watch
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=referencegrants,verbs=get;list;watch
+//+kubebuilder:rbac:groups=gateway-operator.konghq.com,resources=gatewayconfigurations,verbs=create;get;list;watch;update;patch
+//+kubebuilder:rbac:groups=gateway-operator.konghq.com,resources=dataplanes,verbs=create;get;list;watch;update;patch
+//+kubebuilder:rbac:groups=gateway-operator.konghq.com,resources=controlplanes,verbs=create;get;list;watch;update;patch
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=create;get;list;watch;delete
+//+kubebuilder:rbac:groups=core,resources=services,verbs=create;get;list;watch
+//+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=create;get;list;watch;patch
+//+kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=create;get;list;watch
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=create;get;list;watch
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=create;get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
@@ -0,0 +1,532 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	"github.com/kong/gateway-operator/internal/consts"
+	operatorerrors "github.com/kong/gateway-operator/internal/errors"
+	gatewayutils "github.com/kong/gateway-operator/internal/utils/gateway"
+	k8sutils "github.com/kong/gateway-operator/internal/utils/kubernetes"
+)
+
+// -----------------------------------------------------------------------------
+// GatewayRestoreReconciler - Snapshot Loading
+// -----------------------------------------------------------------------------
+
+// loadSnapshot loads the snapshot of a GatewayRestore. The snapshots stored in
+// a volume are read by a Job: nil is returned until it succeeds, and the
+// snapshot is then staged in a Secret owned by the GatewayRestore.
+func (r *GatewayRestoreReconciler) loadSnapshot(
+	ctx context.Context,
+	restore *operatorv1alpha1.GatewayRestore,
+) (*gatewayutils.Snapshot, error) {
+	storage, filePath, err := r.getSnapshotStorage(ctx, restore)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	if storage.Secret != nil {
+		data, err = r.getSnapshotSecretData(ctx, restore.Namespace, storage.Secret.Name)
+	} else {
+		data, err = r.readSnapshotFromVolume(ctx, restore, storage.PersistentVolumeClaim.ClaimName, filePath)
+	}
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	snapshot, err := gatewayutils.DecodeSnapshot(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", operatorerrors.ErrBackupFailed, err)
+	}
+	return snapshot, nil
+}
+
+// getSnapshotStorage returns the storage of the snapshot of a GatewayRestore,
+// along with the path of its file when it's stored in a volume.
+func (r *GatewayRestoreReconciler) getSnapshotStorage(
+	ctx context.Context,
+	restore *operatorv1alpha1.GatewayRestore,
+) (*operatorv1alpha1.GatewayBackupStorage, string, error) {
+	source := restore.Spec.Source
+	if (source.BackupName == nil) == (source.Storage == nil) {
+		return nil, "", fmt.Errorf("%w: exactly one of the backupName and storage sources must be set", operatorerrors.ErrBackupFailed)
+	}
+
+	if source.Storage != nil {
+		if err := validateGatewayBackupStorage(source.Storage); err != nil {
+			return nil, "", err
+		}
+		if source.Storage.PersistentVolumeClaim == nil {
+			return source.Storage, "", nil
+		}
+		if source.Storage.PersistentVolumeClaim.Path == "" {
+			return nil, "", fmt.Errorf("%w: the path of the file of the persistentVolumeClaim storage must be set", operatorerrors.ErrBackupFailed)
+		}
+		return source.Storage, source.Storage.PersistentVolumeClaim.Path, nil
+	}
+
+	backup := &operatorv1alpha1.GatewayBackup{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: restore.Namespace, Name: *source.BackupName}, backup); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, "", fmt.Errorf("%w: GatewayBackup %s not found", operatorerrors.ErrBackupFailed, *source.BackupName)
+		}
+		return nil, "", err
+	}
+	if condition, ok := k8sutils.GetCondition(GatewayBackupConditionTypeCompleted, backup); !ok || condition.Status != metav1.ConditionTrue {
+		return nil, "", fmt.Errorf("%w: GatewayBackup %s isn't completed", operatorerrors.ErrBackupFailed, backup.Name)
+	}
+	if backup.Spec.Storage.PersistentVolumeClaim == nil {
+		return &backup.Spec.Storage, "", nil
+	}
+	return &backup.Spec.Storage, gatewayBackupVolumePath(backup), nil
+}
+
+// getSnapshotSecretData returns the snapshot held by the provided Secret.
+func (r *GatewayRestoreReconciler) getSnapshotSecretData(ctx context.Context, namespace, name string) ([]byte, error) {
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, secret); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: Secret %s not found", operatorerrors.ErrBackupFailed, name)
+		}
+		return nil, err
+	}
+	data, ok := secret.Data[consts.GatewayBackupSnapshotKey]
+	if !ok {
+		return nil, fmt.Errorf("%w: Secret %s has no %s key", operatorerrors.ErrBackupFailed, name, consts.GatewayBackupSnapshotKey)
+	}
+	return data, nil
+}
+
+// readSnapshotFromVolume reads the snapshot stored in the provided file of a
+// volume through a Job writing it into a Secret owned by the GatewayRestore,
+// staging it for the next reconciliations. nil is returned until the Job
+// wrote the snapshot.
+func (r *GatewayRestoreReconciler) readSnapshotFromVolume(
+	ctx context.Context,
+	restore *operatorv1alpha1.GatewayRestore,
+	claimName, filePath string,
+) ([]byte, error) {
+	name := gatewayRestoreSnapshotName(restore)
+	stagingSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: restore.Namespace, Name: name},
+		Type:       corev1.SecretTypeOpaque,
+	}
+	if err := r.ensureGatewayRestoreOwnedObject(ctx, restore, stagingSecret); err != nil {
+		return nil, err
+	}
+	if data, ok := stagingSecret.Data[consts.GatewayBackupSnapshotKey]; ok {
+		return data, nil
+	}
+
+	for _, obj := range []client.Object{
+		generateGatewayRestoreServiceAccount(restore, name),
+		generateGatewayRestoreRole(restore, name, stagingSecret.Name),
+		generateGatewayRestoreRoleBinding(restore, name),
+	} {
+		if err := r.ensureGatewayRestoreOwnedObject(ctx, restore, obj); err != nil {
+			return nil, err
+		}
+	}
+
+	jobs, err := k8sutils.ListJobsForOwner(
+		ctx,
+		r.Client,
+		consts.GatewayOperatorControlledLabel,
+		consts.GatewayRestoreManagedLabelValue,
+		restore.Namespace,
+		restore.UID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) > 1 {
+		return nil, fmt.Errorf("found %d Jobs for GatewayRestore currently unsupported: expected 1 or less", len(jobs))
+	}
+
+	if len(jobs) == 0 {
+		job := generateGatewayRestoreJob(restore, claimName, filePath, stagingSecret.Name, name, r.ImagePolicy)
+		k8sutils.SetOwnerForObject(job, restore)
+		addLabelForGatewayRestore(job)
+		return nil, r.Client.Create(ctx, job)
+	}
+
+	if job := &jobs[0]; jobHasFailed(job) {
+		return nil, fmt.Errorf("%w: Job %s reading the snapshot from the volume failed", operatorerrors.ErrBackupFailed, job.Name)
+	}
+	// the update of the staging Secret by the Job triggers the next
+	// reconciliation, even when it's observed after the success of the Job.
+	return nil, nil
+}
+
+// ensureGatewayRestoreOwnedObject gets the provided object, creating it owned
+// by the GatewayRestore if it doesn't exist. The restore fails if the object
+// exists but isn't owned by the GatewayRestore.
+func (r *GatewayRestoreReconciler) ensureGatewayRestoreOwnedObject(
+	ctx context.Context,
+	restore *operatorv1alpha1.GatewayRestore,
+	obj client.Object,
+) error {
+	gvk, err := apiutil.GVKForObject(obj, r.Client.Scheme())
+	if err != nil {
+		return err
+	}
+
+	err = r.Client.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+	if err == nil {
+		if !k8sutils.IsOwnedByRefUID(obj, restore.UID) {
+			return fmt.Errorf("%w: %s %s already exists", operatorerrors.ErrBackupFailed, gvk.Kind, obj.GetName())
+		}
+		return nil
+	}
+	if !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	k8sutils.SetOwnerForObject(obj, restore)
+	addLabelForGatewayRestore(obj)
+	return r.Client.Create(ctx, obj)
+}
+
+// -----------------------------------------------------------------------------
+// GatewayRestoreReconciler - Objects Restoration
+// -----------------------------------------------------------------------------
+
+// ensureSnapshotRestored recreates the objects of the snapshot of a
+// GatewayRestore. The Gateway, DataPlane and ControlPlane are annotated for
+// their controllers not to reconcile them until they're all recreated, like
+// the GatewayConfiguration to tell it was restored by the GatewayRestore, and
+// the objects already recreated by previous reconciliations are kept.
+func (r *GatewayRestoreReconciler) ensureSnapshotRestored(
+	ctx context.Context,
+	restore *operatorv1alpha1.GatewayRestore,
+	snapshot *gatewayutils.Snapshot,
+) error {
+	namespace := gatewayRestoreTargetNamespace(restore)
+
+	if err := r.ensureTargetNamespaceGranted(ctx, restore, namespace); err != nil {
+		return err
+	}
+	if err := r.ensureGatewayClassRestored(ctx, restore, namespace, snapshot); err != nil {
+		return err
+	}
+
+	gateway := snapshot.Gateway.DeepCopy()
+	gateway.Namespace = namespace
+	r.annotateRestoredObject(restore, gateway)
+	if err := r.ensureObjectRestored(ctx, gateway, r.restoredBy(restore)); err != nil {
+		return err
+	}
+
+	// the certificates are only restored when they are still valid: they are
+	// issued for the namespace of the objects, by the CA of the cluster.
+	// Otherwise, new certificates are issued by the DataPlane and ControlPlane
+	// controllers.
+	ca := &corev1.Secret{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: r.ClusterCASecretNamespace, Name: r.ClusterCASecretName}, ca); err != nil {
+		return err
+	}
+	restoreCertificate := func(certificate *corev1.Secret, owner client.Object) error {
+		if certificate == nil || namespace != snapshot.Gateway.Namespace || !certificateIsIssuedByCA(certificate, ca) {
+			return nil
+		}
+		certificate = certificate.DeepCopy()
+		certificate.Namespace = namespace
+		k8sutils.SetOwnerForObject(certificate, owner)
+		return r.ensureObjectRestored(ctx, certificate, r.ownedBy(owner))
+	}
+
+	if snapshot.DataPlane != nil {
+		dataplane := snapshot.DataPlane.DeepCopy()
+		dataplane.Namespace = namespace
+		r.annotateRestoredObject(restore, dataplane)
+		k8sutils.SetOwnerForObject(dataplane, gateway)
+		if err := r.ensureObjectRestored(ctx, dataplane, r.restoredBy(restore)); err != nil {
+			return err
+		}
+
+		for i := range snapshot.DataPlaneServices {
+			service := snapshot.DataPlaneServices[i].DeepCopy()
+			service.Namespace = namespace
+			// the addresses and ports allocated by the cluster are allocated
+			// again for the restored Service.
+			service.Spec.ClusterIP = ""
+			service.Spec.ClusterIPs = nil
+			service.Spec.HealthCheckNodePort = 0
+			for j := range service.Spec.Ports {
+				service.Spec.Ports[j].NodePort = 0
+			}
+			k8sutils.SetOwnerForObject(service, dataplane)
+			if err := r.ensureObjectRestored(ctx, service, r.ownedBy(dataplane)); err != nil {
+				return err
+			}
+		}
+
+		if err := restoreCertificate(snapshot.DataPlaneCertificate, dataplane); err != nil {
+			return err
+		}
+	}
+
+	if snapshot.ControlPlane != nil {
+		controlplane := snapshot.ControlPlane.DeepCopy()
+		controlplane.Namespace = namespace
+		r.annotateRestoredObject(restore, controlplane)
+		k8sutils.SetOwnerForObject(controlplane, gateway)
+		if err := r.ensureObjectRestored(ctx, controlplane, r.restoredBy(restore)); err != nil {
+			return err
+		}
+
+		if err := restoreCertificate(snapshot.ControlPlaneCertificate, controlplane); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ensureGatewayClassRestored recreates the GatewayClass of the snapshot, along
+// with the GatewayConfiguration it references, when the GatewayClass doesn't
+// exist. The GatewayConfiguration is recreated in the target namespace, where
+// the restore can't overwrite an existing one. The GatewayClasses being
+// cluster-scoped, their restore must be enabled on the operator.
+func (r *GatewayRestoreReconciler) ensureGatewayClassRestored(
+	ctx context.Context,
+	restore *operatorv1alpha1.GatewayRestore,
+	namespace string,
+	snapshot *gatewayutils.Snapshot,
+) error {
+	if snapshot.GatewayClass == nil {
+		return nil
+	}
+	err := r.Client.Get(ctx, client.ObjectKeyFromObject(snapshot.GatewayClass), &gatewayv1alpha2.GatewayClass{})
+	if err == nil || !k8serrors.IsNotFound(err) {
+		return err
+	}
+	if !r.GatewayClassRestoreEnabled {
+		return fmt.Errorf("%w: GatewayClass %s not found, and the restore of GatewayClasses is disabled",
+			operatorerrors.ErrBackupFailed, snapshot.GatewayClass.Name)
+	}
+
+	gatewayClass := snapshot.GatewayClass.DeepCopy()
+	if snapshot.GatewayConfiguration != nil {
+		gatewayConfig := snapshot.GatewayConfiguration.DeepCopy()
+		gatewayConfig.Namespace = namespace
+		r.annotateRestoredObject(restore, gatewayConfig)
+		if err := r.ensureObjectRestored(ctx, gatewayConfig, r.restoredBy(restore)); err != nil {
+			return err
+		}
+		ref := gatewayClass.Spec.ParametersRef
+		ns := gatewayv1alpha2.Namespace(namespace)
+		ref.Namespace = &ns
+	}
+	return r.Client.Create(ctx, gatewayClass)
+}
+
+// ensureTargetNamespaceGranted checks that the GatewayRestore is allowed to
+// restore its Gateway into the provided namespace: restoring into another
+// namespace than the one of the GatewayRestore requires a ReferenceGrant in
+// the target namespace from the GatewayRestores of its namespace to Gateways.
+func (r *GatewayRestoreReconciler) ensureTargetNamespaceGranted(
+	ctx context.Context,
+	restore *operatorv1alpha1.GatewayRestore,
+	namespace string,
+) error {
+	granted, err := gatewayutils.IsReferenceGranted(
+		ctx,
+		r.Client,
+		gatewayv1alpha2.ReferenceGrantFrom{
+			Group:     gatewayv1alpha2.Group(operatorv1alpha1.SchemeGroupVersion.Group),
+			Kind:      "GatewayRestore",
+			Namespace: gatewayv1alpha2.Namespace(restore.Namespace),
+		},
+		gatewayv1alpha2.ReferenceGrantTo{
+			Group: gatewayv1alpha2.GroupName,
+			Kind:  "Gateway",
+		},
+		namespace,
+	)
+	if err != nil {
+		return err
+	}
+	if !granted {
+		return fmt.Errorf("%w: no ReferenceGrant in namespace %s allows the GatewayRestores of namespace %s to restore Gateways",
+			operatorerrors.ErrBackupFailed, namespace, restore.Namespace)
+	}
+	return nil
+}
+
+// ensureObjectRestored creates the provided object, or reads it if it already
+// exists and has been restored by the GatewayRestore, as told by the provided
+// function. The restore can't overwrite the objects it didn't restore. The
+// object is left with its type information, for it to own other objects.
+func (r *GatewayRestoreReconciler) ensureObjectRestored(
+	ctx context.Context,
+	obj client.Object,
+	isRestored func(client.Object) bool,
+) error {
+	gvk, err := apiutil.GVKForObject(obj, r.Client.Scheme())
+	if err != nil {
+		return err
+	}
+	defer obj.GetObjectKind().SetGroupVersionKind(gvk)
+
+	existing, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return fmt.Errorf("unexpected object type %T", obj)
+	}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return r.Client.Create(ctx, obj)
+		}
+		return err
+	}
+
+	if !isRestored(existing) {
+		return fmt.Errorf("%w: %T %s already exists", operatorerrors.ErrBackupFailed, obj, obj.GetName())
+	}
+	return r.Client.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+}
+
+// annotateRestoredObject marks the object as being restored by the
+// GatewayRestore.
+func (r *GatewayRestoreReconciler) annotateRestoredObject(restore *operatorv1alpha1.GatewayRestore, obj client.Object) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[consts.GatewayRestoreInProgressAnnotation] = gatewayRestoreKey(restore)
+	obj.SetAnnotations(annotations)
+}
+
+// restoredBy returns a function telling whether an object is being restored
+// by the GatewayRestore.
+func (r *GatewayRestoreReconciler) restoredBy(restore *operatorv1alpha1.GatewayRestore) func(client.Object) bool {
+	return func(obj client.Object) bool {
+		return obj.GetAnnotations()[consts.GatewayRestoreInProgressAnnotation] == gatewayRestoreKey(restore)
+	}
+}
+
+// ownedBy returns a function telling whether an object is owned by the
+// provided owner.
+func (r *GatewayRestoreReconciler) ownedBy(owner client.Object) func(client.Object) bool {
+	return func(obj client.Object) bool {
+		return k8sutils.IsOwnedByRefUID(obj, owner.GetUID())
+	}
+}
+
+// -----------------------------------------------------------------------------
+// GatewayRestoreReconciler - Release
+// -----------------------------------------------------------------------------
+
+// ensureRestoredObjectsReleased removes the annotation of the objects restored
+// by the GatewayRestore, for their controllers to reconcile them. The objects
+// are looked up in the target namespace rather than in the snapshot, for the
+// objects restored before a failure to be released as well.
+func (r *GatewayRestoreReconciler) ensureRestoredObjectsReleased(
+	ctx context.Context,
+	restore *operatorv1alpha1.GatewayRestore,
+) error {
+	namespace := gatewayRestoreTargetNamespace(restore)
+
+	dataplanes := &operatorv1alpha1.DataPlaneList{}
+	if err := r.Client.List(ctx, dataplanes, client.InNamespace(namespace)); err != nil {
+		return err
+	}
+	controlplanes := &operatorv1alpha1.ControlPlaneList{}
+	if err := r.Client.List(ctx, controlplanes, client.InNamespace(namespace)); err != nil {
+		return err
+	}
+	gateways := &gatewayv1alpha2.GatewayList{}
+	if err := r.Client.List(ctx, gateways, client.InNamespace(namespace)); err != nil {
+		return err
+	}
+	gatewayConfigs := &operatorv1alpha1.GatewayConfigurationList{}
+	if err := r.Client.List(ctx, gatewayConfigs, client.InNamespace(namespace)); err != nil {
+		return err
+	}
+
+	var objs []client.Object
+	for i := range gatewayConfigs.Items {
+		objs = append(objs, &gatewayConfigs.Items[i])
+	}
+	for i := range dataplanes.Items {
+		objs = append(objs, &dataplanes.Items[i])
+	}
+	for i := range controlplanes.Items {
+		objs = append(objs, &controlplanes.Items[i])
+	}
+	// the Gateway is released last, for its controller to find the DataPlane
+	// and ControlPlane it owns ready to be reconciled.
+	for i := range gateways.Items {
+		objs = append(objs, &gateways.Items[i])
+	}
+
+	isRestored := r.restoredBy(restore)
+	for _, obj := range objs {
+		if !isRestored(obj) {
+			continue
+		}
+		annotations := obj.GetAnnotations()
+		delete(annotations, consts.GatewayRestoreInProgressAnnotation)
+		obj.SetAnnotations(annotations)
+		if err := r.Client.Update(ctx, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// GatewayRestoreReconciler - Kong Configuration
+// -----------------------------------------------------------------------------
+
+// ensureKongConfigLoaded loads the Kong configuration of the snapshot in the
+// ready Pods of the restored DataPlane. false is returned until the DataPlane
+// has ready Pods. The restore is completed even if the configuration can't be
+// loaded, as the ControlPlane configures the DataPlane anyway: the returned
+// message tells why it wasn't.
+func (r *GatewayRestoreReconciler) ensureKongConfigLoaded(
+	ctx context.Context,
+	restore *operatorv1alpha1.GatewayRestore,
+	snapshot *gatewayutils.Snapshot,
+) (loaded bool, message string, err error) {
+	if snapshot.DataPlane == nil || snapshot.KongConfig == "" {
+		return true, "", nil
+	}
+
+	dataplane := &operatorv1alpha1.DataPlane{}
+	key := client.ObjectKey{Namespace: gatewayRestoreTargetNamespace(restore), Name: snapshot.DataPlane.Name}
+	if err := r.Client.Get(ctx, key, dataplane); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, "", fmt.Errorf("%w: restored DataPlane %s not found", operatorerrors.ErrBackupFailed, key.Name)
+		}
+		return false, "", err
+	}
+
+	pods, err := getReadyDataPlanePods(ctx, r.Client, dataplane)
+	if err != nil || len(pods) == 0 {
+		return false, "", err
+	}
+
+	httpClient, err := r.adminAPICertificate.newClientForDataPlane(ctx, r.Client, r.ClusterCASecretNamespace, r.ClusterCASecretName, dataplane)
+	if err != nil {
+		return false, "", err
+	}
+	for _, pod := range pods {
+		if err := postDataPlanePodDeclarativeConfig(ctx, httpClient, pod, []byte(snapshot.KongConfig)); err != nil {
+			err = fmt.Errorf("failed to restore the Kong configuration of DataPlane %s: %w", dataplane.Name, err)
+			r.eventRecorder.Event(restore, "Warning", "KongConfigNotRestored", err.Error())
+			return true, err.Error(), nil
+		}
+	}
+	return true, "", nil
+}
@@ -1,14 +1,17 @@
 package controllers
 
 import (
+	"bytes"
 	"context"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
 	"fmt"
+	"net/http"
 	"reflect"
 	"sync"
 	"time"
@@ -18,6 +21,7 @@ import (
 	"github.com/cloudflare/cfssl/signer"
 	"github.com/cloudflare/cfssl/signer/local"
 	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
 	certificatesv1 "k8s.io/api/certificates/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -28,8 +32,10 @@ import (
 	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
 	"github.com/kong/gateway-operator/internal/consts"
 	"github.com/kong/gateway-operator/internal/manager/logging"
+	"github.com/kong/gateway-operator/internal/utils/image"
 	k8sutils "github.com/kong/gateway-operator/internal/utils/kubernetes"
 	k8sresources "github.com/kong/gateway-operator/internal/utils/kubernetes/resources"
+	"github.com/kong/gateway-operator/internal/versions"
 )
 
 // -----------------------------------------------------------------------------
@@ -38,6 +44,11 @@ import (
 
 const requeueWithoutBackoff = time.Millisecond * 200
 
+// automaticUpgradesInterval is the interval the versions of the objects using
+// AutomaticUpgrades are resolved again at, to pick up new versions of the
+// version catalog and open maintenance windows.
+const automaticUpgradesInterval = 10 * time.Minute
+
 // -----------------------------------------------------------------------------
 // Private Functions - Certificate management
 // -----------------------------------------------------------------------------
@@ -173,6 +184,39 @@ func maybeCreateCertificateSecret(ctx context.Context,
 		return false, existingSecret, nil
 	}
 
+	ca := &corev1.Secret{}
+	err = k8sClient.Get(ctx, client.ObjectKey{Namespace: mtlsCASecretNamespace, Name: mtlsCASecretName}, ca)
+	if err != nil {
+		return false, nil, err
+	}
+
+	cert, key, err := issueCertificate(owner.GetNamespace(), owner.GetName(), subject, usages, ca)
+	if err != nil {
+		return false, nil, err
+	}
+
+	generatedSecret.StringData = map[string]string{
+		"ca.crt":  string(ca.Data["tls.crt"]),
+		"tls.crt": string(cert),
+		"tls.key": string(key),
+	}
+
+	err = k8sClient.Create(ctx, generatedSecret)
+	if err != nil {
+		return false, nil, err
+	}
+
+	return true, generatedSecret, nil
+}
+
+// issueCertificate issues a PEM x.509 certificate for subject, signed by the CA
+// in the provided Secret, and returns it along with its PEM private key. The
+// namespace and name identify the signing request.
+func issueCertificate(
+	namespace, name, subject string,
+	usages []certificatesv1.KeyUsage,
+	ca *corev1.Secret,
+) ([]byte, []byte, error) {
 	template := x509.CertificateRequest{
 		Subject: pkix.Name{
 			CommonName:   subject,
@@ -185,12 +229,12 @@ func maybeCreateCertificateSecret(ctx context.Context,
 
 	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
 	if err != nil {
-		return false, nil, err
+		return nil, nil, err
 	}
 
 	der, err := x509.CreateCertificateRequest(rand.Reader, &template, priv)
 	if err != nil {
-		return false, nil, err
+		return nil, nil, err
 	}
 
 	// This is effectively a placeholder so long as we handle signing internally. When actually creating CSR resources,
@@ -205,8 +249,8 @@ func maybeCreateCertificateSecret(ctx context.Context,
 
 	csr := certificatesv1.CertificateSigningRequest{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: owner.GetNamespace(),
-			Name:      owner.GetName(),
+			Namespace: namespace,
+			Name:      name,
 		},
 		Spec: certificatesv1.CertificateSigningRequestSpec{
 			Request: pem.EncodeToMemory(&pem.Block{
@@ -219,36 +263,110 @@ func maybeCreateCertificateSecret(ctx context.Context,
 		},
 	}
 
-	ca := &corev1.Secret{}
-	err = k8sClient.Get(ctx, client.ObjectKey{Namespace: mtlsCASecretNamespace, Name: mtlsCASecretName}, ca)
-	if err != nil {
-		return false, nil, err
-	}
-
 	signed, err := signCertificate(csr, ca)
 	if err != nil {
-		return false, nil, err
+		return nil, nil, err
 	}
 	privDer, err := x509.MarshalECPrivateKey(priv)
 	if err != nil {
-		return false, nil, err
+		return nil, nil, err
 	}
 
-	generatedSecret.StringData = map[string]string{
-		"ca.crt":  string(ca.Data["tls.crt"]),
-		"tls.crt": string(signed),
-		"tls.key": string(pem.EncodeToMemory(&pem.Block{
-			Type:  "EC PRIVATE KEY",
-			Bytes: privDer,
-		})),
+	return signed, pem.EncodeToMemory(&pem.Block{
+		Type:  "EC PRIVATE KEY",
+		Bytes: privDer,
+	}), nil
+}
+
+// certificateExpiry returns the time the certificate of the provided TLS
+// Secret expires at. The current time reported in a status is returned when
+// it's the same, as the times read from the API server aren't deeply equal
+// to the ones parsed from the certificate.
+func certificateExpiry(secret *corev1.Secret, current *metav1.Time) (*metav1.Time, error) {
+	block, _ := pem.Decode(secret.Data["tls.crt"])
+	if block == nil {
+		return nil, fmt.Errorf("no certificate found in Secret %s/%s", secret.Namespace, secret.Name)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate in Secret %s/%s: %w", secret.Namespace, secret.Name, err)
+	}
+	if current != nil && current.Time.Equal(cert.NotAfter) {
+		return current, nil
 	}
+	expiry := metav1.NewTime(cert.NotAfter)
+	return &expiry, nil
+}
 
-	err = k8sClient.Create(ctx, generatedSecret)
+// adminAPIClientCertificate is the mTLS client certificate of the operator for
+// the Kong Admin API of the DataPlanes, issued by the cluster CA on first use.
+// It's issued again when the cluster CA changes.
+type adminAPIClientCertificate struct {
+	lock        sync.Mutex
+	certificate *tls.Certificate
+	ca          []byte
+}
+
+// get returns the certificate, issuing it if it wasn't yet by the provided CA.
+func (c *adminAPIClientCertificate) get(ca *corev1.Secret) (*tls.Certificate, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.certificate != nil && bytes.Equal(c.ca, ca.Data["tls.crt"]) {
+		return c.certificate, nil
+	}
+
+	usages := []certificatesv1.KeyUsage{
+		certificatesv1.UsageKeyEncipherment,
+		certificatesv1.UsageDigitalSignature, certificatesv1.UsageClientAuth,
+	}
+	cert, key, err := issueCertificate(ca.Namespace, adminAPIClientCertificateSubject, adminAPIClientCertificateSubject, usages, ca)
 	if err != nil {
-		return false, nil, err
+		return nil, err
+	}
+	certificate, err := tls.X509KeyPair(cert, key)
+	if err != nil {
+		return nil, err
 	}
 
-	return true, generatedSecret, nil
+	c.certificate = &certificate
+	c.ca = ca.Data["tls.crt"]
+	return c.certificate, nil
+}
+
+// newClientForDataPlane returns an HTTP client for the Kong Admin API of the
+// DataPlane Pods, authenticated by the certificate. The Pods serve the
+// certificate issued for the DataPlane Service by the cluster CA.
+func (c *adminAPIClientCertificate) newClientForDataPlane(
+	ctx context.Context,
+	k8sClient client.Client,
+	caSecretNamespace, caSecretName string,
+	dataplane *operatorv1alpha1.DataPlane,
+) (*http.Client, error) {
+	ca := &corev1.Secret{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: caSecretNamespace, Name: caSecretName}, ca); err != nil {
+		return nil, err
+	}
+	certificate, err := c.get(ca)
+	if err != nil {
+		return nil, err
+	}
+	rootCAs := x509.NewCertPool()
+	if !rootCAs.AppendCertsFromPEM(ca.Data["tls.crt"]) {
+		return nil, fmt.Errorf("failed to load the cluster CA certificate")
+	}
+
+	return &http.Client{
+		Timeout: dataplaneAdminAPITimeout,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				Certificates: []tls.Certificate{*certificate},
+				RootCAs:      rootCAs,
+				ServerName:   fmt.Sprintf("%s.%s.svc", dataplane.Status.Service, dataplane.Namespace),
+				MinVersion:   tls.VersionTLS12,
+			},
+		},
+	}, nil
 }
 
 // -----------------------------------------------------------------------------
@@ -292,6 +410,14 @@ func deploymentOptionsDeepEqual(opts1, opts2 *operatorv1alpha1.DeploymentOptions
 		return false
 	}
 
+	if !reflect.DeepEqual(opts1.SemanticVersion, opts2.SemanticVersion) {
+		return false
+	}
+
+	if !reflect.DeepEqual(opts1.AutomaticUpgrades, opts2.AutomaticUpgrades) {
+		return false
+	}
+
 	if !reflect.DeepEqual(opts1.Env, opts2.Env) {
 		return false
 	}
@@ -303,6 +429,108 @@ func deploymentOptionsDeepEqual(opts1, opts2 *operatorv1alpha1.DeploymentOptions
 	return true
 }
 
+// -----------------------------------------------------------------------------
+// DeploymentOptions - Private Functions - Container Images
+// -----------------------------------------------------------------------------
+
+// containerImageForDeploymentOptions returns the container image to use for the
+// Deployment configured by the given options. The resolved version is the
+// version chosen by AutomaticUpgrades, if any. The version replaces the tag of
+// the image, while its digest is preserved.
+func containerImageForDeploymentOptions(opts *operatorv1alpha1.DeploymentOptions, defaultBaseImage, defaultImage, resolvedVersion string) string {
+	version := opts.Version
+	if opts.AutomaticUpgrades != nil && resolvedVersion != "" {
+		version = &resolvedVersion
+	}
+
+	baseImage := defaultBaseImage
+	if opts.ContainerImage != nil {
+		baseImage = *opts.ContainerImage
+	} else if version == nil {
+		return defaultImage // TODO: https://github.com/Kong/gateway-operator/issues/20
+	}
+	if version == nil {
+		return baseImage
+	}
+
+	ref, err := image.ParseReference(baseImage)
+	if err != nil {
+		// invalid references are rejected by the validation, keep the image
+		// as it is configured for the Deployment to report the error.
+		return fmt.Sprintf("%s:%s", baseImage, *version)
+	}
+	return ref.WithTag(*version).String()
+}
+
+// -----------------------------------------------------------------------------
+// DeploymentOptions - Private Functions - Status
+// -----------------------------------------------------------------------------
+
+// newDeploymentStatus returns the status of the provided Deployment, reporting
+// the image of its container with the provided name, and the provided version
+// of this image.
+func newDeploymentStatus(deployment *appsv1.Deployment, containerName, version string) *operatorv1alpha1.DeploymentStatus {
+	status := &operatorv1alpha1.DeploymentStatus{
+		Name:          deployment.Name,
+		Replicas:      deployment.Status.Replicas,
+		ReadyReplicas: deployment.Status.ReadyReplicas,
+		Version:       version,
+	}
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if container.Name == containerName {
+			status.Image = container.Image
+		}
+	}
+	return status
+}
+
+// -----------------------------------------------------------------------------
+// DeploymentOptions - Private Functions - Automatic Upgrades
+// -----------------------------------------------------------------------------
+
+// resolveAutomaticUpgradeVersion returns the version of the product the
+// Deployment configured by the given options should use according to its
+// AutomaticUpgrades. The first version is resolved from the default version as
+// soon as AutomaticUpgrades are enabled, later upgrades only happen during the
+// maintenance windows.
+func resolveAutomaticUpgradeVersion(
+	opts *operatorv1alpha1.DeploymentOptions,
+	product versions.Product,
+	currentVersion, defaultVersion string,
+	now time.Time,
+) (string, error) {
+	upgrades := opts.AutomaticUpgrades
+	if upgrades == nil {
+		return "", nil
+	}
+	if opts.Version != nil {
+		return "", fmt.Errorf("version can't be set when automaticUpgrades is in use")
+	}
+
+	if currentVersion == "" {
+		return product.ResolveVersion(defaultVersion, upgrades.Policy, upgrades.Channel)
+	}
+
+	inWindow, err := versions.InMaintenanceWindow(upgrades.MaintenanceWindows, now)
+	if err != nil || !inWindow {
+		return currentVersion, err
+	}
+	return product.ResolveVersion(currentVersion, upgrades.Policy, upgrades.Channel)
+}
+
+// rolloutIsInProgress returns whether a rollout in the provided phase has yet
+// to complete. Automatic upgrades wait for it, so that the version they choose
+// is rolled out following the strategy instead of replacing the current one.
+func rolloutIsInProgress(phase operatorv1alpha1.DataPlaneRolloutPhase) bool {
+	switch phase {
+	case operatorv1alpha1.DataPlaneRolloutPhaseProvisioningPreview,
+		operatorv1alpha1.DataPlaneRolloutPhaseAwaitingPromotion,
+		operatorv1alpha1.DataPlaneRolloutPhaseProgressing:
+		return true
+	}
+	return false
+}
+
 // -----------------------------------------------------------------------------
 // Owner based metadata getters - Private Functions
 // -----------------------------------------------------------------------------
@@ -84,39 +84,26 @@ package resources
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/Masterminds/semver"
 	rbacv1 "k8s.io/api/rbac/v1"
 
-	"github.com/kong/gateway-operator/internal/consts"
 	"github.com/kong/gateway-operator/internal/utils/kubernetes/resources/clusterroles"
-	"github.com/kong/gateway-operator/internal/versions"
 )
 
 // -----------------------------------------------------------------------------
 // ClusterRole generator helper
 // -----------------------------------------------------------------------------
 
-// GenerateNewClusterRoleForControlPlane is a helper function that extract
-// the version from the tag, and returns the ClusterRole with all the needed
-// permissions.
-func GenerateNewClusterRoleForControlPlane(controlplaneName string, image *string) (*rbacv1.ClusterRole, error) {
-	version := consts.DefaultControlPlaneTag
+// GenerateNewClusterRoleForControlPlane is a helper function that returns the
+// ClusterRole with all the needed permissions for the given semantic version
+// of the controlplane.
+func GenerateNewClusterRoleForControlPlane(controlplaneName string, version string) (*rbacv1.ClusterRole, error) {
 	var constraint *semver.Constraints
 
-	if image != nil && *image != "" {
-		parts := strings.Split(*image, ":")
-		if len(parts) != 2 || parts[1] == "latest" {
-			version = versions.Latest
-		} else if len(parts) == 2 {
-			version = parts[1]
-		}
-	}
-
 	semVersion, err := semver.NewVersion(version)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("invalid controlplane version %q: %w", version, err)
 	}
 
 	{{ range $constraint, $suffix := .Versions}}
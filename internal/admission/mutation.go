@@ -0,0 +1,135 @@
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	"gomodules.xyz/jsonpatch/v2"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+)
+
+// Mutator is the interface of mutating: it sets the defaults of the objects
+// before they are stored.
+type Mutator interface {
+	MutateControlPlane(context.Context, *operatorv1alpha1.ControlPlane) error
+	// MutateDataPlane mutates a DataPlane, the old DataPlane being nil unless
+	// the DataPlane is updated.
+	MutateDataPlane(ctx context.Context, old, dataPlane *operatorv1alpha1.DataPlane) error
+}
+
+// MutatingRequestHandler handles the requests of mutating objects.
+type MutatingRequestHandler struct {
+	// Mutator mutates the entities that the k8s API-server asks
+	// the server to mutate.
+	Mutator Mutator
+	Logger  logr.Logger
+}
+
+// NewMutatingRequestHandler creates a MutatingRequestHandler to handle mutation
+// requests.
+func NewMutatingRequestHandler(l logr.Logger) *MutatingRequestHandler {
+	return &MutatingRequestHandler{
+		Mutator: &mutator{},
+		Logger:  l.WithValues("component", "mutation-server"),
+	}
+}
+
+// ServeHTTP serves for HTTP requests.
+func (h *MutatingRequestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	serveAdmissionReview(w, r, h.Logger, h.handleMutation)
+}
+
+func (h *MutatingRequestHandler) handleMutation(ctx context.Context, req *admissionv1.AdmissionRequest) (
+	*admissionv1.AdmissionResponse, error) {
+
+	if req == nil {
+		return &admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result: &metav1.Status{
+				Code:    http.StatusBadRequest,
+				Reason:  metav1.StatusReasonBadRequest,
+				Message: "empty request",
+				Status:  metav1.StatusFailure,
+			},
+		}, nil
+	}
+
+	response := &admissionv1.AdmissionResponse{
+		UID:     req.UID,
+		Allowed: true,
+		Result: &metav1.Status{
+			Code:   http.StatusOK,
+			Status: metav1.StatusSuccess,
+		},
+	}
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return response, nil
+	}
+
+	var (
+		mutated      interface{}
+		err          error
+		deserializer = codecs.UniversalDeserializer()
+	)
+	switch req.Resource {
+	case controlPlaneGVResource:
+		controlPlane := &operatorv1alpha1.ControlPlane{}
+		if _, _, err := deserializer.Decode(req.Object.Raw, nil, controlPlane); err != nil {
+			return nil, err
+		}
+		err = h.Mutator.MutateControlPlane(ctx, controlPlane)
+		mutated = controlPlane
+	case dataPlaneGVResource:
+		dataPlane := &operatorv1alpha1.DataPlane{}
+		if _, _, err := deserializer.Decode(req.Object.Raw, nil, dataPlane); err != nil {
+			return nil, err
+		}
+		var oldDataPlane *operatorv1alpha1.DataPlane
+		if req.Operation == admissionv1.Update {
+			oldDataPlane = &operatorv1alpha1.DataPlane{}
+			if _, _, err := deserializer.Decode(req.OldObject.Raw, nil, oldDataPlane); err != nil {
+				return nil, err
+			}
+		}
+		err = h.Mutator.MutateDataPlane(ctx, oldDataPlane, dataPlane)
+		mutated = dataPlane
+	default:
+		return response, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	patch, err := createPatch(req.Object.Raw, mutated)
+	if err != nil {
+		return nil, err
+	}
+	if len(patch) > 0 {
+		patchType := admissionv1.PatchTypeJSONPatch
+		response.Patch = patch
+		response.PatchType = &patchType
+	}
+	return response, nil
+}
+
+// createPatch returns the JSON patch turning the original object into the
+// mutated one, or nil when the object is left unchanged.
+func createPatch(original []byte, mutated interface{}) ([]byte, error) {
+	current, err := json.Marshal(mutated)
+	if err != nil {
+		return nil, err
+	}
+	operations, err := jsonpatch.CreatePatch(original, current)
+	if err != nil {
+		return nil, err
+	}
+	if len(operations) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(operations)
+}
@@ -0,0 +1,193 @@
+package admission
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	"gomodules.xyz/jsonpatch/v2"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	"github.com/kong/gateway-operator/internal/consts"
+	k8sutils "github.com/kong/gateway-operator/internal/utils/kubernetes"
+	dataplanevalidation "github.com/kong/gateway-operator/internal/validation/dataplane"
+)
+
+func TestHandleMutation(t *testing.T) {
+	handler := NewMutatingRequestHandler(logr.Discard())
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	typeMeta := func(kind string) metav1.TypeMeta {
+		return metav1.TypeMeta{APIVersion: operatorv1alpha1.SchemeGroupVersion.String(), Kind: kind}
+	}
+
+	testCases := []struct {
+		name        string
+		resource    metav1.GroupVersionResource
+		object      runtime.Object
+		expectedEnv []string
+	}{
+		{
+			name:     "dataplane_without_env_is_defaulted",
+			resource: dataPlaneGVResource,
+			object: &operatorv1alpha1.DataPlane{
+				TypeMeta:   typeMeta("DataPlane"),
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-dataplane"},
+			},
+			expectedEnv: []string{consts.EnvVarKongDatabase, "KONG_ADMIN_LISTEN", "KONG_PROXY_LISTEN"},
+		},
+		{
+			name:     "dataplane_with_env_from_is_not_defaulted",
+			resource: dataPlaneGVResource,
+			object: &operatorv1alpha1.DataPlane{
+				TypeMeta:   typeMeta("DataPlane"),
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-dataplane"},
+				Spec: operatorv1alpha1.DataPlaneSpec{
+					DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+						DeploymentOptions: operatorv1alpha1.DeploymentOptions{
+							EnvFrom: []corev1.EnvFromSource{
+								{ConfigMapRef: &corev1.ConfigMapEnvSource{
+									LocalObjectReference: corev1.LocalObjectReference{Name: "kong-config"},
+								}},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "controlplane_is_defaulted",
+			resource: controlPlaneGVResource,
+			object: &operatorv1alpha1.ControlPlane{
+				TypeMeta:   typeMeta("ControlPlane"),
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-controlplane"},
+				Spec: operatorv1alpha1.ControlPlaneSpec{
+					ControlPlaneDeploymentOptions: operatorv1alpha1.ControlPlaneDeploymentOptions{
+						DeploymentOptions: operatorv1alpha1.DeploymentOptions{
+							Env: []corev1.EnvVar{{Name: "CONTROLLER_LOG_LEVEL", Value: "debug"}},
+						},
+					},
+				},
+			},
+			expectedEnv: []string{"POD_NAMESPACE", "POD_NAME", "CONTROLLER_KONG_ADMIN_CA_CERT_FILE"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			review := &admissionv1.AdmissionReview{
+				Request: &admissionv1.AdmissionRequest{
+					UID:       "test-uid",
+					Resource:  tc.resource,
+					Operation: admissionv1.Create,
+					Object: runtime.RawExtension{
+						Object: tc.object,
+					},
+				},
+			}
+
+			buf, err := json.Marshal(review)
+			require.NoError(t, err)
+			resp, err := http.Post(server.URL, "application/json", bytes.NewReader(buf))
+			require.NoError(t, err)
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			resp.Body.Close()
+			respReview := &admissionv1.AdmissionReview{}
+			require.NoError(t, json.Unmarshal(body, respReview))
+			mutationResp := respReview.Response
+			require.True(t, mutationResp.Allowed)
+			require.EqualValues(t, "test-uid", mutationResp.UID)
+
+			envPatched := false
+			if len(mutationResp.Patch) > 0 {
+				require.Equal(t, admissionv1.PatchTypeJSONPatch, *mutationResp.PatchType)
+				var operations []jsonpatch.Operation
+				require.NoError(t, json.Unmarshal(mutationResp.Patch, &operations))
+				var env []corev1.EnvVar
+				for _, operation := range operations {
+					if !strings.HasPrefix(operation.Path, "/spec/env") {
+						continue
+					}
+					envPatched = true
+					value, err := json.Marshal(operation.Value)
+					require.NoError(t, err)
+					// either the whole env is added, or env vars are added one by one.
+					if operation.Path == "/spec/env" {
+						require.NoError(t, json.Unmarshal(value, &env))
+					} else {
+						var envVar corev1.EnvVar
+						require.NoError(t, json.Unmarshal(value, &envVar))
+						env = append(env, envVar)
+					}
+				}
+				for _, name := range tc.expectedEnv {
+					require.Contains(t, envNames(env), name)
+				}
+			}
+			require.Equal(t, len(tc.expectedEnv) > 0, envPatched, "the env should only be patched when defaulted")
+		})
+	}
+}
+
+func TestMutateDataPlaneDatabase(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, operatorv1alpha1.AddToScheme(scheme))
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-postgres"},
+			Data:       map[string][]byte{"host": []byte("postgres.default.svc")},
+		},
+	).Build()
+
+	t.Log("creating a DB-less dataplane stored with the default Kong configuration")
+	old := &operatorv1alpha1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-dataplane"},
+	}
+	m := &mutator{}
+	require.NoError(t, m.MutateDataPlane(context.Background(), nil, old))
+	require.Equal(t, "off", k8sutils.EnvValueByName(old.Spec.Env, consts.EnvVarKongDatabase))
+
+	t.Log("updating the dataplane to use a database")
+	dataplane := old.DeepCopy()
+	dataplane.Spec.Database = &operatorv1alpha1.DataPlaneDatabase{
+		Postgres: operatorv1alpha1.PostgresDatabase{
+			ConnectionSecretRef: corev1.LocalObjectReference{Name: "test-postgres"},
+		},
+	}
+	require.NoError(t, m.MutateDataPlane(context.Background(), old, dataplane))
+	require.False(t, k8sutils.IsEnvVarPresent(corev1.EnvVar{Name: consts.EnvVarKongDatabase}, dataplane.Spec.Env),
+		"the DB-less default should be dropped once the database is set")
+	require.NoError(t, dataplanevalidation.NewValidator(c).Validate(context.Background(), dataplane))
+
+	t.Log("keeping the DB-less default of the dataplanes already using a database")
+	updated := dataplane.DeepCopy()
+	updated.Spec.Env = append(updated.Spec.Env, corev1.EnvVar{Name: consts.EnvVarKongDatabase, Value: "off"})
+	require.NoError(t, m.MutateDataPlane(context.Background(), dataplane, updated))
+	require.Equal(t, "off", k8sutils.EnvValueByName(updated.Spec.Env, consts.EnvVarKongDatabase))
+	require.Error(t, dataplanevalidation.NewValidator(c).Validate(context.Background(), updated))
+}
+
+func envNames(env []corev1.EnvVar) []string {
+	names := make([]string, 0, len(env))
+	for _, envVar := range env {
+		names = append(names, envVar.Name)
+	}
+	return names
+}
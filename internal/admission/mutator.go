@@ -0,0 +1,39 @@
+package admission
+
+import (
+	"context"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	"github.com/kong/gateway-operator/internal/consts"
+	controlplaneutils "github.com/kong/gateway-operator/internal/utils/controlplane"
+	dataplaneutils "github.com/kong/gateway-operator/internal/utils/dataplane"
+	gatewayutils "github.com/kong/gateway-operator/internal/utils/gateway"
+	k8sutils "github.com/kong/gateway-operator/internal/utils/kubernetes"
+)
+
+type mutator struct{}
+
+// MutateControlPlane sets the environment variables the ControlPlane always
+// needs. The ones connecting it to its DataPlanes depend on their Services and
+// are still set by the ControlPlane controller.
+func (m *mutator) MutateControlPlane(_ context.Context, controlPlane *operatorv1alpha1.ControlPlane) error {
+	dataplaneNamespace := gatewayutils.GetDataPlaneNamespaceForControlPlane(controlPlane)
+	controlplaneutils.SetControlPlaneDefaults(&controlPlane.Spec.ControlPlaneDeploymentOptions, dataplaneNamespace, "", nil)
+	return nil
+}
+
+// MutateDataPlane sets the default Kong configuration of the DataPlanes which
+// don't configure their environment, since the defaults would take precedence
+// over the environment set from ConfigMaps and Secrets. The DB-less default
+// stored along with them is dropped when a database is set on a DB-less
+// DataPlane, which would be rejected otherwise.
+func (m *mutator) MutateDataPlane(_ context.Context, old, dataPlane *operatorv1alpha1.DataPlane) error {
+	if len(dataPlane.Spec.Env) == 0 && len(dataPlane.Spec.EnvFrom) == 0 {
+		dataplaneutils.SetDataPlaneDefaults(&dataPlane.Spec.DataPlaneDeploymentOptions)
+	}
+	if old != nil && old.Spec.Database == nil && dataPlane.Spec.Database != nil &&
+		k8sutils.EnvValueByName(dataPlane.Spec.Env, consts.EnvVarKongDatabase) == dataplaneutils.KongDefaults[consts.EnvVarKongDatabase] {
+		dataPlane.Spec.Env = k8sutils.RejectEnvByName(dataPlane.Spec.Env, consts.EnvVarKongDatabase)
+	}
+	return nil
+}
@@ -17,7 +17,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	"github.com/kong/gateway-operator/internal/utils/image"
+	"github.com/kong/gateway-operator/internal/validation/controlplane"
 	"github.com/kong/gateway-operator/internal/validation/dataplane"
+	"github.com/kong/gateway-operator/internal/validation/gatewayconfiguration"
 )
 
 var (
@@ -26,17 +29,20 @@ var (
 )
 
 // NewWebhookServerFromManager creates a webhook server in manager.
-func NewWebhookServerFromManager(mgr ctrl.Manager, logger logr.Logger) *webhook.Server {
+func NewWebhookServerFromManager(mgr ctrl.Manager, logger logr.Logger, imagePolicy *image.Policy) *webhook.Server {
 	hookServer := mgr.GetWebhookServer()
-	handler := NewRequestHandler(mgr.GetClient(), logger)
+	handler := NewRequestHandler(mgr.GetClient(), logger, imagePolicy)
 	hookServer.Register("/validate", handler)
+	hookServer.Register("/mutate", NewMutatingRequestHandler(logger))
 	return hookServer
 }
 
 // Validator is the interface of validating
 type Validator interface {
 	ValidateControlPlane(context.Context, operatorv1alpha1.ControlPlane) error
+	ValidateControlPlaneUpdate(ctx context.Context, oldControlPlane, controlPlane operatorv1alpha1.ControlPlane) error
 	ValidateDataPlane(context.Context, operatorv1alpha1.DataPlane) error
+	ValidateGatewayConfiguration(context.Context, operatorv1alpha1.GatewayConfiguration) error
 }
 
 // RequestHandler handles the requests of validating objects.
@@ -48,10 +54,16 @@ type RequestHandler struct {
 }
 
 // NewRequestHandler create a RequestHandler to handle validation requests.
-func NewRequestHandler(c client.Client, l logr.Logger) *RequestHandler {
+// The container images set in the validated objects must comply with the
+// image policy, if any.
+func NewRequestHandler(c client.Client, l logr.Logger, imagePolicy *image.Policy) *RequestHandler {
 	return &RequestHandler{
 		Validator: &validator{
-			dataplaneValidator: dataplane.NewValidator(c),
+			client:                        c,
+			controlplaneValidator:         controlplane.NewValidator(c),
+			dataplaneValidator:            dataplane.NewValidator(c),
+			gatewayConfigurationValidator: gatewayconfiguration.NewValidator(c),
+			imagePolicy:                   imagePolicy,
 		},
 		Logger: l.WithValues("component", "validation-server"),
 	}
@@ -59,41 +71,50 @@ func NewRequestHandler(c client.Client, l logr.Logger) *RequestHandler {
 
 // ServeHTTP serves for HTTP requests.
 func (h *RequestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	serveAdmissionReview(w, r, h.Logger, h.handleValidation)
+}
+
+// serveAdmissionReview decodes the AdmissionReview of the HTTP request, and
+// responds with the AdmissionResponse returned by handle.
+func serveAdmissionReview(
+	w http.ResponseWriter, r *http.Request, logger logr.Logger,
+	handle func(context.Context, *admissionv1.AdmissionRequest) (*admissionv1.AdmissionResponse, error),
+) {
 	if r.Body == nil {
-		h.Logger.Error(fmt.Errorf("empty body"), "received request with empty body")
+		logger.Error(fmt.Errorf("empty body"), "received request with empty body")
 		http.Error(w, "admission review object is missing", http.StatusBadRequest)
 		return
 	}
 	data, err := io.ReadAll(r.Body)
 	if err != nil {
-		h.Logger.Error(err, "failed to read request from client")
+		logger.Error(err, "failed to read request from client")
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	review := &admissionv1.AdmissionReview{}
 	if err := json.Unmarshal(data, review); err != nil {
-		h.Logger.Error(err, "failed to parse AdmissionReview object")
+		logger.Error(err, "failed to parse AdmissionReview object")
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	response, err := h.handleValidation(r.Context(), review.Request)
+	response, err := handle(r.Context(), review.Request)
 	if err != nil {
-		h.Logger.Error(err, "failed to run validation")
+		logger.Error(err, "failed to handle admission request")
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 	review.Response = response
 	data, err = json.Marshal(review)
 	if err != nil {
-		h.Logger.Error(err, "failed to marshal response")
+		logger.Error(err, "failed to marshal response")
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 	_, err = w.Write(data)
 	if err != nil {
-		h.Logger.Error(err, "failed to write response")
+		logger.Error(err, "failed to write response")
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -110,6 +131,11 @@ var (
 		Version:  operatorv1alpha1.SchemeGroupVersion.Version,
 		Resource: "dataplanes",
 	}
+	gatewayConfigurationGVResource = metav1.GroupVersionResource{
+		Group:    operatorv1alpha1.SchemeGroupVersion.Group,
+		Version:  operatorv1alpha1.SchemeGroupVersion.Version,
+		Resource: "gatewayconfigurations",
+	}
 )
 
 func (h *RequestHandler) handleValidation(ctx context.Context, req *admissionv1.AdmissionRequest) (
@@ -142,7 +168,16 @@ func (h *RequestHandler) handleValidation(ctx context.Context, req *admissionv1.
 			if err != nil {
 				return nil, err
 			}
-			err = h.Validator.ValidateControlPlane(ctx, controlPlane)
+			if req.Operation == admissionv1.Update {
+				oldControlPlane := operatorv1alpha1.ControlPlane{}
+				_, _, err = deserializer.Decode(req.OldObject.Raw, nil, &oldControlPlane)
+				if err != nil {
+					return nil, err
+				}
+				err = h.Validator.ValidateControlPlaneUpdate(ctx, oldControlPlane, controlPlane)
+			} else {
+				err = h.Validator.ValidateControlPlane(ctx, controlPlane)
+			}
 			if err != nil {
 				ok = false
 				msg = err.Error()
@@ -161,6 +196,19 @@ func (h *RequestHandler) handleValidation(ctx context.Context, req *admissionv1.
 				msg = err.Error()
 			}
 		}
+	case gatewayConfigurationGVResource:
+		gatewayConfig := operatorv1alpha1.GatewayConfiguration{}
+		if req.Operation == admissionv1.Create || req.Operation == admissionv1.Update {
+			_, _, err := deserializer.Decode(req.Object.Raw, nil, &gatewayConfig)
+			if err != nil {
+				return nil, err
+			}
+			err = h.Validator.ValidateGatewayConfiguration(ctx, gatewayConfig)
+			if err != nil {
+				ok = false
+				msg = err.Error()
+			}
+		}
 	}
 
 	response.UID = req.UID
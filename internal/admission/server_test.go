@@ -15,6 +15,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
 	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
@@ -22,7 +24,10 @@ import (
 )
 
 func TestHandleDataplaneValidation(t *testing.T) {
-	b := fakeclient.NewClientBuilder()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, operatorv1alpha1.AddToScheme(scheme))
+	b := fakeclient.NewClientBuilder().WithScheme(scheme)
 	b.WithObjects(
 		&corev1.ConfigMap{
 			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cm"},
@@ -57,7 +62,7 @@ func TestHandleDataplaneValidation(t *testing.T) {
 	)
 	c := b.Build()
 
-	handler := NewRequestHandler(c, logr.Discard())
+	handler := NewRequestHandler(c, logr.Discard(), nil)
 	server := httptest.NewServer(handler)
 
 	testCases := []struct {
@@ -131,7 +136,7 @@ func TestHandleDataplaneValidation(t *testing.T) {
 				},
 			},
 			hasError: true,
-			errMsg:   "database backend postgres of dataplane not supported currently",
+			errMsg:   "database backend postgres of dataplane requires database to be set",
 		},
 		{
 			name: "validate_error:database=xxx",
@@ -209,7 +214,7 @@ func TestHandleDataplaneValidation(t *testing.T) {
 				},
 			},
 			hasError: true,
-			errMsg:   "database backend postgres of dataplane not supported currently",
+			errMsg:   "database backend postgres of dataplane requires database to be set",
 		},
 		{
 			name: "validate_error:db=xxx_in_cm_envFrom",
@@ -310,3 +315,91 @@ func TestHandleDataplaneValidation(t *testing.T) {
 	}
 
 }
+
+func TestHandleGatewayConfigurationValidation(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, operatorv1alpha1.AddToScheme(scheme))
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).Build()
+
+	handler := NewRequestHandler(c, logr.Discard(), nil)
+	server := httptest.NewServer(handler)
+
+	testCases := []struct {
+		name          string
+		gatewayConfig *operatorv1alpha1.GatewayConfiguration
+		hasError      bool
+		errMsg        string
+	}{
+		{
+			name: "validate_ok:no_options",
+			gatewayConfig: &operatorv1alpha1.GatewayConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-no-options",
+					Namespace: "default",
+				},
+			},
+			hasError: false,
+		},
+		{
+			name: "validate_error:controlplane_dataplane",
+			gatewayConfig: &operatorv1alpha1.GatewayConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-controlplane-dataplane",
+					Namespace: "default",
+				},
+				Spec: operatorv1alpha1.GatewayConfigurationSpec{
+					ControlPlaneDeploymentOptions: &operatorv1alpha1.ControlPlaneDeploymentOptions{
+						DataPlane: pointer.String("kong"),
+					},
+				},
+			},
+			hasError: true,
+			errMsg:   "dataplane can't be set, it is assigned by the Gateway controller",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			review := &admissionv1.AdmissionReview{
+				Request: &admissionv1.AdmissionRequest{
+					UID: "",
+					Kind: metav1.GroupVersionKind{
+						Group:   operatorv1alpha1.SchemeGroupVersion.Group,
+						Version: operatorv1alpha1.SchemeGroupVersion.Version,
+						Kind:    "gatewayconfigurations",
+					},
+					Resource:  gatewayConfigurationGVResource,
+					Name:      tc.gatewayConfig.Name,
+					Namespace: tc.gatewayConfig.Namespace,
+					Operation: admissionv1.Create,
+					Object: runtime.RawExtension{
+						Object: tc.gatewayConfig,
+					},
+				},
+			}
+
+			buf, err := json.Marshal(review)
+			require.NoErrorf(t, err, "there should be error in marshaling into JSON")
+			req, err := http.NewRequest("POST", server.URL, bytes.NewReader(buf))
+			require.NoError(t, err, "there should be no error in making HTTP request")
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err, "there should be no error in getting response")
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err, "there should be no error in reading body")
+			resp.Body.Close()
+			respReview := &admissionv1.AdmissionReview{}
+			err = json.Unmarshal(body, respReview)
+			require.NoError(t, err, "there should be no error in unmarshalling body")
+			validationResp := respReview.Response
+
+			if !tc.hasError {
+				require.EqualValues(t, http.StatusOK, validationResp.Result.Code, "response code should be 200 OK")
+			} else {
+				require.EqualValues(t, http.StatusBadRequest, validationResp.Result.Code, "response code should be 400 Bad Request")
+				require.Contains(t, validationResp.Result.Message, tc.errMsg, "result message should contain expected content")
+			}
+		})
+	}
+}
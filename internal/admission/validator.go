@@ -2,19 +2,162 @@ package admission
 
 import (
 	"context"
+	"errors"
+	"fmt"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	operatorerrors "github.com/kong/gateway-operator/internal/errors"
+	gatewayutils "github.com/kong/gateway-operator/internal/utils/gateway"
+	"github.com/kong/gateway-operator/internal/utils/image"
+	controlplanevalidation "github.com/kong/gateway-operator/internal/validation/controlplane"
 	dataplanevalidation "github.com/kong/gateway-operator/internal/validation/dataplane"
+	gatewayconfigurationvalidation "github.com/kong/gateway-operator/internal/validation/gatewayconfiguration"
+	"github.com/kong/gateway-operator/internal/versions"
 )
 
 type validator struct {
-	dataplaneValidator *dataplanevalidation.Validator
+	client                        client.Client
+	controlplaneValidator         *controlplanevalidation.Validator
+	dataplaneValidator            *dataplanevalidation.Validator
+	gatewayConfigurationValidator *gatewayconfigurationvalidation.Validator
+	imagePolicy                   *image.Policy
 }
 
 func (v *validator) ValidateControlPlane(ctx context.Context, controlPlane operatorv1alpha1.ControlPlane) error {
-	return nil
+	if err := v.controlplaneValidator.Validate(ctx, &controlPlane); err != nil {
+		return err
+	}
+	if err := v.validateContainerImage(&controlPlane.Spec.DeploymentOptions); err != nil {
+		return err
+	}
+	return v.validateControlPlaneVersionCompatibility(ctx, &controlPlane)
+}
+
+func (v *validator) ValidateControlPlaneUpdate(ctx context.Context, oldControlPlane, controlPlane operatorv1alpha1.ControlPlane) error {
+	if err := v.controlplaneValidator.ValidateUpdate(ctx, &oldControlPlane, &controlPlane); err != nil {
+		return err
+	}
+	if err := v.validateContainerImage(&controlPlane.Spec.DeploymentOptions); err != nil {
+		return err
+	}
+	return v.validateControlPlaneVersionCompatibility(ctx, &controlPlane)
 }
 
 func (v *validator) ValidateDataPlane(ctx context.Context, dataPlane operatorv1alpha1.DataPlane) error {
-	return v.dataplaneValidator.Validate(&dataPlane)
+	if err := v.dataplaneValidator.Validate(ctx, &dataPlane); err != nil {
+		return err
+	}
+	if err := v.validateContainerImage(&dataPlane.Spec.DeploymentOptions); err != nil {
+		return err
+	}
+	return v.validateDataPlaneVersionCompatibility(ctx, &dataPlane)
+}
+
+func (v *validator) ValidateGatewayConfiguration(ctx context.Context, gatewayConfig operatorv1alpha1.GatewayConfiguration) error {
+	if err := v.gatewayConfigurationValidator.Validate(ctx, &gatewayConfig); err != nil {
+		return err
+	}
+	if opts := gatewayConfig.Spec.DataPlaneDeploymentOptions; opts != nil {
+		if err := v.validateContainerImage(&opts.DeploymentOptions); err != nil {
+			return fmt.Errorf("invalid dataPlaneDeploymentOptions: %w", err)
+		}
+	}
+	if opts := gatewayConfig.Spec.ControlPlaneDeploymentOptions; opts != nil {
+		if err := v.validateContainerImage(&opts.DeploymentOptions); err != nil {
+			return fmt.Errorf("invalid controlPlaneDeploymentOptions: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateContainerImage rejects the container images set by users which are
+// not allowed by the image policy. The default images are always allowed.
+func (v *validator) validateContainerImage(opts *operatorv1alpha1.DeploymentOptions) error {
+	if opts.ContainerImage == nil {
+		return nil
+	}
+	if err := v.imagePolicy.ValidateImage(*opts.ContainerImage); err != nil {
+		return fmt.Errorf("invalid containerImage: %w", err)
+	}
+	return nil
+}
+
+// validateControlPlaneVersionCompatibility rejects a ControlPlane whose version
+// does not support the version of one of its DataPlanes. DataPlanes which
+// don't exist yet and versions missing from the compatibility matrix are not
+// rejected.
+func (v *validator) validateControlPlaneVersionCompatibility(ctx context.Context, controlPlane *operatorv1alpha1.ControlPlane) error {
+	kicVersion := versions.KICVersionForControlPlane(controlPlane)
+	if kicVersion == "" {
+		return nil
+	}
+
+	names, err := gatewayutils.ListDataPlaneNamesForControlPlane(ctx, v.client, controlPlane)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		dataPlane := &operatorv1alpha1.DataPlane{}
+		nn := types.NamespacedName{Namespace: gatewayutils.GetDataPlaneNamespaceForControlPlane(controlPlane), Name: name}
+		if err := v.client.Get(ctx, nn, dataPlane); err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if err := checkVersionCompatibility(kicVersion, versions.KongVersionForDataPlane(dataPlane)); err != nil {
+			return fmt.Errorf("dataplane %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// validateDataPlaneVersionCompatibility rejects a DataPlane whose version is not
+// supported by the version of one of the ControlPlanes configuring it.
+func (v *validator) validateDataPlaneVersionCompatibility(ctx context.Context, dataPlane *operatorv1alpha1.DataPlane) error {
+	kongVersion := versions.KongVersionForDataPlane(dataPlane)
+	if kongVersion == "" {
+		return nil
+	}
+
+	controlPlanes := &operatorv1alpha1.ControlPlaneList{}
+	if err := v.client.List(ctx, controlPlanes); err != nil {
+		return err
+	}
+	for i := range controlPlanes.Items {
+		controlPlane := &controlPlanes.Items[i]
+		if gatewayutils.GetDataPlaneNamespaceForControlPlane(controlPlane) != dataPlane.Namespace {
+			continue
+		}
+		names, err := gatewayutils.ListDataPlaneNamesForControlPlane(ctx, v.client, controlPlane)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			if name != dataPlane.Name {
+				continue
+			}
+			if err := checkVersionCompatibility(versions.KICVersionForControlPlane(controlPlane), kongVersion); err != nil {
+				return fmt.Errorf("controlplane %s/%s: %w", controlPlane.Namespace, controlPlane.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkVersionCompatibility only returns the errors of incompatible versions,
+// unknown compatibilities are allowed.
+func checkVersionCompatibility(kicVersion, kongVersion string) error {
+	if kicVersion == "" || kongVersion == "" {
+		return nil
+	}
+	err := versions.CheckCompatibility(kicVersion, kongVersion)
+	if errors.Is(err, operatorerrors.ErrIncompatibleVersions) {
+		return err
+	}
+	return nil
 }
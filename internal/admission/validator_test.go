@@ -0,0 +1,152 @@
+package admission
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	"github.com/kong/gateway-operator/internal/consts"
+	operatorerrors "github.com/kong/gateway-operator/internal/errors"
+	"github.com/kong/gateway-operator/internal/utils/image"
+	"github.com/kong/gateway-operator/internal/validation/controlplane"
+	"github.com/kong/gateway-operator/internal/validation/dataplane"
+)
+
+func TestValidateVersionCompatibility(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, operatorv1alpha1.AddToScheme(scheme))
+
+	newDataPlane := func(version string) *operatorv1alpha1.DataPlane {
+		dataplane := &operatorv1alpha1.DataPlane{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kong"},
+		}
+		dataplane.Spec.Env = []corev1.EnvVar{{Name: consts.EnvVarKongDatabase, Value: "off"}}
+		if version != "" {
+			dataplane.Spec.Version = pointer.String(version)
+		}
+		return dataplane
+	}
+	newControlPlane := func(version string) *operatorv1alpha1.ControlPlane {
+		controlplane := &operatorv1alpha1.ControlPlane{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kic"},
+		}
+		controlplane.Spec.DataPlane = pointer.String("kong")
+		if version != "" {
+			controlplane.Spec.Version = pointer.String(version)
+		}
+		return controlplane
+	}
+
+	testCases := []struct {
+		name         string
+		dataplane    *operatorv1alpha1.DataPlane
+		controlplane *operatorv1alpha1.ControlPlane
+		expectedErr  error
+		// expectControlPlaneErr is set when only the controlplane is invalid.
+		expectControlPlaneErr bool
+	}{
+		{
+			name:         "default versions are compatible",
+			dataplane:    newDataPlane(""),
+			controlplane: newControlPlane(""),
+		},
+		{
+			name:         "compatible versions",
+			dataplane:    newDataPlane("3.0"),
+			controlplane: newControlPlane("2.7"),
+		},
+		{
+			name:         "incompatible versions",
+			dataplane:    newDataPlane("3.0"),
+			controlplane: newControlPlane("2.5"),
+			expectedErr:  operatorerrors.ErrIncompatibleVersions,
+		},
+		{
+			name:      "invalid controlplane image",
+			dataplane: newDataPlane(""),
+			controlplane: func() *operatorv1alpha1.ControlPlane {
+				controlplane := newControlPlane("")
+				controlplane.Spec.ContainerImage = pointer.String("registry:port/kong/kic")
+				return controlplane
+			}(),
+			expectControlPlaneErr: true,
+		},
+		{
+			name:         "unknown compatibility is allowed",
+			dataplane:    newDataPlane("3.0"),
+			controlplane: newControlPlane("latest"),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(tc.dataplane, tc.controlplane).Build()
+			v := &validator{
+				client:                c,
+				controlplaneValidator: controlplane.NewValidator(c),
+				dataplaneValidator:    dataplane.NewValidator(c),
+			}
+
+			if tc.expectControlPlaneErr {
+				require.Error(t, v.ValidateControlPlane(ctx, *tc.controlplane))
+				return
+			}
+
+			for _, err := range []error{
+				v.ValidateControlPlane(ctx, *tc.controlplane),
+				v.ValidateDataPlane(ctx, *tc.dataplane),
+			} {
+				if tc.expectedErr == nil {
+					require.NoError(t, err)
+				} else {
+					require.ErrorIs(t, err, tc.expectedErr)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateContainerImagePolicy(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, operatorv1alpha1.AddToScheme(scheme))
+
+	ctx := context.Background()
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).Build()
+	v := &validator{
+		client:                c,
+		controlplaneValidator: controlplane.NewValidator(c),
+		dataplaneValidator:    dataplane.NewValidator(c),
+		imagePolicy:           &image.Policy{AllowedRepositories: []string{"mirror.example.com:5000"}},
+	}
+
+	dataPlane := operatorv1alpha1.DataPlane{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kong"}}
+	controlPlane := operatorv1alpha1.ControlPlane{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kic"}}
+
+	t.Log("the default images are allowed")
+	require.NoError(t, v.ValidateDataPlane(ctx, dataPlane))
+	require.NoError(t, v.ValidateControlPlane(ctx, controlPlane))
+
+	t.Log("the images of the allowed registries are allowed")
+	dataPlane.Spec.ContainerImage = pointer.String("mirror.example.com:5000/kong")
+	controlPlane.Spec.ContainerImage = pointer.String("mirror.example.com:5000/kong/kubernetes-ingress-controller")
+	require.NoError(t, v.ValidateDataPlane(ctx, dataPlane))
+	require.NoError(t, v.ValidateControlPlane(ctx, controlPlane))
+
+	t.Log("the images of other registries are rejected")
+	dataPlane.Spec.ContainerImage = pointer.String("kong")
+	controlPlane.Spec.ContainerImage = pointer.String("kong/kubernetes-ingress-controller")
+	require.ErrorContains(t, v.ValidateDataPlane(ctx, dataPlane), "not pulled from an allowed registry")
+	require.ErrorContains(t, v.ValidateControlPlane(ctx, controlPlane), "not pulled from an allowed registry")
+}
@@ -20,6 +20,119 @@ const (
 	// GatewayManagedLabelValue indicates that the object's lifecycle is managed by
 	// the gateway controller.
 	GatewayManagedLabelValue = "gateway"
+
+	// GatewaySharedDataPlaneLabel is the label that is used for the DataPlanes,
+	// ControlPlanes and NetworkPolicies shared between the Gateways of a
+	// GatewayClass. Its value is the UID of the GatewayClass.
+	GatewaySharedDataPlaneLabel = "konghq.com/gateway-operator-shared-dataplane"
+
+	// DataPlaneRolloutStateLabel is the label that is used for the Deployments
+	// and Services created by the dataplane controller while rolling out changes
+	// of a DataPlane. Such objects aren't labeled as dataplane-managed until
+	// they are promoted.
+	DataPlaneRolloutStateLabel = "gateway-operator.konghq.com/dataplane-rollout-state"
+
+	// DataPlaneRolloutStatePreviewLabelValue indicates that the object is part
+	// of the preview of a DataPlane rollout.
+	DataPlaneRolloutStatePreviewLabelValue = "preview"
+
+	// DataPlanePodTemplateHashLabel is the label that is used for the DataPlane
+	// Pods when a rollout strategy is configured, to tell apart the Pods of
+	// the live and the preview Deployments.
+	DataPlanePodTemplateHashLabel = "gateway-operator.konghq.com/dataplane-pod-template-hash"
+
+	// ControlPlaneRolloutStateLabel is the label that is used for the canary
+	// Deployments created by the controlplane controller while rolling out
+	// changes of a ControlPlane. Such Deployments are never labeled as
+	// controlplane-managed: the changes are applied to the live Deployment
+	// once promoted.
+	ControlPlaneRolloutStateLabel = "gateway-operator.konghq.com/controlplane-rollout-state"
+
+	// ControlPlaneRolloutStateCanaryLabelValue indicates that the object is
+	// the canary of a ControlPlane rollout.
+	ControlPlaneRolloutStateCanaryLabelValue = "canary"
+
+	// ControlPlanePodTemplateHashLabel is the label that is used for the Pods
+	// of the canary Deployment of a ControlPlane, to tell them apart from the
+	// live ones.
+	ControlPlanePodTemplateHashLabel = "gateway-operator.konghq.com/controlplane-pod-template-hash"
+
+	// DataPlaneServiceTypeLabel is the label that is used for the Services of
+	// a DataPlane which don't expose its proxy. Such objects aren't labeled as
+	// dataplane-managed.
+	DataPlaneServiceTypeLabel = "gateway-operator.konghq.com/dataplane-service-type"
+
+	// DataPlaneServiceTypeClusterLabelValue indicates that the Service exposes
+	// the cluster listener of a Kong hybrid mode control plane.
+	DataPlaneServiceTypeClusterLabelValue = "cluster"
+
+	// DataPlaneMigrationsPhaseLabel is the label that is used for the Jobs
+	// running the database migrations of a DataPlane, to tell which migrations
+	// phase they run.
+	DataPlaneMigrationsPhaseLabel = "gateway-operator.konghq.com/dataplane-migrations-phase"
+
+	// DataPlaneMigrationsImageHashLabel is the label that is used for the Jobs
+	// running the database migrations of a DataPlane, with a hash of the
+	// container image they migrate the database to.
+	DataPlaneMigrationsImageHashLabel = "gateway-operator.konghq.com/dataplane-migrations-image-hash"
+
+	// GatewayBackupManagedLabelValue indicates that an object's lifecycle is
+	// managed by the gatewaybackup controller.
+	GatewayBackupManagedLabelValue = "gatewaybackup"
+
+	// GatewayRestoreManagedLabelValue indicates that an object's lifecycle is
+	// managed by the gatewayrestore controller.
+	GatewayRestoreManagedLabelValue = "gatewayrestore"
+)
+
+// -----------------------------------------------------------------------------
+// Consts - Standard Kubernetes Object Annotations
+// -----------------------------------------------------------------------------
+
+const (
+	// GatewaySharedDataPlaneGatewaysAnnotation is the annotation that is used to
+	// keep track of the Gateways using a shared DataPlane, as a comma-separated
+	// list of namespace/name pairs.
+	GatewaySharedDataPlaneGatewaysAnnotation = "gateway-operator.konghq.com/gateways"
+
+	// DataPlanePromotePreviewAnnotation is the annotation that is used to
+	// promote the preview Deployment of a DataPlane using the Manual promotion
+	// strategy, or to progress to the next step of a canary rollout. It's
+	// removed by the controller once the promotion is done.
+	DataPlanePromotePreviewAnnotation = "gateway-operator.konghq.com/promote-preview"
+
+	// ControlPlanePromoteCanaryAnnotation is the annotation that is used to
+	// progress to the next step of the canary rollout of a ControlPlane. It's
+	// the same as the one of the DataPlanes, so that both planes of a Gateway
+	// are promoted alike. It's removed by the controller once the promotion
+	// is done.
+	ControlPlanePromoteCanaryAnnotation = DataPlanePromotePreviewAnnotation
+
+	// DataPlaneLicenseChecksumAnnotation is the annotation that is used for the
+	// DataPlane Pods to keep track of the checksum of the Kong Enterprise
+	// license they run with, so that they are rolled when it changes.
+	DataPlaneLicenseChecksumAnnotation = "gateway-operator.konghq.com/license-checksum"
+
+	// GatewayRestoreInProgressAnnotation is the annotation that is used for the
+	// Gateways, DataPlanes, ControlPlanes and GatewayConfigurations being
+	// restored by a GatewayRestore, whose namespace/name pair is its value.
+	// Such objects aren't reconciled until the restore removes the annotation:
+	// once they're all restored, when the restore fails, or when the
+	// GatewayRestore is deleted.
+	GatewayRestoreInProgressAnnotation = "gateway-operator.konghq.com/restore-in-progress"
+
+	// GatewayConfigurationGenerationsAnnotation is the annotation that is used
+	// for the DataPlanes and ControlPlanes of Gateways to keep track of the
+	// generations of the GatewayConfigurations they have been configured with,
+	// as a comma-separated list of namespace/name=generation entries.
+	GatewayConfigurationGenerationsAnnotation = "gateway-operator.konghq.com/gateway-configuration-generations"
+
+	// GatewayConfigurationAnnotation is the annotation that is used for the
+	// Gateways to reference by name a GatewayConfiguration in their namespace.
+	// The configuration is merged over the ones of the GatewayClass of the
+	// Gateway and of the GatewayConfiguration attached to it through its
+	// targetRef, the fields it sets taking precedence.
+	GatewayConfigurationAnnotation = "gateway-operator.konghq.com/gateway-configuration"
 )
 
 // -----------------------------------------------------------------------------
@@ -32,6 +145,12 @@ const (
 
 	// ControlPlanePrefix is used as a name prefix to generate controlplane-owned objects' name
 	ControlPlanePrefix = "controlplane"
+
+	// GatewayBackupPrefix is used as a name prefix to generate gatewaybackup-owned objects' name
+	GatewayBackupPrefix = "gatewaybackup"
+
+	// GatewayRestorePrefix is used as a name prefix to generate gatewayrestore-owned objects' name
+	GatewayRestorePrefix = "gatewayrestore"
 )
 
 // -----------------------------------------------------------------------------
@@ -92,13 +211,84 @@ const (
 	DataPlaneMetricsPort = 8100
 )
 
+// -----------------------------------------------------------------------------
+// Consts - ControlPlane exposed ports
+// -----------------------------------------------------------------------------
+
+const (
+	// ControlPlaneMetricsPort is the port that the controlplane uses for metrics.
+	ControlPlaneMetricsPort = 10255
+)
+
 // -----------------------------------------------------------------------------
 // Consts - Environment Variable Names
 // -----------------------------------------------------------------------------
 
 const (
 	// EnvVarKongDatabase is the environment variable name to specify database
-	// backend used for dataplane(KOng gateway). Currently only DBLess mode
-	// (empty, or "off") is supported.
+	// backend used for dataplane(KOng gateway). DBLess mode (empty, or "off")
+	// and PostgreSQL are supported.
 	EnvVarKongDatabase = "KONG_DATABASE"
+
+	// KongDatabasePostgres is the database backend of the dataplanes using a
+	// PostgreSQL database.
+	KongDatabasePostgres = "postgres"
+
+	// EnvVarKongLicenseData is the environment variable name to specify the
+	// Kong Enterprise license of the dataplane.
+	EnvVarKongLicenseData = "KONG_LICENSE_DATA"
+
+	// EnvVarKongDeclarativeConfig is the environment variable name to specify
+	// the path of the declarative configuration file of a DB-less dataplane.
+	EnvVarKongDeclarativeConfig = "KONG_DECLARATIVE_CONFIG"
+
+	// EnvVarControllerElectionID is the environment variable name to specify
+	// the ID of the leader election of the ingress controller: only the leader
+	// of an election pushes the configuration to the dataplanes.
+	EnvVarControllerElectionID = "CONTROLLER_ELECTION_ID"
+
+	// DataPlaneLicenseSecretKey is the key of the Secrets referenced by the
+	// dataplanes holding their Kong Enterprise license.
+	DataPlaneLicenseSecretKey = "license"
+)
+
+// -----------------------------------------------------------------------------
+// Consts - DataPlane Declarative Configuration
+// -----------------------------------------------------------------------------
+
+const (
+	// DataPlaneDeclarativeConfigVolumeName is the name of the volume holding
+	// the declarative Kong configuration of the DataPlane Pods.
+	DataPlaneDeclarativeConfigVolumeName = "declarative-config"
+
+	// DataPlaneDeclarativeConfigMountPath is the path the declarative Kong
+	// configuration volume is mounted at in the DataPlane Pods.
+	DataPlaneDeclarativeConfigMountPath = "/var/declarative-config"
+
+	// DataPlaneDeclarativeConfigFileName is the name of the declarative Kong
+	// configuration file in its volume.
+	DataPlaneDeclarativeConfigFileName = "kong.yml"
+)
+
+// -----------------------------------------------------------------------------
+// Consts - Gateway Backup & Restore
+// -----------------------------------------------------------------------------
+
+const (
+	// GatewayBackupSnapshotKey is the key of the Secrets holding the snapshot
+	// of a Gateway.
+	GatewayBackupSnapshotKey = "snapshot.json"
+
+	// GatewayBackupSnapshotMountPath is the path the Secret holding the
+	// snapshot of a Gateway is mounted at in the Jobs copying it to a volume.
+	GatewayBackupSnapshotMountPath = "/var/gateway-snapshot"
+
+	// GatewayBackupVolumeMountPath is the path the volume storing the snapshots
+	// of the Gateways is mounted at in the backup and restore Jobs.
+	GatewayBackupVolumeMountPath = "/var/gateway-backup"
+
+	// DefaultGatewayRestoreJobImage is the default container image of the Jobs
+	// writing the snapshots stored in volumes into the staging Secrets of the
+	// GatewayRestores.
+	DefaultGatewayRestoreJobImage = "bitnami/kubectl:1.25"
 )
@@ -40,3 +40,34 @@ var ErrObjectMissingParametersRef = errors.New("no reference to related objects"
 // ErrDataPlaneNotSet is a custom error that must be used when a specific OwnerReference
 // is expected to be on an object, but it is not found.
 var ErrDataPlaneNotSet = errors.New("no dataplane name set")
+
+// -----------------------------------------------------------------------------
+// ReferenceGrant - Errors
+// -----------------------------------------------------------------------------
+
+// ErrReferenceNotPermitted is a custom error that must be used when an object
+// references another object in a different namespace and no ReferenceGrant in
+// the target namespace permits that reference.
+var ErrReferenceNotPermitted = errors.New("reference not permitted by any ReferenceGrant")
+
+// -----------------------------------------------------------------------------
+// Versions - Errors
+// -----------------------------------------------------------------------------
+
+// ErrIncompatibleVersions is a custom error that must be used when the version
+// of a ControlPlane does not support the version of the DataPlane it configures.
+var ErrIncompatibleVersions = errors.New("incompatible controlplane and dataplane versions")
+
+// ErrUnknownVersionCompatibility is a custom error that must be used when the
+// compatibility of the versions of a ControlPlane and a DataPlane can't be
+// determined, e.g. because one of them is not in the compatibility matrix.
+var ErrUnknownVersionCompatibility = errors.New("unknown controlplane and dataplane versions compatibility")
+
+// -----------------------------------------------------------------------------
+// Backup & Restore - Errors
+// -----------------------------------------------------------------------------
+
+// ErrBackupFailed is a custom error that must be used when a GatewayBackup or
+// a GatewayRestore can't complete whatever the number of retries, e.g. because
+// the objects it refers to are missing or invalid.
+var ErrBackupFailed = errors.New("gateway backup failed")
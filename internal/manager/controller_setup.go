@@ -5,12 +5,16 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"github.com/kong/gateway-operator/controllers"
+	gatewayutils "github.com/kong/gateway-operator/internal/utils/gateway"
+	"github.com/kong/gateway-operator/internal/versions"
 )
 
 // -----------------------------------------------------------------------------
@@ -53,20 +57,88 @@ func (c *ControllerDef) MaybeSetupWithManager(mgr ctrl.Manager) error {
 }
 
 func setupControllers(mgr manager.Manager, c *Config) []ControllerDef {
+	referenceGrantEnabled := CRDExists(mgr.GetClient(), schema.GroupVersionResource{
+		Group:    gatewayv1alpha2.SchemeGroupVersion.Group,
+		Version:  gatewayv1alpha2.SchemeGroupVersion.Version,
+		Resource: "referencegrants",
+	})
+
+	// prefer the graduated v1beta1 version of the Gateway API when the cluster
+	// serves it, and fall back to v1alpha2 otherwise.
+	gatewayAPIVersion := gatewayv1alpha2.SchemeGroupVersion
+	gatewayAPIClient := mgr.GetClient()
+	gatewayAPIV1beta1Enabled := crdExistsChecker{
+		GVR: schema.GroupVersionResource{
+			Group:    gatewayv1beta1.SchemeGroupVersion.Group,
+			Version:  gatewayv1beta1.SchemeGroupVersion.Version,
+			Resource: "gateways",
+		},
+	}.CRDExists(mgr.GetClient())
+	if gatewayAPIV1beta1Enabled {
+		gatewayAPIVersion = gatewayv1beta1.SchemeGroupVersion
+		gatewayAPIClient = gatewayutils.NewV1beta1Client(mgr.GetClient())
+	}
+
+	// AutomaticUpgrades use the catalog embedded in the operator unless a
+	// ConfigMap providing the version catalog is configured.
+	versionCatalog := &versions.CatalogLoader{Client: mgr.GetClient()}
+	if c.VersionCatalogConfigMapName != "" {
+		versionCatalog.ConfigMap = &types.NamespacedName{
+			Namespace: c.VersionCatalogNamespace,
+			Name:      c.VersionCatalogConfigMapName,
+		}
+	}
+
+	imagePolicy := newImagePolicy(c)
+
 	controllers := []ControllerDef{
 		// Gateway controller
 		{
 			Enabled: c.GatewayControllerEnabled,
 			AutoHandler: crdExistsChecker{
 				GVR: schema.GroupVersionResource{
-					Group:    gatewayv1alpha2.SchemeGroupVersion.Group,
-					Version:  gatewayv1alpha2.SchemeGroupVersion.Version,
+					Group:    gatewayAPIVersion.Group,
+					Version:  gatewayAPIVersion.Version,
 					Resource: "gateways",
 				},
 			}.CRDExists,
 			Controller: &controllers.GatewayReconciler{
-				Client: mgr.GetClient(),
-				Scheme: mgr.GetScheme(),
+				Client:                   gatewayAPIClient,
+				Scheme:                   mgr.GetScheme(),
+				ReferenceGrantEnabled:    referenceGrantEnabled,
+				GatewayAPIV1beta1Enabled: gatewayAPIV1beta1Enabled,
+			},
+		},
+		// GatewayClass controller
+		{
+			Enabled: c.GatewayControllerEnabled,
+			AutoHandler: crdExistsChecker{
+				GVR: schema.GroupVersionResource{
+					Group:    gatewayAPIVersion.Group,
+					Version:  gatewayAPIVersion.Version,
+					Resource: "gatewayclasses",
+				},
+			}.CRDExists,
+			Controller: &controllers.GatewayClassReconciler{
+				Client:                   gatewayAPIClient,
+				Scheme:                   mgr.GetScheme(),
+				GatewayAPIV1beta1Enabled: gatewayAPIV1beta1Enabled,
+			},
+		},
+		// GatewayConfiguration controller
+		{
+			Enabled: c.GatewayControllerEnabled,
+			AutoHandler: crdExistsChecker{
+				GVR: schema.GroupVersionResource{
+					Group:    gatewayAPIVersion.Group,
+					Version:  gatewayAPIVersion.Version,
+					Resource: "gatewayclasses",
+				},
+			}.CRDExists,
+			Controller: &controllers.GatewayConfigurationReconciler{
+				Client:                   gatewayAPIClient,
+				Scheme:                   mgr.GetScheme(),
+				GatewayAPIV1beta1Enabled: gatewayAPIV1beta1Enabled,
 			},
 		},
 		// ControlPlane controller
@@ -77,6 +149,9 @@ func setupControllers(mgr manager.Manager, c *Config) []ControllerDef {
 				Scheme:                   mgr.GetScheme(),
 				ClusterCASecretName:      c.ClusterCASecretName,
 				ClusterCASecretNamespace: c.ClusterCASecretNamespace,
+				ReferenceGrantEnabled:    referenceGrantEnabled,
+				VersionCatalog:           versionCatalog,
+				ImagePolicy:              imagePolicy,
 			},
 		},
 		// DataPlane controller
@@ -87,6 +162,45 @@ func setupControllers(mgr manager.Manager, c *Config) []ControllerDef {
 				Scheme:                   mgr.GetScheme(),
 				ClusterCASecretName:      c.ClusterCASecretName,
 				ClusterCASecretNamespace: c.ClusterCASecretNamespace,
+				VersionCatalog:           versionCatalog,
+				ImagePolicy:              imagePolicy,
+			},
+		},
+		// GatewayBackup controller
+		{
+			Enabled: c.GatewayBackupControllerEnabled,
+			AutoHandler: crdExistsChecker{
+				GVR: schema.GroupVersionResource{
+					Group:    gatewayAPIVersion.Group,
+					Version:  gatewayAPIVersion.Version,
+					Resource: "gateways",
+				},
+			}.CRDExists,
+			Controller: &controllers.GatewayBackupReconciler{
+				Client:                   gatewayAPIClient,
+				Scheme:                   mgr.GetScheme(),
+				ClusterCASecretName:      c.ClusterCASecretName,
+				ClusterCASecretNamespace: c.ClusterCASecretNamespace,
+				ImagePolicy:              imagePolicy,
+			},
+		},
+		// GatewayRestore controller
+		{
+			Enabled: c.GatewayBackupControllerEnabled,
+			AutoHandler: crdExistsChecker{
+				GVR: schema.GroupVersionResource{
+					Group:    gatewayAPIVersion.Group,
+					Version:  gatewayAPIVersion.Version,
+					Resource: "gateways",
+				},
+			}.CRDExists,
+			Controller: &controllers.GatewayRestoreReconciler{
+				Client:                     gatewayAPIClient,
+				Scheme:                     mgr.GetScheme(),
+				ClusterCASecretName:        c.ClusterCASecretName,
+				ClusterCASecretNamespace:   c.ClusterCASecretNamespace,
+				ImagePolicy:                imagePolicy,
+				GatewayClassRestoreEnabled: c.GatewayRestoreGatewayClassesEnabled,
 			},
 		},
 	}
@@ -48,11 +48,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
 	"github.com/kong/gateway-operator/internal/admission"
 	"github.com/kong/gateway-operator/internal/manager/metadata"
 	"github.com/kong/gateway-operator/internal/telemetry"
+	"github.com/kong/gateway-operator/internal/utils/image"
 	"github.com/kong/gateway-operator/pkg/vars"
 )
 
@@ -72,6 +74,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(operatorv1alpha1.AddToScheme(scheme))
 	utilruntime.Must(gatewayv1alpha2.AddToScheme(scheme))
+	utilruntime.Must(gatewayv1beta1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
@@ -92,9 +95,32 @@ type Config struct {
 	ClusterCASecretNamespace string
 	LoggerOpts               zap.Options
 
-	GatewayControllerEnabled      bool
-	ControlPlaneControllerEnabled bool
-	DataPlaneControllerEnabled    bool
+	// VersionCatalogConfigMapName is the name of the ConfigMap containing the
+	// version catalog used by AutomaticUpgrades. The catalog embedded in the
+	// operator is used when empty.
+	VersionCatalogConfigMapName string
+	VersionCatalogNamespace     string
+
+	// ImageMirrorRegistry is the registry the default DataPlane and
+	// ControlPlane images are pulled from instead of their original registry.
+	ImageMirrorRegistry string
+	// AllowedImageRepositories lists the registries and repositories the
+	// container images set in DataPlanes and ControlPlanes can be pulled from.
+	// All the images are allowed when empty.
+	AllowedImageRepositories []string
+	// ImagePullSecrets are the names of the Secrets set as imagePullSecrets of
+	// the generated Deployments and ServiceAccounts.
+	ImagePullSecrets []string
+
+	GatewayControllerEnabled       bool
+	ControlPlaneControllerEnabled  bool
+	DataPlaneControllerEnabled     bool
+	GatewayBackupControllerEnabled bool
+
+	// GatewayRestoreGatewayClassesEnabled allows the GatewayRestores to create
+	// the cluster-scoped GatewayClasses of their snapshots missing from the
+	// cluster.
+	GatewayRestoreGatewayClassesEnabled bool
 }
 
 func DefaultConfig() Config {
@@ -110,9 +136,10 @@ func DefaultConfig() Config {
 		ClusterCASecretNamespace: "kong-system",
 		LoggerOpts:               zap.Options{},
 
-		GatewayControllerEnabled:      true,
-		ControlPlaneControllerEnabled: true,
-		DataPlaneControllerEnabled:    true,
+		GatewayControllerEnabled:       true,
+		ControlPlaneControllerEnabled:  true,
+		DataPlaneControllerEnabled:     true,
+		GatewayBackupControllerEnabled: true,
 	}
 }
 
@@ -221,7 +248,7 @@ func runWebhookServer(mgr manager.Manager, cfg Config) {
 	}
 
 	if startWebhook {
-		hookServer := admission.NewWebhookServerFromManager(mgr, ctrl.Log)
+		hookServer := admission.NewWebhookServerFromManager(mgr, ctrl.Log, newImagePolicy(&cfg))
 		hookServer.CertDir = webhookCertDir
 		// add readyz check for checking connection to webhook server
 		// to make the controller to be marked as ready after webhook started.
@@ -234,6 +261,19 @@ func runWebhookServer(mgr manager.Manager, cfg Config) {
 	}
 }
 
+// newImagePolicy returns the image policy configured by the given Config, or
+// nil when no policy is configured.
+func newImagePolicy(cfg *Config) *image.Policy {
+	if cfg.ImageMirrorRegistry == "" && len(cfg.AllowedImageRepositories) == 0 && len(cfg.ImagePullSecrets) == 0 {
+		return nil
+	}
+	return &image.Policy{
+		MirrorRegistry:      cfg.ImageMirrorRegistry,
+		AllowedRepositories: cfg.AllowedImageRepositories,
+		ImagePullSecrets:    cfg.ImagePullSecrets,
+	}
+}
+
 type caManager struct {
 	client          client.Client
 	secretName      string
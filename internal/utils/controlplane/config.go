@@ -0,0 +1,109 @@
+package controlplane
+
+import (
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	dataplaneutils "github.com/kong/gateway-operator/internal/utils/dataplane"
+	k8sutils "github.com/kong/gateway-operator/internal/utils/kubernetes"
+)
+
+// -----------------------------------------------------------------------------
+// ControlPlane Utils - Config Vars & Consts
+// -----------------------------------------------------------------------------
+
+// ClusterCertificateEnvVars are the environment variables pointing the
+// ControlPlane to the cluster certificate mounted in its Pods, used for the
+// mTLS connection to the Kong Admin API of its DataPlanes.
+var ClusterCertificateEnvVars = []corev1.EnvVar{
+	{Name: "CONTROLLER_KONG_ADMIN_TLS_CLIENT_CERT_FILE", Value: "/var/cluster-certificate/tls.crt"},
+	{Name: "CONTROLLER_KONG_ADMIN_TLS_CLIENT_KEY_FILE", Value: "/var/cluster-certificate/tls.key"},
+	{Name: "CONTROLLER_KONG_ADMIN_CA_CERT_FILE", Value: "/var/cluster-certificate/ca.crt"},
+}
+
+// -----------------------------------------------------------------------------
+// ControlPlane Utils - Config
+// -----------------------------------------------------------------------------
+
+// SetControlPlaneDefaults updates the environment variables of control plane
+// and returns true if env field is changed. The environment variables related
+// to the DataPlane are only set when both the namespace and the Service name of
+// the DataPlane are provided. The environment variables listed in dontOverride
+// are left untouched.
+func SetControlPlaneDefaults(
+	spec *operatorv1alpha1.ControlPlaneDeploymentOptions,
+	namespace string, dataplaneServiceName string,
+	dontOverride map[string]struct{},
+) bool {
+	changed := false
+
+	// set env POD_NAMESPACE. should be always from `metadata.namespace` of pod.
+	envSourceMetadataNamespace := &corev1.EnvVarSource{
+		FieldRef: &corev1.ObjectFieldSelector{
+			APIVersion: "v1",
+			FieldPath:  "metadata.namespace",
+		},
+	}
+	if !reflect.DeepEqual(envSourceMetadataNamespace, k8sutils.EnvVarSourceByName(spec.Env, "POD_NAMESPACE")) {
+		spec.Env = k8sutils.UpdateEnvSource(spec.Env, "POD_NAMESPACE", envSourceMetadataNamespace)
+		changed = true
+	}
+
+	// set env POD_NAME. should be always from `metadata.name` of pod.
+	envSourceMetadataName := &corev1.EnvVarSource{
+		FieldRef: &corev1.ObjectFieldSelector{
+			APIVersion: "v1",
+			FieldPath:  "metadata.name",
+		},
+	}
+	if !reflect.DeepEqual(envSourceMetadataName, k8sutils.EnvVarSourceByName(spec.Env, "POD_NAME")) {
+		spec.Env = k8sutils.UpdateEnvSource(spec.Env, "POD_NAME", envSourceMetadataName)
+		changed = true
+	}
+
+	if namespace != "" && dataplaneServiceName != "" {
+		if _, isOverrideDisabled := dontOverride["CONTROLLER_PUBLISH_SERVICE"]; !isOverrideDisabled {
+			publishService := PublishService(dataplaneServiceName, namespace)
+			if k8sutils.EnvValueByName(spec.Env, "CONTROLLER_PUBLISH_SERVICE") != publishService {
+				spec.Env = k8sutils.UpdateEnv(spec.Env, "CONTROLLER_PUBLISH_SERVICE", publishService)
+				changed = true
+			}
+		}
+		if _, isOverrideDisabled := dontOverride["CONTROLLER_KONG_ADMIN_URL"]; !isOverrideDisabled {
+			kongAdminURL := KongAdminURL(dataplaneServiceName, namespace)
+			if k8sutils.EnvValueByName(spec.Env, "CONTROLLER_KONG_ADMIN_URL") != kongAdminURL {
+				spec.Env = k8sutils.UpdateEnv(spec.Env, "CONTROLLER_KONG_ADMIN_URL", kongAdminURL)
+				changed = true
+			}
+		}
+	}
+
+	for _, envVar := range ClusterCertificateEnvVars {
+		if _, isOverrideDisabled := dontOverride[envVar.Name]; isOverrideDisabled {
+			continue
+		}
+		if k8sutils.EnvValueByName(spec.Env, envVar.Name) != envVar.Value ||
+			k8sutils.EnvVarSourceByName(spec.Env, envVar.Name) != nil {
+			spec.Env = k8sutils.UpdateEnv(spec.Env, envVar.Name, envVar.Value)
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// KongAdminURL returns the URL of the Kong Admin API exposed by the given
+// DataPlane Service.
+func KongAdminURL(dataplaneServiceName, dataplaneNamespace string) string {
+	return fmt.Sprintf("https://%s.%s.svc:%d",
+		dataplaneServiceName, dataplaneNamespace, dataplaneutils.DefaultKongAdminPort)
+}
+
+// PublishService returns the namespaced name of the given DataPlane Service,
+// as published by the ControlPlane.
+func PublishService(dataplaneServiceName, dataplaneNamespace string) string {
+	return fmt.Sprintf("%s/%s", dataplaneNamespace, dataplaneServiceName)
+}
@@ -0,0 +1,86 @@
+package controlplane
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// -----------------------------------------------------------------------------
+// ControlPlane Utils - Metrics
+// -----------------------------------------------------------------------------
+
+// configurationPushMetric is the name of the counter of the Kong
+// configuration pushes exposed by the ingress controller.
+const configurationPushMetric = "ingress_controller_configuration_push_count"
+
+// ConfigurationPushCounts is the number of Kong configuration pushes of a
+// ControlPlane.
+type ConfigurationPushCounts struct {
+	// Successful is the number of successful pushes.
+	Successful int64
+
+	// Failed is the number of failed pushes.
+	Failed int64
+}
+
+// Add adds the provided counts to the counts.
+func (c *ConfigurationPushCounts) Add(other ConfigurationPushCounts) {
+	c.Successful += other.Successful
+	c.Failed += other.Failed
+}
+
+// ParseConfigurationPushCounts parses the metrics exposed in the Prometheus
+// text format by the ingress controller and returns the number of Kong
+// configuration pushes.
+func ParseConfigurationPushCounts(r io.Reader) (ConfigurationPushCounts, error) {
+	var counts ConfigurationPushCounts
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, rest, hasLabels := strings.Cut(line, "{")
+		if !hasLabels || name != configurationPushMetric {
+			continue
+		}
+		labels, value, found := strings.Cut(rest, "}")
+		if !found {
+			return ConfigurationPushCounts{}, fmt.Errorf("malformed metric: %s", line)
+		}
+		// the value can be followed by a timestamp
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			return ConfigurationPushCounts{}, fmt.Errorf("missing value for metric: %s", line)
+		}
+		count, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return ConfigurationPushCounts{}, fmt.Errorf("invalid value for metric %s: %w", line, err)
+		}
+
+		if getLabelValue(labels, "success") == "true" {
+			counts.Successful += int64(count)
+		} else {
+			counts.Failed += int64(count)
+		}
+	}
+
+	return counts, scanner.Err()
+}
+
+// getLabelValue returns the value of the label from a list of labels in the
+// Prometheus text format, e.g. protocol="db-less",success="true".
+func getLabelValue(labels, name string) string {
+	for _, label := range strings.Split(labels, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(label), "=")
+		if found && key == name {
+			return strings.Trim(value, `"`)
+		}
+	}
+	return ""
+}
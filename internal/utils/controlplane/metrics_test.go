@@ -0,0 +1,55 @@
+package controlplane
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConfigurationPushCounts(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		metrics  string
+		expected ConfigurationPushCounts
+		wantErr  bool
+	}{
+		{
+			name: "no pushes",
+			metrics: `# HELP go_goroutines Number of goroutines that currently exist.
+# TYPE go_goroutines gauge
+go_goroutines 112
+`,
+		},
+		{
+			name: "successful and failed pushes",
+			metrics: `# HELP ingress_controller_configuration_push_count Count of successful/failed configuration pushes to Kong.
+# TYPE ingress_controller_configuration_push_count counter
+ingress_controller_configuration_push_count{dataplane="https://10.244.0.12:8444",protocol="db-less",success="true"} 42
+ingress_controller_configuration_push_count{dataplane="https://10.244.0.12:8444",protocol="db-less",success="false"} 3
+ingress_controller_configuration_push_count{dataplane="https://10.244.0.13:8444",protocol="db-less",success="true"} 40 1666102800000
+`,
+			expected: ConfigurationPushCounts{Successful: 82, Failed: 3},
+		},
+		{
+			name:    "malformed metric",
+			metrics: `ingress_controller_configuration_push_count{success="true" 42`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid value",
+			metrics: `ingress_controller_configuration_push_count{success="true"} many`,
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			counts, err := ParseConfigurationPushCounts(strings.NewReader(tt.metrics))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, counts)
+		})
+	}
+}
@@ -2,11 +2,13 @@ package dataplane
 
 import (
 	"fmt"
+	"path"
 	"sort"
 
 	corev1 "k8s.io/api/core/v1"
 
 	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	"github.com/kong/gateway-operator/internal/consts"
 	k8sutils "github.com/kong/gateway-operator/internal/utils/kubernetes"
 )
 
@@ -34,6 +36,10 @@ const (
 
 	// DefaultKongStatusPort is the default port used for Kong proxy status
 	DefaultKongStatusPort = 8100
+
+	// DefaultKongClusterPort is the default port used by the Kong hybrid mode
+	// control planes for the connections of their data planes
+	DefaultKongClusterPort = 8005
 )
 
 // KongDefaults are the baseline Kong proxy configuration options needed for
@@ -72,9 +78,13 @@ var KongDefaults = map[string]string{
 
 // SetDataPlaneDefaults sets any unset default configuration options on the
 // DataPlane. No configuration is overridden. EnvVars are sorted
-// lexographically as a side effect.
+// lexographically as a side effect. The database of the DataPlanes using one
+// is set by DatabaseEnvVars.
 func SetDataPlaneDefaults(spec *operatorv1alpha1.DataPlaneDeploymentOptions) {
 	for k, v := range KongDefaults {
+		if k == consts.EnvVarKongDatabase && spec.Database != nil {
+			continue
+		}
 		envVar := corev1.EnvVar{Name: k, Value: v}
 		if !k8sutils.IsEnvVarPresent(envVar, spec.Env) {
 			spec.Env = append(spec.Env, envVar)
@@ -82,3 +92,89 @@ func SetDataPlaneDefaults(spec *operatorv1alpha1.DataPlaneDeploymentOptions) {
 	}
 	sort.Sort(k8sutils.SortableEnvVars(spec.Env))
 }
+
+// postgresConnectionEnvVars maps the keys of the Secret holding the connection
+// settings of a PostgreSQL database to the Kong settings.
+var postgresConnectionEnvVars = []struct {
+	key      string
+	name     string
+	optional bool
+}{
+	{key: "host", name: "KONG_PG_HOST"},
+	{key: "port", name: "KONG_PG_PORT", optional: true},
+	{key: "user", name: "KONG_PG_USER", optional: true},
+	{key: "password", name: "KONG_PG_PASSWORD", optional: true},
+	{key: "database", name: "KONG_PG_DATABASE", optional: true},
+}
+
+// DatabaseEnvVars returns the environment variables configuring the DataPlane
+// to use its database, with the connection settings set from the Secret it
+// references. No environment variable is returned for the DataPlanes without
+// database.
+func DatabaseEnvVars(spec *operatorv1alpha1.DataPlaneDeploymentOptions) []corev1.EnvVar {
+	if spec.Database == nil {
+		return nil
+	}
+
+	envVars := []corev1.EnvVar{{Name: consts.EnvVarKongDatabase, Value: consts.KongDatabasePostgres}}
+	for _, envVar := range postgresConnectionEnvVars {
+		optional := envVar.optional
+		envVars = append(envVars, corev1.EnvVar{
+			Name: envVar.name,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: spec.Database.Postgres.ConnectionSecretRef,
+					Key:                  envVar.key,
+					Optional:             &optional,
+				},
+			},
+		})
+	}
+	return envVars
+}
+
+// HybridEnvVars returns the environment variables configuring the role of the
+// DataPlane in a Kong hybrid mode cluster, using the certificate issued by the
+// operator for the DataPlane for the mutual TLS connections of the cluster.
+// The data planes connect to the clusterControlPlane address, and check that
+// the certificate of their control plane was issued for clusterServerName. No
+// environment variable is returned for the traditional DataPlanes.
+func HybridEnvVars(spec *operatorv1alpha1.DataPlaneDeploymentOptions, clusterControlPlane, clusterServerName string) []corev1.EnvVar {
+	var envVars []corev1.EnvVar
+	switch spec.Role {
+	case operatorv1alpha1.DataPlaneRoleControlPlane:
+		envVars = []corev1.EnvVar{
+			{Name: "KONG_CLUSTER_LISTEN", Value: fmt.Sprintf("0.0.0.0:%d", DefaultKongClusterPort)},
+		}
+	case operatorv1alpha1.DataPlaneRoleDataPlane:
+		envVars = []corev1.EnvVar{
+			{Name: "KONG_CLUSTER_CONTROL_PLANE", Value: clusterControlPlane},
+			{Name: "KONG_CLUSTER_SERVER_NAME", Value: clusterServerName},
+		}
+	default:
+		return nil
+	}
+
+	return append([]corev1.EnvVar{
+		{Name: "KONG_ROLE", Value: string(spec.Role)},
+		{Name: "KONG_CLUSTER_MTLS", Value: "pki"},
+		{Name: "KONG_CLUSTER_CERT", Value: "/var/cluster-certificate/tls.crt"},
+		{Name: "KONG_CLUSTER_CERT_KEY", Value: "/var/cluster-certificate/tls.key"},
+		{Name: "KONG_CLUSTER_CA_CERT", Value: "/var/cluster-certificate/ca.crt"},
+	}, envVars...)
+}
+
+// DeclarativeConfigEnvVars returns the environment variables configuring the
+// DataPlane to load its declarative configuration from the file of the volume
+// mounted in its Pods. No environment variable is returned for the DataPlanes
+// without declarative configuration.
+func DeclarativeConfigEnvVars(spec *operatorv1alpha1.DataPlaneDeploymentOptions) []corev1.EnvVar {
+	if spec.DeclarativeConfig == nil {
+		return nil
+	}
+
+	return []corev1.EnvVar{{
+		Name:  consts.EnvVarKongDeclarativeConfig,
+		Value: path.Join(consts.DataPlaneDeclarativeConfigMountPath, consts.DataPlaneDeclarativeConfigFileName),
+	}}
+}
@@ -0,0 +1,103 @@
+package dataplane
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	"github.com/kong/gateway-operator/internal/consts"
+)
+
+// -----------------------------------------------------------------------------
+// DataPlane Utils - Kong Enterprise License
+// -----------------------------------------------------------------------------
+
+// licenseDateFormat is the format of the dates of the Kong Enterprise licenses.
+const licenseDateFormat = "2006-01-02"
+
+// License is a Kong Enterprise license.
+type License struct {
+	// Customer is the customer the license was issued to.
+	Customer string
+
+	// ExpirationTime is the time the license expires at: the licenses are valid
+	// until the end of their expiration date (in UTC).
+	ExpirationTime time.Time
+}
+
+// Expired returns whether the license is expired at the provided time.
+func (l License) Expired(now time.Time) bool {
+	return !now.Before(l.ExpirationTime)
+}
+
+// licenseDocument is the JSON document of a Kong Enterprise license.
+type licenseDocument struct {
+	License *struct {
+		Payload *struct {
+			Customer              string `json:"customer"`
+			LicenseKey            string `json:"license_key"`
+			LicenseExpirationDate string `json:"license_expiration_date"`
+		} `json:"payload"`
+		Signature string `json:"signature"`
+		Version   string `json:"version"`
+	} `json:"license"`
+}
+
+// ParseLicense parses a Kong Enterprise license. Its signature can only be
+// verified by Kong, hence only its structure is checked.
+func ParseLicense(data []byte) (License, error) {
+	var document licenseDocument
+	if err := json.Unmarshal(data, &document); err != nil {
+		return License{}, fmt.Errorf("license is not valid JSON: %w", err)
+	}
+
+	license := document.License
+	if license == nil || license.Payload == nil {
+		return License{}, fmt.Errorf("license must have a payload")
+	}
+	if license.Version == "" {
+		return License{}, fmt.Errorf("license must have a version")
+	}
+	if license.Signature == "" {
+		return License{}, fmt.Errorf("license must have a signature")
+	}
+	if _, err := hex.DecodeString(license.Signature); err != nil {
+		return License{}, fmt.Errorf("license signature must be hex encoded")
+	}
+	if license.Payload.LicenseKey == "" {
+		return License{}, fmt.Errorf("license must have a license_key")
+	}
+
+	expirationDate, err := time.Parse(licenseDateFormat, license.Payload.LicenseExpirationDate)
+	if err != nil {
+		return License{}, fmt.Errorf("license must have a license_expiration_date formatted as %s", licenseDateFormat)
+	}
+
+	return License{
+		Customer:       license.Payload.Customer,
+		ExpirationTime: expirationDate.AddDate(0, 0, 1),
+	}, nil
+}
+
+// LicenseEnvVars returns the environment variables setting the Kong Enterprise
+// license of the DataPlane from the Secret it references. No environment
+// variable is returned for the DataPlanes without license.
+func LicenseEnvVars(spec *operatorv1alpha1.DataPlaneDeploymentOptions) []corev1.EnvVar {
+	if spec.License == nil {
+		return nil
+	}
+
+	return []corev1.EnvVar{{
+		Name: consts.EnvVarKongLicenseData,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: spec.License.SecretRef,
+				Key:                  consts.DataPlaneLicenseSecretKey,
+			},
+		},
+	}}
+}
@@ -0,0 +1,78 @@
+package dataplane
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLicense(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		license  string
+		expected License
+		wantErr  string
+	}{
+		{
+			name:    "valid license",
+			license: `{"license":{"payload":{"admin_seats":"1","customer":"Kong","dataplanes":"1","license_creation_date":"2022-01-01","license_expiration_date":"2023-01-31","license_key":"ASDASDASDASDASDASDASDASDASD_a1VASASD","product_subscription":"Kong Enterprise Edition","support_plan":"None"},"signature":"6985968131533a967fcc721244a979948b1066967f1e9cd65dbd8eeabe060fc32d894a2945f5e4a03c1cd2198c74e058ac63d28b045c2f1fcec95877bd790e1b","version":"1"}}`,
+			expected: License{
+				Customer:       "Kong",
+				ExpirationTime: time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:    "invalid JSON",
+			license: `license`,
+			wantErr: "license is not valid JSON",
+		},
+		{
+			name:    "missing payload",
+			license: `{"license":{"signature":"6985","version":"1"}}`,
+			wantErr: "license must have a payload",
+		},
+		{
+			name:    "missing version",
+			license: `{"license":{"payload":{"license_expiration_date":"2023-01-31","license_key":"key"},"signature":"6985"}}`,
+			wantErr: "license must have a version",
+		},
+		{
+			name:    "missing signature",
+			license: `{"license":{"payload":{"license_expiration_date":"2023-01-31","license_key":"key"},"version":"1"}}`,
+			wantErr: "license must have a signature",
+		},
+		{
+			name:    "signature not hex encoded",
+			license: `{"license":{"payload":{"license_expiration_date":"2023-01-31","license_key":"key"},"signature":"signature","version":"1"}}`,
+			wantErr: "license signature must be hex encoded",
+		},
+		{
+			name:    "missing license key",
+			license: `{"license":{"payload":{"license_expiration_date":"2023-01-31"},"signature":"6985","version":"1"}}`,
+			wantErr: "license must have a license_key",
+		},
+		{
+			name:    "invalid expiration date",
+			license: `{"license":{"payload":{"license_expiration_date":"01/31/2023","license_key":"key"},"signature":"6985","version":"1"}}`,
+			wantErr: "license must have a license_expiration_date formatted as 2006-01-02",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			license, err := ParseLicense([]byte(tt.license))
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, license)
+		})
+	}
+}
+
+func TestLicenseExpired(t *testing.T) {
+	license := License{ExpirationTime: time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC)}
+	require.False(t, license.Expired(time.Date(2023, time.January, 31, 23, 59, 59, 0, time.UTC)))
+	require.True(t, license.Expired(time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC)))
+}
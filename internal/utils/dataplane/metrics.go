@@ -0,0 +1,105 @@
+package dataplane
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// -----------------------------------------------------------------------------
+// DataPlane Utils - Metrics
+// -----------------------------------------------------------------------------
+
+// httpStatusMetrics are the names of the counters of HTTP responses by status
+// code exposed by the Kong Prometheus plugin: kong_http_status up to Kong 2.8,
+// kong_http_requests_total from Kong 3.0 on.
+var httpStatusMetrics = []string{
+	"kong_http_status",
+	"kong_http_requests_total",
+}
+
+// HTTPResponseCounts is the number of HTTP responses served by a DataPlane.
+type HTTPResponseCounts struct {
+	// Total is the number of responses.
+	Total float64
+
+	// ServerErrors is the number of responses with a 5xx status code.
+	ServerErrors float64
+}
+
+// Add adds the provided counts to the counts.
+func (c *HTTPResponseCounts) Add(other HTTPResponseCounts) {
+	c.Total += other.Total
+	c.ServerErrors += other.ServerErrors
+}
+
+// ErrorRate returns the percentage of the responses with a 5xx status code,
+// or 0 if there were no responses.
+func (c HTTPResponseCounts) ErrorRate() float64 {
+	if c.Total == 0 {
+		return 0
+	}
+	return c.ServerErrors / c.Total * 100
+}
+
+// ParseHTTPResponseCounts parses the metrics exposed in the Prometheus text
+// format by Kong on its status port and returns the number of HTTP responses.
+func ParseHTTPResponseCounts(r io.Reader) (HTTPResponseCounts, error) {
+	var counts HTTPResponseCounts
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, rest, hasLabels := strings.Cut(line, "{")
+		if !hasLabels || !isHTTPStatusMetric(name) {
+			continue
+		}
+		labels, value, found := strings.Cut(rest, "}")
+		if !found {
+			return HTTPResponseCounts{}, fmt.Errorf("malformed metric: %s", line)
+		}
+		// the value can be followed by a timestamp
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			return HTTPResponseCounts{}, fmt.Errorf("missing value for metric: %s", line)
+		}
+		count, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return HTTPResponseCounts{}, fmt.Errorf("invalid value for metric %s: %w", line, err)
+		}
+
+		counts.Total += count
+		if strings.HasPrefix(getLabelValue(labels, "code"), "5") {
+			counts.ServerErrors += count
+		}
+	}
+
+	return counts, scanner.Err()
+}
+
+func isHTTPStatusMetric(name string) bool {
+	for _, metric := range httpStatusMetrics {
+		if name == metric {
+			return true
+		}
+	}
+	return false
+}
+
+// getLabelValue returns the value of the label from a list of labels in the
+// Prometheus text format, e.g. service="a",code="200".
+func getLabelValue(labels, name string) string {
+	for _, label := range strings.Split(labels, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(label), "=")
+		if found && key == name {
+			return strings.Trim(value, `"`)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,63 @@
+package dataplane
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHTTPResponseCounts(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		metrics  string
+		expected HTTPResponseCounts
+		wantErr  bool
+	}{
+		{
+			name: "no requests",
+			metrics: `# HELP kong_datastore_reachable Datastore reachable from Kong, 0 is unreachable
+# TYPE kong_datastore_reachable gauge
+kong_datastore_reachable 1
+`,
+		},
+		{
+			name: "kong 2.x metrics",
+			metrics: `# HELP kong_http_status HTTP status codes per service/route in Kong
+# TYPE kong_http_status counter
+kong_http_status{service="echo",route="echo",code="200"} 90
+kong_http_status{service="echo",route="echo",code="404"} 2
+kong_http_status{service="echo",route="echo",code="503"} 8
+kong_nginx_http_current_connections{state="accepted"} 10
+`,
+			expected: HTTPResponseCounts{Total: 100, ServerErrors: 8},
+		},
+		{
+			name: "kong 3.x metrics",
+			metrics: `# TYPE kong_http_requests_total counter
+kong_http_requests_total{service="echo",route="echo",code="200",source="service",consumer=""} 15
+kong_http_requests_total{service="echo",route="echo",code="502",source="kong",consumer=""} 5 1665000000000
+`,
+			expected: HTTPResponseCounts{Total: 20, ServerErrors: 5},
+		},
+		{
+			name:    "invalid value",
+			metrics: `kong_http_status{service="echo",route="echo",code="200"} many`,
+			wantErr: true,
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			counts, err := ParseHTTPResponseCounts(strings.NewReader(tt.metrics))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, counts)
+		})
+	}
+
+	require.Equal(t, float64(25), HTTPResponseCounts{Total: 20, ServerErrors: 5}.ErrorRate())
+	require.Zero(t, HTTPResponseCounts{}.ErrorRate())
+}
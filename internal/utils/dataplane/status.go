@@ -0,0 +1,60 @@
+package dataplane
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// -----------------------------------------------------------------------------
+// DataPlane Utils - Status
+// -----------------------------------------------------------------------------
+
+// KongStatus is the health of a Kong node, as reported on the /status
+// endpoint of its status port.
+type KongStatus struct {
+	// ConfigurationHash is the hash of the configuration loaded by a DB-less
+	// Kong node. It's empty when no configuration is loaded, and for the Kong
+	// nodes using a database.
+	ConfigurationHash string
+
+	// DatabaseReachable tells whether the Kong node reaches its database. It's
+	// always true for DB-less Kong nodes.
+	DatabaseReachable bool
+
+	// Workers is the number of Nginx worker processes of the Kong node.
+	Workers int
+}
+
+// kongStatusResponse is the subset of the /status response of Kong read by
+// the operator.
+type kongStatusResponse struct {
+	ConfigurationHash string `json:"configuration_hash"`
+	Database          struct {
+		Reachable bool `json:"reachable"`
+	} `json:"database"`
+	Memory struct {
+		WorkersLuaVMs []json.RawMessage `json:"workers_lua_vms"`
+	} `json:"memory"`
+}
+
+// ParseKongStatus parses the response of the /status endpoint of Kong.
+func ParseKongStatus(r io.Reader) (KongStatus, error) {
+	var resp kongStatusResponse
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return KongStatus{}, fmt.Errorf("invalid Kong status: %w", err)
+	}
+
+	status := KongStatus{
+		ConfigurationHash: resp.ConfigurationHash,
+		DatabaseReachable: resp.Database.Reachable,
+		Workers:           len(resp.Memory.WorkersLuaVMs),
+	}
+	// the DB-less Kong nodes which haven't loaded any configuration yet
+	// report a hash made of zeros.
+	if strings.Trim(status.ConfigurationHash, "0") == "" {
+		status.ConfigurationHash = ""
+	}
+	return status, nil
+}
@@ -0,0 +1,56 @@
+package dataplane
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKongStatus(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		status   string
+		expected KongStatus
+		wantErr  bool
+	}{
+		{
+			name: "db-less node with configuration",
+			status: `{
+  "configuration_hash": "779742c3d7afbc8d2d3cda5c8ef8d1da",
+  "database": {"reachable": true},
+  "memory": {
+    "workers_lua_vms": [{"http_allocated_gc": "37.44 MiB", "pid": 1258}, {"http_allocated_gc": "37.42 MiB", "pid": 1259}],
+    "lua_shared_dicts": {"kong": {"allocated_slabs": "0.04 MiB", "capacity": "5.00 MiB"}}
+  },
+  "server": {"connections_accepted": 10, "connections_active": 2}
+}`,
+			expected: KongStatus{ConfigurationHash: "779742c3d7afbc8d2d3cda5c8ef8d1da", DatabaseReachable: true, Workers: 2},
+		},
+		{
+			name:     "db-less node without configuration",
+			status:   `{"configuration_hash": "00000000000000000000000000000000", "database": {"reachable": true}, "memory": {"workers_lua_vms": [{"pid": 1258}]}}`,
+			expected: KongStatus{DatabaseReachable: true, Workers: 1},
+		},
+		{
+			name:     "node with an unreachable database",
+			status:   `{"database": {"reachable": false}, "memory": {"workers_lua_vms": [{"pid": 1258}]}}`,
+			expected: KongStatus{Workers: 1},
+		},
+		{
+			name:    "invalid status",
+			status:  `<html>`,
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			status, err := ParseKongStatus(strings.NewReader(tt.status))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, status)
+		})
+	}
+}
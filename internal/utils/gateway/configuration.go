@@ -0,0 +1,181 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	"github.com/kong/gateway-operator/internal/consts"
+)
+
+// -----------------------------------------------------------------------------
+// Gateway Utils - Public Functions - GatewayConfigurations
+// -----------------------------------------------------------------------------
+
+// GetGatewayConfigurationGenerations returns the generations of the
+// GatewayConfigurations the provided object has been configured with, keyed by
+// their namespace/name pairs. Malformed entries are ignored.
+func GetGatewayConfigurationGenerations(obj client.Object) map[types.NamespacedName]int64 {
+	value := obj.GetAnnotations()[consts.GatewayConfigurationGenerationsAnnotation]
+	if value == "" {
+		return nil
+	}
+
+	generations := make(map[types.NamespacedName]int64)
+	for _, entry := range strings.Split(value, ",") {
+		key, generation, ok := strings.Cut(entry, "=")
+		if !ok {
+			continue
+		}
+		namespace, name, ok := strings.Cut(key, "/")
+		if !ok {
+			continue
+		}
+		g, err := strconv.ParseInt(generation, 10, 64)
+		if err != nil {
+			continue
+		}
+		generations[types.NamespacedName{Namespace: namespace, Name: name}] = g
+	}
+	return generations
+}
+
+// SetGatewayConfigurationGenerations records the current generations of the
+// provided GatewayConfigurations on the object, replacing the ones recorded
+// before. The GatewayConfigurations without a name, which stand for the
+// default configuration, are not recorded. It returns true if the object has
+// been changed.
+func SetGatewayConfigurationGenerations(obj client.Object, gatewayConfigs ...*operatorv1alpha1.GatewayConfiguration) bool {
+	entries := make([]string, 0, len(gatewayConfigs))
+	for _, gatewayConfig := range gatewayConfigs {
+		if gatewayConfig == nil || gatewayConfig.Name == "" {
+			continue
+		}
+		entries = append(entries, fmt.Sprintf("%s=%d", client.ObjectKeyFromObject(gatewayConfig), gatewayConfig.Generation))
+	}
+	sort.Strings(entries)
+	value := strings.Join(entries, ",")
+
+	annotations := obj.GetAnnotations()
+	current, ok := annotations[consts.GatewayConfigurationGenerationsAnnotation]
+	switch {
+	case value == "" && !ok:
+		return false
+	case value == "":
+		delete(annotations, consts.GatewayConfigurationGenerationsAnnotation)
+	case value == current:
+		return false
+	default:
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[consts.GatewayConfigurationGenerationsAnnotation] = value
+	}
+	obj.SetAnnotations(annotations)
+	return true
+}
+
+// GatewayConfigurationTargetsGateway returns true if the GatewayConfiguration
+// is attached to the provided Gateway through its targetRef.
+func GatewayConfigurationTargetsGateway(gatewayConfig *operatorv1alpha1.GatewayConfiguration, gateway *gatewayv1alpha2.Gateway) bool {
+	targetRef := gatewayConfig.Spec.TargetRef
+	return targetRef != nil &&
+		(targetRef.Group == "" || targetRef.Group == gatewayv1alpha2.GroupName) &&
+		targetRef.Kind == operatorv1alpha1.GatewayConfigurationTargetKindGateway &&
+		targetRef.Name == gateway.Name &&
+		gatewayConfig.Namespace == gateway.Namespace
+}
+
+// ListGatewayConfigurationsAttachedToGateway is a helper function to map a
+// list of GatewayConfigurations attached to the provided Gateway through their
+// targetRef, ordered by precedence: the oldest GatewayConfiguration first, and
+// the one with the lowest name first for GatewayConfigurations created at the
+// same time.
+func ListGatewayConfigurationsAttachedToGateway(
+	ctx context.Context,
+	c client.Client,
+	gateway *gatewayv1alpha2.Gateway,
+) ([]operatorv1alpha1.GatewayConfiguration, error) {
+	gatewayConfigList := &operatorv1alpha1.GatewayConfigurationList{}
+	if err := c.List(ctx, gatewayConfigList, client.InNamespace(gateway.Namespace)); err != nil {
+		return nil, err
+	}
+
+	gatewayConfigs := make([]operatorv1alpha1.GatewayConfiguration, 0)
+	for _, gatewayConfig := range gatewayConfigList.Items {
+		if GatewayConfigurationTargetsGateway(&gatewayConfig, gateway) {
+			gatewayConfigs = append(gatewayConfigs, gatewayConfig)
+		}
+	}
+	sort.Slice(gatewayConfigs, func(i, j int) bool {
+		ti, tj := gatewayConfigs[i].CreationTimestamp, gatewayConfigs[j].CreationTimestamp
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		return gatewayConfigs[i].Name < gatewayConfigs[j].Name
+	})
+	return gatewayConfigs, nil
+}
+
+// ListDataPlanesForGatewayConfiguration is a helper function to map a list of
+// DataPlanes managed by the gateway controller which have been configured with
+// the provided GatewayConfiguration.
+func ListDataPlanesForGatewayConfiguration(
+	ctx context.Context,
+	c client.Client,
+	gatewayConfig *operatorv1alpha1.GatewayConfiguration,
+) ([]operatorv1alpha1.DataPlane, error) {
+	dataplaneList := &operatorv1alpha1.DataPlaneList{}
+	if err := c.List(
+		ctx,
+		dataplaneList,
+		client.MatchingLabels{consts.GatewayOperatorControlledLabel: consts.GatewayManagedLabelValue},
+	); err != nil {
+		return nil, err
+	}
+
+	key := client.ObjectKeyFromObject(gatewayConfig)
+	dataplanes := make([]operatorv1alpha1.DataPlane, 0)
+	for _, dataplane := range dataplaneList.Items {
+		dataplane := dataplane
+		if _, ok := GetGatewayConfigurationGenerations(&dataplane)[key]; ok {
+			dataplanes = append(dataplanes, dataplane)
+		}
+	}
+	return dataplanes, nil
+}
+
+// ListControlPlanesForGatewayConfiguration is a helper function to map a list
+// of ControlPlanes managed by the gateway controller which have been
+// configured with the provided GatewayConfiguration.
+func ListControlPlanesForGatewayConfiguration(
+	ctx context.Context,
+	c client.Client,
+	gatewayConfig *operatorv1alpha1.GatewayConfiguration,
+) ([]operatorv1alpha1.ControlPlane, error) {
+	controlplaneList := &operatorv1alpha1.ControlPlaneList{}
+	if err := c.List(
+		ctx,
+		controlplaneList,
+		client.MatchingLabels{consts.GatewayOperatorControlledLabel: consts.GatewayManagedLabelValue},
+	); err != nil {
+		return nil, err
+	}
+
+	key := client.ObjectKeyFromObject(gatewayConfig)
+	controlplanes := make([]operatorv1alpha1.ControlPlane, 0)
+	for _, controlplane := range controlplaneList.Items {
+		controlplane := controlplane
+		if _, ok := GetGatewayConfigurationGenerations(&controlplane)[key]; ok {
+			controlplanes = append(controlplanes, controlplane)
+		}
+	}
+	return controlplanes, nil
+}
@@ -0,0 +1,138 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	"github.com/kong/gateway-operator/internal/consts"
+)
+
+func TestGatewayConfigurationGenerations(t *testing.T) {
+	dataplane := &operatorv1alpha1.DataPlane{}
+	gatewayConfigA := &operatorv1alpha1.GatewayConfiguration{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-b", Name: "a", Generation: 2}}
+	gatewayConfigB := &operatorv1alpha1.GatewayConfiguration{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-a", Name: "b", Generation: 1}}
+
+	t.Log("recording the generations of the gatewayconfigurations")
+	require.True(t, SetGatewayConfigurationGenerations(dataplane, gatewayConfigA, gatewayConfigB))
+	require.False(t, SetGatewayConfigurationGenerations(dataplane, gatewayConfigB, gatewayConfigA))
+	require.Equal(t, "ns-a/b=1,ns-b/a=2", dataplane.Annotations[consts.GatewayConfigurationGenerationsAnnotation])
+	require.Equal(t, map[types.NamespacedName]int64{
+		{Namespace: "ns-a", Name: "b"}: 1,
+		{Namespace: "ns-b", Name: "a"}: 2,
+	}, GetGatewayConfigurationGenerations(dataplane))
+
+	t.Log("recording a new generation of a gatewayconfiguration")
+	gatewayConfigA.Generation = 3
+	require.True(t, SetGatewayConfigurationGenerations(dataplane, gatewayConfigA))
+	require.Equal(t, map[types.NamespacedName]int64{
+		{Namespace: "ns-b", Name: "a"}: 3,
+	}, GetGatewayConfigurationGenerations(dataplane))
+
+	t.Log("falling back to the default configuration")
+	require.True(t, SetGatewayConfigurationGenerations(dataplane, new(operatorv1alpha1.GatewayConfiguration)))
+	require.NotContains(t, dataplane.Annotations, consts.GatewayConfigurationGenerationsAnnotation)
+	require.False(t, SetGatewayConfigurationGenerations(dataplane))
+	require.Empty(t, GetGatewayConfigurationGenerations(dataplane))
+
+	t.Log("ignoring malformed entries")
+	dataplane.Annotations[consts.GatewayConfigurationGenerationsAnnotation] = "ns-a/b=1,ns-a/c,c=2,ns-a/d=x"
+	require.Equal(t, map[types.NamespacedName]int64{
+		{Namespace: "ns-a", Name: "b"}: 1,
+	}, GetGatewayConfigurationGenerations(dataplane))
+}
+
+func TestListDataPlanesForGatewayConfiguration(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, operatorv1alpha1.AddToScheme(scheme))
+
+	newDataPlane := func(namespace, name string, generations string) *operatorv1alpha1.DataPlane {
+		return &operatorv1alpha1.DataPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+				Labels: map[string]string{
+					consts.GatewayOperatorControlledLabel: consts.GatewayManagedLabelValue,
+				},
+				Annotations: map[string]string{
+					consts.GatewayConfigurationGenerationsAnnotation: generations,
+				},
+			},
+		}
+	}
+
+	c := fakeclient.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			newDataPlane("ns-a", "dataplane-1", "ns-config/config-1=1"),
+			newDataPlane("ns-b", "dataplane-1", "ns-config/config-1=2"),
+			newDataPlane("ns-a", "dataplane-2", "ns-config/config-2=1"),
+			newDataPlane("ns-a", "dataplane-3", ""),
+		).
+		Build()
+
+	for _, tt := range []struct {
+		name     string
+		config   string
+		expected int
+	}{
+		{name: "dataplanes of a gatewayconfiguration used by several gateways", config: "config-1", expected: 2},
+		{name: "dataplanes of a gatewayconfiguration used by one gateway", config: "config-2", expected: 1},
+		{name: "dataplanes of an unused gatewayconfiguration", config: "config-3", expected: 0},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			gatewayConfig := &operatorv1alpha1.GatewayConfiguration{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-config", Name: tt.config}}
+			dataplanes, err := ListDataPlanesForGatewayConfiguration(context.Background(), c, gatewayConfig)
+			require.NoError(t, err)
+			require.Len(t, dataplanes, tt.expected)
+		})
+	}
+}
+
+func TestListGatewayConfigurationsAttachedToGateway(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, operatorv1alpha1.AddToScheme(scheme))
+
+	now := metav1.Now()
+	before := metav1.NewTime(now.Add(-time.Hour))
+	newGatewayConfig := func(namespace, name string, created metav1.Time, targetRef *operatorv1alpha1.GatewayConfigurationTargetReference) *operatorv1alpha1.GatewayConfiguration {
+		return &operatorv1alpha1.GatewayConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, CreationTimestamp: created},
+			Spec:       operatorv1alpha1.GatewayConfigurationSpec{TargetRef: targetRef},
+		}
+	}
+	targetRef := func(kind operatorv1alpha1.GatewayConfigurationTargetKind, name string) *operatorv1alpha1.GatewayConfigurationTargetReference {
+		return &operatorv1alpha1.GatewayConfigurationTargetReference{Kind: kind, Name: name}
+	}
+
+	c := fakeclient.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			newGatewayConfig("default", "newer", now, targetRef(operatorv1alpha1.GatewayConfigurationTargetKindGateway, "kong")),
+			newGatewayConfig("default", "older-b", before, targetRef(operatorv1alpha1.GatewayConfigurationTargetKindGateway, "kong")),
+			newGatewayConfig("default", "older-a", before, targetRef(operatorv1alpha1.GatewayConfigurationTargetKindGateway, "kong")),
+			newGatewayConfig("default", "other-gateway", before, targetRef(operatorv1alpha1.GatewayConfigurationTargetKindGateway, "other")),
+			newGatewayConfig("default", "gatewayclass", before, targetRef(operatorv1alpha1.GatewayConfigurationTargetKindGatewayClass, "kong")),
+			newGatewayConfig("default", "untargeted", before, nil),
+			newGatewayConfig("other", "other-namespace", before, targetRef(operatorv1alpha1.GatewayConfigurationTargetKindGateway, "kong")),
+		).
+		Build()
+
+	gateway := &gatewayv1alpha2.Gateway{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kong"}}
+	gatewayConfigs, err := ListGatewayConfigurationsAttachedToGateway(context.Background(), c, gateway)
+	require.NoError(t, err)
+	names := make([]string, 0, len(gatewayConfigs))
+	for _, gatewayConfig := range gatewayConfigs {
+		names = append(names, gatewayConfig.Name)
+	}
+	require.Equal(t, []string{"older-a", "older-b", "newer"}, names)
+}
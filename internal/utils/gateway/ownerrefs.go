@@ -3,8 +3,10 @@ package gateway
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
@@ -96,13 +98,112 @@ func GetDataPlaneForControlPlane(
 		return nil, fmt.Errorf("%w, controlplane = %s/%s", operatorerrors.ErrDataPlaneNotSet, controlplane.Namespace, controlplane.Name)
 	}
 
+	return GetDataPlaneByNameForControlPlane(ctx, c, controlplane, *controlplane.Spec.DataPlane)
+}
+
+// GetDataPlaneByNameForControlPlane retrieves one of the DataPlane objects a
+// ControlPlane is responsible for, verifying that the reference is permitted
+// when the DataPlane lives in another namespace.
+func GetDataPlaneByNameForControlPlane(
+	ctx context.Context,
+	c client.Client,
+	controlplane *operatorv1alpha1.ControlPlane,
+	name string,
+) (*operatorv1alpha1.DataPlane, error) {
+	dataplaneNamespace := GetDataPlaneNamespaceForControlPlane(controlplane)
+	if dataplaneNamespace != controlplane.Namespace {
+		dataplaneName := gatewayv1alpha2.ObjectName(name)
+		granted, err := IsReferenceGranted(ctx, c,
+			gatewayv1alpha2.ReferenceGrantFrom{
+				Group:     gatewayv1alpha2.Group(operatorv1alpha1.SchemeGroupVersion.Group),
+				Kind:      "ControlPlane",
+				Namespace: gatewayv1alpha2.Namespace(controlplane.Namespace),
+			},
+			gatewayv1alpha2.ReferenceGrantTo{
+				Group: gatewayv1alpha2.Group(operatorv1alpha1.SchemeGroupVersion.Group),
+				Kind:  "DataPlane",
+				Name:  &dataplaneName,
+			},
+			dataplaneNamespace,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if !granted {
+			return nil, fmt.Errorf("%w, controlplane = %s/%s, dataplane = %s/%s", operatorerrors.ErrReferenceNotPermitted,
+				controlplane.Namespace, controlplane.Name, dataplaneNamespace, name)
+		}
+	}
+
 	dataplane := operatorv1alpha1.DataPlane{}
-	if err := c.Get(ctx, types.NamespacedName{Namespace: controlplane.Namespace, Name: *controlplane.Spec.DataPlane}, &dataplane); err != nil {
+	if err := c.Get(ctx, types.NamespacedName{Namespace: dataplaneNamespace, Name: name}, &dataplane); err != nil {
 		return nil, err
 	}
 	return &dataplane, nil
 }
 
+// ListDataPlaneNamesForControlPlane returns the names of all the DataPlane
+// objects a ControlPlane is responsible for: the DataPlane, the additional
+// DataPlanes and the DataPlanes matching the DataPlaneSelector. The DataPlane
+// comes first, followed by the others in alphabetical order.
+func ListDataPlaneNamesForControlPlane(
+	ctx context.Context,
+	c client.Client,
+	controlplane *operatorv1alpha1.ControlPlane,
+) ([]string, error) {
+	names := make([]string, 0, len(controlplane.Spec.DataPlanes)+1)
+	if controlplane.Spec.DataPlane != nil && *controlplane.Spec.DataPlane != "" {
+		names = append(names, *controlplane.Spec.DataPlane)
+	}
+
+	additionalNames := make([]string, 0, len(controlplane.Spec.DataPlanes))
+	additionalNames = append(additionalNames, controlplane.Spec.DataPlanes...)
+	if controlplane.Spec.DataPlaneSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(controlplane.Spec.DataPlaneSelector)
+		if err != nil {
+			return nil, err
+		}
+		dataplaneList := &operatorv1alpha1.DataPlaneList{}
+		if err := c.List(ctx, dataplaneList,
+			client.InNamespace(GetDataPlaneNamespaceForControlPlane(controlplane)),
+			client.MatchingLabelsSelector{Selector: selector},
+		); err != nil {
+			return nil, err
+		}
+		for _, dataplane := range dataplaneList.Items {
+			additionalNames = append(additionalNames, dataplane.Name)
+		}
+	}
+	sort.Strings(additionalNames)
+
+	for _, name := range additionalNames {
+		if name == "" {
+			continue
+		}
+		found := false
+		for _, existing := range names {
+			if existing == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			names = append(names, name)
+		}
+	}
+
+	return names, nil
+}
+
+// GetDataPlaneNamespaceForControlPlane returns the namespace of the DataPlane
+// referenced by a ControlPlane, defaulting to the ControlPlane's own namespace.
+func GetDataPlaneNamespaceForControlPlane(controlplane *operatorv1alpha1.ControlPlane) string {
+	if controlplane.Spec.DataPlaneNamespace != nil && *controlplane.Spec.DataPlaneNamespace != "" {
+		return *controlplane.Spec.DataPlaneNamespace
+	}
+	return controlplane.Namespace
+}
+
 // GetDataplaneServiceName is a helper functions that retrieves the name of the service owned by dataplane
 func GetDataplaneServiceName(
 	ctx context.Context,
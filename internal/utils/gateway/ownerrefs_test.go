@@ -0,0 +1,81 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+)
+
+func TestListDataPlaneNamesForControlPlane(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, operatorv1alpha1.AddToScheme(scheme))
+
+	c := fakeclient.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			newTestDataPlane("default", "edge-b", map[string]string{"tier": "edge"}),
+			newTestDataPlane("default", "edge-a", map[string]string{"tier": "edge"}),
+			newTestDataPlane("default", "internal", map[string]string{"tier": "internal"}),
+			newTestDataPlane("other", "edge-c", map[string]string{"tier": "edge"}),
+		).
+		Build()
+
+	primary := "primary"
+	otherNamespace := "other"
+	for _, tt := range []struct {
+		name     string
+		spec     operatorv1alpha1.ControlPlaneDeploymentOptions
+		expected []string
+	}{
+		{
+			name:     "no dataplanes",
+			expected: []string{},
+		},
+		{
+			name:     "single dataplane",
+			spec:     operatorv1alpha1.ControlPlaneDeploymentOptions{DataPlane: &primary},
+			expected: []string{"primary"},
+		},
+		{
+			name: "dataplane followed by the sorted additional dataplanes without duplicates",
+			spec: operatorv1alpha1.ControlPlaneDeploymentOptions{
+				DataPlane:  &primary,
+				DataPlanes: []string{"internal", "primary", "edge-a"},
+			},
+			expected: []string{"primary", "edge-a", "internal"},
+		},
+		{
+			name: "dataplanes matching the selector",
+			spec: operatorv1alpha1.ControlPlaneDeploymentOptions{
+				DataPlaneSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "edge"}},
+				DataPlanes:        []string{"edge-a"},
+			},
+			expected: []string{"edge-a", "edge-b"},
+		},
+		{
+			name: "dataplanes matching the selector in the dataplane namespace",
+			spec: operatorv1alpha1.ControlPlaneDeploymentOptions{
+				DataPlaneNamespace: &otherNamespace,
+				DataPlaneSelector:  &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "edge"}},
+			},
+			expected: []string{"edge-c"},
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			controlplane := &operatorv1alpha1.ControlPlane{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kong"},
+				Spec:       operatorv1alpha1.ControlPlaneSpec{ControlPlaneDeploymentOptions: tt.spec},
+			}
+			names, err := ListDataPlaneNamesForControlPlane(context.Background(), c, controlplane)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, names)
+		})
+	}
+}
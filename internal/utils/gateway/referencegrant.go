@@ -0,0 +1,85 @@
+package gateway
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// -----------------------------------------------------------------------------
+// Gateway Utils - Public Functions - ReferenceGrants
+// -----------------------------------------------------------------------------
+
+// IsReferenceGranted indicates whether a reference from an object identified by
+// from to the object identified by to, living in the toNamespace namespace, is
+// permitted. References within a single namespace are always permitted, while
+// cross-namespace references require a ReferenceGrant in the target namespace
+// which lists both the referrer and the referenced object.
+func IsReferenceGranted(
+	ctx context.Context,
+	c client.Client,
+	from gatewayv1alpha2.ReferenceGrantFrom,
+	to gatewayv1alpha2.ReferenceGrantTo,
+	toNamespace string,
+) (bool, error) {
+	if string(from.Namespace) == toNamespace {
+		return true, nil
+	}
+
+	referenceGrantList := &gatewayv1alpha2.ReferenceGrantList{}
+	if err := c.List(ctx, referenceGrantList, client.InNamespace(toNamespace)); err != nil {
+		// when the ReferenceGrant CRD is not installed in the cluster there
+		// are no grants, hence no cross-namespace reference is permitted.
+		if meta.IsNoMatchError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	for _, referenceGrant := range referenceGrantList.Items {
+		if referenceGrantPermits(referenceGrant, from, to) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// referenceGrantPermits returns true if the provided ReferenceGrant lists the
+// referrer in its From section and the referenced object in its To section.
+func referenceGrantPermits(
+	referenceGrant gatewayv1alpha2.ReferenceGrant,
+	from gatewayv1alpha2.ReferenceGrantFrom,
+	to gatewayv1alpha2.ReferenceGrantTo,
+) bool {
+	fromPermitted := false
+	for _, grantFrom := range referenceGrant.Spec.From {
+		if grantFrom.Group == from.Group &&
+			grantFrom.Kind == from.Kind &&
+			grantFrom.Namespace == from.Namespace {
+			fromPermitted = true
+			break
+		}
+	}
+	if !fromPermitted {
+		return false
+	}
+
+	for _, grantTo := range referenceGrant.Spec.To {
+		if grantTo.Group != to.Group || grantTo.Kind != to.Kind {
+			continue
+		}
+		// a ReferenceGrant with no name targets all the objects of the given
+		// group and kind in its namespace.
+		if grantTo.Name == nil || *grantTo.Name == "" {
+			return true
+		}
+		if to.Name != nil && *grantTo.Name == *to.Name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,122 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+func TestIsReferenceGranted(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, gatewayv1alpha2.AddToScheme(scheme))
+
+	secretName := gatewayv1alpha2.ObjectName("cert")
+	otherSecretName := gatewayv1alpha2.ObjectName("other-cert")
+
+	fromGateway := gatewayv1alpha2.ReferenceGrantFrom{
+		Group:     gatewayv1alpha2.GroupName,
+		Kind:      "Gateway",
+		Namespace: "gateways",
+	}
+
+	client := fakeclient.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			&gatewayv1alpha2.ReferenceGrant{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "certs", Name: "allow-gateways"},
+				Spec: gatewayv1alpha2.ReferenceGrantSpec{
+					From: []gatewayv1alpha2.ReferenceGrantFrom{fromGateway},
+					To: []gatewayv1alpha2.ReferenceGrantTo{{
+						Group: "",
+						Kind:  "Secret",
+						Name:  &secretName,
+					}},
+				},
+			},
+			&gatewayv1alpha2.ReferenceGrant{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "all-certs", Name: "allow-gateways"},
+				Spec: gatewayv1alpha2.ReferenceGrantSpec{
+					From: []gatewayv1alpha2.ReferenceGrantFrom{fromGateway},
+					To: []gatewayv1alpha2.ReferenceGrantTo{{
+						Group: "",
+						Kind:  "Secret",
+					}},
+				},
+			},
+		).
+		Build()
+
+	for _, tt := range []struct {
+		name        string
+		from        gatewayv1alpha2.ReferenceGrantFrom
+		to          gatewayv1alpha2.ReferenceGrantTo
+		toNamespace string
+		granted     bool
+	}{
+		{
+			name:        "references in the same namespace are always granted",
+			from:        fromGateway,
+			to:          gatewayv1alpha2.ReferenceGrantTo{Kind: "Secret", Name: &otherSecretName},
+			toNamespace: "gateways",
+			granted:     true,
+		},
+		{
+			name:        "cross-namespace reference to a granted object name is granted",
+			from:        fromGateway,
+			to:          gatewayv1alpha2.ReferenceGrantTo{Kind: "Secret", Name: &secretName},
+			toNamespace: "certs",
+			granted:     true,
+		},
+		{
+			name:        "cross-namespace reference to a different object name is not granted",
+			from:        fromGateway,
+			to:          gatewayv1alpha2.ReferenceGrantTo{Kind: "Secret", Name: &otherSecretName},
+			toNamespace: "certs",
+			granted:     false,
+		},
+		{
+			name:        "cross-namespace reference is granted by a grant without a name",
+			from:        fromGateway,
+			to:          gatewayv1alpha2.ReferenceGrantTo{Kind: "Secret", Name: &otherSecretName},
+			toNamespace: "all-certs",
+			granted:     true,
+		},
+		{
+			name: "cross-namespace reference from a namespace not listed in the grant is not granted",
+			from: gatewayv1alpha2.ReferenceGrantFrom{
+				Group:     gatewayv1alpha2.GroupName,
+				Kind:      "Gateway",
+				Namespace: "other",
+			},
+			to:          gatewayv1alpha2.ReferenceGrantTo{Kind: "Secret", Name: &secretName},
+			toNamespace: "certs",
+			granted:     false,
+		},
+		{
+			name:        "cross-namespace reference to a different kind is not granted",
+			from:        fromGateway,
+			to:          gatewayv1alpha2.ReferenceGrantTo{Kind: "ConfigMap", Name: &secretName},
+			toNamespace: "certs",
+			granted:     false,
+		},
+		{
+			name:        "cross-namespace reference to a namespace without grants is not granted",
+			from:        fromGateway,
+			to:          gatewayv1alpha2.ReferenceGrantTo{Kind: "Secret", Name: &secretName},
+			toNamespace: "empty",
+			granted:     false,
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			granted, err := IsReferenceGranted(context.Background(), client, tt.from, tt.to, tt.toNamespace)
+			require.NoError(t, err)
+			require.Equal(t, tt.granted, granted)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package gateway
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	"github.com/kong/gateway-operator/internal/consts"
+)
+
+// -----------------------------------------------------------------------------
+// Gateway Utils - Public Functions - Shared DataPlanes
+// -----------------------------------------------------------------------------
+
+// LabelObjectAsShared ensures that labels are set on the provided object to
+// signal that it's shared between the Gateways identified by the provided key.
+func LabelObjectAsShared(obj client.Object, key string) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[consts.GatewaySharedDataPlaneLabel] = key
+	obj.SetLabels(labels)
+}
+
+// ListSharedDataPlanes is a helper function to map a list of DataPlanes that
+// are shared between Gateways and managed by the gateway controller. An empty
+// namespace lists the DataPlanes across all the namespaces, while an empty key
+// lists the DataPlanes shared by the Gateways of any GatewayClass.
+func ListSharedDataPlanes(
+	ctx context.Context,
+	c client.Client,
+	namespace, key string,
+) ([]operatorv1alpha1.DataPlane, error) {
+	dataplaneList := &operatorv1alpha1.DataPlaneList{}
+	if err := c.List(ctx, dataplaneList, sharedListOptions(namespace, key)...); err != nil {
+		return nil, err
+	}
+	return dataplaneList.Items, nil
+}
+
+// ListSharedControlPlanes is a helper function to map a list of ControlPlanes
+// that are shared between Gateways and managed by the gateway controller. An
+// empty key lists the ControlPlanes shared by the Gateways of any GatewayClass.
+func ListSharedControlPlanes(
+	ctx context.Context,
+	c client.Client,
+	namespace, key string,
+) ([]operatorv1alpha1.ControlPlane, error) {
+	controlplaneList := &operatorv1alpha1.ControlPlaneList{}
+	if err := c.List(ctx, controlplaneList, sharedListOptions(namespace, key)...); err != nil {
+		return nil, err
+	}
+	return controlplaneList.Items, nil
+}
+
+// ListSharedNetworkPolicies is a helper function to map a list of NetworkPolicies
+// that are shared between Gateways and managed by the gateway controller. An
+// empty key lists the NetworkPolicies shared by the Gateways of any GatewayClass.
+func ListSharedNetworkPolicies(
+	ctx context.Context,
+	c client.Client,
+	namespace, key string,
+) ([]networkingv1.NetworkPolicy, error) {
+	networkPolicyList := &networkingv1.NetworkPolicyList{}
+	if err := c.List(ctx, networkPolicyList, sharedListOptions(namespace, key)...); err != nil {
+		return nil, err
+	}
+	return networkPolicyList.Items, nil
+}
+
+// GetSharedDataPlaneGateways returns the namespace/name pairs of the Gateways
+// which are using the provided shared DataPlane.
+func GetSharedDataPlaneGateways(dataplane *operatorv1alpha1.DataPlane) []string {
+	value := dataplane.Annotations[consts.GatewaySharedDataPlaneGatewaysAnnotation]
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// AddSharedDataPlaneGateway records the provided Gateway as a user of the
+// shared DataPlane. It returns true if the DataPlane has been changed.
+func AddSharedDataPlaneGateway(dataplane *operatorv1alpha1.DataPlane, gateway *gatewayv1alpha2.Gateway) bool {
+	key := client.ObjectKeyFromObject(gateway).String()
+	gateways := GetSharedDataPlaneGateways(dataplane)
+	for _, g := range gateways {
+		if g == key {
+			return false
+		}
+	}
+	setSharedDataPlaneGateways(dataplane, append(gateways, key))
+	return true
+}
+
+// RemoveSharedDataPlaneGateway removes the provided Gateway from the users of
+// the shared DataPlane. It returns true if the DataPlane has been changed.
+func RemoveSharedDataPlaneGateway(dataplane *operatorv1alpha1.DataPlane, gateway *gatewayv1alpha2.Gateway) bool {
+	key := client.ObjectKeyFromObject(gateway).String()
+	gateways := GetSharedDataPlaneGateways(dataplane)
+	remaining := make([]string, 0, len(gateways))
+	for _, g := range gateways {
+		if g != key {
+			remaining = append(remaining, g)
+		}
+	}
+	if len(remaining) == len(gateways) {
+		return false
+	}
+	setSharedDataPlaneGateways(dataplane, remaining)
+	return true
+}
+
+// -----------------------------------------------------------------------------
+// Gateway Utils - Private Functions - Shared DataPlanes
+// -----------------------------------------------------------------------------
+
+func sharedListOptions(namespace, key string) []client.ListOption {
+	opts := []client.ListOption{
+		client.MatchingLabels{consts.GatewayOperatorControlledLabel: consts.GatewayManagedLabelValue},
+	}
+	if key == "" {
+		opts = append(opts, client.HasLabels{consts.GatewaySharedDataPlaneLabel})
+	} else {
+		opts = append(opts, client.MatchingLabels{consts.GatewaySharedDataPlaneLabel: key})
+	}
+	if namespace != "" {
+		opts = append(opts, client.InNamespace(namespace))
+	}
+	return opts
+}
+
+func setSharedDataPlaneGateways(dataplane *operatorv1alpha1.DataPlane, gateways []string) {
+	sort.Strings(gateways)
+	if dataplane.Annotations == nil {
+		dataplane.Annotations = make(map[string]string)
+	}
+	dataplane.Annotations[consts.GatewaySharedDataPlaneGatewaysAnnotation] = strings.Join(gateways, ",")
+}
@@ -0,0 +1,82 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	"github.com/kong/gateway-operator/internal/consts"
+)
+
+func TestSharedDataPlaneGateways(t *testing.T) {
+	dataplane := &operatorv1alpha1.DataPlane{}
+	gatewayA := &gatewayv1alpha2.Gateway{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-b", Name: "a"}}
+	gatewayB := &gatewayv1alpha2.Gateway{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-a", Name: "b"}}
+
+	t.Log("recording the gateways using the shared dataplane")
+	require.True(t, AddSharedDataPlaneGateway(dataplane, gatewayA))
+	require.True(t, AddSharedDataPlaneGateway(dataplane, gatewayB))
+	require.False(t, AddSharedDataPlaneGateway(dataplane, gatewayA))
+	require.Equal(t, []string{"ns-a/b", "ns-b/a"}, GetSharedDataPlaneGateways(dataplane))
+
+	t.Log("releasing the shared dataplane")
+	require.True(t, RemoveSharedDataPlaneGateway(dataplane, gatewayA))
+	require.False(t, RemoveSharedDataPlaneGateway(dataplane, gatewayA))
+	require.Equal(t, []string{"ns-a/b"}, GetSharedDataPlaneGateways(dataplane))
+	require.True(t, RemoveSharedDataPlaneGateway(dataplane, gatewayB))
+	require.Empty(t, GetSharedDataPlaneGateways(dataplane))
+}
+
+func TestListSharedDataPlanes(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, operatorv1alpha1.AddToScheme(scheme))
+
+	sharedLabels := func(key string) map[string]string {
+		return map[string]string{
+			consts.GatewayOperatorControlledLabel: consts.GatewayManagedLabelValue,
+			consts.GatewaySharedDataPlaneLabel:    key,
+		}
+	}
+
+	c := fakeclient.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			newTestDataPlane("ns-a", "shared-1", sharedLabels("class-1")),
+			newTestDataPlane("ns-b", "shared-1", sharedLabels("class-1")),
+			newTestDataPlane("ns-a", "shared-2", sharedLabels("class-2")),
+			newTestDataPlane("ns-a", "dedicated", map[string]string{
+				consts.GatewayOperatorControlledLabel: consts.GatewayManagedLabelValue,
+			}),
+		).
+		Build()
+
+	for _, tt := range []struct {
+		name      string
+		namespace string
+		key       string
+		expected  int
+	}{
+		{name: "dataplanes of a class in a namespace", namespace: "ns-a", key: "class-1", expected: 1},
+		{name: "dataplanes of a class in all namespaces", key: "class-1", expected: 2},
+		{name: "dataplanes of all classes in a namespace", namespace: "ns-a", expected: 2},
+		{name: "dataplanes of all classes in all namespaces", expected: 3},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			dataplanes, err := ListSharedDataPlanes(context.Background(), c, tt.namespace, tt.key)
+			require.NoError(t, err)
+			require.Len(t, dataplanes, tt.expected)
+		})
+	}
+}
+
+// newTestDataPlane returns a DataPlane with the provided labels.
+func newTestDataPlane(namespace, name string, labels map[string]string) *operatorv1alpha1.DataPlane {
+	return &operatorv1alpha1.DataPlane{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels}}
+}
@@ -0,0 +1,91 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+)
+
+// -----------------------------------------------------------------------------
+// Gateway Utils - Snapshots
+// -----------------------------------------------------------------------------
+
+// SnapshotFormatVersion is the version of the format of the Gateway snapshots.
+const SnapshotFormatVersion = "v1"
+
+// Snapshot is the state of a Gateway backed up by a GatewayBackup. The objects
+// only keep the metadata which can be restored: their identity, labels and
+// annotations.
+type Snapshot struct {
+	// FormatVersion is the version of the format of the snapshot.
+	FormatVersion string `json:"formatVersion"`
+
+	// Gateway is the backed up Gateway.
+	Gateway *gatewayv1alpha2.Gateway `json:"gateway"`
+
+	// GatewayClass is the GatewayClass of the Gateway.
+	GatewayClass *gatewayv1alpha2.GatewayClass `json:"gatewayClass,omitempty"`
+
+	// GatewayConfiguration is the GatewayConfiguration referenced by the
+	// GatewayClass.
+	GatewayConfiguration *operatorv1alpha1.GatewayConfiguration `json:"gatewayConfiguration,omitempty"`
+
+	// DataPlane is the DataPlane owned by the Gateway.
+	DataPlane *operatorv1alpha1.DataPlane `json:"dataplane,omitempty"`
+
+	// ControlPlane is the ControlPlane owned by the Gateway.
+	ControlPlane *operatorv1alpha1.ControlPlane `json:"controlplane,omitempty"`
+
+	// DataPlaneServices are the Services owned by the DataPlane.
+	DataPlaneServices []corev1.Service `json:"dataplaneServices,omitempty"`
+
+	// DataPlaneCertificate is the Secret holding the certificate issued for
+	// the DataPlane.
+	DataPlaneCertificate *corev1.Secret `json:"dataplaneCertificate,omitempty"`
+
+	// ControlPlaneCertificate is the Secret holding the certificate issued for
+	// the ControlPlane.
+	ControlPlaneCertificate *corev1.Secret `json:"controlplaneCertificate,omitempty"`
+
+	// KongConfig is the declarative configuration Kong was running, as
+	// returned by the Kong Admin API of the DataPlane.
+	KongConfig string `json:"kongConfig,omitempty"`
+}
+
+// EncodeSnapshot encodes a snapshot, indented for it to be read line by line.
+func EncodeSnapshot(snapshot *Snapshot) ([]byte, error) {
+	snapshot.FormatVersion = SnapshotFormatVersion
+	return json.MarshalIndent(snapshot, "", "  ")
+}
+
+// DecodeSnapshot decodes a snapshot and checks its format.
+func DecodeSnapshot(data []byte) (*Snapshot, error) {
+	snapshot := &Snapshot{}
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return nil, fmt.Errorf("snapshot is not valid JSON: %w", err)
+	}
+	if snapshot.FormatVersion != SnapshotFormatVersion {
+		return nil, fmt.Errorf("unsupported snapshot format version %q, expected %q", snapshot.FormatVersion, SnapshotFormatVersion)
+	}
+	if snapshot.Gateway == nil {
+		return nil, fmt.Errorf("snapshot must have a gateway")
+	}
+	return snapshot, nil
+}
+
+// SnapshotObjectMeta returns the metadata of an object which is kept in a
+// snapshot: the metadata set by the API server and the owner references
+// can't be restored as such.
+func SnapshotObjectMeta(meta metav1.ObjectMeta) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Namespace:   meta.Namespace,
+		Name:        meta.Name,
+		Labels:      meta.Labels,
+		Annotations: meta.Annotations,
+	}
+}
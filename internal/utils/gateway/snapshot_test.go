@@ -0,0 +1,50 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+func TestSnapshotEncoding(t *testing.T) {
+	snapshot := &Snapshot{
+		Gateway: &gatewayv1alpha2.Gateway{
+			ObjectMeta: SnapshotObjectMeta(metav1.ObjectMeta{
+				Namespace:       "default",
+				Name:            "kong",
+				UID:             "gateway-uid",
+				ResourceVersion: "42",
+				Labels:          map[string]string{"app": "kong"},
+			}),
+			Spec: gatewayv1alpha2.GatewaySpec{GatewayClassName: "kong"},
+		},
+		KongConfig: `{"_format_version":"3.0"}`,
+	}
+	require.Empty(t, snapshot.Gateway.UID)
+	require.Empty(t, snapshot.Gateway.ResourceVersion)
+
+	data, err := EncodeSnapshot(snapshot)
+	require.NoError(t, err)
+
+	decoded, err := DecodeSnapshot(data)
+	require.NoError(t, err)
+	require.Equal(t, SnapshotFormatVersion, decoded.FormatVersion)
+	require.Equal(t, snapshot.Gateway, decoded.Gateway)
+	require.Equal(t, snapshot.KongConfig, decoded.KongConfig)
+
+	for _, tc := range []struct {
+		name string
+		data string
+	}{
+		{name: "invalid JSON", data: `{`},
+		{name: "unsupported version", data: `{"formatVersion":"v0","gateway":{}}`},
+		{name: "missing gateway", data: `{"formatVersion":"v1"}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := DecodeSnapshot([]byte(tc.data))
+			require.Error(t, err)
+		})
+	}
+}
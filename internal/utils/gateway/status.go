@@ -25,12 +25,13 @@ func IsScheduled(gateway *gatewayv1alpha2.Gateway) bool {
 }
 
 // IsReady indicates whether or not the provided Gateway object was
-// marked as ready by the controller.
+// marked as ready by the controller for its current generation.
 func IsReady(gateway *gatewayv1alpha2.Gateway) bool {
 	for _, cond := range gateway.Status.Conditions {
 		if cond.Type == string(gatewayv1alpha2.GatewayConditionReady) &&
 			cond.Reason == string(kubernetes.ResourceReadyReason) &&
-			cond.Status == metav1.ConditionTrue {
+			cond.Status == metav1.ConditionTrue &&
+			cond.ObservedGeneration >= gateway.Generation {
 			return true
 		}
 	}
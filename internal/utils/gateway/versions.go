@@ -0,0 +1,202 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// -----------------------------------------------------------------------------
+// Gateway Utils - Public Functions - Gateway API Versions
+// -----------------------------------------------------------------------------
+
+// GatewayForVersion returns an empty Gateway object of the Gateway API version
+// served by the cluster, which can be used to set up watches.
+func GatewayForVersion(v1beta1Enabled bool) client.Object {
+	if v1beta1Enabled {
+		return &gatewayv1beta1.Gateway{}
+	}
+	return &gatewayv1alpha2.Gateway{}
+}
+
+// GatewayClassForVersion returns an empty GatewayClass object of the Gateway API
+// version served by the cluster, which can be used to set up watches.
+func GatewayClassForVersion(v1beta1Enabled bool) client.Object {
+	if v1beta1Enabled {
+		return &gatewayv1beta1.GatewayClass{}
+	}
+	return &gatewayv1alpha2.GatewayClass{}
+}
+
+// AsV1alpha2Gateway returns the provided object as a v1alpha2 Gateway,
+// converting it when it is a v1beta1 Gateway. It returns false if the object
+// is not a Gateway.
+func AsV1alpha2Gateway(obj client.Object) (*gatewayv1alpha2.Gateway, bool) {
+	switch gateway := obj.(type) {
+	case *gatewayv1alpha2.Gateway:
+		return gateway, true
+	case *gatewayv1beta1.Gateway:
+		converted := new(gatewayv1alpha2.Gateway)
+		if err := convertGatewayAPIObject(gateway, converted); err != nil {
+			return nil, false
+		}
+		return converted, true
+	default:
+		return nil, false
+	}
+}
+
+// AsV1alpha2GatewayClass returns the provided object as a v1alpha2 GatewayClass,
+// converting it when it is a v1beta1 GatewayClass. It returns false if the
+// object is not a GatewayClass.
+func AsV1alpha2GatewayClass(obj client.Object) (*gatewayv1alpha2.GatewayClass, bool) {
+	switch gatewayClass := obj.(type) {
+	case *gatewayv1alpha2.GatewayClass:
+		return gatewayClass, true
+	case *gatewayv1beta1.GatewayClass:
+		converted := new(gatewayv1alpha2.GatewayClass)
+		if err := convertGatewayAPIObject(gatewayClass, converted); err != nil {
+			return nil, false
+		}
+		return converted, true
+	default:
+		return nil, false
+	}
+}
+
+// NewV1beta1Client wraps the provided client so that Gateways, GatewayClasses
+// and HTTPRoutes are read from and written to the API server using their
+// v1beta1 version, while callers keep working with the v1alpha2 types. It must be used when the
+// cluster has the graduated Gateway API CRDs installed.
+func NewV1beta1Client(c client.Client) client.Client {
+	return &v1beta1Client{Client: c}
+}
+
+// -----------------------------------------------------------------------------
+// Gateway Utils - Private Functions - Gateway API Versions
+// -----------------------------------------------------------------------------
+
+// v1beta1Client is a client.Client which translates v1alpha2 Gateways,
+// GatewayClasses and HTTPRoutes to their v1beta1 counterparts.
+type v1beta1Client struct {
+	client.Client
+}
+
+func (c *v1beta1Client) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	converted, ok := v1beta1ObjectFor(obj)
+	if !ok {
+		return c.Client.Get(ctx, key, obj)
+	}
+	if err := c.Client.Get(ctx, key, converted); err != nil {
+		return err
+	}
+	return convertGatewayAPIObject(converted, obj)
+}
+
+func (c *v1beta1Client) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	var converted client.ObjectList
+	switch list.(type) {
+	case *gatewayv1alpha2.GatewayList:
+		converted = &gatewayv1beta1.GatewayList{}
+	case *gatewayv1alpha2.GatewayClassList:
+		converted = &gatewayv1beta1.GatewayClassList{}
+	case *gatewayv1alpha2.HTTPRouteList:
+		converted = &gatewayv1beta1.HTTPRouteList{}
+	default:
+		return c.Client.List(ctx, list, opts...)
+	}
+	if err := c.Client.List(ctx, converted, opts...); err != nil {
+		return err
+	}
+	return convertGatewayAPIObject(converted, list)
+}
+
+func (c *v1beta1Client) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	return c.write(obj, func(o client.Object) error { return c.Client.Create(ctx, o, opts...) })
+}
+
+func (c *v1beta1Client) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	return c.write(obj, func(o client.Object) error { return c.Client.Delete(ctx, o, opts...) })
+}
+
+func (c *v1beta1Client) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	return c.write(obj, func(o client.Object) error { return c.Client.Update(ctx, o, opts...) })
+}
+
+func (c *v1beta1Client) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return c.write(obj, func(o client.Object) error { return c.Client.Patch(ctx, o, patch, opts...) })
+}
+
+func (c *v1beta1Client) Status() client.StatusWriter {
+	return &v1beta1StatusWriter{client: c, StatusWriter: c.Client.Status()}
+}
+
+// write converts the provided object to its v1beta1 counterpart (if it has
+// one), runs the write operation and converts the result back.
+func (c *v1beta1Client) write(obj client.Object, op func(client.Object) error) error {
+	converted, ok := v1beta1ObjectFor(obj)
+	if !ok {
+		return op(obj)
+	}
+	if err := convertGatewayAPIObject(obj, converted); err != nil {
+		return err
+	}
+	// the object might carry the v1alpha2 type information, which the API server
+	// would reject when served through the v1beta1 endpoint.
+	converted.GetObjectKind().SetGroupVersionKind(
+		gatewayv1beta1.SchemeGroupVersion.WithKind(reflect.TypeOf(converted).Elem().Name()),
+	)
+	if err := op(converted); err != nil {
+		return err
+	}
+	return convertGatewayAPIObject(converted, obj)
+}
+
+// v1beta1StatusWriter is a client.StatusWriter which translates v1alpha2
+// Gateways, GatewayClasses and HTTPRoutes to their v1beta1 counterparts.
+type v1beta1StatusWriter struct {
+	client.StatusWriter
+	client *v1beta1Client
+}
+
+func (w *v1beta1StatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	return w.client.write(obj, func(o client.Object) error { return w.StatusWriter.Update(ctx, o, opts...) })
+}
+
+func (w *v1beta1StatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return w.client.write(obj, func(o client.Object) error { return w.StatusWriter.Patch(ctx, o, patch, opts...) })
+}
+
+// v1beta1ObjectFor returns an empty v1beta1 counterpart of the provided v1alpha2
+// Gateway API object, or false if the object has no v1beta1 counterpart.
+func v1beta1ObjectFor(obj client.Object) (client.Object, bool) {
+	switch obj.(type) {
+	case *gatewayv1alpha2.Gateway:
+		return &gatewayv1beta1.Gateway{}, true
+	case *gatewayv1alpha2.GatewayClass:
+		return &gatewayv1beta1.GatewayClass{}, true
+	case *gatewayv1alpha2.HTTPRoute:
+		return &gatewayv1beta1.HTTPRoute{}, true
+	default:
+		return nil, false
+	}
+}
+
+// convertGatewayAPIObject converts between the v1alpha2 and v1beta1 versions of
+// a Gateway API object. Both versions share the same schema, hence the
+// conversion is a plain round trip through their JSON representation.
+func convertGatewayAPIObject(from, to interface{}) error {
+	b, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+
+	// reset the target first, as unmarshalling merges maps into existing values.
+	target := reflect.ValueOf(to).Elem()
+	target.Set(reflect.Zero(target.Type()))
+	return json.Unmarshal(b, to)
+}
@@ -0,0 +1,109 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func TestV1beta1Client(t *testing.T) {
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, gatewayv1alpha2.AddToScheme(scheme))
+	require.NoError(t, gatewayv1beta1.AddToScheme(scheme))
+
+	fakeClient := fakeclient.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			&gatewayv1beta1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "kong"},
+				Spec: gatewayv1beta1.GatewayClassSpec{
+					ControllerName: "konghq.com/gateway-operator",
+				},
+			},
+			&gatewayv1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "echo"},
+				Spec: gatewayv1beta1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayv1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayv1beta1.ParentReference{{Name: "kong"}},
+					},
+					Hostnames: []gatewayv1beta1.Hostname{"echo.example.com"},
+				},
+			},
+			&gatewayv1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kong"},
+				Spec: gatewayv1beta1.GatewaySpec{
+					GatewayClassName: "kong",
+					Listeners: []gatewayv1beta1.Listener{{
+						Name:     "http",
+						Protocol: gatewayv1beta1.HTTPProtocolType,
+						Port:     80,
+					}},
+				},
+			},
+		).
+		Build()
+	c := NewV1beta1Client(fakeClient)
+
+	t.Log("reading a v1beta1 Gateway as a v1alpha2 Gateway")
+	gateway := new(gatewayv1alpha2.Gateway)
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "kong"}, gateway))
+	require.Equal(t, gatewayv1alpha2.ObjectName("kong"), gateway.Spec.GatewayClassName)
+	require.Len(t, gateway.Spec.Listeners, 1)
+	require.Equal(t, gatewayv1alpha2.PortNumber(80), gateway.Spec.Listeners[0].Port)
+
+	t.Log("writing the status of a v1alpha2 Gateway to the v1beta1 Gateway")
+	gateway.Status.Conditions = []metav1.Condition{{
+		Type:               string(gatewayv1alpha2.GatewayConditionScheduled),
+		Status:             metav1.ConditionTrue,
+		Reason:             string(gatewayv1alpha2.GatewayReasonScheduled),
+		LastTransitionTime: metav1.Now(),
+	}}
+	require.NoError(t, c.Status().Update(ctx, gateway))
+
+	storedGateway := new(gatewayv1beta1.Gateway)
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "kong"}, storedGateway))
+	require.Len(t, storedGateway.Status.Conditions, 1)
+	require.Equal(t, string(gatewayv1beta1.GatewayConditionScheduled), storedGateway.Status.Conditions[0].Type)
+
+	t.Log("listing v1beta1 GatewayClasses as v1alpha2 GatewayClasses")
+	gatewayClasses := new(gatewayv1alpha2.GatewayClassList)
+	require.NoError(t, c.List(ctx, gatewayClasses))
+	require.Len(t, gatewayClasses.Items, 1)
+	require.Equal(t, gatewayv1alpha2.GatewayController("konghq.com/gateway-operator"), gatewayClasses.Items[0].Spec.ControllerName)
+
+	t.Log("reading a v1beta1 HTTPRoute as a v1alpha2 HTTPRoute")
+	httpRoute := new(gatewayv1alpha2.HTTPRoute)
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "echo"}, httpRoute))
+	require.Equal(t, []gatewayv1alpha2.Hostname{"echo.example.com"}, httpRoute.Spec.Hostnames)
+	require.Len(t, httpRoute.Spec.ParentRefs, 1)
+	require.Equal(t, gatewayv1alpha2.ObjectName("kong"), httpRoute.Spec.ParentRefs[0].Name)
+
+	t.Log("writing a v1alpha2 HTTPRoute to the v1beta1 HTTPRoute")
+	httpRoute.Spec.Hostnames = append(httpRoute.Spec.Hostnames, "echo.example.org")
+	require.NoError(t, c.Update(ctx, httpRoute))
+	storedHTTPRoute := new(gatewayv1beta1.HTTPRoute)
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "echo"}, storedHTTPRoute))
+	require.Equal(t, []gatewayv1beta1.Hostname{"echo.example.com", "echo.example.org"}, storedHTTPRoute.Spec.Hostnames)
+
+	t.Log("listing v1beta1 HTTPRoutes as v1alpha2 HTTPRoutes")
+	httpRoutes := new(gatewayv1alpha2.HTTPRouteList)
+	require.NoError(t, c.List(ctx, httpRoutes, client.InNamespace("default")))
+	require.Len(t, httpRoutes.Items, 1)
+	require.Equal(t, "echo", httpRoutes.Items[0].Name)
+
+	t.Log("converting watched v1beta1 objects to v1alpha2 objects")
+	convertedClass, ok := AsV1alpha2GatewayClass(&gatewayv1beta1.GatewayClass{ObjectMeta: metav1.ObjectMeta{Name: "kong"}})
+	require.True(t, ok)
+	require.Equal(t, "kong", convertedClass.Name)
+	_, ok = AsV1alpha2Gateway(&gatewayv1beta1.GatewayClass{})
+	require.False(t, ok)
+}
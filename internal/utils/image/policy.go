@@ -0,0 +1,103 @@
+package image
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// -----------------------------------------------------------------------------
+// Image Utils - Policy
+// -----------------------------------------------------------------------------
+
+// DefaultRegistry is the registry of the images referenced without registry.
+const DefaultRegistry = "docker.io"
+
+// Policy is the operator-level policy of the container images used by the
+// generated Deployments. A nil Policy allows all the images and doesn't change
+// them.
+type Policy struct {
+	// MirrorRegistry is the registry, optionally followed by a path, the
+	// default images are pulled from instead of their original registry.
+	// e.g. with the mirror.example.com:5000/kong mirror registry, the
+	// kong/kubernetes-ingress-controller:2.7 default image is pulled as
+	// mirror.example.com:5000/kong/kong/kubernetes-ingress-controller:2.7.
+	MirrorRegistry string
+
+	// AllowedRepositories lists the registries and repositories the images set
+	// by users can be pulled from, e.g. mirror.example.com:5000 or
+	// docker.io/kong. Images referenced without registry belong to the
+	// docker.io registry, in the library namespace when their repository has a
+	// single component, e.g. kong:3.0 is docker.io/library/kong:3.0. All the
+	// images are allowed when empty.
+	AllowedRepositories []string
+
+	// ImagePullSecrets are the names of the Secrets added as imagePullSecrets to
+	// the generated Deployments and ServiceAccounts.
+	ImagePullSecrets []string
+}
+
+// MirrorDefaultImage returns the given default image rewritten to be pulled
+// from the MirrorRegistry, if any.
+func (p *Policy) MirrorDefaultImage(image string) string {
+	if p == nil || p.MirrorRegistry == "" {
+		return image
+	}
+	ref, err := ParseReference(image)
+	if err != nil {
+		return image
+	}
+
+	mirrored := strings.TrimSuffix(p.MirrorRegistry, "/") + "/" + ref.Repository
+	if ref.Tag != "" {
+		mirrored += ":" + ref.Tag
+	}
+	if ref.Digest != "" {
+		mirrored += "@" + ref.Digest
+	}
+	return mirrored
+}
+
+// ValidateImage returns an error when the image isn't a valid reference or
+// doesn't belong to one of the AllowedRepositories.
+func (p *Policy) ValidateImage(image string) error {
+	ref, err := ParseReference(image)
+	if err != nil {
+		return err
+	}
+	if p == nil || len(p.AllowedRepositories) == 0 {
+		return nil
+	}
+
+	name := ref.Name()
+	if ref.Registry == "" {
+		// the official images of the default registry belong to its library
+		// namespace.
+		if !strings.Contains(ref.Repository, "/") {
+			name = "library/" + name
+		}
+		name = DefaultRegistry + "/" + name
+	}
+	for _, allowed := range p.AllowedRepositories {
+		allowed = strings.TrimSuffix(allowed, "/")
+		if name == allowed || strings.HasPrefix(name, allowed+"/") {
+			return nil
+		}
+	}
+	return fmt.Errorf("image %s is not pulled from an allowed registry or repository: %s",
+		image, strings.Join(p.AllowedRepositories, ", "))
+}
+
+// LocalObjectReferences returns the ImagePullSecrets as references for the
+// imagePullSecrets of Pods and ServiceAccounts.
+func (p *Policy) LocalObjectReferences() []corev1.LocalObjectReference {
+	if p == nil || len(p.ImagePullSecrets) == 0 {
+		return nil
+	}
+	refs := make([]corev1.LocalObjectReference, 0, len(p.ImagePullSecrets))
+	for _, name := range p.ImagePullSecrets {
+		refs = append(refs, corev1.LocalObjectReference{Name: name})
+	}
+	return refs
+}
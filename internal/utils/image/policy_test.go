@@ -0,0 +1,60 @@
+package image
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestPolicyMirrorDefaultImage(t *testing.T) {
+	var nilPolicy *Policy
+	assert.Equal(t, "kong:3.0", nilPolicy.MirrorDefaultImage("kong:3.0"))
+
+	policy := &Policy{MirrorRegistry: "mirror.example.com:5000/dockerhub/"}
+	assert.Equal(t, "mirror.example.com:5000/dockerhub/kong:3.0", policy.MirrorDefaultImage("kong:3.0"))
+	assert.Equal(t, "mirror.example.com:5000/dockerhub/kong/kubernetes-ingress-controller",
+		policy.MirrorDefaultImage("kong/kubernetes-ingress-controller"))
+	assert.Equal(t, "mirror.example.com:5000/dockerhub/kong/kong:3.0",
+		policy.MirrorDefaultImage("ghcr.io/kong/kong:3.0"))
+}
+
+func TestPolicyValidateImage(t *testing.T) {
+	var nilPolicy *Policy
+	require.NoError(t, nilPolicy.ValidateImage("kong:3.0"))
+	require.Error(t, nilPolicy.ValidateImage("kong:2.8:3.0"))
+
+	policy := &Policy{AllowedRepositories: []string{"mirror.example.com:5000", "docker.io/kong/"}}
+	for _, image := range []string{
+		"mirror.example.com:5000/kong:3.0",
+		"mirror.example.com:5000/kong/kubernetes-ingress-controller:2.7",
+		"kong/kubernetes-ingress-controller:2.7",
+		"docker.io/kong/kong:3.0",
+	} {
+		require.NoError(t, policy.ValidateImage(image), image)
+	}
+	for _, image := range []string{
+		"kong:3.0",
+		"mirror.example.com/kong:3.0",
+		"ghcr.io/kong/kong:3.0",
+		"docker.io/kongx/kong:3.0",
+	} {
+		require.Error(t, policy.ValidateImage(image), image)
+	}
+
+	policy = &Policy{AllowedRepositories: []string{"docker.io/library/kong"}}
+	require.NoError(t, policy.ValidateImage("kong:3.0"))
+	require.Error(t, policy.ValidateImage("kong/kong:3.0"))
+}
+
+func TestPolicyLocalObjectReferences(t *testing.T) {
+	var nilPolicy *Policy
+	assert.Nil(t, nilPolicy.LocalObjectReferences())
+
+	policy := &Policy{ImagePullSecrets: []string{"mirror-credentials", "registry-credentials"}}
+	assert.Equal(t, []corev1.LocalObjectReference{
+		{Name: "mirror-credentials"},
+		{Name: "registry-credentials"},
+	}, policy.LocalObjectReferences())
+}
@@ -0,0 +1,116 @@
+package image
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// -----------------------------------------------------------------------------
+// Image Utils - References
+// -----------------------------------------------------------------------------
+
+var (
+	// registryRegexp matches a registry host, optionally followed by a port.
+	registryRegexp = regexp.MustCompile(`^(?:[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)*(?::[0-9]+)?$`)
+
+	// pathComponentRegexp matches a component of a repository path.
+	pathComponentRegexp = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|[-]*)[a-z0-9]+)*$`)
+
+	// tagRegexp matches an image tag.
+	tagRegexp = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+
+	// digestRegexp matches an image digest.
+	digestRegexp = regexp.MustCompile(`^[a-z0-9]+(?:[.+_-][a-z0-9]+)*:[a-zA-Z0-9=_-]{32,}$`)
+)
+
+// Reference is a parsed container image reference, in the
+// [registry[:port]/]repository[:tag][@digest] format.
+type Reference struct {
+	// Registry is the registry host of the image, including its port. It is
+	// empty for images of the default registry referenced without registry.
+	Registry string
+
+	// Repository is the path of the image repository in the registry.
+	Repository string
+
+	// Tag is the tag of the image, if any.
+	Tag string
+
+	// Digest is the digest of the image, if any. It takes precedence over the
+	// tag when pulling the image.
+	Digest string
+}
+
+// ParseReference parses a container image reference.
+func ParseReference(ref string) (Reference, error) {
+	if ref == "" {
+		return Reference{}, fmt.Errorf("invalid image reference: empty reference")
+	}
+
+	var reference Reference
+	name := ref
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, reference.Digest = name[:i], name[i+1:]
+		if !digestRegexp.MatchString(reference.Digest) {
+			return Reference{}, fmt.Errorf("invalid image reference %q: invalid digest %q", ref, reference.Digest)
+		}
+	}
+
+	// the tag follows the last colon after the last slash, a colon before it
+	// separates the registry host from its port.
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, reference.Tag = name[:i], name[i+1:]
+		if !tagRegexp.MatchString(reference.Tag) {
+			return Reference{}, fmt.Errorf("invalid image reference %q: invalid tag %q", ref, reference.Tag)
+		}
+	}
+
+	// the first component of the name is a registry when it can't be part of
+	// a repository path: it includes a dot or a port, or is localhost.
+	if i := strings.Index(name, "/"); i >= 0 {
+		if host := name[:i]; strings.ContainsAny(host, ".:") || host == "localhost" {
+			if !registryRegexp.MatchString(host) {
+				return Reference{}, fmt.Errorf("invalid image reference %q: invalid registry %q", ref, host)
+			}
+			reference.Registry, name = host, name[i+1:]
+		}
+	}
+
+	for _, component := range strings.Split(name, "/") {
+		if !pathComponentRegexp.MatchString(component) {
+			return Reference{}, fmt.Errorf("invalid image reference %q: invalid repository %q", ref, name)
+		}
+	}
+	reference.Repository = name
+
+	return reference, nil
+}
+
+// Name returns the name of the image: its registry and repository.
+func (r Reference) Name() string {
+	if r.Registry == "" {
+		return r.Repository
+	}
+	return r.Registry + "/" + r.Repository
+}
+
+// String returns the reference in the [registry[:port]/]repository[:tag][@digest]
+// format.
+func (r Reference) String() string {
+	ref := r.Name()
+	if r.Tag != "" {
+		ref += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		ref += "@" + r.Digest
+	}
+	return ref
+}
+
+// WithTag returns a copy of the reference with the given tag. The digest, if
+// any, is preserved.
+func (r Reference) WithTag(tag string) Reference {
+	r.Tag = tag
+	return r
+}
@@ -0,0 +1,130 @@
+package image
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/pointer"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+)
+
+func TestParseReference(t *testing.T) {
+	const digest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	testCases := []struct {
+		ref      string
+		expected Reference
+		wantErr  bool
+	}{
+		{
+			ref:      "kong",
+			expected: Reference{Repository: "kong"},
+		},
+		{
+			ref:      "kong:2.8",
+			expected: Reference{Repository: "kong", Tag: "2.8"},
+		},
+		{
+			ref:      "kong/kubernetes-ingress-controller:2.5.0",
+			expected: Reference{Repository: "kong/kubernetes-ingress-controller", Tag: "2.5.0"},
+		},
+		{
+			ref:      "registry:5000/kong/kic:2.5",
+			expected: Reference{Registry: "registry:5000", Repository: "kong/kic", Tag: "2.5"},
+		},
+		{
+			ref:      "registry:5000/kong/kic",
+			expected: Reference{Registry: "registry:5000", Repository: "kong/kic"},
+		},
+		{
+			ref:      "localhost/kong",
+			expected: Reference{Registry: "localhost", Repository: "kong"},
+		},
+		{
+			ref:      "ghcr.io/kong/kong@" + digest,
+			expected: Reference{Registry: "ghcr.io", Repository: "kong/kong", Digest: digest},
+		},
+		{
+			ref:      "ghcr.io:443/kong/kong:3.0@" + digest,
+			expected: Reference{Registry: "ghcr.io:443", Repository: "kong/kong", Tag: "3.0", Digest: digest},
+		},
+		{ref: "", wantErr: true},
+		{ref: "Kong:2.8", wantErr: true},
+		{ref: "kong:2.8:3.0", wantErr: true},
+		{ref: "kong:", wantErr: true},
+		{ref: "kong@sha256:abc", wantErr: true},
+		{ref: "registry:port/kong", wantErr: true},
+		{ref: "kong//kong", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.ref, func(t *testing.T) {
+			reference, err := ParseReference(tc.ref)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, reference)
+			assert.Equal(t, tc.ref, reference.String())
+		})
+	}
+}
+
+func TestReferenceWithTag(t *testing.T) {
+	reference, err := ParseReference("registry:5000/kong/kic:2.5@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef")
+	require.NoError(t, err)
+	assert.Equal(t, "registry:5000/kong/kic:2.6@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+		reference.WithTag("2.6").String())
+	assert.Equal(t, "registry:5000/kong/kic", reference.Name())
+}
+
+func TestValidateDeploymentOptions(t *testing.T) {
+	testCases := []struct {
+		name    string
+		opts    operatorv1alpha1.DeploymentOptions
+		wantErr bool
+	}{
+		{
+			name: "no image options",
+		},
+		{
+			name: "valid image options",
+			opts: operatorv1alpha1.DeploymentOptions{
+				ContainerImage:  pointer.String("registry:5000/kong/kic"),
+				Version:         pointer.String("2.5-custom"),
+				SemanticVersion: pointer.String("2.5.0"),
+			},
+		},
+		{
+			name:    "invalid container image",
+			opts:    operatorv1alpha1.DeploymentOptions{ContainerImage: pointer.String("kong:2.8:3.0")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid version",
+			opts:    operatorv1alpha1.DeploymentOptions{Version: pointer.String("2.8/3.0")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid semantic version",
+			opts:    operatorv1alpha1.DeploymentOptions{SemanticVersion: pointer.String("latest")},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateDeploymentOptions(&tc.opts)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package image
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+)
+
+// -----------------------------------------------------------------------------
+// Image Utils - Validation
+// -----------------------------------------------------------------------------
+
+// ValidateDeploymentOptions validates the container image related fields of
+// the DeploymentOptions: the ContainerImage must be a valid image reference,
+// the Version a valid image tag and the SemanticVersion a semantic version.
+func ValidateDeploymentOptions(opts *operatorv1alpha1.DeploymentOptions) error {
+	if opts.ContainerImage != nil {
+		if _, err := ParseReference(*opts.ContainerImage); err != nil {
+			return fmt.Errorf("invalid containerImage: %w", err)
+		}
+	}
+	if opts.Version != nil && !tagRegexp.MatchString(*opts.Version) {
+		return fmt.Errorf("invalid version %q: not a valid image tag", *opts.Version)
+	}
+	if opts.SemanticVersion != nil {
+		if _, err := semver.NewVersion(*opts.SemanticVersion); err != nil {
+			return fmt.Errorf("invalid semanticVersion %q: %w", *opts.SemanticVersion, err)
+		}
+	}
+	return nil
+}
@@ -20,6 +20,74 @@ func IsEnvVarPresent(envVar corev1.EnvVar, envVars []corev1.EnvVar) (found bool)
 	return
 }
 
+// EnvValueByName returns the value of the first env var with the given name.
+// If no env var with the given name is found, an empty string is returned.
+func EnvValueByName(env []corev1.EnvVar, name string) string {
+	for _, envVar := range env {
+		if envVar.Name == name {
+			return envVar.Value
+		}
+	}
+	return ""
+}
+
+// EnvVarSourceByName returns the ValueFrom of the first env var with the given name.
+// returns nil if env var is not found, or does not have a ValueFrom field.
+func EnvVarSourceByName(env []corev1.EnvVar, name string) *corev1.EnvVarSource {
+	for _, envVar := range env {
+		if envVar.Name == name {
+			return envVar.ValueFrom
+		}
+	}
+	return nil
+}
+
+// UpdateEnv updates env var with `name` to the value `val`.
+func UpdateEnv(envVars []corev1.EnvVar, name, val string) []corev1.EnvVar {
+	newEnvVars := make([]corev1.EnvVar, 0, len(envVars))
+	for _, envVar := range envVars {
+		if envVar.Name != name {
+			newEnvVars = append(newEnvVars, envVar)
+		}
+	}
+
+	newEnvVars = append(newEnvVars, corev1.EnvVar{
+		Name:  name,
+		Value: val,
+	})
+
+	return newEnvVars
+}
+
+// UpdateEnvSource updates env var with `name` to come from `envSource`.
+func UpdateEnvSource(envVars []corev1.EnvVar, name string, envSource *corev1.EnvVarSource) []corev1.EnvVar {
+	newEnvVars := make([]corev1.EnvVar, 0, len(envVars))
+	for _, envVar := range envVars {
+		if envVar.Name != name {
+			newEnvVars = append(newEnvVars, envVar)
+		}
+	}
+
+	newEnvVars = append(newEnvVars, corev1.EnvVar{
+		Name:      name,
+		ValueFrom: envSource,
+	})
+
+	return newEnvVars
+}
+
+// RejectEnvByName returns a copy of the given env vars,
+// but with the env vars with the given name removed.
+func RejectEnvByName(envVars []corev1.EnvVar, name string) []corev1.EnvVar {
+	newEnvVars := make([]corev1.EnvVar, 0, len(envVars))
+	for _, envVar := range envVars {
+		if envVar.Name != name {
+			newEnvVars = append(newEnvVars, envVar)
+		}
+	}
+	return newEnvVars
+}
+
 // -----------------------------------------------------------------------------
 // Kubernetes Utils - Sortable EnvVars
 // -----------------------------------------------------------------------------
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -43,6 +44,39 @@ func ListDeploymentsForOwner(
 	return deployments, nil
 }
 
+// ListJobsForOwner is a helper function to map a list of Jobs
+// by label and reduce by OwnerReference UID and namespace to efficiently list
+// only the objects owned by the provided UID.
+func ListJobsForOwner(
+	ctx context.Context,
+	c client.Client,
+	requiredLabel string,
+	requiredValue string,
+	namespace string,
+	uid types.UID,
+) ([]batchv1.Job, error) {
+	jobList := &batchv1.JobList{}
+
+	err := c.List(
+		ctx,
+		jobList,
+		client.InNamespace(namespace),
+		client.MatchingLabels{requiredLabel: requiredValue},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]batchv1.Job, 0)
+	for _, job := range jobList.Items {
+		if IsOwnedByRefUID(&job.ObjectMeta, uid) {
+			jobs = append(jobs, job)
+		}
+	}
+
+	return jobs, nil
+}
+
 // ListServicesForOwner is a helper function to map a list of Services
 // by label and reduce by OwnerReference UID and namespace to efficiently list
 // only the objects owned by the provided UID.
@@ -8,48 +8,44 @@ import (
 
 	"github.com/kong/gateway-operator/internal/utils/kubernetes/resources"
 	"github.com/kong/gateway-operator/internal/utils/kubernetes/resources/clusterroles"
+	"github.com/kong/gateway-operator/internal/versions"
 )
 
 func TestClusterroleHelpers(t *testing.T) {
 	var testCases = []struct {
 		controlplane        string
-		image               string
+		version             string
 		expectedClusterRole *rbacv1.ClusterRole
 	}{
 		{
 			controlplane:        "test_2.1",
-			image:               "kong/kubernetes-ingress-controller:2.1",
+			version:             "2.1",
 			expectedClusterRole: clusterroles.GenerateNewClusterRoleForControlPlane_ge2_1_lt2_2("test_2.1"),
 		},
 		{
 			controlplane:        "test_2.2.1",
-			image:               "kong/kubernetes-ingress-controller:2.2",
+			version:             "2.2.1",
 			expectedClusterRole: clusterroles.GenerateNewClusterRoleForControlPlane_ge2_2_lt2_3("test_2.2.1"),
 		},
 		{
 			controlplane:        "test_2.3",
-			image:               "kong/kubernetes-ingress-controller:2.3",
+			version:             "2.3",
 			expectedClusterRole: clusterroles.GenerateNewClusterRoleForControlPlane_ge2_3_lt2_4("test_2.3"),
 		},
 		{
 			controlplane:        "test_2.4.2",
-			image:               "kong/kubernetes-ingress-controller:2.4.2",
+			version:             "2.4.2",
 			expectedClusterRole: clusterroles.GenerateNewClusterRoleForControlPlane_ge2_4("test_2.4.2"),
 		},
 		{
 			controlplane:        "test_latest",
-			image:               "kong/kubernetes-ingress-controller:latest",
+			version:             versions.Latest,
 			expectedClusterRole: clusterroles.GenerateNewClusterRoleForControlPlane_ge2_4("test_latest"),
 		},
-		{
-			controlplane:        "test_empty",
-			image:               "kong/kubernetes-ingress-controller",
-			expectedClusterRole: clusterroles.GenerateNewClusterRoleForControlPlane_ge2_4("test_empty"),
-		},
 	}
 
 	for _, tc := range testCases {
-		clusterRole, err := resources.GenerateNewClusterRoleForControlPlane(tc.controlplane, &tc.image)
+		clusterRole, err := resources.GenerateNewClusterRoleForControlPlane(tc.controlplane, tc.version)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -58,3 +54,11 @@ func TestClusterroleHelpers(t *testing.T) {
 		}
 	}
 }
+
+func TestClusterroleHelpersInvalidVersion(t *testing.T) {
+	for _, version := range []string{"", "latest", "2.0", "not-a-version"} {
+		if _, err := resources.GenerateNewClusterRoleForControlPlane("test", version); err == nil {
+			t.Fatalf("expected an error for version %q", version)
+		}
+	}
+}
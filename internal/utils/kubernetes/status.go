@@ -44,14 +44,18 @@ const (
 )
 
 // ConditionsAware represents a CRD type that has been enabled with metav1.Conditions,
-// it can then benefit of a series of utility methods.
+// it can then benefit of a series of utility methods. Its generation is the
+// generation of the spec its conditions are observed for.
 type ConditionsAware interface {
 	GetConditions() []metav1.Condition
 	SetConditions(conditions []metav1.Condition)
+	GetGeneration() int64
 }
 
-// SetCondition sets a new condition to the provided resource
+// SetCondition sets a new condition to the provided resource, observed for the
+// current generation of the resource.
 func SetCondition(condition metav1.Condition, resource ConditionsAware) {
+	condition.ObservedGeneration = resource.GetGeneration()
 	newConditions := make([]metav1.Condition, 0)
 
 	for i := 0; i < len(resource.GetConditions()); i++ {
@@ -63,6 +67,23 @@ func SetCondition(condition metav1.Condition, resource ConditionsAware) {
 	resource.SetConditions(append(newConditions, condition))
 }
 
+// RemoveCondition removes the condition with the given type from the provided
+// resource. It returns true if the condition was found.
+func RemoveCondition(cType ConditionType, resource ConditionsAware) bool {
+	newConditions := make([]metav1.Condition, 0)
+	for _, condition := range resource.GetConditions() {
+		if condition.Type != string(cType) {
+			newConditions = append(newConditions, condition)
+		}
+	}
+
+	if len(newConditions) == len(resource.GetConditions()) {
+		return false
+	}
+	resource.SetConditions(newConditions)
+	return true
+}
+
 // GetCondition returns the condition with the given type, if it exists. If the condition does not exists it returns false.
 func GetCondition(cType ConditionType, resource ConditionsAware) (metav1.Condition, bool) {
 	for _, condition := range resource.GetConditions() {
@@ -115,11 +136,13 @@ func areAllConditionsReady(resource ConditionsAware) bool {
 }
 
 // IsReady evaluates whether a resource is in Ready state, meaning
-// that all its conditions are in the True state.
+// that all its conditions are in the True state. A Ready condition observed for
+// a previous generation of the resource is stale: the resource isn't ready
+// until its current spec has been reconciled.
 func IsReady(resource ConditionsAware) bool {
 	for _, condition := range resource.GetConditions() {
 		if condition.Type == string(ReadyType) {
-			return condition.Status == metav1.ConditionTrue
+			return condition.Status == metav1.ConditionTrue && condition.ObservedGeneration >= resource.GetGeneration()
 		}
 	}
 	return false
@@ -136,8 +159,9 @@ func NewCondition(cType ConditionType, status metav1.ConditionStatus, reason Con
 	}
 }
 
-// NeedsUpdate retrieves the persisted state and compares all the conditions
-// to decide whether the status must be updated or not
+// NeedsUpdate retrieves the persisted state and compares all the conditions,
+// including the generation they were observed for, to decide whether the
+// status must be updated or not
 func NeedsUpdate(current, updated ConditionsAware) bool {
 	if len(current.GetConditions()) != len(updated.GetConditions()) {
 		return true
@@ -148,7 +172,7 @@ func NeedsUpdate(current, updated ConditionsAware) bool {
 		if !exists {
 			return true
 		}
-		if u.Reason != c.Reason || u.Message != c.Message || u.Status != c.Status {
+		if u.Reason != c.Reason || u.Message != c.Message || u.Status != c.Status || u.ObservedGeneration != c.ObservedGeneration {
 			return true
 		}
 	}
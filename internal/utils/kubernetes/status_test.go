@@ -9,6 +9,7 @@ import (
 
 type TestResource struct {
 	Conditions []metav1.Condition
+	Generation int64
 }
 
 func (r *TestResource) GetConditions() []metav1.Condition {
@@ -19,6 +20,10 @@ func (r *TestResource) SetConditions(conditions []metav1.Condition) {
 	r.Conditions = conditions
 }
 
+func (r *TestResource) GetGeneration() int64 {
+	return r.Generation
+}
+
 func TestGetCondition(t *testing.T) {
 	expected := metav1.Condition{
 		Type:               "example",
@@ -282,6 +287,78 @@ func TestSetCondition(t *testing.T) {
 	}
 }
 
+func TestSetConditionObservedGeneration(t *testing.T) {
+	resource := &TestResource{Generation: 3}
+	SetCondition(metav1.Condition{Type: "example", Status: metav1.ConditionTrue, ObservedGeneration: 1}, resource)
+	condition, exists := GetCondition("example", resource)
+	assert.True(t, exists)
+	assert.Equal(t, int64(3), condition.ObservedGeneration)
+
+	SetReady(resource)
+	ready, exists := GetCondition(ReadyType, resource)
+	assert.True(t, exists)
+	assert.Equal(t, int64(3), ready.ObservedGeneration)
+}
+
+func TestRemoveCondition(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		conditions    []metav1.Condition
+		condition     string
+		expected      []metav1.Condition
+		expectedFound bool
+	}{
+		{
+			"missing_condition",
+			[]metav1.Condition{
+				{
+					Type:   "example1",
+					Status: metav1.ConditionTrue,
+				},
+			},
+			"example2",
+			[]metav1.Condition{
+				{
+					Type:   "example1",
+					Status: metav1.ConditionTrue,
+				},
+			},
+			false,
+		},
+		{
+			"remove_condition",
+			[]metav1.Condition{
+				{
+					Type:   "example1",
+					Status: metav1.ConditionTrue,
+				},
+				{
+					Type:   "example2",
+					Status: metav1.ConditionFalse,
+				},
+			},
+			"example2",
+			[]metav1.Condition{
+				{
+					Type:   "example1",
+					Status: metav1.ConditionTrue,
+				},
+			},
+			true,
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			resource := &TestResource{
+				Conditions: tt.conditions,
+			}
+			found := RemoveCondition(ConditionType(tt.condition), resource)
+			assert.Equal(t, tt.expectedFound, found)
+			assert.ElementsMatch(t, resource.GetConditions(), tt.expected)
+		})
+	}
+}
+
 func TestIsValidCondition(t *testing.T) {
 	resource := &TestResource{
 		Conditions: []metav1.Condition{
@@ -384,7 +461,7 @@ func TestIsReady(t *testing.T) {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			resource := &TestResource{
-				tt.conditions,
+				Conditions: tt.conditions,
 			}
 			current := IsReady(resource)
 			assert.Equal(t, current, tt.expected)
@@ -392,6 +469,18 @@ func TestIsReady(t *testing.T) {
 	}
 }
 
+func TestIsReadyStaleGeneration(t *testing.T) {
+	resource := &TestResource{Generation: 2}
+	SetReady(resource)
+	assert.True(t, IsReady(resource))
+
+	t.Log("a new generation of the resource is not ready until it's reconciled")
+	resource.Generation = 3
+	assert.False(t, IsReady(resource))
+	SetReady(resource)
+	assert.True(t, IsReady(resource))
+}
+
 func TestSetReady(t *testing.T) {
 	for _, tt := range []struct {
 		name       string
@@ -489,7 +578,7 @@ func TestSetReady(t *testing.T) {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			resource := &TestResource{
-				tt.conditions,
+				Conditions: tt.conditions,
 			}
 			SetReady(resource)
 			current := IsReady(resource)
@@ -593,6 +682,20 @@ func TestNeedsUpdate(t *testing.T) {
 			},
 			true,
 		},
+		{
+			"different observed generation",
+			[]metav1.Condition{defaultCondition},
+			[]metav1.Condition{
+				{
+					Type:               "type",
+					Reason:             "reason",
+					Message:            "message",
+					Status:             metav1.StatusSuccess,
+					ObservedGeneration: 2,
+				},
+			},
+			true,
+		},
 		{
 			"one more condition status",
 			[]metav1.Condition{defaultCondition},
@@ -632,8 +735,8 @@ func TestNeedsUpdate(t *testing.T) {
 	} {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			current := &TestResource{tt.current}
-			updated := &TestResource{tt.updated}
+			current := &TestResource{Conditions: tt.current}
+			updated := &TestResource{Conditions: tt.updated}
 			assert.Equal(t, tt.expected, NeedsUpdate(current, updated))
 			assert.Equal(t, tt.expected, NeedsUpdate(updated, current))
 		})
@@ -0,0 +1,280 @@
+package controlplane
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	"github.com/kong/gateway-operator/internal/consts"
+	operatorerrors "github.com/kong/gateway-operator/internal/errors"
+	controlplaneutils "github.com/kong/gateway-operator/internal/utils/controlplane"
+	dataplaneutils "github.com/kong/gateway-operator/internal/utils/dataplane"
+	gatewayutils "github.com/kong/gateway-operator/internal/utils/gateway"
+	"github.com/kong/gateway-operator/internal/utils/image"
+	k8sresources "github.com/kong/gateway-operator/internal/utils/kubernetes/resources"
+	dataplanevalidation "github.com/kong/gateway-operator/internal/validation/dataplane"
+	"github.com/kong/gateway-operator/internal/versions"
+)
+
+// kongAdminEnvVarPrefix is the prefix of the environment variables configuring
+// the connection of the ControlPlane to the Kong Admin API of its DataPlanes,
+// which are owned by the operator.
+const kongAdminEnvVarPrefix = "CONTROLLER_KONG_ADMIN_"
+
+// operatorOwnedEnvVars are the environment variables the ControlPlane controller
+// sets to a fixed value.
+var operatorOwnedEnvVars = func() map[string]corev1.EnvVar {
+	envVars := map[string]corev1.EnvVar{
+		"POD_NAMESPACE": {
+			Name: "POD_NAMESPACE",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					APIVersion: "v1",
+					FieldPath:  "metadata.namespace",
+				},
+			},
+		},
+	}
+	for _, envVar := range controlplaneutils.ClusterCertificateEnvVars {
+		envVars[envVar.Name] = envVar
+	}
+	return envVars
+}()
+
+// Validator validates ControlPlane objects.
+type Validator struct {
+	c client.Client
+}
+
+// NewValidator creates a ControlPlane validator.
+func NewValidator(c client.Client) *Validator {
+	return &Validator{c: c}
+}
+
+// Validate validates a ControlPlane object and return the first validation error found.
+func (v *Validator) Validate(ctx context.Context, controlplane *operatorv1alpha1.ControlPlane) error {
+	return v.validate(ctx, controlplane, nil)
+}
+
+// ValidateUpdate validates the update of a ControlPlane object: the updated
+// object must be valid, and its immutable fields must not change.
+func (v *Validator) ValidateUpdate(ctx context.Context, oldControlPlane, controlplane *operatorv1alpha1.ControlPlane) error {
+	if err := v.validate(ctx, controlplane, oldControlPlane); err != nil {
+		return err
+	}
+
+	// the resources of the DataPlanes in the previous namespace would be left
+	// configured by the ControlPlane.
+	if gatewayutils.GetDataPlaneNamespaceForControlPlane(oldControlPlane) != gatewayutils.GetDataPlaneNamespaceForControlPlane(controlplane) {
+		return fmt.Errorf("dataplaneNamespace is immutable")
+	}
+	// the ControlPlane would not release the resources of the previous classes.
+	if oldControlPlane.Spec.GatewayClass != nil && !reflect.DeepEqual(oldControlPlane.Spec.GatewayClass, controlplane.Spec.GatewayClass) {
+		return fmt.Errorf("gatewayClass is immutable once set")
+	}
+	if oldControlPlane.Spec.IngressClass != nil && !reflect.DeepEqual(oldControlPlane.Spec.IngressClass, controlplane.Spec.IngressClass) {
+		return fmt.Errorf("ingressClass is immutable once set")
+	}
+	return nil
+}
+
+func (v *Validator) validate(ctx context.Context, controlplane, oldControlPlane *operatorv1alpha1.ControlPlane) error {
+	dataplaneNamespace := gatewayutils.GetDataPlaneNamespaceForControlPlane(controlplane)
+	err := v.ValidateDeployOptions(controlplane.Name, dataplaneNamespace, &controlplane.Spec.ControlPlaneDeploymentOptions)
+	if err != nil {
+		return err
+	}
+
+	err = v.ValidateClasses(&controlplane.Spec)
+	if err != nil {
+		return err
+	}
+
+	// the DataPlanes which were already referenced may have been deleted since,
+	// the ControlPlane must still be updatable.
+	var oldOpts *operatorv1alpha1.ControlPlaneDeploymentOptions
+	if oldControlPlane != nil {
+		oldOpts = &oldControlPlane.Spec.ControlPlaneDeploymentOptions
+	}
+	err = v.validateDataPlanesExist(ctx, dataplaneNamespace, &controlplane.Spec.ControlPlaneDeploymentOptions, oldOpts)
+	if err != nil {
+		return err
+	}
+
+	return validateMultipleDataPlanesSupport(controlplane)
+}
+
+// ValidateDeployOptions validates the ControlPlaneDeploymentOptions field of
+// ControlPlane object.
+func (v *Validator) ValidateDeployOptions(
+	controlplaneName, dataplaneNamespace string,
+	opts *operatorv1alpha1.ControlPlaneDeploymentOptions,
+) error {
+	if err := image.ValidateDeploymentOptions(&opts.DeploymentOptions); err != nil {
+		return err
+	}
+
+	if err := ValidateVersion(controlplaneName, &opts.DeploymentOptions); err != nil {
+		return err
+	}
+
+	if opts.Rollout != nil && opts.Rollout.Strategy.Canary != nil {
+		if err := dataplanevalidation.ValidateCanaryStrategy(opts.Rollout.Strategy.Canary); err != nil {
+			return err
+		}
+	}
+
+	return ValidateEnv(dataplaneNamespace, opts.Env)
+}
+
+// ValidateClasses validates the GatewayClass and IngressClass names of the
+// ControlPlane.
+func (v *Validator) ValidateClasses(spec *operatorv1alpha1.ControlPlaneSpec) error {
+	if spec.GatewayClass != nil {
+		if errs := validation.IsDNS1123Subdomain(string(*spec.GatewayClass)); len(errs) > 0 {
+			return fmt.Errorf("invalid gatewayClass %q: %s", *spec.GatewayClass, strings.Join(errs, ", "))
+		}
+	}
+	if spec.IngressClass != nil {
+		if errs := validation.IsDNS1123Subdomain(*spec.IngressClass); len(errs) > 0 {
+			return fmt.Errorf("invalid ingressClass %q: %s", *spec.IngressClass, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+// ValidateVersion returns an error when the version of the ControlPlane is not
+// supported by the ClusterRoles generated for the ControlPlanes. The versions
+// chosen by AutomaticUpgrades are not validated, only their policy is.
+func ValidateVersion(controlplaneName string, opts *operatorv1alpha1.DeploymentOptions) error {
+	if opts.AutomaticUpgrades != nil {
+		return dataplanevalidation.ValidateAutomaticUpgrades(opts)
+	}
+
+	version, ok := versions.VersionFromDeploymentOptions(opts)
+	switch {
+	case ok:
+	case opts.ContainerImage != nil:
+		// images without tag use the ClusterRole of the latest version.
+		version = versions.Latest
+	default:
+		version = consts.DefaultControlPlaneTag
+	}
+	if version == "latest" {
+		version = versions.Latest
+	}
+
+	if _, err := k8sresources.GenerateNewClusterRoleForControlPlane(controlplaneName, version); err != nil {
+		return fmt.Errorf("unsupported controlplane version: %w", err)
+	}
+	return nil
+}
+
+// ValidateEnv returns an error when the environment variables override those
+// owned by the operator: POD_NAMESPACE and the CONTROLLER_KONG_ADMIN_* ones may
+// only be set to the values the operator sets, the Kong Admin API URLs must be
+// the ones of DataPlanes in the given namespace. No Kong Admin API URL is
+// allowed when the namespace is empty.
+func ValidateEnv(dataplaneNamespace string, envs []corev1.EnvVar) error {
+	for _, env := range envs {
+		if expected, ok := operatorOwnedEnvVars[env.Name]; ok {
+			if !reflect.DeepEqual(env, expected) {
+				return fmt.Errorf("env %s is owned by the operator and can't be overridden", env.Name)
+			}
+			continue
+		}
+
+		if env.Name == "CONTROLLER_KONG_ADMIN_URL" {
+			if err := validateKongAdminURL(dataplaneNamespace, env); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if strings.HasPrefix(env.Name, kongAdminEnvVarPrefix) {
+			return fmt.Errorf("env %s is owned by the operator and can't be overridden", env.Name)
+		}
+	}
+	return nil
+}
+
+// validateKongAdminURL validates the Kong Admin API URLs set by the operator:
+// one URL per DataPlane Service, in the DataPlane namespace.
+func validateKongAdminURL(dataplaneNamespace string, env corev1.EnvVar) error {
+	if env.ValueFrom != nil || dataplaneNamespace == "" {
+		return fmt.Errorf("env %s is owned by the operator and can't be overridden", env.Name)
+	}
+
+	suffix := fmt.Sprintf(".%s.svc:%d", dataplaneNamespace, dataplaneutils.DefaultKongAdminPort)
+	for _, url := range strings.Split(env.Value, ",") {
+		serviceName := strings.TrimSuffix(strings.TrimPrefix(url, "https://"), suffix)
+		if len(validation.IsDNS1035Label(serviceName)) > 0 || url != "https://"+serviceName+suffix {
+			return fmt.Errorf("env %s is owned by the operator and can't be overridden", env.Name)
+		}
+	}
+	return nil
+}
+
+// validateDataPlanesExist returns an error when a DataPlane newly referenced by
+// name, i.e. not referenced by the old options, doesn't exist.
+func (v *Validator) validateDataPlanesExist(ctx context.Context, dataplaneNamespace string, opts, oldOpts *operatorv1alpha1.ControlPlaneDeploymentOptions) error {
+	oldNames := make(map[string]struct{})
+	if oldOpts != nil {
+		for _, name := range dataplaneNames(oldOpts) {
+			oldNames[name] = struct{}{}
+		}
+	}
+
+	for _, name := range dataplaneNames(opts) {
+		if _, ok := oldNames[name]; ok {
+			continue
+		}
+		dataplane := &operatorv1alpha1.DataPlane{}
+		namespacedName := k8stypes.NamespacedName{Namespace: dataplaneNamespace, Name: name}
+		err := v.c.Get(ctx, namespacedName, dataplane)
+		if k8serrors.IsNotFound(err) {
+			return fmt.Errorf("dataplane %s does not exist in namespace %s", name, dataplaneNamespace)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get dataplane %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// validateMultipleDataPlanesSupport returns an error when the ControlPlane may
+// manage more than one DataPlane, through DataPlanes or DataPlaneSelector, and
+// its version is known not to accept a list of Kong Admin API URLs. Unknown
+// versions, e.g. of custom images without tag, are allowed.
+func validateMultipleDataPlanesSupport(controlplane *operatorv1alpha1.ControlPlane) error {
+	opts := &controlplane.Spec.ControlPlaneDeploymentOptions
+	if len(dataplaneNames(opts)) < 2 && opts.DataPlaneSelector == nil {
+		return nil
+	}
+	kicVersion := versions.KICVersionForControlPlane(controlplane)
+	if kicVersion == "" {
+		return nil
+	}
+	if err := versions.CheckMultipleDataPlanesSupport(kicVersion); errors.Is(err, operatorerrors.ErrIncompatibleVersions) {
+		return err
+	}
+	return nil
+}
+
+// dataplaneNames returns the names of the DataPlanes referenced by name.
+func dataplaneNames(opts *operatorv1alpha1.ControlPlaneDeploymentOptions) []string {
+	var names []string
+	if opts.DataPlane != nil && *opts.DataPlane != "" {
+		names = append(names, *opts.DataPlane)
+	}
+	return append(names, opts.DataPlanes...)
+}
@@ -0,0 +1,264 @@
+package controlplane
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+)
+
+func TestValidate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, operatorv1alpha1.AddToScheme(scheme))
+	b := fakeclient.NewClientBuilder().WithScheme(scheme)
+	b.WithObjects(
+		&operatorv1alpha1.DataPlane{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-dataplane"},
+		},
+		&operatorv1alpha1.DataPlane{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other-dataplane"},
+		},
+	)
+
+	newControlPlane := func(mutate func(*operatorv1alpha1.ControlPlane)) *operatorv1alpha1.ControlPlane {
+		controlplane := &operatorv1alpha1.ControlPlane{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-controlplane"},
+		}
+		controlplane.Spec.DataPlane = pointer.String("test-dataplane")
+		if mutate != nil {
+			mutate(controlplane)
+		}
+		return controlplane
+	}
+
+	testCases := []struct {
+		msg          string
+		controlplane *operatorv1alpha1.ControlPlane
+		hasError     bool
+		errMsg       string
+	}{
+		{
+			msg:          "controlplane with an existing dataplane should be valid",
+			controlplane: newControlPlane(nil),
+		},
+		{
+			msg: "controlplane without dataplane should be valid",
+			controlplane: newControlPlane(func(controlplane *operatorv1alpha1.ControlPlane) {
+				controlplane.Spec.DataPlane = nil
+			}),
+		},
+		{
+			msg: "controlplane with a missing dataplane should be invalid",
+			controlplane: newControlPlane(func(controlplane *operatorv1alpha1.ControlPlane) {
+				controlplane.Spec.DataPlanes = []string{"missing-dataplane"}
+			}),
+			hasError: true,
+			errMsg:   "dataplane missing-dataplane does not exist in namespace default",
+		},
+		{
+			msg: "controlplane with multiple dataplanes and a version accepting several admin urls should be valid",
+			controlplane: newControlPlane(func(controlplane *operatorv1alpha1.ControlPlane) {
+				controlplane.Spec.Version = pointer.String("2.9.0")
+				controlplane.Spec.DataPlanes = []string{"other-dataplane"}
+			}),
+		},
+		{
+			msg: "controlplane with multiple dataplanes and the default version should be invalid",
+			controlplane: newControlPlane(func(controlplane *operatorv1alpha1.ControlPlane) {
+				controlplane.Spec.DataPlanes = []string{"other-dataplane"}
+			}),
+			hasError: true,
+			errMsg:   "can't manage more than one dataplane",
+		},
+		{
+			msg: "controlplane selecting dataplanes with an old version should be invalid",
+			controlplane: newControlPlane(func(controlplane *operatorv1alpha1.ControlPlane) {
+				controlplane.Spec.Version = pointer.String("2.6.0")
+				controlplane.Spec.DataPlaneSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "edge"}}
+			}),
+			hasError: true,
+			errMsg:   "can't manage more than one dataplane",
+		},
+		{
+			msg: "controlplane with a version without ClusterRole should be invalid",
+			controlplane: newControlPlane(func(controlplane *operatorv1alpha1.ControlPlane) {
+				controlplane.Spec.Version = pointer.String("2.0")
+			}),
+			hasError: true,
+			errMsg:   "unsupported controlplane version",
+		},
+		{
+			msg: "controlplane with automatic upgrades following a channel should be valid",
+			controlplane: newControlPlane(func(controlplane *operatorv1alpha1.ControlPlane) {
+				controlplane.Spec.AutomaticUpgrades = &operatorv1alpha1.AutomaticUpgrades{
+					Policy:  operatorv1alpha1.UpgradePolicyChannel,
+					Channel: "2.6",
+				}
+			}),
+		},
+		{
+			msg: "controlplane with automatic upgrades following a missing channel should be invalid",
+			controlplane: newControlPlane(func(controlplane *operatorv1alpha1.ControlPlane) {
+				controlplane.Spec.AutomaticUpgrades = &operatorv1alpha1.AutomaticUpgrades{
+					Policy: operatorv1alpha1.UpgradePolicyChannel,
+				}
+			}),
+			hasError: true,
+			errMsg:   "channel must be set when the Channel upgrade policy is in use",
+		},
+		{
+			msg: "controlplane with automatic upgrades and a version should be invalid",
+			controlplane: newControlPlane(func(controlplane *operatorv1alpha1.ControlPlane) {
+				controlplane.Spec.Version = pointer.String("2.6.0")
+				controlplane.Spec.AutomaticUpgrades = &operatorv1alpha1.AutomaticUpgrades{
+					Policy: operatorv1alpha1.UpgradePolicyPatch,
+				}
+			}),
+			hasError: true,
+			errMsg:   "version can't be set when automaticUpgrades is in use",
+		},
+		{
+			msg: "controlplane with canary steps of increasing weights should be valid",
+			controlplane: newControlPlane(func(controlplane *operatorv1alpha1.ControlPlane) {
+				controlplane.Spec.Rollout = &operatorv1alpha1.ControlPlaneRollout{
+					Strategy: operatorv1alpha1.ControlPlaneRolloutStrategy{
+						Canary: &operatorv1alpha1.CanaryStrategy{
+							Steps: []operatorv1alpha1.CanaryStep{{Weight: 20}, {Weight: 50}},
+						},
+					},
+				}
+			}),
+		},
+		{
+			msg: "controlplane with canary steps of decreasing weights should be invalid",
+			controlplane: newControlPlane(func(controlplane *operatorv1alpha1.ControlPlane) {
+				controlplane.Spec.Rollout = &operatorv1alpha1.ControlPlaneRollout{
+					Strategy: operatorv1alpha1.ControlPlaneRolloutStrategy{
+						Canary: &operatorv1alpha1.CanaryStrategy{
+							Steps: []operatorv1alpha1.CanaryStep{{Weight: 50}, {Weight: 20}},
+						},
+					},
+				}
+			}),
+			hasError: true,
+			errMsg:   "canary step 1 weight must be greater than the weight of the previous step",
+		},
+		{
+			msg: "controlplane with the latest image should be valid",
+			controlplane: newControlPlane(func(controlplane *operatorv1alpha1.ControlPlane) {
+				controlplane.Spec.Version = pointer.String("latest")
+			}),
+		},
+		{
+			msg: "controlplane with the env set by the operator should be valid",
+			controlplane: newControlPlane(func(controlplane *operatorv1alpha1.ControlPlane) {
+				controlplane.Spec.Env = []corev1.EnvVar{
+					operatorOwnedEnvVars["POD_NAMESPACE"],
+					operatorOwnedEnvVars["CONTROLLER_KONG_ADMIN_CA_CERT_FILE"],
+					{Name: "CONTROLLER_KONG_ADMIN_URL", Value: "https://dataplane-a.default.svc:8444,https://dataplane-b.default.svc:8444"},
+					{Name: "CONTROLLER_LOG_LEVEL", Value: "debug"},
+				}
+			}),
+		},
+		{
+			msg: "controlplane overriding POD_NAMESPACE should be invalid",
+			controlplane: newControlPlane(func(controlplane *operatorv1alpha1.ControlPlane) {
+				controlplane.Spec.Env = []corev1.EnvVar{{Name: "POD_NAMESPACE", Value: "kong"}}
+			}),
+			hasError: true,
+			errMsg:   "env POD_NAMESPACE is owned by the operator",
+		},
+		{
+			msg: "controlplane overriding the Kong Admin API URL should be invalid",
+			controlplane: newControlPlane(func(controlplane *operatorv1alpha1.ControlPlane) {
+				controlplane.Spec.Env = []corev1.EnvVar{{Name: "CONTROLLER_KONG_ADMIN_URL", Value: "https://kong.example.com:8444"}}
+			}),
+			hasError: true,
+			errMsg:   "env CONTROLLER_KONG_ADMIN_URL is owned by the operator",
+		},
+		{
+			msg: "controlplane setting another Kong Admin API env should be invalid",
+			controlplane: newControlPlane(func(controlplane *operatorv1alpha1.ControlPlane) {
+				controlplane.Spec.Env = []corev1.EnvVar{{Name: "CONTROLLER_KONG_ADMIN_TOKEN", Value: "secret"}}
+			}),
+			hasError: true,
+			errMsg:   "env CONTROLLER_KONG_ADMIN_TOKEN is owned by the operator",
+		},
+		{
+			msg: "controlplane with an invalid gatewayClass should be invalid",
+			controlplane: newControlPlane(func(controlplane *operatorv1alpha1.ControlPlane) {
+				gatewayClass := gatewayv1alpha2.ObjectName("Kong_Class")
+				controlplane.Spec.GatewayClass = &gatewayClass
+			}),
+			hasError: true,
+			errMsg:   "invalid gatewayClass",
+		},
+		{
+			msg: "controlplane with an invalid ingressClass should be invalid",
+			controlplane: newControlPlane(func(controlplane *operatorv1alpha1.ControlPlane) {
+				controlplane.Spec.IngressClass = pointer.String("kong/ingress")
+			}),
+			hasError: true,
+			errMsg:   "invalid ingressClass",
+		},
+	}
+
+	for _, tc := range testCases {
+		v := &Validator{
+			c: b.Build(),
+		}
+		err := v.Validate(context.Background(), tc.controlplane)
+		if !tc.hasError {
+			require.NoErrorf(t, err, tc.msg)
+		} else {
+			require.ErrorContainsf(t, err, tc.errMsg, tc.msg)
+		}
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, operatorv1alpha1.AddToScheme(scheme))
+	v := &Validator{
+		c: fakeclient.NewClientBuilder().WithScheme(scheme).Build(),
+	}
+
+	gatewayClass := gatewayv1alpha2.ObjectName("kong")
+	oldControlPlane := &operatorv1alpha1.ControlPlane{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-controlplane"},
+	}
+	oldControlPlane.Spec.GatewayClass = &gatewayClass
+	oldControlPlane.Spec.DataPlane = pointer.String("deleted-dataplane")
+
+	t.Log("a dataplane which was deleted since it was referenced is still allowed")
+	controlplane := oldControlPlane.DeepCopy()
+	controlplane.Spec.IngressClass = pointer.String("kong")
+	require.NoError(t, v.ValidateUpdate(context.Background(), oldControlPlane, controlplane))
+
+	t.Log("a new reference to a missing dataplane is rejected")
+	controlplane = oldControlPlane.DeepCopy()
+	controlplane.Spec.DataPlane = pointer.String("missing-dataplane")
+	require.ErrorContains(t, v.ValidateUpdate(context.Background(), oldControlPlane, controlplane), "dataplane missing-dataplane does not exist")
+
+	t.Log("the gatewayClass is immutable once set")
+	controlplane = oldControlPlane.DeepCopy()
+	otherGatewayClass := gatewayv1alpha2.ObjectName("other")
+	controlplane.Spec.GatewayClass = &otherGatewayClass
+	require.ErrorContains(t, v.ValidateUpdate(context.Background(), oldControlPlane, controlplane), "gatewayClass is immutable")
+
+	t.Log("the dataplaneNamespace is immutable")
+	controlplane = oldControlPlane.DeepCopy()
+	controlplane.Spec.DataPlaneNamespace = pointer.String("other")
+	require.ErrorContains(t, v.ValidateUpdate(context.Background(), oldControlPlane, controlplane), "dataplaneNamespace is immutable")
+}
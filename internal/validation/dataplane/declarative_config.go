@@ -0,0 +1,239 @@
+package dataplane
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+)
+
+// -----------------------------------------------------------------------------
+// DataPlane Validation - Declarative Kong Configuration
+// -----------------------------------------------------------------------------
+
+// declarativeFormatVersions are the versions of the declarative configuration
+// format supported by Kong.
+var declarativeFormatVersions = []string{"1.1", "2.1", "3.0"}
+
+// declarativeMetadataFields are the top level fields of the declarative
+// configuration which aren't entities.
+var declarativeMetadataFields = map[string]struct{}{
+	"_format_version": {},
+	"_transform":      {},
+	"_comment":        {},
+	"_workspace":      {},
+}
+
+// entitySchema is the subset of the Kong declarative schema of an entity which
+// is validated at admission: Kong validates the rest of the schema when the
+// configuration is loaded.
+type entitySchema struct {
+	// requiredOneOf are the groups of fields of which at least one must be set.
+	requiredOneOf [][]string
+
+	// nested are the collections of entities which can be nested in the entity.
+	nested []string
+}
+
+// entitySchemas are the schemas of the entity collections of the declarative
+// configuration, all of which can be set at the top level.
+var entitySchemas = map[string]entitySchema{
+	"services": {
+		requiredOneOf: [][]string{{"url", "host"}},
+		nested:        []string{"routes", "plugins"},
+	},
+	"routes": {
+		requiredOneOf: [][]string{{"paths", "hosts", "methods", "headers", "snis", "sources", "destinations"}},
+		nested:        []string{"plugins"},
+	},
+	"consumers": {
+		requiredOneOf: [][]string{{"username", "custom_id"}},
+		nested: []string{
+			"plugins", "acls", "basicauth_credentials", "hmacauth_credentials",
+			"jwt_secrets", "keyauth_credentials", "oauth2_credentials",
+		},
+	},
+	"consumer_groups": {
+		requiredOneOf: [][]string{{"name"}},
+	},
+	"plugins": {
+		requiredOneOf: [][]string{{"name"}},
+	},
+	"upstreams": {
+		requiredOneOf: [][]string{{"name"}},
+		nested:        []string{"targets"},
+	},
+	"targets": {
+		requiredOneOf: [][]string{{"target"}},
+	},
+	"certificates": {
+		requiredOneOf: [][]string{{"cert"}, {"key"}},
+		nested:        []string{"snis"},
+	},
+	"ca_certificates": {
+		requiredOneOf: [][]string{{"cert"}},
+	},
+	"snis": {
+		requiredOneOf: [][]string{{"name"}},
+	},
+	"vaults": {
+		requiredOneOf: [][]string{{"name"}, {"prefix"}},
+	},
+	"acls": {
+		requiredOneOf: [][]string{{"group"}},
+	},
+	"basicauth_credentials": {
+		requiredOneOf: [][]string{{"username"}, {"password"}},
+	},
+	"hmacauth_credentials": {
+		requiredOneOf: [][]string{{"username"}},
+	},
+	"jwt_secrets":         {},
+	"keyauth_credentials": {},
+	"oauth2_credentials": {
+		requiredOneOf: [][]string{{"name"}},
+	},
+}
+
+// ValidateDeclarativeConfig validates a declarative Kong configuration (kong.yml)
+// against the subset of the Kong declarative schema the DataPlanes rely on,
+// and returns the first error found with the path of the invalid field in the
+// configuration.
+func ValidateDeclarativeConfig(data []byte) error {
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return fmt.Errorf("declarative config is not valid YAML: %w", err)
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal(jsonData, &config); err != nil || config == nil {
+		return fmt.Errorf("declarative config must be a YAML object")
+	}
+
+	formatVersion, ok := config["_format_version"].(string)
+	if !ok {
+		return field.Required(field.NewPath("_format_version"), "")
+	}
+	if !isDeclarativeFormatVersion(formatVersion) {
+		return field.NotSupported(field.NewPath("_format_version"), formatVersion, declarativeFormatVersions)
+	}
+
+	// sort the fields for the errors to be reported in a stable order.
+	fields := make([]string, 0, len(config))
+	for name := range config {
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+
+	for _, name := range fields {
+		if _, ok := declarativeMetadataFields[name]; ok {
+			continue
+		}
+		if _, ok := entitySchemas[name]; !ok {
+			return field.Invalid(field.NewPath(name), name, "unknown entity collection")
+		}
+		if err := validateEntities(field.NewPath(name), name, config[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateEntities validates a collection of entities of the declarative
+// configuration, along with the entities nested in them.
+func validateEntities(path *field.Path, collection string, value interface{}) *field.Error {
+	entities, ok := value.([]interface{})
+	if !ok {
+		return field.Invalid(path, value, "must be a list of entities")
+	}
+
+	schema := entitySchemas[collection]
+	for i, value := range entities {
+		entityPath := path.Index(i)
+		entity, ok := value.(map[string]interface{})
+		if !ok {
+			return field.Invalid(entityPath, value, "must be an entity object")
+		}
+
+		for _, oneOf := range schema.requiredOneOf {
+			if !hasAnyField(entity, oneOf) {
+				return field.Required(entityPath.Child(oneOf[0]), requiredDetail(oneOf))
+			}
+		}
+		if err := validateEntityFields(entityPath, collection, entity); err != nil {
+			return err
+		}
+
+		for _, nested := range schema.nested {
+			if nestedEntities, ok := entity[nested]; ok {
+				if err := validateEntities(entityPath.Child(nested), nested, nestedEntities); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateEntityFields validates the fields of the entities which can be
+// checked without Kong: the plugins must be bundled with Kong, as the
+// DataPlanes can only enable those, the URLs of the services must be absolute
+// and the paths of the routes must be absolute or regular expressions.
+func validateEntityFields(path *field.Path, collection string, entity map[string]interface{}) *field.Error {
+	switch collection {
+	case "plugins":
+		name, _ := entity["name"].(string)
+		if _, ok := bundledPlugins[name]; !ok {
+			return field.Invalid(path.Child("name"), entity["name"], "unknown plugin")
+		}
+	case "services":
+		if value, ok := entity["url"]; ok {
+			rawURL, _ := value.(string)
+			if u, err := url.Parse(rawURL); err != nil || u.Scheme == "" || u.Host == "" {
+				return field.Invalid(path.Child("url"), value, "must be an absolute URL")
+			}
+		}
+	case "routes":
+		if value, ok := entity["paths"]; ok {
+			paths, ok := value.([]interface{})
+			if !ok {
+				return field.Invalid(path.Child("paths"), value, "must be a list of paths")
+			}
+			for i, value := range paths {
+				routePath, _ := value.(string)
+				if !strings.HasPrefix(routePath, "/") && !strings.HasPrefix(routePath, "~") {
+					return field.Invalid(path.Child("paths").Index(i), value, "must start with / or ~ for regular expressions")
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func hasAnyField(entity map[string]interface{}, fields []string) bool {
+	for _, name := range fields {
+		if value, ok := entity[name]; ok && value != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func requiredDetail(fields []string) string {
+	if len(fields) == 1 {
+		return ""
+	}
+	return fmt.Sprintf("one of %s must be set", strings.Join(fields, ", "))
+}
+
+func isDeclarativeFormatVersion(version string) bool {
+	for _, supported := range declarativeFormatVersions {
+		if version == supported {
+			return true
+		}
+	}
+	return false
+}
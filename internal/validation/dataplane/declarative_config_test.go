@@ -0,0 +1,151 @@
+package dataplane
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDeclarativeConfig(t *testing.T) {
+	testCases := []struct {
+		msg      string
+		config   string
+		hasError bool
+		errMsg   string
+	}{
+		{
+			msg: "configuration with nested entities should be valid",
+			config: `_format_version: "3.0"
+services:
+- name: echo
+  url: http://echo.default.svc:1027
+  routes:
+  - name: echo
+    paths:
+    - /echo
+    - ~/echo/v\d+$
+    plugins:
+    - name: key-auth
+consumers:
+- username: alice
+  keyauth_credentials:
+  - key: secret
+upstreams:
+- name: echo
+  targets:
+  - target: echo.default.svc:1027
+`,
+		},
+		{
+			msg:    "configuration in JSON should be valid",
+			config: `{"_format_version": "2.1", "services": [{"host": "echo.default.svc", "port": 1027}]}`,
+		},
+		{
+			msg:      "configuration which isn't YAML should be invalid",
+			config:   "services: [",
+			hasError: true,
+			errMsg:   "declarative config is not valid YAML",
+		},
+		{
+			msg:      "configuration which isn't an object should be invalid",
+			config:   "- services",
+			hasError: true,
+			errMsg:   "declarative config must be a YAML object",
+		},
+		{
+			msg:      "configuration without format version should be invalid",
+			config:   "services: []",
+			hasError: true,
+			errMsg:   "_format_version: Required value",
+		},
+		{
+			msg:      "configuration with an unsupported format version should be invalid",
+			config:   `_format_version: "0.1"`,
+			hasError: true,
+			errMsg:   `_format_version: Unsupported value: "0.1"`,
+		},
+		{
+			msg: "configuration with an unknown entity collection should be invalid",
+			config: `_format_version: "3.0"
+ingresses: []
+`,
+			hasError: true,
+			errMsg:   "ingresses: Invalid value: \"ingresses\": unknown entity collection",
+		},
+		{
+			msg: "entity collection which isn't a list should be invalid",
+			config: `_format_version: "3.0"
+services:
+  name: echo
+`,
+			hasError: true,
+			errMsg:   "services: Invalid value: map[string]interface {}{\"name\":\"echo\"}: must be a list of entities",
+		},
+		{
+			msg: "service without url nor host should be invalid",
+			config: `_format_version: "3.0"
+services:
+- name: echo
+`,
+			hasError: true,
+			errMsg:   "services[0].url: Required value: one of url, host must be set",
+		},
+		{
+			msg: "service with a relative url should be invalid",
+			config: `_format_version: "3.0"
+services:
+- url: echo
+`,
+			hasError: true,
+			errMsg:   "services[0].url: Invalid value: \"echo\": must be an absolute URL",
+		},
+		{
+			msg: "nested route without matching rule should be invalid",
+			config: `_format_version: "3.0"
+services:
+- url: http://echo.default.svc:1027
+  routes:
+  - name: echo
+`,
+			hasError: true,
+			errMsg:   "services[0].routes[0].paths: Required value",
+		},
+		{
+			msg: "route with a relative path should be invalid",
+			config: `_format_version: "3.0"
+routes:
+- paths:
+  - echo
+`,
+			hasError: true,
+			errMsg:   "routes[0].paths[0]: Invalid value: \"echo\": must start with / or ~ for regular expressions",
+		},
+		{
+			msg: "plugin which isn't bundled should be invalid",
+			config: `_format_version: "3.0"
+plugins:
+- name: custom-auth
+`,
+			hasError: true,
+			errMsg:   "plugins[0].name: Invalid value: \"custom-auth\": unknown plugin",
+		},
+		{
+			msg: "certificate without key should be invalid",
+			config: `_format_version: "3.0"
+certificates:
+- cert: cert
+`,
+			hasError: true,
+			errMsg:   "certificates[0].key: Required value",
+		},
+	}
+
+	for _, tc := range testCases {
+		err := ValidateDeclarativeConfig([]byte(tc.config))
+		if !tc.hasError {
+			require.NoErrorf(t, err, tc.msg)
+		} else {
+			require.ErrorContainsf(t, err, tc.errMsg, tc.msg)
+		}
+	}
+}
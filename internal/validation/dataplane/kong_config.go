@@ -0,0 +1,261 @@
+package dataplane
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	"github.com/kong/gateway-operator/internal/consts"
+	dataplaneutils "github.com/kong/gateway-operator/internal/utils/dataplane"
+	"github.com/kong/gateway-operator/internal/utils/image"
+)
+
+// -----------------------------------------------------------------------------
+// DataPlane Validation - Kong Configuration
+// -----------------------------------------------------------------------------
+
+// listener is a port a listen directive must include.
+type listener struct {
+	port int
+	ssl  bool
+}
+
+// requiredListeners are the ports targeted by the Services and probes generated
+// for the DataPlanes, which the listen directives must include.
+var requiredListeners = map[string][]listener{
+	"KONG_PROXY_LISTEN": {
+		{port: dataplaneutils.DefaultKongHTTPPort},
+		{port: dataplaneutils.DefaultKongHTTPSPort, ssl: true},
+	},
+	"KONG_ADMIN_LISTEN": {
+		{port: dataplaneutils.DefaultKongAdminPort, ssl: true},
+	},
+	"KONG_STATUS_LISTEN": {
+		{port: dataplaneutils.DefaultKongStatusPort},
+	},
+}
+
+// listenFlags are the flags of the listen directives, with whether they take
+// a value.
+var listenFlags = map[string]bool{
+	"backlog":        true,
+	"bind":           false,
+	"deferred":       false,
+	"http2":          false,
+	"ipv6only":       true,
+	"proxy_protocol": false,
+	"reuseport":      false,
+	"so_keepalive":   true,
+	"ssl":            false,
+}
+
+// clusterCertificateEnvVars are the settings which must point at the files of
+// the cluster certificate volume mounted in the DataPlane Pods, for the mTLS
+// connection of the ControlPlanes to the Kong Admin API.
+var clusterCertificateEnvVars = []string{
+	"KONG_ADMIN_SSL_CERT",
+	"KONG_ADMIN_SSL_CERT_KEY",
+	"KONG_NGINX_ADMIN_SSL_CLIENT_CERTIFICATE",
+}
+
+// bundledPlugins are the plugins bundled with Kong.
+var bundledPlugins = map[string]struct{}{
+	"acl":                   {},
+	"acme":                  {},
+	"aws-lambda":            {},
+	"azure-functions":       {},
+	"basic-auth":            {},
+	"bot-detection":         {},
+	"correlation-id":        {},
+	"cors":                  {},
+	"datadog":               {},
+	"file-log":              {},
+	"grpc-gateway":          {},
+	"grpc-web":              {},
+	"hmac-auth":             {},
+	"http-log":              {},
+	"ip-restriction":        {},
+	"jwt":                   {},
+	"key-auth":              {},
+	"ldap-auth":             {},
+	"loggly":                {},
+	"oauth2":                {},
+	"opentelemetry":         {},
+	"post-function":         {},
+	"pre-function":          {},
+	"prometheus":            {},
+	"proxy-cache":           {},
+	"rate-limiting":         {},
+	"request-size-limiting": {},
+	"request-termination":   {},
+	"request-transformer":   {},
+	"response-ratelimiting": {},
+	"response-transformer":  {},
+	"session":               {},
+	"statsd":                {},
+	"syslog":                {},
+	"tcp-log":               {},
+	"udp-log":               {},
+	"zipkin":                {},
+}
+
+// minimumValues are the numeric settings with the minimum value they accept.
+var minimumValues = map[string]int{
+	"KONG_DB_CACHE_TTL":                    0,
+	"KONG_LUA_SOCKET_POOL_SIZE":            1,
+	"KONG_NGINX_ADMIN_SSL_VERIFY_DEPTH":    0,
+	"KONG_UPSTREAM_KEEPALIVE_IDLE_TIMEOUT": 0,
+	"KONG_UPSTREAM_KEEPALIVE_MAX_REQUESTS": 0,
+	"KONG_UPSTREAM_KEEPALIVE_POOL_SIZE":    0,
+}
+
+// ValidateKongConfig validates the Kong configuration set by the environment
+// variables of the DataPlaneDeploymentOptions which the operator relies on,
+// and returns the first error found with the field path of the invalid
+// environment variable under envPath. The environment variables set from
+// ConfigMaps and Secrets are not validated.
+func ValidateKongConfig(envPath *field.Path, opts *operatorv1alpha1.DataPlaneDeploymentOptions) error {
+	for i, env := range opts.Env {
+		if env.ValueFrom != nil || env.Value == "" {
+			continue
+		}
+		valuePath := envPath.Index(i).Child("value")
+
+		var detail string
+		switch {
+		case requiredListeners[env.Name] != nil:
+			detail = validateListen(env.Name, env.Value)
+		case env.Name == "KONG_PLUGINS":
+			detail = validatePlugins(env.Value, allowsCustomPlugins(opts))
+		case env.Name == "KONG_NGINX_WORKER_PROCESSES":
+			if n, err := strconv.Atoi(env.Value); env.Value != "auto" && (err != nil || n < 1) {
+				detail = fmt.Sprintf("%s must be auto or a positive integer", env.Name)
+			}
+		case isClusterCertificateEnvVar(env.Name):
+			if expected := dataplaneutils.KongDefaults[env.Name]; env.Value != expected {
+				detail = fmt.Sprintf("%s must be %s, from the cluster certificate volume", env.Name, expected)
+			}
+		default:
+			if minimum, ok := minimumValues[env.Name]; ok {
+				if n, err := strconv.Atoi(env.Value); err != nil || n < minimum {
+					detail = fmt.Sprintf("%s must be an integer greater than or equal to %d", env.Name, minimum)
+				}
+			}
+		}
+
+		if detail != "" {
+			return field.Invalid(valuePath, env.Value, detail)
+		}
+	}
+	return nil
+}
+
+// validateListen validates a listen directive, made of comma-separated
+// listeners such as 0.0.0.0:8443 http2 ssl, and returns the detail of the
+// first error found.
+func validateListen(name, value string) string {
+	listening := make(map[int]bool)
+	for _, entry := range strings.Split(value, ",") {
+		fields := strings.Fields(entry)
+		if len(fields) == 0 {
+			return fmt.Sprintf("%s has an empty listener", name)
+		}
+		if len(fields) == 1 && fields[0] == "off" {
+			continue
+		}
+
+		separator := strings.LastIndex(fields[0], ":")
+		if separator <= 0 {
+			return fmt.Sprintf("%s listener %q must be an address followed by a port", name, fields[0])
+		}
+		port, err := strconv.Atoi(fields[0][separator+1:])
+		if err != nil || port < 1 || port > 65535 {
+			return fmt.Sprintf("%s listener %q has an invalid port", name, fields[0])
+		}
+
+		ssl := false
+		for _, flag := range fields[1:] {
+			flagName, flagValue, hasValue := strings.Cut(flag, "=")
+			takesValue, ok := listenFlags[flagName]
+			if !ok || takesValue != hasValue || (hasValue && flagValue == "") {
+				return fmt.Sprintf("%s listener %q has an invalid flag %q", name, fields[0], flag)
+			}
+			if flagName == "backlog" {
+				if n, err := strconv.Atoi(flagValue); err != nil || n < 1 {
+					return fmt.Sprintf("%s listener %q has an invalid flag %q", name, fields[0], flag)
+				}
+			}
+			ssl = ssl || flagName == "ssl"
+		}
+		listening[port] = ssl
+	}
+
+	for _, required := range requiredListeners[name] {
+		ssl, ok := listening[required.port]
+		switch {
+		case !ok:
+			return fmt.Sprintf("%s must listen on port %d", name, required.port)
+		case required.ssl && !ssl:
+			return fmt.Sprintf("%s must listen with ssl on port %d", name, required.port)
+		case !required.ssl && ssl:
+			return fmt.Sprintf("%s must listen without ssl on port %d", name, required.port)
+		}
+	}
+	return ""
+}
+
+// validatePlugins validates the comma-separated plugins: bundled, off, or the
+// names of bundled plugins. The names of the other plugins are only checked
+// when custom plugins are allowed.
+func validatePlugins(value string, customPluginsAllowed bool) string {
+	plugins := strings.Split(value, ",")
+	for _, plugin := range plugins {
+		plugin = strings.TrimSpace(plugin)
+		switch plugin {
+		case "bundled":
+			continue
+		case "off":
+			if len(plugins) > 1 {
+				return "KONG_PLUGINS can't list plugins when off"
+			}
+			continue
+		}
+		if _, ok := bundledPlugins[plugin]; !ok && !customPluginsAllowed {
+			return fmt.Sprintf("KONG_PLUGINS has an unknown plugin %q, "+
+				"only the bundled plugins are available without a license, a custom image or KONG_LUA_PACKAGE_PATH", plugin)
+		}
+	}
+	return ""
+}
+
+// allowsCustomPlugins tells whether the DataPlane can run plugins which
+// aren't bundled with Kong: the Kong Enterprise plugins, which require a
+// license, and the custom plugins, installed in a custom image or loaded from
+// the Lua package path.
+func allowsCustomPlugins(opts *operatorv1alpha1.DataPlaneDeploymentOptions) bool {
+	if opts.License != nil {
+		return true
+	}
+	for _, env := range opts.Env {
+		if env.Name == consts.EnvVarKongLicenseData || env.Name == "KONG_LUA_PACKAGE_PATH" {
+			return true
+		}
+	}
+	if opts.ContainerImage != nil {
+		ref, err := image.ParseReference(*opts.ContainerImage)
+		return err != nil || ref.Name() != consts.DefaultDataPlaneBaseImage
+	}
+	return false
+}
+
+func isClusterCertificateEnvVar(name string) bool {
+	for _, envName := range clusterCertificateEnvVars {
+		if name == envName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package dataplane
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	dataplaneutils "github.com/kong/gateway-operator/internal/utils/dataplane"
+)
+
+func TestValidateKongConfig(t *testing.T) {
+	envPath := field.NewPath("spec", "env")
+
+	testCases := []struct {
+		msg      string
+		envs     []corev1.EnvVar
+		options  func(*operatorv1alpha1.DataPlaneDeploymentOptions)
+		hasError bool
+		errMsg   string
+	}{
+		{
+			msg:  "listen directives including the required ports should be valid",
+			envs: []corev1.EnvVar{{Name: "KONG_PROXY_LISTEN", Value: "[::]:8000, 0.0.0.0:8443 http2 ssl, 0.0.0.0:9000 proxy_protocol"}},
+		},
+		{
+			msg:      "proxy listen without the HTTPS port should be invalid",
+			envs:     []corev1.EnvVar{{Name: "KONG_LOG_LEVEL", Value: "debug"}, {Name: "KONG_PROXY_LISTEN", Value: "0.0.0.0:8000"}},
+			hasError: true,
+			errMsg:   "spec.env[1].value: Invalid value: \"0.0.0.0:8000\": KONG_PROXY_LISTEN must listen on port 8443",
+		},
+		{
+			msg:      "admin listen without ssl should be invalid",
+			envs:     []corev1.EnvVar{{Name: "KONG_ADMIN_LISTEN", Value: "0.0.0.0:8444"}},
+			hasError: true,
+			errMsg:   "KONG_ADMIN_LISTEN must listen with ssl on port 8444",
+		},
+		{
+			msg:      "listen directive without port should be invalid",
+			envs:     []corev1.EnvVar{{Name: "KONG_STATUS_LISTEN", Value: "0.0.0.0"}},
+			hasError: true,
+			errMsg:   "must be an address followed by a port",
+		},
+		{
+			msg:      "listen directive with an unknown flag should be invalid",
+			envs:     []corev1.EnvVar{{Name: "KONG_STATUS_LISTEN", Value: "0.0.0.0:8100 fast"}},
+			hasError: true,
+			errMsg:   "has an invalid flag \"fast\"",
+		},
+		{
+			msg:      "listen directive with an invalid backlog should be invalid",
+			envs:     []corev1.EnvVar{{Name: "KONG_STATUS_LISTEN", Value: "0.0.0.0:8100 backlog=many"}},
+			hasError: true,
+			errMsg:   "has an invalid flag \"backlog=many\"",
+		},
+		{
+			msg:      "admin certificate outside of the cluster certificate volume should be invalid",
+			envs:     []corev1.EnvVar{{Name: "KONG_ADMIN_SSL_CERT", Value: "/etc/kong/tls.crt"}},
+			hasError: true,
+			errMsg:   "KONG_ADMIN_SSL_CERT must be /var/cluster-certificate/tls.crt",
+		},
+		{
+			msg:  "bundled plugins should be valid",
+			envs: []corev1.EnvVar{{Name: "KONG_PLUGINS", Value: "bundled, prometheus,key-auth"}},
+		},
+		{
+			msg:      "unknown plugins should be invalid",
+			envs:     []corev1.EnvVar{{Name: "KONG_PLUGINS", Value: "bundled,my-plugin"}},
+			hasError: true,
+			errMsg:   "spec.env[0].value: Invalid value: \"bundled,my-plugin\": KONG_PLUGINS has an unknown plugin \"my-plugin\"",
+		},
+		{
+			msg:  "custom plugins loaded from the lua package path should be valid",
+			envs: []corev1.EnvVar{{Name: "KONG_PLUGINS", Value: "bundled,my-plugin"}, {Name: "KONG_LUA_PACKAGE_PATH", Value: "/opt/?.lua;;"}},
+		},
+		{
+			msg:  "custom plugins of a custom image should be valid",
+			envs: []corev1.EnvVar{{Name: "KONG_PLUGINS", Value: "bundled,my-plugin"}},
+			options: func(opts *operatorv1alpha1.DataPlaneDeploymentOptions) {
+				opts.ContainerImage = pointer.String("registry.example.com/kong-custom")
+			},
+		},
+		{
+			msg:  "enterprise plugins of a licensed dataplane should be valid",
+			envs: []corev1.EnvVar{{Name: "KONG_PLUGINS", Value: "bundled,openid-connect"}},
+			options: func(opts *operatorv1alpha1.DataPlaneDeploymentOptions) {
+				opts.ContainerImage = pointer.String("kong/kong-gateway")
+				opts.License = &operatorv1alpha1.DataPlaneLicense{}
+			},
+		},
+		{
+			msg:  "unknown plugins of the default image should be invalid",
+			envs: []corev1.EnvVar{{Name: "KONG_PLUGINS", Value: "bundled,my-plugin"}},
+			options: func(opts *operatorv1alpha1.DataPlaneDeploymentOptions) {
+				opts.ContainerImage = pointer.String("kong")
+			},
+			hasError: true,
+			errMsg:   "KONG_PLUGINS has an unknown plugin \"my-plugin\"",
+		},
+		{
+			msg:      "plugins off along with other plugins should be invalid",
+			envs:     []corev1.EnvVar{{Name: "KONG_PLUGINS", Value: "off,cors"}},
+			hasError: true,
+			errMsg:   "KONG_PLUGINS can't list plugins when off",
+		},
+		{
+			msg:  "auto worker processes should be valid",
+			envs: []corev1.EnvVar{{Name: "KONG_NGINX_WORKER_PROCESSES", Value: "auto"}},
+		},
+		{
+			msg:      "zero worker processes should be invalid",
+			envs:     []corev1.EnvVar{{Name: "KONG_NGINX_WORKER_PROCESSES", Value: "0"}},
+			hasError: true,
+			errMsg:   "KONG_NGINX_WORKER_PROCESSES must be auto or a positive integer",
+		},
+		{
+			msg:      "negative keepalive pool size should be invalid",
+			envs:     []corev1.EnvVar{{Name: "KONG_UPSTREAM_KEEPALIVE_POOL_SIZE", Value: "-1"}},
+			hasError: true,
+			errMsg:   "KONG_UPSTREAM_KEEPALIVE_POOL_SIZE must be an integer greater than or equal to 0",
+		},
+		{
+			msg: "env from ConfigMaps should not be validated",
+			envs: []corev1.EnvVar{{
+				Name: "KONG_PROXY_LISTEN",
+				ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "kong-config"},
+					Key:                  "proxy_listen",
+				}},
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		opts := &operatorv1alpha1.DataPlaneDeploymentOptions{}
+		opts.Env = tc.envs
+		if tc.options != nil {
+			tc.options(opts)
+		}
+		err := ValidateKongConfig(envPath, opts)
+		if !tc.hasError {
+			require.NoErrorf(t, err, tc.msg)
+		} else {
+			require.ErrorContainsf(t, err, tc.errMsg, tc.msg)
+		}
+	}
+
+	t.Log("the default Kong configuration should be valid")
+	opts := &operatorv1alpha1.DataPlaneDeploymentOptions{}
+	dataplaneutils.SetDataPlaneDefaults(opts)
+	require.NoError(t, ValidateKongConfig(envPath, opts))
+}
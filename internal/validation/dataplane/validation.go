@@ -8,10 +8,13 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
 	"github.com/kong/gateway-operator/internal/consts"
+	dataplaneutils "github.com/kong/gateway-operator/internal/utils/dataplane"
+	"github.com/kong/gateway-operator/internal/utils/image"
 )
 
 // Validator validates DataPlane objects.
@@ -25,8 +28,13 @@ func NewValidator(c client.Client) *Validator {
 }
 
 // Validate validates a DataPlane object and return the first validation error found.
-func (v *Validator) Validate(dataplane *operatorv1alpha1.DataPlane) error {
-	err := v.ValidateDeployOptions(dataplane.Namespace, &dataplane.Spec.DeploymentOptions)
+func (v *Validator) Validate(ctx context.Context, dataplane *operatorv1alpha1.DataPlane) error {
+	err := v.ValidateDeployOptions(ctx, dataplane.Namespace, &dataplane.Spec.DataPlaneDeploymentOptions)
+	if err != nil {
+		return err
+	}
+
+	err = ValidateKongConfig(field.NewPath("spec", "env"), &dataplane.Spec.DataPlaneDeploymentOptions)
 	if err != nil {
 		return err
 	}
@@ -34,33 +42,232 @@ func (v *Validator) Validate(dataplane *operatorv1alpha1.DataPlane) error {
 	return nil
 }
 
-// ValidateDeployOptions validates the DeploymentOptions field of DataPlane object.
-func (v *Validator) ValidateDeployOptions(namespace string, opts *operatorv1alpha1.DeploymentOptions) error {
+// ValidateAutomaticUpgrades returns an error when the AutomaticUpgrades of the
+// DeploymentOptions are used along with a fixed version, or when the channel
+// of the Channel upgrade policy is missing.
+func ValidateAutomaticUpgrades(opts *operatorv1alpha1.DeploymentOptions) error {
+	if opts.AutomaticUpgrades == nil {
+		return nil
+	}
+	if opts.Version != nil {
+		return fmt.Errorf("version can't be set when automaticUpgrades is in use")
+	}
+	if opts.AutomaticUpgrades.Policy == operatorv1alpha1.UpgradePolicyChannel && opts.AutomaticUpgrades.Channel == "" {
+		return fmt.Errorf("channel must be set when the %s upgrade policy is in use", operatorv1alpha1.UpgradePolicyChannel)
+	}
+	return nil
+}
+
+// ValidateDeployOptions validates the DataPlaneDeploymentOptions field of DataPlane object.
+func (v *Validator) ValidateDeployOptions(ctx context.Context, namespace string, opts *operatorv1alpha1.DataPlaneDeploymentOptions) error {
+	if err := image.ValidateDeploymentOptions(&opts.DeploymentOptions); err != nil {
+		return err
+	}
+
+	if err := ValidateAutomaticUpgrades(&opts.DeploymentOptions); err != nil {
+		return err
+	}
+
+	if err := validateRole(opts); err != nil {
+		return err
+	}
+
+	if err := v.ValidateRollout(opts.Rollout); err != nil {
+		return err
+	}
 
 	// validate db mode.
-	dbMode, dbModeFound, err := v.getDBModeFromEnv(namespace, opts.Env)
+	dbMode, dbModeFound, err := v.getDBModeFromEnv(ctx, namespace, opts.Env)
 	if err != nil {
 		return err
 	}
 
 	// if dbMode not found in envVar, search for it in EnvVarFrom.
 	if !dbModeFound {
-		dbMode, _, err = v.getDBModeFromEnvFrom(namespace, opts.EnvFrom)
+		dbMode, _, err = v.getDBModeFromEnvFrom(ctx, namespace, opts.EnvFrom)
 		if err != nil {
 			return err
 		}
 	}
 
-	// only support dbless mode.
-	if dbMode != "" && dbMode != "off" {
+	// support dbless mode, and postgres mode when the database is set.
+	switch dbMode {
+	case "", "off":
+		if opts.Database != nil && dbMode != "" {
+			return fmt.Errorf("database backend %s of dataplane can't be set along with database", dbMode)
+		}
+	case consts.KongDatabasePostgres:
+		if opts.Database == nil {
+			return fmt.Errorf("database backend %s of dataplane requires database to be set", dbMode)
+		}
+	default:
 		return fmt.Errorf("database backend %s of dataplane not supported currently", dbMode)
 	}
+
+	if opts.Database != nil {
+		if err := v.ValidateDatabase(ctx, namespace, opts.Database); err != nil {
+			return err
+		}
+	}
+
+	if opts.License != nil {
+		if err := v.ValidateLicense(ctx, namespace, opts.License); err != nil {
+			return err
+		}
+	}
+
+	if opts.DeclarativeConfig != nil {
+		// the hybrid mode data planes are configured by their control plane.
+		if opts.Database != nil || opts.Role == operatorv1alpha1.DataPlaneRoleDataPlane {
+			return fmt.Errorf("declarativeConfig can only be set on DB-less traditional dataplanes")
+		}
+		return v.ValidateDeclarativeConfigSource(ctx, namespace, opts.DeclarativeConfig)
+	}
+	return nil
+}
+
+// validateRole validates the role of a DataPlane in a Kong cluster: the
+// hybrid mode control planes store the configuration of the cluster in their
+// database, and the data planes connect to a control plane.
+func validateRole(opts *operatorv1alpha1.DataPlaneDeploymentOptions) error {
+	switch opts.Role {
+	case operatorv1alpha1.DataPlaneRoleControlPlane:
+		if opts.Database == nil {
+			return fmt.Errorf("role %s of dataplane requires database to be set", opts.Role)
+		}
+	case operatorv1alpha1.DataPlaneRoleDataPlane:
+		if opts.Database != nil {
+			return fmt.Errorf("role %s of dataplane can't be set along with database", opts.Role)
+		}
+		if opts.ClusterControlPlane == "" {
+			return fmt.Errorf("clusterControlPlane must be set when the role of dataplane is %s", opts.Role)
+		}
+		return nil
+	}
+
+	if opts.ClusterControlPlane != "" {
+		return fmt.Errorf("clusterControlPlane can only be set when the role of dataplane is %s", operatorv1alpha1.DataPlaneRoleDataPlane)
+	}
+	return nil
+}
+
+// ValidateDatabase validates the Database field of DataPlane object: its
+// connection Secret must exist and set at least the host of the database.
+func (v *Validator) ValidateDatabase(ctx context.Context, namespace string, database *operatorv1alpha1.DataPlaneDatabase) error {
+	secretName := database.Postgres.ConnectionSecretRef.Name
+	if secretName == "" {
+		return fmt.Errorf("database connection secret of dataplane must be set")
+	}
+
+	secret := &corev1.Secret{}
+	namespacedName := k8stypes.NamespacedName{Namespace: namespace, Name: secretName}
+	if err := v.c.Get(ctx, namespacedName, secret); err != nil {
+		return fmt.Errorf("failed to get database connection secret %s: %w", secretName, err)
+	}
+	if len(secret.Data["host"]) == 0 {
+		return fmt.Errorf("database connection secret %s must set the host key", secretName)
+	}
+	return nil
+}
+
+// ValidateLicense validates the License field of DataPlane object: its Secret
+// must exist and hold a well-formed Kong Enterprise license. Its expiration is
+// reported by the DataPlane controller instead, as the license expires while
+// the DataPlane runs.
+func (v *Validator) ValidateLicense(ctx context.Context, namespace string, license *operatorv1alpha1.DataPlaneLicense) error {
+	secretName := license.SecretRef.Name
+	if secretName == "" {
+		return fmt.Errorf("license secret of dataplane must be set")
+	}
+
+	secret := &corev1.Secret{}
+	namespacedName := k8stypes.NamespacedName{Namespace: namespace, Name: secretName}
+	if err := v.c.Get(ctx, namespacedName, secret); err != nil {
+		return fmt.Errorf("failed to get license secret %s: %w", secretName, err)
+	}
+	data := secret.Data[consts.DataPlaneLicenseSecretKey]
+	if len(data) == 0 {
+		return fmt.Errorf("license secret %s must set the %s key", secretName, consts.DataPlaneLicenseSecretKey)
+	}
+	if _, err := dataplaneutils.ParseLicense(data); err != nil {
+		return fmt.Errorf("invalid license in secret %s: %w", secretName, err)
+	}
+	return nil
+}
+
+// ValidateDeclarativeConfigSource validates the DeclarativeConfig field of
+// DataPlane object: exactly one source must be set, and it must hold a valid
+// declarative Kong configuration.
+func (v *Validator) ValidateDeclarativeConfigSource(ctx context.Context, namespace string, source *operatorv1alpha1.DataPlaneDeclarativeConfig) error {
+	var (
+		data []byte
+		kind string
+		name string
+	)
+	switch {
+	case source.ConfigMapKeyRef != nil && source.SecretKeyRef != nil:
+		return fmt.Errorf("only one source of the declarative config can be set")
+	case source.ConfigMapKeyRef != nil:
+		kind, name = "configMap", source.ConfigMapKeyRef.Name
+		cm := &corev1.ConfigMap{}
+		namespacedName := k8stypes.NamespacedName{Namespace: namespace, Name: name}
+		if err := v.c.Get(ctx, namespacedName, cm); err != nil {
+			return fmt.Errorf("failed to get declarative config configMap %s: %w", name, err)
+		}
+		data = []byte(cm.Data[source.ConfigMapKeyRef.Key])
+	case source.SecretKeyRef != nil:
+		kind, name = "secret", source.SecretKeyRef.Name
+		secret := &corev1.Secret{}
+		namespacedName := k8stypes.NamespacedName{Namespace: namespace, Name: name}
+		if err := v.c.Get(ctx, namespacedName, secret); err != nil {
+			return fmt.Errorf("failed to get declarative config secret %s: %w", name, err)
+		}
+		data = secret.Data[source.SecretKeyRef.Key]
+	default:
+		return fmt.Errorf("a source of the declarative config must be set")
+	}
+
+	if len(data) == 0 {
+		return fmt.Errorf("declarative config %s %s must set the selected key", kind, name)
+	}
+	if err := ValidateDeclarativeConfig(data); err != nil {
+		return fmt.Errorf("invalid declarative config in %s %s: %w", kind, name, err)
+	}
+	return nil
+}
+
+// ValidateRollout validates the Rollout field of DataPlane object.
+func (v *Validator) ValidateRollout(rollout *operatorv1alpha1.DataPlaneRollout) error {
+	if rollout == nil {
+		return nil
+	}
+
+	strategy := rollout.Strategy
+	if strategy.BlueGreen != nil && strategy.Canary != nil {
+		return fmt.Errorf("only one rollout strategy can be set")
+	}
+
+	if strategy.Canary != nil {
+		return ValidateCanaryStrategy(strategy.Canary)
+	}
+	return nil
+}
+
+// ValidateCanaryStrategy validates a canary rollout strategy, used by both
+// the DataPlanes and the ControlPlanes: the weight of the canary Pods must
+// increase at each step.
+func ValidateCanaryStrategy(canary *operatorv1alpha1.CanaryStrategy) error {
+	for i, step := range canary.Steps {
+		if i > 0 && step.Weight <= canary.Steps[i-1].Weight {
+			return fmt.Errorf("canary step %d weight must be greater than the weight of the previous step", i)
+		}
+	}
 	return nil
 }
 
 // getDBModeFromEnv gets the dbmode from Env.
 // If the second return value is false, the dbMode is not found in Env.
-func (v *Validator) getDBModeFromEnv(namespace string, envs []corev1.EnvVar) (string, bool, error) {
+func (v *Validator) getDBModeFromEnv(ctx context.Context, namespace string, envs []corev1.EnvVar) (string, bool, error) {
 
 	dbMode := ""
 	dbModeFound := false
@@ -75,14 +282,14 @@ func (v *Validator) getDBModeFromEnv(namespace string, envs []corev1.EnvVar) (st
 				// value is empty,get from ValueFrom from configmap/secret.
 				if envVar.ValueFrom.ConfigMapKeyRef != nil {
 					var err error
-					dbMode, dbModeFound, err = v.getValueFromConfigMapKeyRef(namespace, envVar.ValueFrom.ConfigMapKeyRef)
+					dbMode, dbModeFound, err = v.getValueFromConfigMapKeyRef(ctx, namespace, envVar.ValueFrom.ConfigMapKeyRef)
 					if err != nil {
 						return "", false, err
 					}
 				}
 				if envVar.ValueFrom.SecretKeyRef != nil {
 					var err error
-					dbMode, dbModeFound, err = v.getValueFromSecretRef(namespace, envVar.ValueFrom.SecretKeyRef)
+					dbMode, dbModeFound, err = v.getValueFromSecretRef(ctx, namespace, envVar.ValueFrom.SecretKeyRef)
 					if err != nil {
 						return "", false, err
 					}
@@ -93,7 +300,7 @@ func (v *Validator) getDBModeFromEnv(namespace string, envs []corev1.EnvVar) (st
 	return dbMode, dbModeFound, nil
 }
 
-func (v *Validator) getDBModeFromEnvFrom(namespace string, envFroms []corev1.EnvFromSource) (string, bool, error) {
+func (v *Validator) getDBModeFromEnvFrom(ctx context.Context, namespace string, envFroms []corev1.EnvFromSource) (string, bool, error) {
 	dbMode := ""
 	dbModeFound := false
 	for _, envFrom := range envFroms {
@@ -102,7 +309,7 @@ func (v *Validator) getDBModeFromEnvFrom(namespace string, envFroms []corev1.Env
 		if strings.HasPrefix(consts.EnvVarKongDatabase, envFrom.Prefix) {
 			if envFrom.ConfigMapRef != nil {
 				var err error
-				dbMode, dbModeFound, err = v.getDBModeFromConfigMapRef(namespace, envFrom.Prefix, envFrom.ConfigMapRef)
+				dbMode, dbModeFound, err = v.getDBModeFromConfigMapRef(ctx, namespace, envFrom.Prefix, envFrom.ConfigMapRef)
 				// technically it goes slightly against eventual-consistency to throw an error here,
 				// but the alternative is that we would need to validate ALL ConfigMaps on create
 				// and do relational mapping to DataPlane resources to validate that they aren't
@@ -114,7 +321,7 @@ func (v *Validator) getDBModeFromEnvFrom(namespace string, envFroms []corev1.Env
 			}
 			if envFrom.SecretRef != nil {
 				var err error
-				dbMode, dbModeFound, err = v.getDBModeFromSecretRef(namespace, envFrom.Prefix, envFrom.SecretRef)
+				dbMode, dbModeFound, err = v.getDBModeFromSecretRef(ctx, namespace, envFrom.Prefix, envFrom.SecretRef)
 				if err != nil {
 					return "", false, err
 				}
@@ -124,10 +331,10 @@ func (v *Validator) getDBModeFromEnvFrom(namespace string, envFroms []corev1.Env
 	return dbMode, dbModeFound, nil
 }
 
-func (v *Validator) getValueFromConfigMapKeyRef(namespace string, cmKeyRef *corev1.ConfigMapKeySelector) (string, bool, error) {
+func (v *Validator) getValueFromConfigMapKeyRef(ctx context.Context, namespace string, cmKeyRef *corev1.ConfigMapKeySelector) (string, bool, error) {
 	cm := &corev1.ConfigMap{}
 	namespacedName := k8stypes.NamespacedName{Namespace: namespace, Name: cmKeyRef.Name}
-	err := v.c.Get(context.Background(), namespacedName, cm)
+	err := v.c.Get(ctx, namespacedName, cm)
 	if err != nil {
 		return "", false, fmt.Errorf("failed to get configMap %s in configMapKeyRef: %w", cmKeyRef.Name, err)
 	}
@@ -137,10 +344,10 @@ func (v *Validator) getValueFromConfigMapKeyRef(namespace string, cmKeyRef *core
 	return "", false, nil
 }
 
-func (v *Validator) getValueFromSecretRef(namespace string, secretKeyRef *corev1.SecretKeySelector) (string, bool, error) {
+func (v *Validator) getValueFromSecretRef(ctx context.Context, namespace string, secretKeyRef *corev1.SecretKeySelector) (string, bool, error) {
 	secret := &corev1.Secret{}
 	namespacedName := k8stypes.NamespacedName{Namespace: namespace, Name: secretKeyRef.Name}
-	err := v.c.Get(context.Background(), namespacedName, secret)
+	err := v.c.Get(ctx, namespacedName, secret)
 	if err != nil {
 		return "", false, fmt.Errorf("failed to get secret %s in secretRef: %w", secretKeyRef.Name, err)
 	}
@@ -153,10 +360,10 @@ func (v *Validator) getValueFromSecretRef(namespace string, secretKeyRef *corev1
 	return "", false, nil
 }
 
-func (v *Validator) getDBModeFromConfigMapRef(namespace string, prefix string, cmRef *corev1.ConfigMapEnvSource) (string, bool, error) {
+func (v *Validator) getDBModeFromConfigMapRef(ctx context.Context, namespace string, prefix string, cmRef *corev1.ConfigMapEnvSource) (string, bool, error) {
 	cm := &corev1.ConfigMap{}
 	namespacedName := k8stypes.NamespacedName{Namespace: namespace, Name: cmRef.Name}
-	err := v.c.Get(context.Background(), namespacedName, cm)
+	err := v.c.Get(ctx, namespacedName, cm)
 	if err != nil {
 		return "", false, fmt.Errorf("failed to get configMap %s in configMapRef: %w", cmRef.Name, err)
 	}
@@ -171,10 +378,10 @@ func (v *Validator) getDBModeFromConfigMapRef(namespace string, prefix string, c
 	return dbMode, ok, nil
 }
 
-func (v *Validator) getDBModeFromSecretRef(namespace string, prefix string, secretRef *corev1.SecretEnvSource) (string, bool, error) {
+func (v *Validator) getDBModeFromSecretRef(ctx context.Context, namespace string, prefix string, secretRef *corev1.SecretEnvSource) (string, bool, error) {
 	secret := &corev1.Secret{}
 	namespacedName := k8stypes.NamespacedName{Namespace: namespace, Name: secretRef.Name}
-	err := v.c.Get(context.Background(), namespacedName, secret)
+	err := v.c.Get(ctx, namespacedName, secret)
 	if err != nil {
 		return "", false, fmt.Errorf("failed to get secret %s in secretRef: %w", secretRef, err)
 	}
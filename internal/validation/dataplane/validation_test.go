@@ -1,12 +1,14 @@
 package dataplane
 
 import (
+	"context"
 	"encoding/base64"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
@@ -38,6 +40,44 @@ func TestValidateDeployOptions(t *testing.T) {
 				"KONG_DATABASE": "xxx",
 			},
 		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-postgres"},
+			Data: map[string][]byte{
+				"host":     []byte("postgres.default.svc"),
+				"password": []byte("kong"),
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-postgres-without-host"},
+			Data: map[string][]byte{
+				"password": []byte("kong"),
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-license"},
+			Data: map[string][]byte{
+				"license": []byte(`{"license":{"payload":{"customer":"Kong","license_expiration_date":"2023-01-31","license_key":"key"},"signature":"6985","version":"1"}}`),
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-license-without-signature"},
+			Data: map[string][]byte{
+				"license": []byte(`{"license":{"payload":{"customer":"Kong","license_expiration_date":"2023-01-31","license_key":"key"},"version":"1"}}`),
+			},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-declarative-config"},
+			Data: map[string]string{
+				"kong.yml":         "_format_version: \"3.0\"\nservices:\n- url: http://echo.default.svc:1027\n",
+				"invalid-kong.yml": "_format_version: \"3.0\"\nservices:\n- name: echo\n",
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-declarative-config"},
+			Data: map[string][]byte{
+				"kong.yml": []byte("_format_version: \"3.0\"\n"),
+			},
+		},
 		&corev1.Secret{
 			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-secret-2"},
 			// fake client does not encode fields in StringData to Data,
@@ -99,7 +139,7 @@ func TestValidateDeployOptions(t *testing.T) {
 			hasError: false,
 		},
 		{
-			msg: "dataplane with dbmode=postgres should be invalid",
+			msg: "dataplane with dbmode=postgres and no database should be invalid",
 			dataplane: &operatorv1alpha1.DataPlane{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "test-db-postgres",
@@ -119,7 +159,7 @@ func TestValidateDeployOptions(t *testing.T) {
 				},
 			},
 			hasError: true,
-			errMsg:   "database backend postgres of dataplane not supported currently",
+			errMsg:   "database backend postgres of dataplane requires database to be set",
 		},
 		{
 			msg: "dataplane with arbitrary dbmode should be invalid",
@@ -172,7 +212,7 @@ func TestValidateDeployOptions(t *testing.T) {
 			hasError: false,
 		},
 		{
-			msg: "dataplane with dbmode=postgres (from secret) should be invalid",
+			msg: "dataplane with dbmode=postgres (from secret) and no database should be invalid",
 			dataplane: &operatorv1alpha1.DataPlane{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "test-db-postgres-in-secret",
@@ -197,7 +237,7 @@ func TestValidateDeployOptions(t *testing.T) {
 				},
 			},
 			hasError: true,
-			errMsg:   "database backend postgres of dataplane not supported currently",
+			errMsg:   "database backend postgres of dataplane requires database to be set",
 		},
 		{
 			msg: "dataplane with dbmode=xxx (from configmap in envFrom) should be invalid",
@@ -249,13 +289,529 @@ func TestValidateDeployOptions(t *testing.T) {
 			hasError: true,
 			errMsg:   "database backend xxx of dataplane not supported currently",
 		},
+		{
+			msg: "dataplane with a database should be valid",
+			dataplane: &operatorv1alpha1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-database",
+					Namespace: "default",
+				},
+				Spec: operatorv1alpha1.DataPlaneSpec{
+					DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+						DeploymentOptions: operatorv1alpha1.DeploymentOptions{
+							Env: []corev1.EnvVar{
+								{
+									Name:  consts.EnvVarKongDatabase,
+									Value: "postgres",
+								},
+							},
+						},
+						Database: &operatorv1alpha1.DataPlaneDatabase{
+							Postgres: operatorv1alpha1.PostgresDatabase{
+								ConnectionSecretRef: corev1.LocalObjectReference{Name: "test-postgres"},
+							},
+						},
+					},
+				},
+			},
+			hasError: false,
+		},
+		{
+			msg: "dataplane with a database and dbmode=off should be invalid",
+			dataplane: &operatorv1alpha1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-database-db-off",
+					Namespace: "default",
+				},
+				Spec: operatorv1alpha1.DataPlaneSpec{
+					DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+						DeploymentOptions: operatorv1alpha1.DeploymentOptions{
+							Env: []corev1.EnvVar{
+								{
+									Name:  consts.EnvVarKongDatabase,
+									Value: "off",
+								},
+							},
+						},
+						Database: &operatorv1alpha1.DataPlaneDatabase{
+							Postgres: operatorv1alpha1.PostgresDatabase{
+								ConnectionSecretRef: corev1.LocalObjectReference{Name: "test-postgres"},
+							},
+						},
+					},
+				},
+			},
+			hasError: true,
+			errMsg:   "database backend off of dataplane can't be set along with database",
+		},
+		{
+			msg: "dataplane with a missing database connection secret should be invalid",
+			dataplane: &operatorv1alpha1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-database-missing-secret",
+					Namespace: "default",
+				},
+				Spec: operatorv1alpha1.DataPlaneSpec{
+					DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+						Database: &operatorv1alpha1.DataPlaneDatabase{
+							Postgres: operatorv1alpha1.PostgresDatabase{
+								ConnectionSecretRef: corev1.LocalObjectReference{Name: "test-postgres-missing"},
+							},
+						},
+					},
+				},
+			},
+			hasError: true,
+			errMsg:   "failed to get database connection secret test-postgres-missing",
+		},
+		{
+			msg: "dataplane with a database connection secret without host should be invalid",
+			dataplane: &operatorv1alpha1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-database-without-host",
+					Namespace: "default",
+				},
+				Spec: operatorv1alpha1.DataPlaneSpec{
+					DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+						Database: &operatorv1alpha1.DataPlaneDatabase{
+							Postgres: operatorv1alpha1.PostgresDatabase{
+								ConnectionSecretRef: corev1.LocalObjectReference{Name: "test-postgres-without-host"},
+							},
+						},
+					},
+				},
+			},
+			hasError: true,
+			errMsg:   "database connection secret test-postgres-without-host must set the host key",
+		},
+		{
+			msg: "dataplane with a license should be valid",
+			dataplane: &operatorv1alpha1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-license",
+					Namespace: "default",
+				},
+				Spec: operatorv1alpha1.DataPlaneSpec{
+					DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+						License: &operatorv1alpha1.DataPlaneLicense{
+							SecretRef: corev1.LocalObjectReference{Name: "test-license"},
+						},
+					},
+				},
+			},
+			hasError: false,
+		},
+		{
+			msg: "dataplane with a missing license secret should be invalid",
+			dataplane: &operatorv1alpha1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-license-missing-secret",
+					Namespace: "default",
+				},
+				Spec: operatorv1alpha1.DataPlaneSpec{
+					DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+						License: &operatorv1alpha1.DataPlaneLicense{
+							SecretRef: corev1.LocalObjectReference{Name: "test-license-missing"},
+						},
+					},
+				},
+			},
+			hasError: true,
+			errMsg:   "failed to get license secret test-license-missing",
+		},
+		{
+			msg: "dataplane with a license secret without license should be invalid",
+			dataplane: &operatorv1alpha1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-license-without-key",
+					Namespace: "default",
+				},
+				Spec: operatorv1alpha1.DataPlaneSpec{
+					DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+						License: &operatorv1alpha1.DataPlaneLicense{
+							SecretRef: corev1.LocalObjectReference{Name: "test-secret"},
+						},
+					},
+				},
+			},
+			hasError: true,
+			errMsg:   "license secret test-secret must set the license key",
+		},
+		{
+			msg: "dataplane with a malformed license should be invalid",
+			dataplane: &operatorv1alpha1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-license-without-signature",
+					Namespace: "default",
+				},
+				Spec: operatorv1alpha1.DataPlaneSpec{
+					DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+						License: &operatorv1alpha1.DataPlaneLicense{
+							SecretRef: corev1.LocalObjectReference{Name: "test-license-without-signature"},
+						},
+					},
+				},
+			},
+			hasError: true,
+			errMsg:   "invalid license in secret test-license-without-signature: license must have a signature",
+		},
+		{
+			msg: "dataplane with a declarative config from a configmap should be valid",
+			dataplane: &operatorv1alpha1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-declarative-config-cm",
+					Namespace: "default",
+				},
+				Spec: operatorv1alpha1.DataPlaneSpec{
+					DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+						DeclarativeConfig: &operatorv1alpha1.DataPlaneDeclarativeConfig{
+							ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "test-declarative-config"},
+								Key:                  "kong.yml",
+							},
+						},
+					},
+				},
+			},
+			hasError: false,
+		},
+		{
+			msg: "dataplane with a declarative config from a secret should be valid",
+			dataplane: &operatorv1alpha1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-declarative-config-secret",
+					Namespace: "default",
+				},
+				Spec: operatorv1alpha1.DataPlaneSpec{
+					DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+						DeclarativeConfig: &operatorv1alpha1.DataPlaneDeclarativeConfig{
+							SecretKeyRef: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "test-declarative-config"},
+								Key:                  "kong.yml",
+							},
+						},
+					},
+				},
+			},
+			hasError: false,
+		},
+		{
+			msg: "dataplane with two declarative config sources should be invalid",
+			dataplane: &operatorv1alpha1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-declarative-config-two-sources",
+					Namespace: "default",
+				},
+				Spec: operatorv1alpha1.DataPlaneSpec{
+					DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+						DeclarativeConfig: &operatorv1alpha1.DataPlaneDeclarativeConfig{
+							ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "test-declarative-config"},
+								Key:                  "kong.yml",
+							},
+							SecretKeyRef: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "test-declarative-config"},
+								Key:                  "kong.yml",
+							},
+						},
+					},
+				},
+			},
+			hasError: true,
+			errMsg:   "only one source of the declarative config can be set",
+		},
+		{
+			msg: "dataplane with an empty declarative config source should be invalid",
+			dataplane: &operatorv1alpha1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-declarative-config-no-source",
+					Namespace: "default",
+				},
+				Spec: operatorv1alpha1.DataPlaneSpec{
+					DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+						DeclarativeConfig: &operatorv1alpha1.DataPlaneDeclarativeConfig{},
+					},
+				},
+			},
+			hasError: true,
+			errMsg:   "a source of the declarative config must be set",
+		},
+		{
+			msg: "dataplane with a missing declarative config secret should be invalid",
+			dataplane: &operatorv1alpha1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-declarative-config-missing",
+					Namespace: "default",
+				},
+				Spec: operatorv1alpha1.DataPlaneSpec{
+					DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+						DeclarativeConfig: &operatorv1alpha1.DataPlaneDeclarativeConfig{
+							SecretKeyRef: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "test-declarative-config-missing"},
+								Key:                  "kong.yml",
+							},
+						},
+					},
+				},
+			},
+			hasError: true,
+			errMsg:   "failed to get declarative config secret test-declarative-config-missing",
+		},
+		{
+			msg: "dataplane with a missing declarative config key should be invalid",
+			dataplane: &operatorv1alpha1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-declarative-config-missing-key",
+					Namespace: "default",
+				},
+				Spec: operatorv1alpha1.DataPlaneSpec{
+					DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+						DeclarativeConfig: &operatorv1alpha1.DataPlaneDeclarativeConfig{
+							ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "test-declarative-config"},
+								Key:                  "missing.yml",
+							},
+						},
+					},
+				},
+			},
+			hasError: true,
+			errMsg:   "declarative config configMap test-declarative-config must set the selected key",
+		},
+		{
+			msg: "dataplane with an invalid declarative config should be invalid",
+			dataplane: &operatorv1alpha1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-declarative-config-invalid",
+					Namespace: "default",
+				},
+				Spec: operatorv1alpha1.DataPlaneSpec{
+					DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+						DeclarativeConfig: &operatorv1alpha1.DataPlaneDeclarativeConfig{
+							ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "test-declarative-config"},
+								Key:                  "invalid-kong.yml",
+							},
+						},
+					},
+				},
+			},
+			hasError: true,
+			errMsg:   "invalid declarative config in configMap test-declarative-config: services[0].url: Required value",
+		},
+		{
+			msg: "hybrid mode data plane with a declarative config should be invalid",
+			dataplane: &operatorv1alpha1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-declarative-config-hybrid",
+					Namespace: "default",
+				},
+				Spec: operatorv1alpha1.DataPlaneSpec{
+					DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+						Role:                operatorv1alpha1.DataPlaneRoleDataPlane,
+						ClusterControlPlane: "test-hybrid-cp",
+						DeclarativeConfig: &operatorv1alpha1.DataPlaneDeclarativeConfig{
+							ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "test-declarative-config"},
+								Key:                  "kong.yml",
+							},
+						},
+					},
+				},
+			},
+			hasError: true,
+			errMsg:   "declarativeConfig can only be set on DB-less traditional dataplanes",
+		},
+		{
+			msg: "hybrid mode control plane with a database should be valid",
+			dataplane: &operatorv1alpha1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-hybrid-cp",
+					Namespace: "default",
+				},
+				Spec: operatorv1alpha1.DataPlaneSpec{
+					DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+						Role: operatorv1alpha1.DataPlaneRoleControlPlane,
+						Database: &operatorv1alpha1.DataPlaneDatabase{
+							Postgres: operatorv1alpha1.PostgresDatabase{
+								ConnectionSecretRef: corev1.LocalObjectReference{Name: "test-postgres"},
+							},
+						},
+					},
+				},
+			},
+			hasError: false,
+		},
+		{
+			msg: "hybrid mode control plane without database should be invalid",
+			dataplane: &operatorv1alpha1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-hybrid-cp-without-database",
+					Namespace: "default",
+				},
+				Spec: operatorv1alpha1.DataPlaneSpec{
+					DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+						Role: operatorv1alpha1.DataPlaneRoleControlPlane,
+					},
+				},
+			},
+			hasError: true,
+			errMsg:   "role control_plane of dataplane requires database to be set",
+		},
+		{
+			msg: "hybrid mode data plane connecting to a control plane should be valid",
+			dataplane: &operatorv1alpha1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-hybrid-dp",
+					Namespace: "default",
+				},
+				Spec: operatorv1alpha1.DataPlaneSpec{
+					DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+						Role:                operatorv1alpha1.DataPlaneRoleDataPlane,
+						ClusterControlPlane: "test-hybrid-cp",
+					},
+				},
+			},
+			hasError: false,
+		},
+		{
+			msg: "hybrid mode data plane without control plane should be invalid",
+			dataplane: &operatorv1alpha1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-hybrid-dp-without-cp",
+					Namespace: "default",
+				},
+				Spec: operatorv1alpha1.DataPlaneSpec{
+					DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+						Role: operatorv1alpha1.DataPlaneRoleDataPlane,
+					},
+				},
+			},
+			hasError: true,
+			errMsg:   "clusterControlPlane must be set when the role of dataplane is data_plane",
+		},
+		{
+			msg: "hybrid mode data plane with a database should be invalid",
+			dataplane: &operatorv1alpha1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-hybrid-dp-with-database",
+					Namespace: "default",
+				},
+				Spec: operatorv1alpha1.DataPlaneSpec{
+					DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+						Role:                operatorv1alpha1.DataPlaneRoleDataPlane,
+						ClusterControlPlane: "test-hybrid-cp",
+						Database: &operatorv1alpha1.DataPlaneDatabase{
+							Postgres: operatorv1alpha1.PostgresDatabase{
+								ConnectionSecretRef: corev1.LocalObjectReference{Name: "test-postgres"},
+							},
+						},
+					},
+				},
+			},
+			hasError: true,
+			errMsg:   "role data_plane of dataplane can't be set along with database",
+		},
+		{
+			msg: "traditional dataplane with a control plane should be invalid",
+			dataplane: &operatorv1alpha1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-traditional-with-cp",
+					Namespace: "default",
+				},
+				Spec: operatorv1alpha1.DataPlaneSpec{
+					DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+						ClusterControlPlane: "test-hybrid-cp",
+					},
+				},
+			},
+			hasError: true,
+			errMsg:   "clusterControlPlane can only be set when the role of dataplane is data_plane",
+		},
+		{
+			msg: "dataplane with version and automatic upgrades should be invalid",
+			dataplane: &operatorv1alpha1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-version-and-automatic-upgrades",
+					Namespace: "default",
+				},
+				Spec: operatorv1alpha1.DataPlaneSpec{
+					DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+						DeploymentOptions: operatorv1alpha1.DeploymentOptions{
+							Version: pointer.String("2.8.1"),
+							AutomaticUpgrades: &operatorv1alpha1.AutomaticUpgrades{
+								Policy: operatorv1alpha1.UpgradePolicyPatch,
+							},
+						},
+					},
+				},
+			},
+			hasError: true,
+			errMsg:   "version can't be set when automaticUpgrades is in use",
+		},
+		{
+			msg: "dataplane with channel upgrade policy and no channel should be invalid",
+			dataplane: &operatorv1alpha1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-automatic-upgrades-without-channel",
+					Namespace: "default",
+				},
+				Spec: operatorv1alpha1.DataPlaneSpec{
+					DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+						DeploymentOptions: operatorv1alpha1.DeploymentOptions{
+							AutomaticUpgrades: &operatorv1alpha1.AutomaticUpgrades{
+								Policy: operatorv1alpha1.UpgradePolicyChannel,
+							},
+						},
+					},
+				},
+			},
+			hasError: true,
+			errMsg:   "channel must be set when the Channel upgrade policy is in use",
+		},
+		{
+			msg: "dataplane with an unparseable container image should be invalid",
+			dataplane: &operatorv1alpha1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-invalid-container-image",
+					Namespace: "default",
+				},
+				Spec: operatorv1alpha1.DataPlaneSpec{
+					DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+						DeploymentOptions: operatorv1alpha1.DeploymentOptions{
+							ContainerImage: pointer.String("kong:2.8:3.0"),
+						},
+					},
+				},
+			},
+			hasError: true,
+			errMsg:   "invalid containerImage",
+		},
+		{
+			msg: "dataplane with a registry port and digest pinned container image should be valid",
+			dataplane: &operatorv1alpha1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-digest-container-image",
+					Namespace: "default",
+				},
+				Spec: operatorv1alpha1.DataPlaneSpec{
+					DataPlaneDeploymentOptions: operatorv1alpha1.DataPlaneDeploymentOptions{
+						DeploymentOptions: operatorv1alpha1.DeploymentOptions{
+							ContainerImage:  pointer.String("registry:5000/kong/kong@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"),
+							SemanticVersion: pointer.String("3.0.0"),
+						},
+					},
+				},
+			},
+			hasError: false,
+		},
 	}
 
 	for _, tc := range testCases {
 		v := &Validator{
 			c: b.Build(),
 		}
-		err := v.Validate(tc.dataplane)
+		err := v.Validate(context.Background(), tc.dataplane)
 		if !tc.hasError {
 			require.NoErrorf(t, err, tc.msg)
 		} else {
@@ -263,3 +819,70 @@ func TestValidateDeployOptions(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateRollout(t *testing.T) {
+	v := NewValidator(fakeclient.NewClientBuilder().Build())
+
+	testCases := []struct {
+		msg     string
+		rollout *operatorv1alpha1.DataPlaneRollout
+		errMsg  string
+	}{
+		{
+			msg: "no rollout should be valid",
+		},
+		{
+			msg: "blue/green rollout should be valid",
+			rollout: &operatorv1alpha1.DataPlaneRollout{
+				Strategy: operatorv1alpha1.DataPlaneRolloutStrategy{
+					BlueGreen: &operatorv1alpha1.BlueGreenStrategy{},
+				},
+			},
+		},
+		{
+			msg: "canary rollout with increasing weights should be valid",
+			rollout: &operatorv1alpha1.DataPlaneRollout{
+				Strategy: operatorv1alpha1.DataPlaneRolloutStrategy{
+					Canary: &operatorv1alpha1.CanaryStrategy{
+						Steps: []operatorv1alpha1.CanaryStep{{Weight: 10}, {Weight: 50}, {Weight: 100}},
+					},
+				},
+			},
+		},
+		{
+			msg: "canary rollout with decreasing weights should be invalid",
+			rollout: &operatorv1alpha1.DataPlaneRollout{
+				Strategy: operatorv1alpha1.DataPlaneRolloutStrategy{
+					Canary: &operatorv1alpha1.CanaryStrategy{
+						Steps: []operatorv1alpha1.CanaryStep{{Weight: 50}, {Weight: 50}},
+					},
+				},
+			},
+			errMsg: "canary step 1 weight must be greater than the weight of the previous step",
+		},
+		{
+			msg: "rollout with both strategies should be invalid",
+			rollout: &operatorv1alpha1.DataPlaneRollout{
+				Strategy: operatorv1alpha1.DataPlaneRolloutStrategy{
+					BlueGreen: &operatorv1alpha1.BlueGreenStrategy{},
+					Canary: &operatorv1alpha1.CanaryStrategy{
+						Steps: []operatorv1alpha1.CanaryStep{{Weight: 50}},
+					},
+				},
+			},
+			errMsg: "only one rollout strategy can be set",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.msg, func(t *testing.T) {
+			err := v.ValidateRollout(tc.rollout)
+			if tc.errMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.errMsg)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package gatewayconfiguration
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	controlplanevalidation "github.com/kong/gateway-operator/internal/validation/controlplane"
+	dataplanevalidation "github.com/kong/gateway-operator/internal/validation/dataplane"
+)
+
+// Validator validates GatewayConfiguration objects.
+type Validator struct {
+	controlplaneValidator *controlplanevalidation.Validator
+	dataplaneValidator    *dataplanevalidation.Validator
+}
+
+// NewValidator creates a GatewayConfiguration validator.
+func NewValidator(c client.Client) *Validator {
+	return &Validator{
+		controlplaneValidator: controlplanevalidation.NewValidator(c),
+		dataplaneValidator:    dataplanevalidation.NewValidator(c),
+	}
+}
+
+// Validate validates a GatewayConfiguration object and return the first validation error found.
+func (v *Validator) Validate(ctx context.Context, gatewayConfig *operatorv1alpha1.GatewayConfiguration) error {
+	if opts := gatewayConfig.Spec.DataPlaneDeploymentOptions; opts != nil {
+		// the configuration pushed by the ControlPlanes of the Gateways
+		// replaces any declarative configuration of their DataPlanes.
+		if opts.DeclarativeConfig != nil {
+			return fmt.Errorf("invalid dataPlaneDeploymentOptions: declarativeConfig of dataplane is not supported by Gateways")
+		}
+		if err := v.dataplaneValidator.ValidateDeployOptions(ctx, gatewayConfig.Namespace, opts); err != nil {
+			return fmt.Errorf("invalid dataPlaneDeploymentOptions: %w", err)
+		}
+		// the DataPlanes of the Gateways are configured by their ControlPlanes
+		// through the Kong Admin API, which the hybrid mode data planes don't
+		// serve.
+		if opts.Role != "" && opts.Role != operatorv1alpha1.DataPlaneRoleTraditional {
+			return fmt.Errorf("invalid dataPlaneDeploymentOptions: role %s of dataplane is not supported by Gateways", opts.Role)
+		}
+		envPath := field.NewPath("spec", "dataPlaneDeploymentOptions", "env")
+		if err := dataplanevalidation.ValidateKongConfig(envPath, opts); err != nil {
+			return err
+		}
+	}
+
+	if opts := gatewayConfig.Spec.ControlPlaneDeploymentOptions; opts != nil {
+		if err := v.ValidateControlPlaneDeployOptions(gatewayConfig.Name, opts); err != nil {
+			return fmt.Errorf("invalid controlPlaneDeploymentOptions: %w", err)
+		}
+	}
+
+	if targetRef := gatewayConfig.Spec.TargetRef; targetRef != nil {
+		if err := ValidateTargetRef(targetRef); err != nil {
+			return fmt.Errorf("invalid targetRef: %w", err)
+		}
+		// the shared dataplanes are configured by the GatewayConfigurations of
+		// the GatewayClasses for all the Gateways using them.
+		if gatewayConfig.Spec.SharedDataPlane != nil {
+			return fmt.Errorf("sharedDataPlane can't be set in a GatewayConfiguration attached to a Gateway")
+		}
+	}
+	// prepared for more validations
+	return nil
+}
+
+// ValidateControlPlaneDeployOptions validates the ControlPlaneDeploymentOptions
+// field of GatewayConfiguration object. The DataPlane of the ControlPlanes is
+// assigned by the Gateway controller, in the namespace of each Gateway, hence
+// neither the DataPlane nor the Kong Admin API URLs can be set.
+func (v *Validator) ValidateControlPlaneDeployOptions(gatewayConfigName string, opts *operatorv1alpha1.ControlPlaneDeploymentOptions) error {
+	if opts.DataPlane != nil && *opts.DataPlane != "" {
+		return fmt.Errorf("dataplane can't be set, it is assigned by the Gateway controller")
+	}
+	return v.controlplaneValidator.ValidateDeployOptions(gatewayConfigName, "", opts)
+}
+
+// ValidateTargetRef validates the TargetRef field of GatewayConfiguration
+// object: GatewayConfigurations can only be attached to Gateways.
+func ValidateTargetRef(targetRef *operatorv1alpha1.GatewayConfigurationTargetReference) error {
+	if targetRef.Group != "" && targetRef.Group != gatewayv1alpha2.GroupName {
+		return fmt.Errorf("group %s is not supported, only %s is", targetRef.Group, gatewayv1alpha2.GroupName)
+	}
+	if targetRef.Kind != operatorv1alpha1.GatewayConfigurationTargetKindGateway {
+		return fmt.Errorf("kind %s is not supported, only %s is", targetRef.Kind, operatorv1alpha1.GatewayConfigurationTargetKindGateway)
+	}
+	if targetRef.Name == "" {
+		return fmt.Errorf("name must be set")
+	}
+	return nil
+}
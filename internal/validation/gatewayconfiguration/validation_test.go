@@ -0,0 +1,194 @@
+package gatewayconfiguration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	"github.com/kong/gateway-operator/internal/consts"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		msg           string
+		gatewayConfig *operatorv1alpha1.GatewayConfiguration
+		hasError      bool
+		errMsg        string
+	}{
+		{
+			msg:           "gatewayconfiguration without options should be valid",
+			gatewayConfig: &operatorv1alpha1.GatewayConfiguration{},
+		},
+		{
+			msg: "gatewayconfiguration with valid options should be valid",
+			gatewayConfig: &operatorv1alpha1.GatewayConfiguration{
+				Spec: operatorv1alpha1.GatewayConfigurationSpec{
+					DataPlaneDeploymentOptions: &operatorv1alpha1.DataPlaneDeploymentOptions{
+						DeploymentOptions: operatorv1alpha1.DeploymentOptions{
+							Env: []corev1.EnvVar{{Name: consts.EnvVarKongDatabase, Value: "off"}},
+						},
+					},
+					ControlPlaneDeploymentOptions: &operatorv1alpha1.ControlPlaneDeploymentOptions{
+						DeploymentOptions: operatorv1alpha1.DeploymentOptions{
+							Version: pointer.String("2.7"),
+							Env:     []corev1.EnvVar{{Name: "CONTROLLER_LOG_LEVEL", Value: "debug"}},
+						},
+					},
+				},
+			},
+		},
+		{
+			msg: "gatewayconfiguration with a dataplane postgres database backend and no database should be invalid",
+			gatewayConfig: &operatorv1alpha1.GatewayConfiguration{
+				Spec: operatorv1alpha1.GatewayConfigurationSpec{
+					DataPlaneDeploymentOptions: &operatorv1alpha1.DataPlaneDeploymentOptions{
+						DeploymentOptions: operatorv1alpha1.DeploymentOptions{
+							Env: []corev1.EnvVar{{Name: consts.EnvVarKongDatabase, Value: "postgres"}},
+						},
+					},
+				},
+			},
+			hasError: true,
+			errMsg:   "invalid dataPlaneDeploymentOptions: database backend postgres of dataplane requires database to be set",
+		},
+		{
+			msg: "gatewayconfiguration with a hybrid mode dataplane role should be invalid",
+			gatewayConfig: &operatorv1alpha1.GatewayConfiguration{
+				Spec: operatorv1alpha1.GatewayConfigurationSpec{
+					DataPlaneDeploymentOptions: &operatorv1alpha1.DataPlaneDeploymentOptions{
+						Role:                operatorv1alpha1.DataPlaneRoleDataPlane,
+						ClusterControlPlane: "kong-cp",
+					},
+				},
+			},
+			hasError: true,
+			errMsg:   "invalid dataPlaneDeploymentOptions: role data_plane of dataplane is not supported by Gateways",
+		},
+		{
+			msg: "gatewayconfiguration with a dataplane declarative config should be invalid",
+			gatewayConfig: &operatorv1alpha1.GatewayConfiguration{
+				Spec: operatorv1alpha1.GatewayConfigurationSpec{
+					DataPlaneDeploymentOptions: &operatorv1alpha1.DataPlaneDeploymentOptions{
+						DeclarativeConfig: &operatorv1alpha1.DataPlaneDeclarativeConfig{
+							ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "kong-config"},
+								Key:                  "kong.yml",
+							},
+						},
+					},
+				},
+			},
+			hasError: true,
+			errMsg:   "invalid dataPlaneDeploymentOptions: declarativeConfig of dataplane is not supported by Gateways",
+		},
+		{
+			msg: "gatewayconfiguration with an invalid dataplane Kong configuration should be invalid",
+			gatewayConfig: &operatorv1alpha1.GatewayConfiguration{
+				Spec: operatorv1alpha1.GatewayConfigurationSpec{
+					DataPlaneDeploymentOptions: &operatorv1alpha1.DataPlaneDeploymentOptions{
+						DeploymentOptions: operatorv1alpha1.DeploymentOptions{
+							Env: []corev1.EnvVar{{Name: "KONG_NGINX_WORKER_PROCESSES", Value: "many"}},
+						},
+					},
+				},
+			},
+			hasError: true,
+			errMsg:   "spec.dataPlaneDeploymentOptions.env[0].value: Invalid value: \"many\"",
+		},
+		{
+			msg: "gatewayconfiguration with a controlplane dataplane should be invalid",
+			gatewayConfig: &operatorv1alpha1.GatewayConfiguration{
+				Spec: operatorv1alpha1.GatewayConfigurationSpec{
+					ControlPlaneDeploymentOptions: &operatorv1alpha1.ControlPlaneDeploymentOptions{
+						DataPlane: pointer.String("kong"),
+					},
+				},
+			},
+			hasError: true,
+			errMsg:   "invalid controlPlaneDeploymentOptions: dataplane can't be set",
+		},
+		{
+			msg: "gatewayconfiguration with a Kong Admin API URL should be invalid",
+			gatewayConfig: &operatorv1alpha1.GatewayConfiguration{
+				Spec: operatorv1alpha1.GatewayConfigurationSpec{
+					ControlPlaneDeploymentOptions: &operatorv1alpha1.ControlPlaneDeploymentOptions{
+						DeploymentOptions: operatorv1alpha1.DeploymentOptions{
+							Env: []corev1.EnvVar{{Name: "CONTROLLER_KONG_ADMIN_URL", Value: "https://kong.default.svc:8444"}},
+						},
+					},
+				},
+			},
+			hasError: true,
+			errMsg:   "env CONTROLLER_KONG_ADMIN_URL is owned by the operator",
+		},
+		{
+			msg: "gatewayconfiguration with an unsupported controlplane version should be invalid",
+			gatewayConfig: &operatorv1alpha1.GatewayConfiguration{
+				ObjectMeta: metav1.ObjectMeta{Name: "kong"},
+				Spec: operatorv1alpha1.GatewayConfigurationSpec{
+					ControlPlaneDeploymentOptions: &operatorv1alpha1.ControlPlaneDeploymentOptions{
+						DeploymentOptions: operatorv1alpha1.DeploymentOptions{
+							Version: pointer.String("1.3"),
+						},
+					},
+				},
+			},
+			hasError: true,
+			errMsg:   "unsupported controlplane version",
+		},
+		{
+			msg: "gatewayconfiguration attached to a gateway should be valid",
+			gatewayConfig: &operatorv1alpha1.GatewayConfiguration{
+				Spec: operatorv1alpha1.GatewayConfigurationSpec{
+					TargetRef: &operatorv1alpha1.GatewayConfigurationTargetReference{
+						Kind: operatorv1alpha1.GatewayConfigurationTargetKindGateway,
+						Name: "kong",
+					},
+				},
+			},
+		},
+		{
+			msg: "gatewayconfiguration attached to a gatewayclass should be invalid",
+			gatewayConfig: &operatorv1alpha1.GatewayConfiguration{
+				Spec: operatorv1alpha1.GatewayConfigurationSpec{
+					TargetRef: &operatorv1alpha1.GatewayConfigurationTargetReference{
+						Kind: operatorv1alpha1.GatewayConfigurationTargetKindGatewayClass,
+						Name: "kong",
+					},
+				},
+			},
+			hasError: true,
+			errMsg:   "invalid targetRef: kind GatewayClass is not supported, only Gateway is",
+		},
+		{
+			msg: "gatewayconfiguration attached to a gateway with a shared dataplane should be invalid",
+			gatewayConfig: &operatorv1alpha1.GatewayConfiguration{
+				Spec: operatorv1alpha1.GatewayConfigurationSpec{
+					SharedDataPlane: &operatorv1alpha1.SharedDataPlaneOptions{},
+					TargetRef: &operatorv1alpha1.GatewayConfigurationTargetReference{
+						Kind: operatorv1alpha1.GatewayConfigurationTargetKindGateway,
+						Name: "kong",
+					},
+				},
+			},
+			hasError: true,
+			errMsg:   "sharedDataPlane can't be set in a GatewayConfiguration attached to a Gateway",
+		},
+	}
+
+	for _, tc := range testCases {
+		v := NewValidator(fakeclient.NewClientBuilder().Build())
+		err := v.Validate(context.Background(), tc.gatewayConfig)
+		if !tc.hasError {
+			require.NoErrorf(t, err, tc.msg)
+		} else {
+			require.ErrorContainsf(t, err, tc.errMsg, tc.msg)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package versions
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// CatalogConfigMapKey is the key of the version catalog in a ConfigMap
+// providing it.
+const CatalogConfigMapKey = "catalog.yaml"
+
+//go:embed catalog.yaml
+var embeddedCatalog []byte
+
+// -----------------------------------------------------------------------------
+// Version Catalog
+// -----------------------------------------------------------------------------
+
+// Catalog lists the versions of the products managed by the operator which
+// can be used by AutomaticUpgrades.
+type Catalog struct {
+	// Kong lists the versions of the DataPlane's container image.
+	Kong Product `json:"kong"`
+	// KongIngressController lists the versions of the ControlPlane's
+	// container image.
+	KongIngressController Product `json:"kongIngressController"`
+}
+
+// Product lists the known versions of a product and the channels restricting
+// them.
+type Product struct {
+	// Versions are the known versions of the product.
+	Versions []string `json:"versions"`
+	// Channels map the channel names to the semver constraint the versions of
+	// the channel satisfy.
+	Channels map[string]string `json:"channels,omitempty"`
+}
+
+// ParseCatalog parses a version catalog, validating its versions and channels.
+func ParseCatalog(data []byte) (*Catalog, error) {
+	catalog := &Catalog{}
+	if err := yaml.UnmarshalStrict(data, catalog); err != nil {
+		return nil, fmt.Errorf("failed to parse version catalog: %w", err)
+	}
+	if err := catalog.Kong.validate(); err != nil {
+		return nil, fmt.Errorf("invalid kong versions in version catalog: %w", err)
+	}
+	if err := catalog.KongIngressController.validate(); err != nil {
+		return nil, fmt.Errorf("invalid kongIngressController versions in version catalog: %w", err)
+	}
+	return catalog, nil
+}
+
+// EmbeddedCatalog returns the version catalog embedded in the operator.
+func EmbeddedCatalog() (*Catalog, error) {
+	return ParseCatalog(embeddedCatalog)
+}
+
+// -----------------------------------------------------------------------------
+// Version Catalog - Loader
+// -----------------------------------------------------------------------------
+
+// CatalogLoader loads the version catalog from a ConfigMap, falling back to
+// the embedded catalog when no ConfigMap is configured.
+type CatalogLoader struct {
+	Client    client.Client
+	ConfigMap *types.NamespacedName
+}
+
+// Load returns the version catalog. A nil CatalogLoader loads the embedded
+// catalog.
+func (l *CatalogLoader) Load(ctx context.Context) (*Catalog, error) {
+	if l == nil || l.ConfigMap == nil {
+		return EmbeddedCatalog()
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := l.Client.Get(ctx, *l.ConfigMap, configMap); err != nil {
+		return nil, fmt.Errorf("failed to get version catalog ConfigMap %s: %w", l.ConfigMap, err)
+	}
+	data, ok := configMap.Data[CatalogConfigMapKey]
+	if !ok {
+		return nil, fmt.Errorf("version catalog ConfigMap %s has no %s key", l.ConfigMap, CatalogConfigMapKey)
+	}
+	return ParseCatalog([]byte(data))
+}
@@ -0,0 +1,201 @@
+package versions
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	"github.com/kong/gateway-operator/internal/consts"
+	operatorerrors "github.com/kong/gateway-operator/internal/errors"
+	"github.com/kong/gateway-operator/internal/utils/image"
+)
+
+// Compatibility describes the Kong versions supported by a range of KIC
+// versions.
+type Compatibility struct {
+	// KongIngressController is the semver constraint of the KIC versions.
+	KongIngressController string
+	// Kong is the semver constraint of the Kong versions supported by the KIC
+	// versions.
+	Kong string
+	// DefaultKongIngressController is the KIC version used with the Kong
+	// versions when only the Kong version is set.
+	DefaultKongIngressController string
+	// DefaultKong is the Kong version used with the KIC versions when only the
+	// KIC version is set.
+	DefaultKong string
+}
+
+// CompatibilityMatrix lists the Kong versions supported by the KIC versions,
+// from the newest KIC versions to the oldest ones. It follows the semver
+// constraint syntax (see https://github.com/Masterminds/semver#basic-comparisons).
+//
+// Whenever a KIC release changes the supported Kong versions, a new entry
+// should be added at the top of the matrix and the previous most updated entry
+// should be limited to the older KIC versions.
+var CompatibilityMatrix = []Compatibility{
+	{
+		KongIngressController:        ">=2.7",
+		Kong:                         ">=2.8",
+		DefaultKongIngressController: "2.7",
+		DefaultKong:                  "3.0",
+	},
+	{
+		KongIngressController:        ">=2.4, <2.7",
+		Kong:                         ">=2.6, <3.0",
+		DefaultKongIngressController: "2.6",
+		DefaultKong:                  "2.8",
+	},
+	{
+		KongIngressController:        ">=2.1, <2.4",
+		Kong:                         ">=2.3, <3.0",
+		DefaultKongIngressController: "2.3",
+		DefaultKong:                  "2.8",
+	},
+}
+
+// CheckCompatibility returns an ErrIncompatibleVersions error when the KIC
+// version does not support the Kong version, and an
+// ErrUnknownVersionCompatibility error when either version is not a semantic
+// version or the KIC version is not in the compatibility matrix.
+func CheckCompatibility(kicVersion, kongVersion string) error {
+	kic, err := semver.NewVersion(kicVersion)
+	if err != nil {
+		return fmt.Errorf("%w: invalid controlplane version %q", operatorerrors.ErrUnknownVersionCompatibility, kicVersion)
+	}
+	kong, err := semver.NewVersion(kongVersion)
+	if err != nil {
+		return fmt.Errorf("%w: invalid dataplane version %q", operatorerrors.ErrUnknownVersionCompatibility, kongVersion)
+	}
+
+	for _, compatibility := range CompatibilityMatrix {
+		if !mustConstraint(compatibility.KongIngressController).Check(kic) {
+			continue
+		}
+		if !mustConstraint(compatibility.Kong).Check(kong) {
+			return fmt.Errorf("%w: controlplane version %s supports dataplane versions %s, got %s",
+				operatorerrors.ErrIncompatibleVersions, kicVersion, compatibility.Kong, kongVersion)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: controlplane version %s not found in the compatibility matrix",
+		operatorerrors.ErrUnknownVersionCompatibility, kicVersion)
+}
+
+// MultipleDataPlanesKongIngressController is the semver constraint of the KIC
+// versions accepting a list of Kong Admin API URLs, which the ControlPlanes
+// managing more than one DataPlane require.
+const MultipleDataPlanesKongIngressController = ">=2.9"
+
+// CheckMultipleDataPlanesSupport returns an ErrIncompatibleVersions error when
+// the KIC version can't manage more than one DataPlane, and an
+// ErrUnknownVersionCompatibility error when it is not a semantic version.
+func CheckMultipleDataPlanesSupport(kicVersion string) error {
+	kic, err := semver.NewVersion(kicVersion)
+	if err != nil {
+		return fmt.Errorf("%w: invalid controlplane version %q", operatorerrors.ErrUnknownVersionCompatibility, kicVersion)
+	}
+	if !mustConstraint(MultipleDataPlanesKongIngressController).Check(kic) {
+		return fmt.Errorf("%w: controlplane version %s can't manage more than one dataplane, versions %s are required",
+			operatorerrors.ErrIncompatibleVersions, kicVersion, MultipleDataPlanesKongIngressController)
+	}
+	return nil
+}
+
+// DefaultKongVersionForKIC returns the Kong version to use with the given KIC
+// version: the default DataPlane version when the KIC version supports it,
+// the default Kong version of the compatibility matrix otherwise.
+func DefaultKongVersionForKIC(kicVersion string) (string, bool) {
+	if CheckCompatibility(kicVersion, consts.DefaultDataPlaneTag) == nil {
+		return consts.DefaultDataPlaneTag, true
+	}
+
+	kic, err := semver.NewVersion(kicVersion)
+	if err != nil {
+		return "", false
+	}
+	for _, compatibility := range CompatibilityMatrix {
+		if mustConstraint(compatibility.KongIngressController).Check(kic) {
+			return compatibility.DefaultKong, true
+		}
+	}
+	return "", false
+}
+
+// DefaultKICVersionForKong returns the KIC version to use with the given Kong
+// version: the default ControlPlane version when it supports the Kong version,
+// the default KIC version of the newest compatibility matrix entry supporting
+// the Kong version otherwise.
+func DefaultKICVersionForKong(kongVersion string) (string, bool) {
+	if CheckCompatibility(consts.DefaultControlPlaneTag, kongVersion) == nil {
+		return consts.DefaultControlPlaneTag, true
+	}
+
+	kong, err := semver.NewVersion(kongVersion)
+	if err != nil {
+		return "", false
+	}
+	for _, compatibility := range CompatibilityMatrix {
+		if mustConstraint(compatibility.Kong).Check(kong) {
+			return compatibility.DefaultKongIngressController, true
+		}
+	}
+	return "", false
+}
+
+// -----------------------------------------------------------------------------
+// Versions of DataPlanes and ControlPlanes
+// -----------------------------------------------------------------------------
+
+// VersionFromDeploymentOptions returns the version of the container image
+// explicitly set in the DeploymentOptions: the SemanticVersion, the Version,
+// or the tag of the ContainerImage.
+func VersionFromDeploymentOptions(opts *operatorv1alpha1.DeploymentOptions) (string, bool) {
+	if opts.SemanticVersion != nil && *opts.SemanticVersion != "" {
+		return *opts.SemanticVersion, true
+	}
+	if opts.Version != nil && *opts.Version != "" {
+		return *opts.Version, true
+	}
+	if opts.ContainerImage != nil {
+		if ref, err := image.ParseReference(*opts.ContainerImage); err == nil && ref.Tag != "" {
+			return ref.Tag, true
+		}
+	}
+	return "", false
+}
+
+// KongVersionForDataPlane returns the Kong version a DataPlane runs, or an
+// empty string when it can't be determined.
+func KongVersionForDataPlane(dataplane *operatorv1alpha1.DataPlane) string {
+	return deploymentOptionsVersion(&dataplane.Spec.DeploymentOptions, dataplane.Status.Version, consts.DefaultDataPlaneTag)
+}
+
+// KICVersionForControlPlane returns the KIC version a ControlPlane runs, or an
+// empty string when it can't be determined.
+func KICVersionForControlPlane(controlplane *operatorv1alpha1.ControlPlane) string {
+	return deploymentOptionsVersion(&controlplane.Spec.DeploymentOptions, controlplane.Status.Version, consts.DefaultControlPlaneTag)
+}
+
+func deploymentOptionsVersion(opts *operatorv1alpha1.DeploymentOptions, resolvedVersion, defaultVersion string) string {
+	if opts.AutomaticUpgrades != nil {
+		return resolvedVersion
+	}
+	if version, ok := VersionFromDeploymentOptions(opts); ok {
+		return version
+	}
+	if opts.ContainerImage != nil {
+		// a custom image without tag, whose version is unknown.
+		return ""
+	}
+	return defaultVersion
+}
+
+func mustConstraint(constraint string) *semver.Constraints {
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		panic(fmt.Sprintf("invalid constraint %q in the compatibility matrix: %v", constraint, err))
+	}
+	return c
+}
@@ -0,0 +1,131 @@
+package versions
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/pointer"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+	"github.com/kong/gateway-operator/internal/consts"
+	operatorerrors "github.com/kong/gateway-operator/internal/errors"
+)
+
+func TestCompatibilityMatrix(t *testing.T) {
+	for _, compatibility := range CompatibilityMatrix {
+		for _, constraint := range []string{compatibility.KongIngressController, compatibility.Kong} {
+			_, err := semver.NewConstraint(constraint)
+			require.NoError(t, err)
+		}
+		require.NoError(t, CheckCompatibility(compatibility.DefaultKongIngressController, compatibility.DefaultKong))
+	}
+
+	require.NoError(t, CheckCompatibility(consts.DefaultControlPlaneTag, consts.DefaultDataPlaneTag),
+		"the default controlplane and dataplane versions must be compatible")
+}
+
+func TestCheckCompatibility(t *testing.T) {
+	testCases := []struct {
+		kic         string
+		kong        string
+		expectedErr error
+	}{
+		{kic: "2.5", kong: "2.8"},
+		{kic: "2.7.0", kong: "3.0.1"},
+		{kic: "2.3.1", kong: "2.4"},
+		{kic: "2.5", kong: "3.0", expectedErr: operatorerrors.ErrIncompatibleVersions},
+		{kic: "2.2", kong: "2.1", expectedErr: operatorerrors.ErrIncompatibleVersions},
+		{kic: "1.3", kong: "2.8", expectedErr: operatorerrors.ErrUnknownVersionCompatibility},
+		{kic: "latest", kong: "2.8", expectedErr: operatorerrors.ErrUnknownVersionCompatibility},
+		{kic: "2.5", kong: "", expectedErr: operatorerrors.ErrUnknownVersionCompatibility},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.kic+"/"+tc.kong, func(t *testing.T) {
+			err := CheckCompatibility(tc.kic, tc.kong)
+			if tc.expectedErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestCheckMultipleDataPlanesSupport(t *testing.T) {
+	require.NoError(t, CheckMultipleDataPlanesSupport("2.9.0"))
+	require.NoError(t, CheckMultipleDataPlanesSupport("3.0"))
+	require.ErrorIs(t, CheckMultipleDataPlanesSupport(consts.DefaultControlPlaneTag), operatorerrors.ErrIncompatibleVersions)
+	require.ErrorIs(t, CheckMultipleDataPlanesSupport("latest"), operatorerrors.ErrUnknownVersionCompatibility)
+}
+
+func TestDefaultVersions(t *testing.T) {
+	version, ok := DefaultKongVersionForKIC("2.4")
+	require.True(t, ok)
+	assert.Equal(t, consts.DefaultDataPlaneTag, version)
+
+	version, ok = DefaultKongVersionForKIC("2.7.1")
+	require.True(t, ok)
+	assert.Equal(t, consts.DefaultDataPlaneTag, version, "the default dataplane version is preferred when supported")
+
+	_, ok = DefaultKongVersionForKIC("latest")
+	require.False(t, ok)
+
+	version, ok = DefaultKICVersionForKong("2.8.1")
+	require.True(t, ok)
+	assert.Equal(t, consts.DefaultControlPlaneTag, version)
+
+	version, ok = DefaultKICVersionForKong("3.0")
+	require.True(t, ok)
+	assert.Equal(t, "2.7", version)
+
+	_, ok = DefaultKICVersionForKong("2.0")
+	require.False(t, ok)
+}
+
+func TestKongVersionForDataPlane(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     operatorv1alpha1.DeploymentOptions
+		status   string
+		expected string
+	}{
+		{
+			name:     "default version",
+			expected: consts.DefaultDataPlaneTag,
+		},
+		{
+			name:     "version",
+			opts:     operatorv1alpha1.DeploymentOptions{Version: pointer.String("3.0")},
+			expected: "3.0",
+		},
+		{
+			name:     "image tag",
+			opts:     operatorv1alpha1.DeploymentOptions{ContainerImage: pointer.String("registry:5000/kong/kong:2.8.1")},
+			expected: "2.8.1",
+		},
+		{
+			name: "image without tag",
+			opts: operatorv1alpha1.DeploymentOptions{ContainerImage: pointer.String("registry:5000/kong/kong")},
+		},
+		{
+			name:     "automatic upgrades",
+			opts:     operatorv1alpha1.DeploymentOptions{AutomaticUpgrades: &operatorv1alpha1.AutomaticUpgrades{}},
+			status:   "2.8.3",
+			expected: "2.8.3",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			dataplane := &operatorv1alpha1.DataPlane{}
+			dataplane.Spec.DeploymentOptions = tc.opts
+			dataplane.Status.Version = tc.status
+			assert.Equal(t, tc.expected, KongVersionForDataPlane(dataplane))
+		})
+	}
+}
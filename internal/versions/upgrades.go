@@ -0,0 +1,122 @@
+package versions
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/semver"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+)
+
+// -----------------------------------------------------------------------------
+// Automatic Upgrades - Version Resolution
+// -----------------------------------------------------------------------------
+
+// ResolveVersion returns the newest version of the product the given upgrade
+// policy allows to upgrade the current version to. Versions are never
+// downgraded: the current version is returned when the catalog holds no newer
+// allowed version.
+func (p Product) ResolveVersion(current string, policy operatorv1alpha1.UpgradePolicy, channel string) (string, error) {
+	currentVersion, err := semver.NewVersion(current)
+	if err != nil {
+		return "", fmt.Errorf("invalid current version %s: %w", current, err)
+	}
+
+	var allowed func(*semver.Version) bool
+	switch policy {
+	case operatorv1alpha1.UpgradePolicyPatch, "":
+		allowed = func(v *semver.Version) bool {
+			return v.Major() == currentVersion.Major() && v.Minor() == currentVersion.Minor() && v.Prerelease() == ""
+		}
+	case operatorv1alpha1.UpgradePolicyMinor:
+		allowed = func(v *semver.Version) bool {
+			return v.Major() == currentVersion.Major() && v.Prerelease() == ""
+		}
+	case operatorv1alpha1.UpgradePolicyChannel:
+		constraint, ok := p.Channels[channel]
+		if !ok {
+			return "", fmt.Errorf("channel %q not found in version catalog", channel)
+		}
+		constraints, err := semver.NewConstraint(constraint)
+		if err != nil {
+			return "", fmt.Errorf("invalid constraint for channel %q: %w", channel, err)
+		}
+		allowed = constraints.Check
+	default:
+		return "", fmt.Errorf("unsupported upgrade policy %s", policy)
+	}
+
+	resolved, resolvedVersion := current, currentVersion
+	for _, candidate := range p.Versions {
+		v, err := semver.NewVersion(candidate)
+		if err != nil {
+			return "", fmt.Errorf("invalid version %s in version catalog: %w", candidate, err)
+		}
+		if allowed(v) && v.GreaterThan(resolvedVersion) {
+			resolved, resolvedVersion = candidate, v
+		}
+	}
+	return resolved, nil
+}
+
+func (p Product) validate() error {
+	for _, v := range p.Versions {
+		if _, err := semver.NewVersion(v); err != nil {
+			return fmt.Errorf("invalid version %s: %w", v, err)
+		}
+	}
+	for name, constraint := range p.Channels {
+		if _, err := semver.NewConstraint(constraint); err != nil {
+			return fmt.Errorf("invalid constraint for channel %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// Automatic Upgrades - Maintenance Windows
+// -----------------------------------------------------------------------------
+
+// InMaintenanceWindow returns true when the given time falls into one of the
+// maintenance windows. Any time is considered to be in a maintenance window
+// when there are no windows.
+func InMaintenanceWindow(windows []operatorv1alpha1.MaintenanceWindow, now time.Time) (bool, error) {
+	if len(windows) == 0 {
+		return true, nil
+	}
+
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	for _, window := range windows {
+		start, err := time.Parse("15:04", window.Start)
+		if err != nil {
+			return false, fmt.Errorf("invalid maintenance window start %s: %w", window.Start, err)
+		}
+
+		// the window may have started on one of the previous days, when it
+		// spans midnight or lasts longer than a day.
+		for daysAgo := 0; daysAgo <= int(window.Duration.Hours()/24)+1; daysAgo++ {
+			windowStart := today.AddDate(0, 0, -daysAgo).Add(time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute)
+			if !windowStartsOn(window, windowStart.Weekday()) {
+				continue
+			}
+			if !now.Before(windowStart) && now.Before(windowStart.Add(window.Duration.Duration)) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func windowStartsOn(window operatorv1alpha1.MaintenanceWindow, day time.Weekday) bool {
+	if len(window.Days) == 0 {
+		return true
+	}
+	for _, d := range window.Days {
+		if string(d) == day.String() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+package versions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
+)
+
+func TestEmbeddedCatalog(t *testing.T) {
+	catalog, err := EmbeddedCatalog()
+	require.NoError(t, err)
+	require.NotEmpty(t, catalog.Kong.Versions)
+	require.NotEmpty(t, catalog.KongIngressController.Versions)
+}
+
+func TestParseCatalog(t *testing.T) {
+	_, err := ParseCatalog([]byte("kong:\n  versions: [\"not-a-version\"]\n"))
+	require.Error(t, err)
+
+	_, err = ParseCatalog([]byte("kong:\n  versions: [\"2.8.0\"]\n  channels:\n    stable: \"~~2.8\"\n"))
+	require.Error(t, err)
+
+	_, err = ParseCatalog([]byte("kong:\n  versions: [\"2.8.0\"]\nunknown: true\n"))
+	require.Error(t, err)
+
+	catalog, err := ParseCatalog([]byte("kong:\n  versions: [\"2.8.0\"]\n  channels:\n    stable: \">=2.8, <3.0\"\n"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"2.8.0"}, catalog.Kong.Versions)
+	require.Equal(t, ">=2.8, <3.0", catalog.Kong.Channels["stable"])
+}
+
+func TestProductResolveVersion(t *testing.T) {
+	product := Product{
+		Versions: []string{"2.7.0", "2.8.0", "2.8.2", "2.8.1", "2.9.0-rc.1", "3.0.0", "3.1.0"},
+		Channels: map[string]string{
+			"stable": ">=2.8, <3.0",
+			"latest": ">=2.8",
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		current  string
+		policy   operatorv1alpha1.UpgradePolicy
+		channel  string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "patch policy upgrades to the newest patch version",
+			current:  "2.8",
+			policy:   operatorv1alpha1.UpgradePolicyPatch,
+			expected: "2.8.2",
+		},
+		{
+			name:     "patch policy is the default",
+			current:  "2.8.1",
+			expected: "2.8.2",
+		},
+		{
+			name:     "minor policy upgrades to the newest minor version",
+			current:  "2.7.0",
+			policy:   operatorv1alpha1.UpgradePolicyMinor,
+			expected: "2.8.2",
+		},
+		{
+			name:     "channel policy upgrades to the newest version of the channel",
+			current:  "2.7.0",
+			policy:   operatorv1alpha1.UpgradePolicyChannel,
+			channel:  "latest",
+			expected: "3.1.0",
+		},
+		{
+			name:     "versions are never downgraded",
+			current:  "3.0.0",
+			policy:   operatorv1alpha1.UpgradePolicyChannel,
+			channel:  "stable",
+			expected: "3.0.0",
+		},
+		{
+			name:    "unknown channels are rejected",
+			current: "2.8.0",
+			policy:  operatorv1alpha1.UpgradePolicyChannel,
+			channel: "edge",
+			wantErr: true,
+		},
+		{
+			name:    "invalid current versions are rejected",
+			current: "latest",
+			policy:  operatorv1alpha1.UpgradePolicyPatch,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			version, err := product.ResolveVersion(tc.current, tc.policy, tc.channel)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, version)
+		})
+	}
+}
+
+func TestInMaintenanceWindow(t *testing.T) {
+	// 2022-09-05 is a Monday.
+	monday := func(hour, minute int) time.Time {
+		return time.Date(2022, time.September, 5, hour, minute, 0, 0, time.UTC)
+	}
+
+	testCases := []struct {
+		name     string
+		windows  []operatorv1alpha1.MaintenanceWindow
+		now      time.Time
+		expected bool
+	}{
+		{
+			name:     "no windows",
+			now:      monday(12, 0),
+			expected: true,
+		},
+		{
+			name: "inside a daily window",
+			windows: []operatorv1alpha1.MaintenanceWindow{
+				{Start: "11:30", Duration: metav1.Duration{Duration: time.Hour}},
+			},
+			now:      monday(12, 0),
+			expected: true,
+		},
+		{
+			name: "after a daily window",
+			windows: []operatorv1alpha1.MaintenanceWindow{
+				{Start: "11:30", Duration: metav1.Duration{Duration: time.Hour}},
+			},
+			now:      monday(12, 30),
+			expected: false,
+		},
+		{
+			name: "inside a window started on the previous day",
+			windows: []operatorv1alpha1.MaintenanceWindow{
+				{Days: []operatorv1alpha1.Weekday{"Sunday"}, Start: "23:00", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+			},
+			now:      monday(0, 30),
+			expected: true,
+		},
+		{
+			name: "on a day the window does not start on",
+			windows: []operatorv1alpha1.MaintenanceWindow{
+				{Days: []operatorv1alpha1.Weekday{"Saturday", "Sunday"}, Start: "11:30", Duration: metav1.Duration{Duration: time.Hour}},
+			},
+			now:      monday(12, 0),
+			expected: false,
+		},
+		{
+			name: "inside a window lasting several days",
+			windows: []operatorv1alpha1.MaintenanceWindow{
+				{Days: []operatorv1alpha1.Weekday{"Saturday"}, Start: "00:00", Duration: metav1.Duration{Duration: 72 * time.Hour}},
+			},
+			now:      monday(12, 0),
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			inWindow, err := InMaintenanceWindow(tc.windows, tc.now)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, inWindow)
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/kong/gateway-operator/internal/manager"
 	"github.com/kong/gateway-operator/internal/manager/metadata"
@@ -27,19 +28,26 @@ import (
 
 func main() {
 	var (
-		metricsAddr                  string
-		probeAddr                    string
-		disableLeaderElection        bool
-		controllerName               string
-		anonymousReports             bool
-		apiServerHost                string
-		kubeconfigPath               string
-		clusterCASecret              string
-		clusterCASecretNamespace     string
-		enableControllerGateway      bool
-		enableControllerControlPlane bool
-		enableControllerDataPlane    bool
-		version                      bool
+		metricsAddr                        string
+		probeAddr                          string
+		disableLeaderElection              bool
+		controllerName                     string
+		anonymousReports                   bool
+		apiServerHost                      string
+		kubeconfigPath                     string
+		clusterCASecret                    string
+		clusterCASecretNamespace           string
+		versionCatalogConfigMap            string
+		versionCatalogNamespace            string
+		imageMirrorRegistry                string
+		allowedImageRepositories           string
+		imagePullSecrets                   string
+		enableControllerGateway            bool
+		enableControllerControlPlane       bool
+		enableControllerDataPlane          bool
+		enableControllerGatewayBackup      bool
+		enableGatewayRestoreGatewayClasses bool
+		version                            bool
 	)
 
 	flagSet := flag.NewFlagSet("", flag.ExitOnError)
@@ -55,10 +63,18 @@ func main() {
 	flagSet.StringVar(&controllerName, "controller-name", "", "a controller name to use if other than the default, only needed for multi-tenancy")
 	flagSet.StringVar(&clusterCASecret, "cluster-ca-secret", "kong-operator-ca", "name of the Secret containing the cluster CA certificate")
 	flagSet.StringVar(&clusterCASecretNamespace, "cluster-ca-secret-namespace", "", "name of the namespace for Secret containing the cluster CA certificate")
+	flagSet.StringVar(&versionCatalogConfigMap, "version-catalog-configmap", "", "name of the ConfigMap containing the version catalog used by automatic upgrades, the embedded catalog is used if not set")
+	flagSet.StringVar(&versionCatalogNamespace, "version-catalog-configmap-namespace", "", "name of the namespace for ConfigMap containing the version catalog, defaults to the cluster CA Secret namespace")
+
+	flagSet.StringVar(&imageMirrorRegistry, "image-mirror-registry", "", "registry the default DataPlane and ControlPlane images are pulled from instead of their original registry")
+	flagSet.StringVar(&allowedImageRepositories, "allowed-image-repositories", "", "comma-separated list of the registries and repositories the container images of DataPlanes and ControlPlanes can be pulled from, all images are allowed if not set")
+	flagSet.StringVar(&imagePullSecrets, "image-pull-secrets", "", "comma-separated list of the Secrets set as imagePullSecrets of the generated Deployments and ServiceAccounts")
 
 	flagSet.BoolVar(&enableControllerGateway, "enable-controller-gateway", true, "Enable the Gateway controller.")
 	flagSet.BoolVar(&enableControllerControlPlane, "enable-controller-controlplane", true, "Enable the ControlPlane controller.")
 	flagSet.BoolVar(&enableControllerDataPlane, "enable-controller-dataplane", true, "Enable the DataPlane controller.")
+	flagSet.BoolVar(&enableControllerGatewayBackup, "enable-controller-gatewaybackup", true, "Enable the GatewayBackup and GatewayRestore controllers.")
+	flagSet.BoolVar(&enableGatewayRestoreGatewayClasses, "enable-gatewayrestore-gatewayclasses", false, "Allow the GatewayRestores to create the cluster-scoped GatewayClasses of their snapshots missing from the cluster.")
 
 	flagSet.BoolVar(&version, "v", false, "Print version information")
 
@@ -99,21 +115,32 @@ func main() {
 		}
 	}
 
+	if versionCatalogNamespace == "" {
+		versionCatalogNamespace = clusterCASecretNamespace
+	}
+
 	cfg := manager.Config{
-		DevelopmentMode:               developmentModeEnabled,
-		MetricsAddr:                   metricsAddr,
-		ProbeAddr:                     probeAddr,
-		LeaderElection:                leaderElection,
-		ControllerName:                controllerName,
-		AnonymousReports:              anonymousReports,
-		APIServerPath:                 apiServerHost,
-		KubeconfigPath:                kubeconfigPath,
-		ClusterCASecretName:           clusterCASecret,
-		ClusterCASecretNamespace:      clusterCASecretNamespace,
-		GatewayControllerEnabled:      enableControllerGateway,
-		ControlPlaneControllerEnabled: enableControllerControlPlane,
-		DataPlaneControllerEnabled:    enableControllerDataPlane,
-		LoggerOpts:                    loggerOpts,
+		DevelopmentMode:                     developmentModeEnabled,
+		MetricsAddr:                         metricsAddr,
+		ProbeAddr:                           probeAddr,
+		LeaderElection:                      leaderElection,
+		ControllerName:                      controllerName,
+		AnonymousReports:                    anonymousReports,
+		APIServerPath:                       apiServerHost,
+		KubeconfigPath:                      kubeconfigPath,
+		ClusterCASecretName:                 clusterCASecret,
+		ClusterCASecretNamespace:            clusterCASecretNamespace,
+		VersionCatalogConfigMapName:         versionCatalogConfigMap,
+		VersionCatalogNamespace:             versionCatalogNamespace,
+		ImageMirrorRegistry:                 imageMirrorRegistry,
+		AllowedImageRepositories:            splitCommaSeparatedList(allowedImageRepositories),
+		ImagePullSecrets:                    splitCommaSeparatedList(imagePullSecrets),
+		GatewayControllerEnabled:            enableControllerGateway,
+		ControlPlaneControllerEnabled:       enableControllerControlPlane,
+		DataPlaneControllerEnabled:          enableControllerDataPlane,
+		GatewayBackupControllerEnabled:      enableControllerGatewayBackup,
+		GatewayRestoreGatewayClassesEnabled: enableGatewayRestoreGatewayClasses,
+		LoggerOpts:                          loggerOpts,
 	}
 
 	if err := manager.Run(cfg); err != nil {
@@ -121,3 +148,15 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// splitCommaSeparatedList splits a comma-separated list, ignoring the empty
+// items.
+func splitCommaSeparatedList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -43,7 +43,7 @@ func TestDataplaneValidatingWebhook(t *testing.T) {
 			errMsg: "",
 		},
 		{
-			name: "database_postgres_not_supported",
+			name: "database_postgres_without_database",
 			dataplane: &operatorv1alpha1.DataPlane{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: testNamespace.Name,
@@ -60,7 +60,7 @@ func TestDataplaneValidatingWebhook(t *testing.T) {
 				},
 			},
 
-			errMsg: "database backend postgres of dataplane not supported currently",
+			errMsg: "database backend postgres of dataplane requires database to be set",
 		},
 	}
 
@@ -17,6 +17,7 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
 	"github.com/kong/gateway-operator/internal/consts"
@@ -110,6 +111,100 @@ func TestControlPlaneWhenNoDataPlane(t *testing.T) {
 	require.Eventually(t, Not(controlPlaneHasActiveDeployment(t, ctx, controlplaneName)), controlPlaneCondDeadline, controlPlaneCondTick)
 }
 
+func TestControlPlaneCrossNamespaceDataPlane(t *testing.T) {
+	namespace, cleaner := setup(t)
+	defer func() { assert.NoError(t, cleaner.Cleanup(ctx)) }()
+
+	t.Log("creating a namespace for the dataplane")
+	dataplaneNamespace, err := k8sClient.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: uuid.NewString(),
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	cleaner.AddNamespace(dataplaneNamespace)
+
+	dataplaneClient := operatorClient.ApisV1alpha1().DataPlanes(dataplaneNamespace.Name)
+	controlplaneClient := operatorClient.ApisV1alpha1().ControlPlanes(namespace.Name)
+
+	dataplaneName := types.NamespacedName{
+		Namespace: dataplaneNamespace.Name,
+		Name:      uuid.NewString(),
+	}
+	dataplane := &operatorv1alpha1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: dataplaneName.Namespace,
+			Name:      dataplaneName.Name,
+		},
+	}
+
+	controlplaneName := types.NamespacedName{
+		Namespace: namespace.Name,
+		Name:      uuid.NewString(),
+	}
+	controlplane := &operatorv1alpha1.ControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: controlplaneName.Namespace,
+			Name:      controlplaneName.Name,
+		},
+		Spec: operatorv1alpha1.ControlPlaneSpec{
+			ControlPlaneDeploymentOptions: operatorv1alpha1.ControlPlaneDeploymentOptions{
+				DataPlane:          &dataplane.Name,
+				DataPlaneNamespace: &dataplaneNamespace.Name,
+			},
+		},
+	}
+
+	t.Log("deploying dataplane resource")
+	dataplane, err = dataplaneClient.Create(ctx, dataplane, metav1.CreateOptions{})
+	require.NoError(t, err)
+	cleaner.Add(dataplane)
+
+	t.Log("verifying services managed by the dataplane")
+	require.Eventually(t, dataPlaneHasService(t, ctx, dataplaneName), controlPlaneCondDeadline, controlPlaneCondTick)
+
+	t.Log("deploying controlplane resource referencing the dataplane in another namespace")
+	controlplane, err = controlplaneClient.Create(ctx, controlplane, metav1.CreateOptions{})
+	require.NoError(t, err)
+	cleaner.Add(controlplane)
+
+	t.Log("verifying controlplane reports that the reference to the dataplane is not permitted")
+	require.Eventually(t, controlPlaneDetectedRefNotPermitted(t, ctx, controlplaneName), controlPlaneCondDeadline, controlPlaneCondTick)
+
+	t.Log("verifying controlplane deployment has no active replicas")
+	require.Eventually(t, Not(controlPlaneHasActiveDeployment(t, ctx, controlplaneName)), controlPlaneCondDeadline, controlPlaneCondTick)
+
+	t.Log("granting the reference from the controlplane to the dataplane")
+	dataplaneObjectName := gatewayv1alpha2.ObjectName(dataplane.Name)
+	referenceGrant := &gatewayv1alpha2.ReferenceGrant{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: dataplaneNamespace.Name,
+			Name:      uuid.NewString(),
+		},
+		Spec: gatewayv1alpha2.ReferenceGrantSpec{
+			From: []gatewayv1alpha2.ReferenceGrantFrom{{
+				Group:     gatewayv1alpha2.Group(operatorv1alpha1.SchemeGroupVersion.Group),
+				Kind:      "ControlPlane",
+				Namespace: gatewayv1alpha2.Namespace(namespace.Name),
+			}},
+			To: []gatewayv1alpha2.ReferenceGrantTo{{
+				Group: gatewayv1alpha2.Group(operatorv1alpha1.SchemeGroupVersion.Group),
+				Kind:  "DataPlane",
+				Name:  &dataplaneObjectName,
+			}},
+		},
+	}
+	referenceGrant, err = gatewayClient.GatewayV1alpha2().ReferenceGrants(dataplaneNamespace.Name).Create(ctx, referenceGrant, metav1.CreateOptions{})
+	require.NoError(t, err)
+	cleaner.Add(referenceGrant)
+
+	t.Log("verifying controlplane is now provisioned")
+	require.Eventually(t, controlPlaneIsProvisioned(t, ctx, controlplaneName), controlPlaneCondDeadline, controlPlaneCondTick)
+
+	t.Log("verifying controlplane deployment has active replicas")
+	require.Eventually(t, controlPlaneHasActiveDeployment(t, ctx, controlplaneName), controlPlaneCondDeadline, controlPlaneCondTick)
+}
+
 func TestControlPlaneEssentials(t *testing.T) {
 	namespace, cleaner := setup(t)
 	defer func() { assert.NoError(t, cleaner.Cleanup(ctx)) }()
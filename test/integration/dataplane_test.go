@@ -12,14 +12,18 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/kong/kubernetes-testing-framework/pkg/clusters"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/kong/gateway-operator/apis/v1alpha1"
 	"github.com/kong/gateway-operator/controllers"
+	"github.com/kong/gateway-operator/internal/consts"
+	k8sutils "github.com/kong/gateway-operator/internal/utils/kubernetes"
 )
 
 func TestDataplaneEssentials(t *testing.T) {
@@ -112,6 +116,258 @@ func TestDataplaneEssentials(t *testing.T) {
 	verifyConnectivity(t, dataplaneIP)
 }
 
+func TestDataPlaneBlueGreenRollout(t *testing.T) {
+	namespace, cleaner := setup(t)
+	defer func() { assert.NoError(t, cleaner.Cleanup(ctx)) }()
+
+	t.Log("deploying dataplane resource with the blue/green rollout strategy")
+	dataplaneName := types.NamespacedName{
+		Namespace: namespace.Name,
+		Name:      uuid.NewString(),
+	}
+	dataplane := &v1alpha1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: dataplaneName.Namespace,
+			Name:      dataplaneName.Name,
+		},
+		Spec: v1alpha1.DataPlaneSpec{
+			DataPlaneDeploymentOptions: v1alpha1.DataPlaneDeploymentOptions{
+				Rollout: &v1alpha1.DataPlaneRollout{
+					Strategy: v1alpha1.DataPlaneRolloutStrategy{
+						BlueGreen: &v1alpha1.BlueGreenStrategy{
+							Promotion: v1alpha1.ManualPromotion,
+						},
+					},
+				},
+			},
+		},
+	}
+	dataplaneClient := operatorClient.ApisV1alpha1().DataPlanes(namespace.Name)
+	dataplane, err := dataplaneClient.Create(ctx, dataplane, metav1.CreateOptions{})
+	require.NoError(t, err)
+	cleaner.Add(dataplane)
+
+	t.Log("verifying the dataplane gets provisioned without rollout in progress")
+	var rollout v1alpha1.DataPlaneRolloutStatus
+	require.Eventually(t, dataPlaneHasActiveDeployment(t, ctx, dataplaneName), time.Minute, time.Second)
+	require.Eventually(t, dataPlaneRolloutIsInPhase(t, ctx, dataplaneName, v1alpha1.DataPlaneRolloutPhaseComplete, &rollout), time.Minute, time.Second)
+	liveDeployment := rollout.Deployment
+
+	t.Log("changing the dataplane spec")
+	require.Eventually(t, func() bool {
+		dataplane, err = dataplaneClient.Get(ctx, dataplane.Name, metav1.GetOptions{})
+		require.NoError(t, err)
+		dataplane.Spec.Env = append(dataplane.Spec.Env, corev1.EnvVar{Name: "KONG_LOG_LEVEL", Value: "debug"})
+		_, err = dataplaneClient.Update(ctx, dataplane, metav1.UpdateOptions{})
+		return err == nil
+	}, time.Minute, time.Second)
+
+	t.Log("verifying the preview deployment gets ready and awaits promotion")
+	require.Eventually(t, dataPlaneRolloutIsInPhase(t, ctx, dataplaneName, v1alpha1.DataPlaneRolloutPhaseAwaitingPromotion, &rollout), 2*time.Minute, time.Second)
+	require.Equal(t, liveDeployment, rollout.Deployment)
+	require.NotEmpty(t, rollout.PreviewDeployment)
+	require.NotEmpty(t, rollout.PreviewService)
+	previewDeployment := rollout.PreviewDeployment
+
+	t.Log("promoting the preview deployment")
+	require.Eventually(t, func() bool {
+		dataplane, err = dataplaneClient.Get(ctx, dataplane.Name, metav1.GetOptions{})
+		require.NoError(t, err)
+		if dataplane.Annotations == nil {
+			dataplane.Annotations = map[string]string{}
+		}
+		dataplane.Annotations[consts.DataPlanePromotePreviewAnnotation] = "true"
+		_, err = dataplaneClient.Update(ctx, dataplane, metav1.UpdateOptions{})
+		return err == nil
+	}, time.Minute, time.Second)
+
+	t.Log("verifying the dataplane service routes the traffic to the promoted deployment")
+	require.Eventually(t, dataPlaneRolloutIsInPhase(t, ctx, dataplaneName, v1alpha1.DataPlaneRolloutPhaseComplete, &rollout), time.Minute, time.Second)
+	require.Equal(t, previewDeployment, rollout.Deployment)
+	require.Eventually(t, dataPlaneHasActiveDeployment(t, ctx, dataplaneName), time.Minute, time.Second)
+	var dataplaneService corev1.Service
+	require.Eventually(t, dataPlaneHasActiveService(t, ctx, dataplaneName, &dataplaneService), time.Minute, time.Second)
+	deployment, err := k8sClient.AppsV1().Deployments(namespace.Name).Get(ctx, rollout.Deployment, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, deployment.Spec.Selector.MatchLabels, dataplaneService.Spec.Selector)
+}
+
+func TestDataPlanePostgresDatabase(t *testing.T) {
+	namespace, cleaner := setup(t)
+	defer func() { assert.NoError(t, cleaner.Cleanup(ctx)) }()
+
+	t.Log("deploying a postgres database")
+	connectionSecret := deployPostgres(t, namespace, cleaner)
+
+	t.Log("deploying dataplane resource using the postgres database")
+	dataplaneName := types.NamespacedName{
+		Namespace: namespace.Name,
+		Name:      uuid.NewString(),
+	}
+	dataplane := &v1alpha1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: dataplaneName.Namespace,
+			Name:      dataplaneName.Name,
+		},
+		Spec: v1alpha1.DataPlaneSpec{
+			DataPlaneDeploymentOptions: v1alpha1.DataPlaneDeploymentOptions{
+				Database: &v1alpha1.DataPlaneDatabase{
+					Postgres: v1alpha1.PostgresDatabase{
+						ConnectionSecretRef: corev1.LocalObjectReference{Name: connectionSecret.Name},
+					},
+				},
+			},
+		},
+	}
+	dataplane, err := operatorClient.ApisV1alpha1().DataPlanes(namespace.Name).Create(ctx, dataplane, metav1.CreateOptions{})
+	require.NoError(t, err)
+	cleaner.Add(dataplane)
+
+	t.Log("verifying the database gets migrated and the dataplane provisioned")
+	hasTrueCondition := func(conditionType k8sutils.ConditionType) func(dataplane *v1alpha1.DataPlane) bool {
+		return func(dataplane *v1alpha1.DataPlane) bool {
+			condition, ok := k8sutils.GetCondition(conditionType, dataplane)
+			return ok && condition.Status == metav1.ConditionTrue
+		}
+	}
+	require.Eventually(t, dataPlanePredicate(t, ctx, dataplaneName, hasTrueCondition(controllers.DataPlaneConditionTypeDatabaseMigrated)), 3*time.Minute, time.Second)
+	require.Eventually(t, dataPlanePredicate(t, ctx, dataplaneName, hasTrueCondition(controllers.DataPlaneConditionTypeProvisioned)), 2*time.Minute, time.Second)
+	require.Eventually(t, dataPlaneHasActiveDeployment(t, ctx, dataplaneName), time.Minute, time.Second)
+
+	t.Log("verifying the database was bootstrapped by a job")
+	jobs, err := k8sClient.BatchV1().Jobs(namespace.Name).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", consts.DataPlaneMigrationsPhaseLabel, "bootstrap"),
+	})
+	require.NoError(t, err)
+	require.Len(t, jobs.Items, 1)
+	require.EqualValues(t, 1, jobs.Items[0].Status.Succeeded)
+}
+
+func TestDataPlaneHybridMode(t *testing.T) {
+	namespace, cleaner := setup(t)
+	defer func() { assert.NoError(t, cleaner.Cleanup(ctx)) }()
+
+	t.Log("deploying a postgres database")
+	connectionSecret := deployPostgres(t, namespace, cleaner)
+
+	t.Log("deploying the hybrid mode control plane and data plane")
+	dataplaneClient := operatorClient.ApisV1alpha1().DataPlanes(namespace.Name)
+	controlplane := &v1alpha1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace.Name,
+			Name:      uuid.NewString(),
+		},
+		Spec: v1alpha1.DataPlaneSpec{
+			DataPlaneDeploymentOptions: v1alpha1.DataPlaneDeploymentOptions{
+				Role: v1alpha1.DataPlaneRoleControlPlane,
+				Database: &v1alpha1.DataPlaneDatabase{
+					Postgres: v1alpha1.PostgresDatabase{
+						ConnectionSecretRef: corev1.LocalObjectReference{Name: connectionSecret.Name},
+					},
+				},
+			},
+		},
+	}
+	controlplane, err := dataplaneClient.Create(ctx, controlplane, metav1.CreateOptions{})
+	require.NoError(t, err)
+	cleaner.Add(controlplane)
+	dataplane := &v1alpha1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace.Name,
+			Name:      uuid.NewString(),
+		},
+		Spec: v1alpha1.DataPlaneSpec{
+			DataPlaneDeploymentOptions: v1alpha1.DataPlaneDeploymentOptions{
+				Role:                v1alpha1.DataPlaneRoleDataPlane,
+				ClusterControlPlane: controlplane.Name,
+			},
+		},
+	}
+	dataplane, err = dataplaneClient.Create(ctx, dataplane, metav1.CreateOptions{})
+	require.NoError(t, err)
+	cleaner.Add(dataplane)
+
+	t.Log("verifying the control plane exposes its cluster listener")
+	controlplaneName := types.NamespacedName{Namespace: namespace.Name, Name: controlplane.Name}
+	require.Eventually(t, dataPlanePredicate(t, ctx, controlplaneName, func(controlplane *v1alpha1.DataPlane) bool {
+		return controlplane.Status.ClusterService != ""
+	}), 3*time.Minute, time.Second)
+
+	t.Log("verifying both get provisioned")
+	isProvisioned := func(dataplane *v1alpha1.DataPlane) bool {
+		return k8sutils.IsValidCondition(controllers.DataPlaneConditionTypeProvisioned, dataplane)
+	}
+	require.Eventually(t, dataPlanePredicate(t, ctx, controlplaneName, isProvisioned), 3*time.Minute, time.Second)
+	dataplaneName := types.NamespacedName{Namespace: namespace.Name, Name: dataplane.Name}
+	require.Eventually(t, dataPlanePredicate(t, ctx, dataplaneName, isProvisioned), 2*time.Minute, time.Second)
+
+	t.Log("verifying the data plane pods connect to the cluster service of the control plane")
+	controlplane, err = dataplaneClient.Get(ctx, controlplane.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	deployments, err := k8sClient.AppsV1().Deployments(namespace.Name).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("app=%s", dataplane.Name),
+	})
+	require.NoError(t, err)
+	require.Len(t, deployments.Items, 1)
+	require.Contains(t, deployments.Items[0].Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+		Name:  "KONG_CLUSTER_CONTROL_PLANE",
+		Value: fmt.Sprintf("%s.%s.svc:8005", controlplane.Status.ClusterService, namespace.Name),
+	})
+}
+
+// deployPostgres deploys a PostgreSQL database to the namespace, and returns
+// the Secret holding its connection settings.
+func deployPostgres(t *testing.T, namespace *corev1.Namespace, cleaner *clusters.Cleaner) *corev1.Secret {
+	postgresLabels := map[string]string{"app": "postgres"}
+	postgresDeployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "postgres", Labels: postgresLabels},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: postgresLabels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: postgresLabels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:  "postgres",
+						Image: "postgres:13",
+						Env: []corev1.EnvVar{
+							{Name: "POSTGRES_USER", Value: "kong"},
+							{Name: "POSTGRES_PASSWORD", Value: "kong"},
+							{Name: "POSTGRES_DB", Value: "kong"},
+						},
+						Ports: []corev1.ContainerPort{{ContainerPort: 5432}},
+					}},
+				},
+			},
+		},
+	}
+	postgresDeployment, err := k8sClient.AppsV1().Deployments(namespace.Name).Create(ctx, postgresDeployment, metav1.CreateOptions{})
+	require.NoError(t, err)
+	cleaner.Add(postgresDeployment)
+	postgresService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "postgres"},
+		Spec: corev1.ServiceSpec{
+			Selector: postgresLabels,
+			Ports:    []corev1.ServicePort{{Port: 5432}},
+		},
+	}
+	postgresService, err = k8sClient.CoreV1().Services(namespace.Name).Create(ctx, postgresService, metav1.CreateOptions{})
+	require.NoError(t, err)
+	cleaner.Add(postgresService)
+	connectionSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "postgres-connection"},
+		StringData: map[string]string{
+			"host":     fmt.Sprintf("%s.%s.svc", postgresService.Name, namespace.Name),
+			"user":     "kong",
+			"password": "kong",
+			"database": "kong",
+		},
+	}
+	connectionSecret, err = k8sClient.CoreV1().Secrets(namespace.Name).Create(ctx, connectionSecret, metav1.CreateOptions{})
+	require.NoError(t, err)
+	cleaner.Add(connectionSecret)
+	return connectionSecret
+}
+
 func verifyConnectivity(t *testing.T, dataplaneIP string) {
 	t.Log("verifying un-authenticated requests fail")
 	badhttpc := http.Client{
@@ -19,7 +19,9 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
 	"github.com/kong/gateway-operator/internal/consts"
+	gatewayutils "github.com/kong/gateway-operator/internal/utils/gateway"
 	k8sresources "github.com/kong/gateway-operator/internal/utils/kubernetes/resources"
 	"github.com/kong/gateway-operator/pkg/vars"
 )
@@ -243,6 +245,88 @@ func TestGatewayDataPlaneNetworkPolicy(t *testing.T) {
 	require.Eventually(t, Not(gatewayNetworkPoliciesExist(t, ctx, gateway)), time.Minute, time.Second)
 }
 
+func TestGatewaySharedDataPlane(t *testing.T) {
+	namespace, cleaner := setup(t)
+	defer func() { assert.NoError(t, cleaner.Cleanup(ctx)) }()
+
+	t.Log("deploying a GatewayConfiguration resource which enables sharing the DataPlane")
+	gatewayConfig := &operatorv1alpha1.GatewayConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace.Name,
+			Name:      uuid.NewString(),
+		},
+		Spec: operatorv1alpha1.GatewayConfigurationSpec{
+			SharedDataPlane: &operatorv1alpha1.SharedDataPlaneOptions{
+				Scope: operatorv1alpha1.SharedDataPlaneScopeNamespace,
+			},
+		},
+	}
+	gatewayConfig, err := operatorClient.ApisV1alpha1().GatewayConfigurations(namespace.Name).Create(ctx, gatewayConfig, metav1.CreateOptions{})
+	require.NoError(t, err)
+	cleaner.Add(gatewayConfig)
+
+	t.Log("deploying a GatewayClass resource with the GatewayConfiguration attached via ParametersReference")
+	gatewayClass := generateGatewayClass()
+	gatewayClass.Spec.ParametersRef = &gatewayv1alpha2.ParametersReference{
+		Group:     gatewayv1alpha2.Group(operatorv1alpha1.SchemeGroupVersion.Group),
+		Kind:      gatewayv1alpha2.Kind("GatewayConfiguration"),
+		Namespace: (*gatewayv1alpha2.Namespace)(&gatewayConfig.Namespace),
+		Name:      gatewayConfig.Name,
+	}
+	gatewayClass, err = gatewayClient.GatewayV1alpha2().GatewayClasses().Create(ctx, gatewayClass, metav1.CreateOptions{})
+	require.NoError(t, err)
+	cleaner.Add(gatewayClass)
+
+	t.Log("deploying two Gateway resources with the same listeners")
+	firstGatewayNSN := types.NamespacedName{Name: uuid.NewString(), Namespace: namespace.Name}
+	firstGateway, err := gatewayClient.GatewayV1alpha2().Gateways(namespace.Name).Create(ctx, generateGateway(firstGatewayNSN, gatewayClass), metav1.CreateOptions{})
+	require.NoError(t, err)
+	cleaner.Add(firstGateway)
+
+	t.Log("verifying the first Gateway gets marked as Ready")
+	require.Eventually(t, gatewayIsReady(t, ctx, firstGatewayNSN), gatewayReadyTimeLimit, time.Second)
+
+	secondGatewayNSN := types.NamespacedName{Name: uuid.NewString(), Namespace: namespace.Name}
+	secondGateway, err := gatewayClient.GatewayV1alpha2().Gateways(namespace.Name).Create(ctx, generateGateway(secondGatewayNSN, gatewayClass), metav1.CreateOptions{})
+	require.NoError(t, err)
+	cleaner.Add(secondGateway)
+
+	t.Log("verifying the second Gateway gets marked as Ready")
+	require.Eventually(t, gatewayIsReady(t, ctx, secondGatewayNSN), gatewayReadyTimeLimit, time.Second)
+
+	t.Log("verifying a single DataPlane and ControlPlane pair is shared by both Gateways")
+	require.Eventually(t, sharedDataPlaneHasGateways(t, ctx, namespace.Name, gatewayClass, firstGatewayNSN, secondGatewayNSN), subresourceReadinessWait, time.Second)
+	controlplanes, err := gatewayutils.ListSharedControlPlanes(ctx, mgrClient, namespace.Name, string(gatewayClass.UID))
+	require.NoError(t, err)
+	require.Len(t, controlplanes, 1)
+	require.Empty(t, mustListDataPlanesForGateway(t, ctx, secondGateway))
+
+	t.Log("verifying the listener conflict is reported on the second Gateway only")
+	require.Eventually(t, gatewayListenerHasConflictedCondition(t, ctx, secondGatewayNSN, metav1.ConditionTrue, gatewayv1alpha2.ListenerReasonHostnameConflict), gatewayReadyTimeLimit, time.Second)
+	require.Eventually(t, gatewayListenerHasConflictedCondition(t, ctx, firstGatewayNSN, metav1.ConditionFalse, gatewayv1alpha2.ListenerReasonNoConflicts), gatewayReadyTimeLimit, time.Second)
+
+	t.Log("deleting the first Gateway resource")
+	require.NoError(t, gatewayClient.GatewayV1alpha2().Gateways(namespace.Name).Delete(ctx, firstGateway.Name, metav1.DeleteOptions{}))
+
+	t.Log("verifying the shared DataPlane is kept for the second Gateway")
+	require.Eventually(t, sharedDataPlaneHasGateways(t, ctx, namespace.Name, gatewayClass, secondGatewayNSN), time.Minute, time.Second)
+
+	t.Log("verifying the conflict of the second Gateway is resolved")
+	require.Eventually(t, gatewayListenerHasConflictedCondition(t, ctx, secondGatewayNSN, metav1.ConditionFalse, gatewayv1alpha2.ListenerReasonNoConflicts), gatewayReadyTimeLimit, time.Second)
+
+	t.Log("deleting the second Gateway resource")
+	require.NoError(t, gatewayClient.GatewayV1alpha2().Gateways(namespace.Name).Delete(ctx, secondGateway.Name, metav1.DeleteOptions{}))
+
+	t.Log("verifying the shared DataPlane and ControlPlane are deleted")
+	require.Eventually(t, func() bool {
+		dataplanes, err := gatewayutils.ListSharedDataPlanes(ctx, mgrClient, namespace.Name, string(gatewayClass.UID))
+		require.NoError(t, err)
+		controlplanes, err := gatewayutils.ListSharedControlPlanes(ctx, mgrClient, namespace.Name, string(gatewayClass.UID))
+		require.NoError(t, err)
+		return len(dataplanes) == 0 && len(controlplanes) == 0
+	}, time.Minute, time.Second)
+}
+
 type networkPolicyIngressRuleDecorator struct {
 	Rule networkingv1.NetworkPolicyIngressRule
 }
@@ -13,6 +13,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
+	operatorv1alpha1 "github.com/kong/gateway-operator/apis/v1alpha1"
 	gatewayutils "github.com/kong/gateway-operator/internal/utils/gateway"
 	"github.com/kong/gateway-operator/pkg/vars"
 )
@@ -143,3 +144,77 @@ func TestGatewayClassCreation(t *testing.T) {
 		return gatewayutils.IsScheduled(gateway)
 	}, gatewaySchedulingTimeLimit, time.Second)
 }
+
+func TestGatewayClassAcceptedCondition(t *testing.T) {
+	namespace, cleaner := setup(t)
+	defer func() { assert.NoError(t, cleaner.Cleanup(ctx)) }()
+
+	t.Log("deploying a GatewayClass referencing a non-existent GatewayConfiguration")
+	gatewayConfigName := uuid.NewString()
+	gatewayClass := &gatewayv1alpha2.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: uuid.NewString(),
+		},
+		Spec: gatewayv1alpha2.GatewayClassSpec{
+			ControllerName: gatewayv1alpha2.GatewayController(vars.ControllerName),
+			ParametersRef: &gatewayv1alpha2.ParametersReference{
+				Group:     gatewayv1alpha2.Group(operatorv1alpha1.SchemeGroupVersion.Group),
+				Kind:      gatewayv1alpha2.Kind("GatewayConfiguration"),
+				Namespace: (*gatewayv1alpha2.Namespace)(&namespace.Name),
+				Name:      gatewayConfigName,
+			},
+		},
+	}
+	gatewayClass, err := gatewayClient.GatewayV1alpha2().GatewayClasses().Create(ctx, gatewayClass, metav1.CreateOptions{})
+	require.NoError(t, err)
+	cleaner.Add(gatewayClass)
+
+	t.Log("verifying that the GatewayClass is not accepted because of invalid parameters")
+	require.Eventually(t, gatewayClassAcceptedConditionHasReason(t, ctx, gatewayClass.Name, metav1.ConditionFalse, gatewayv1alpha2.GatewayClassReasonInvalidParameters),
+		gatewaySchedulingTimeLimit, time.Second)
+
+	t.Log("deploying the referenced GatewayConfiguration")
+	gatewayConfig := &operatorv1alpha1.GatewayConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace.Name,
+			Name:      gatewayConfigName,
+		},
+	}
+	gatewayConfig, err = operatorClient.ApisV1alpha1().GatewayConfigurations(namespace.Name).Create(ctx, gatewayConfig, metav1.CreateOptions{})
+	require.NoError(t, err)
+	cleaner.Add(gatewayConfig)
+
+	t.Log("verifying that the GatewayClass is now accepted")
+	require.Eventually(t, gatewayClassAcceptedConditionHasReason(t, ctx, gatewayClass.Name, metav1.ConditionTrue, gatewayv1alpha2.GatewayClassReasonAccepted),
+		gatewaySchedulingTimeLimit, time.Second)
+
+	t.Log("deploying a Gateway using the GatewayClass")
+	gateway := &gatewayv1alpha2.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace.Name,
+			Name:      uuid.NewString(),
+		},
+		Spec: gatewayv1alpha2.GatewaySpec{
+			GatewayClassName: gatewayv1alpha2.ObjectName(gatewayClass.Name),
+			Listeners: []gatewayv1alpha2.Listener{{
+				Name:     "http",
+				Protocol: gatewayv1alpha2.HTTPProtocolType,
+				Port:     gatewayv1alpha2.PortNumber(80),
+			}},
+		},
+	}
+	gateway, err = gatewayClient.GatewayV1alpha2().Gateways(namespace.Name).Create(ctx, gateway, metav1.CreateOptions{})
+	require.NoError(t, err)
+	cleaner.Add(gateway)
+
+	t.Log("verifying that the GatewayClass gets the gateway-exists finalizer")
+	require.Eventually(t, gatewayClassHasFinalizer(t, ctx, gatewayClass.Name, gatewayv1alpha2.GatewayClassFinalizerGatewaysExist),
+		gatewaySchedulingTimeLimit, time.Second)
+
+	t.Log("deleting the Gateway")
+	require.NoError(t, gatewayClient.GatewayV1alpha2().Gateways(namespace.Name).Delete(ctx, gateway.Name, metav1.DeleteOptions{}))
+
+	t.Log("verifying that the gateway-exists finalizer is removed from the GatewayClass")
+	require.Eventually(t, Not(gatewayClassHasFinalizer(t, ctx, gatewayClass.Name, gatewayv1alpha2.GatewayClassFinalizerGatewaysExist)),
+		gatewaySchedulingTimeLimit, time.Second)
+}
@@ -7,6 +7,8 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"reflect"
+	"sort"
 	"strings"
 	"testing"
 
@@ -83,6 +85,23 @@ func controlPlaneDetectedNoDataplane(t *testing.T, ctx context.Context, controlp
 	})
 }
 
+// controlPlaneDetectedRefNotPermitted is a helper function for tests that returns a function
+// that can be used to check if a ControlPlane detected that the reference to its dataplane
+// is not permitted by any ReferenceGrant.
+// Should be used in conjunction with require.Eventually or assert.Eventually.
+func controlPlaneDetectedRefNotPermitted(t *testing.T, ctx context.Context, controlplane types.NamespacedName) func() bool {
+	return controlPlanePredicate(t, ctx, controlplane, func(c *operatorv1alpha1.ControlPlane) bool {
+		for _, condition := range c.Status.Conditions {
+			if condition.Type == string(controllers.ControlPlaneConditionTypeProvisioned) &&
+				condition.Status == metav1.ConditionFalse &&
+				condition.Reason == string(controllers.ControlPlaneConditionReasonRefNotPermitted) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
 // controlPlaneIsProvisioned is a helper function for tests that returns a function
 // that can be used to check if a ControlPlane was provisioned.
 // Should be used in conjunction with require.Eventually or assert.Eventually.
@@ -259,9 +278,129 @@ func getResponseBodyContains(t *testing.T, ctx context.Context, url string, resp
 	}
 }
 
+// gatewayClassAcceptedConditionHasReason is a helper function for tests that returns a function
+// that can be used to check if a GatewayClass has the Accepted condition with the given status and reason.
+// Should be used in conjunction with require.Eventually or assert.Eventually.
+func gatewayClassAcceptedConditionHasReason(
+	t *testing.T,
+	ctx context.Context,
+	gatewayClassName string,
+	status metav1.ConditionStatus,
+	reason gatewayv1alpha2.GatewayClassConditionReason,
+) func() bool {
+	return func() bool {
+		gatewayClass, err := gatewayClient.GatewayV1alpha2().GatewayClasses().Get(ctx, gatewayClassName, metav1.GetOptions{})
+		require.NoError(t, err)
+		for _, condition := range gatewayClass.Status.Conditions {
+			if condition.Type == string(gatewayv1alpha2.GatewayClassConditionStatusAccepted) &&
+				condition.Status == status &&
+				condition.Reason == string(reason) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// gatewayClassHasFinalizer is a helper function for tests that returns a function
+// that can be used to check if a GatewayClass has the given finalizer.
+// Should be used in conjunction with require.Eventually or assert.Eventually.
+func gatewayClassHasFinalizer(t *testing.T, ctx context.Context, gatewayClassName string, finalizer string) func() bool {
+	return func() bool {
+		gatewayClass, err := gatewayClient.GatewayV1alpha2().GatewayClasses().Get(ctx, gatewayClassName, metav1.GetOptions{})
+		require.NoError(t, err)
+		for _, f := range gatewayClass.Finalizers {
+			if f == finalizer {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// sharedDataPlaneHasGateways is a helper function for tests that returns a function
+// that can be used to check if a single shared DataPlane exists for the GatewayClass
+// in the namespace, and that it's used exactly by the given Gateways.
+// Should be used in conjunction with require.Eventually or assert.Eventually.
+func sharedDataPlaneHasGateways(
+	t *testing.T,
+	ctx context.Context,
+	namespace string,
+	gatewayClass *gatewayv1alpha2.GatewayClass,
+	gateways ...types.NamespacedName,
+) func() bool {
+	return func() bool {
+		dataplanes, err := gatewayutils.ListSharedDataPlanes(ctx, mgrClient, namespace, string(gatewayClass.UID))
+		require.NoError(t, err)
+		if len(dataplanes) != 1 {
+			return false
+		}
+		expected := make([]string, 0, len(gateways))
+		for _, gateway := range gateways {
+			expected = append(expected, gateway.String())
+		}
+		sort.Strings(expected)
+		return reflect.DeepEqual(expected, gatewayutils.GetSharedDataPlaneGateways(&dataplanes[0]))
+	}
+}
+
+// gatewayListenerHasConflictedCondition is a helper function for tests that returns a function
+// that can be used to check if all the listeners of a Gateway have the Conflicted condition
+// with the given status and reason.
+// Should be used in conjunction with require.Eventually or assert.Eventually.
+func gatewayListenerHasConflictedCondition(
+	t *testing.T,
+	ctx context.Context,
+	gatewayNSN types.NamespacedName,
+	status metav1.ConditionStatus,
+	reason gatewayv1alpha2.ListenerConditionReason,
+) func() bool {
+	return func() bool {
+		gateway := mustGetGateway(t, ctx, gatewayNSN)
+		if len(gateway.Status.Listeners) == 0 {
+			return false
+		}
+		for _, listener := range gateway.Status.Listeners {
+			found := false
+			for _, condition := range listener.Conditions {
+				if condition.Type == string(gatewayv1alpha2.ListenerConditionConflicted) &&
+					condition.Status == status &&
+					condition.Reason == string(reason) {
+					found = true
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	}
+}
+
 // Not is a helper function for tests that returns a negation of a predicate.
 func Not(predicate func() bool) func() bool {
 	return func() bool {
 		return !predicate()
 	}
 }
+
+// dataPlaneRolloutIsInPhase is a helper function for tests that returns a function
+// that can be used to check if the rollout of a DataPlane is in the provided phase.
+// Should be used in conjunction with require.Eventually or assert.Eventually.
+func dataPlaneRolloutIsInPhase(
+	t *testing.T,
+	ctx context.Context,
+	dataplaneName types.NamespacedName,
+	phase operatorv1alpha1.DataPlaneRolloutPhase,
+	ret *operatorv1alpha1.DataPlaneRolloutStatus,
+) func() bool {
+	return dataPlanePredicate(t, ctx, dataplaneName, func(dataplane *operatorv1alpha1.DataPlane) bool {
+		if dataplane.Status.Rollout == nil || dataplane.Status.Rollout.Phase != phase {
+			return false
+		}
+		if ret != nil {
+			*ret = *dataplane.Status.Rollout
+		}
+		return true
+	})
+}
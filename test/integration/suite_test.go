@@ -249,6 +249,7 @@ func startControllerManager() {
 	cfg.GatewayControllerEnabled = true
 	cfg.ControlPlaneControllerEnabled = true
 	cfg.DataPlaneControllerEnabled = true
+	cfg.GatewayBackupControllerEnabled = true
 
 	if runWebhookTests {
 		cfg.WebhookCertDir = webhookCertDir
@@ -307,7 +308,7 @@ func generateWebhookCertificates() error {
 
 // prepareWebhook prepares for running webhook if we are going to run webhook tests. includes:
 // - creating self-signed TLS certificates for webhook server
-// - creating validaing webhook resource in test cluster
+// - creating validating and mutating webhook resources in test cluster
 func prepareWebhook() error {
 	// get IP for generating certificate and for clients to access.
 	if webhookServerIP == "" {
@@ -327,11 +328,21 @@ func prepareWebhook() error {
 
 	// create webhook resources in k8s.
 	fmt.Println("INFO: creating a validating webhook and waiting for it to start")
-	return createValidatingWebhook(
+	err = createValidatingWebhook(
 		ctx, k8sClient,
 		fmt.Sprintf("https://%s:%d/validate", webhookServerIP, webhookServerPort),
 		webhookCertDir+"/ca.crt",
 	)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("INFO: creating a mutating webhook")
+	return createMutatingWebhook(
+		ctx, k8sClient,
+		fmt.Sprintf("https://%s:%d/mutate", webhookServerIP, webhookServerPort),
+		webhookCertDir+"/ca.crt",
+	)
 }
 
 // waitForWebhook waits for webhook server being able to be accessed by HTTPS.
@@ -118,6 +118,48 @@ func createValidatingWebhook(ctx context.Context, k8sClient *kubernetes.Clientse
 						URL:      &webhookURL,
 						CABundle: caContent,
 					},
+					Rules: []admissionregistrationv1.RuleWithOperations{
+						{
+							Operations: []admissionregistrationv1.OperationType{
+								"CREATE",
+								"UPDATE",
+							},
+							Rule: admissionregistrationv1.Rule{
+								APIGroups:   []string{"gateway-operator.konghq.com"},
+								APIVersions: []string{"v1alpha1"},
+								Resources:   []string{"controlplanes", "dataplanes", "gatewayconfigurations"},
+							},
+						},
+					},
+					SideEffects:             &sideEffect,
+					AdmissionReviewVersions: []string{"v1", "v1beta1"},
+				},
+			},
+		},
+		metav1.CreateOptions{})
+	return err
+}
+
+// createMutatingWebhook creates mutating webhook for gateway operator.
+func createMutatingWebhook(ctx context.Context, k8sClient *kubernetes.Clientset, webhookURL string, caPath string) error {
+	sideEffect := admissionregistrationv1.SideEffectClassNone
+	caContent, err := os.ReadFile(caPath)
+	if err != nil {
+		return err
+	}
+
+	_, err = k8sClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Create(ctx,
+		&admissionregistrationv1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "gateway-operator-mutating-webhook",
+			},
+			Webhooks: []admissionregistrationv1.MutatingWebhook{
+				{
+					Name: "gateway-operator-mutation.konghq.com",
+					ClientConfig: admissionregistrationv1.WebhookClientConfig{
+						URL:      &webhookURL,
+						CABundle: caContent,
+					},
 					Rules: []admissionregistrationv1.RuleWithOperations{
 						{
 							Operations: []admissionregistrationv1.OperationType{
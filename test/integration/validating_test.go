@@ -67,7 +67,7 @@ func testDataplaneReconcileValidation(t *testing.T, namespace *corev1.Namespace)
 			validatingOK: true,
 		},
 		{
-			name: "reconciler:database_postgres_not_supported",
+			name: "reconciler:database_postgres_without_database",
 			dataplane: &operatorv1alpha1.DataPlane{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: namespace.Name,
@@ -84,7 +84,7 @@ func testDataplaneReconcileValidation(t *testing.T, namespace *corev1.Namespace)
 				},
 			},
 			validatingOK:     false,
-			conditionMessage: "database backend postgres of dataplane not supported currently",
+			conditionMessage: "database backend postgres of dataplane requires database to be set",
 		},
 
 		{
@@ -210,7 +210,7 @@ func testDataplaneValidatingWebhook(t *testing.T, namespace *corev1.Namespace) {
 			errMsg: "",
 		},
 		{
-			name: "webhook:database_postgres_not_supported",
+			name: "webhook:database_postgres_without_database",
 			dataplane: &operatorv1alpha1.DataPlane{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: namespace.Name,
@@ -226,7 +226,7 @@ func testDataplaneValidatingWebhook(t *testing.T, namespace *corev1.Namespace) {
 					},
 				},
 			},
-			errMsg: "database backend postgres of dataplane not supported currently",
+			errMsg: "database backend postgres of dataplane requires database to be set",
 		},
 		{
 			name: "webhook:database_xxx_not_supported",